@@ -0,0 +1,296 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/palantir/policy-bot/policy"
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/palantir/policy-bot/pull/pulltest"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+var lintCmdConfig struct {
+	PolicyPath string
+	PullPath   string
+}
+
+var LintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Evaluates a policy against a described pull request.",
+	Long: "Evaluates a policy file against a pull request described in a YAML file, without " +
+		"connecting to GitHub, and prints the result of each rule. Returns an error if the " +
+		"policy is invalid or if evaluation fails.",
+
+	RunE: lintCmd,
+}
+
+// lintPullRequest describes a pull request and the users related to it. It is
+// converted to a pull.Context for local evaluation.
+type lintPullRequest struct {
+	Title      string `yaml:"title"`
+	Author     string `yaml:"author"`
+	Body       string `yaml:"body"`
+	BaseBranch string `yaml:"base_branch"`
+	HeadBranch string `yaml:"head_branch"`
+	Draft      bool   `yaml:"draft"`
+
+	Files    []lintFile        `yaml:"files"`
+	Commits  []lintCommit      `yaml:"commits"`
+	Comments []lintComment     `yaml:"comments"`
+	Reviews  []lintReview      `yaml:"reviews"`
+	Labels   []string          `yaml:"labels"`
+	Statuses map[string]string `yaml:"statuses"`
+
+	OrgMemberships  map[string][]string        `yaml:"org_memberships"`
+	TeamMemberships map[string][]string        `yaml:"team_memberships"`
+	Collaborators   map[string]pull.Permission `yaml:"collaborators"`
+}
+
+type lintFile struct {
+	Filename  string `yaml:"filename"`
+	Status    string `yaml:"status"`
+	Additions int    `yaml:"additions"`
+	Deletions int    `yaml:"deletions"`
+}
+
+type lintCommit struct {
+	SHA            string   `yaml:"sha"`
+	Parents        []string `yaml:"parents"`
+	Author         string   `yaml:"author"`
+	Committer      string   `yaml:"committer"`
+	AuthorEmail    string   `yaml:"author_email"`
+	CommitterEmail string   `yaml:"committer_email"`
+}
+
+type lintComment struct {
+	Author    string    `yaml:"author"`
+	Body      string    `yaml:"body"`
+	CreatedAt time.Time `yaml:"created_at"`
+}
+
+type lintReview struct {
+	Author    string    `yaml:"author"`
+	State     string    `yaml:"state"`
+	Body      string    `yaml:"body"`
+	CreatedAt time.Time `yaml:"created_at"`
+}
+
+func readPolicyConfig(path string) (*policy.Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed reading policy file: %s", path)
+	}
+
+	config, err := policy.UnmarshalConfig(path, b)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed parsing policy file: %s", path)
+	}
+	return config, nil
+}
+
+// parseLintPullRequest creates a pull.Context from a YAML description of a
+// pull request. Commits are listed from oldest to newest and default to a
+// linear history. Comments and reviews without a creation time are assigned
+// times one minute apart, in the order they are listed, ending at now.
+func parseLintPullRequest(b []byte, now time.Time) (*pulltest.Context, error) {
+	var pr lintPullRequest
+	if err := yaml.UnmarshalStrict(b, &pr); err != nil {
+		return nil, errors.Wrap(err, "failed parsing pull request")
+	}
+
+	prctx := &pulltest.Context{
+		EvaluationTimestampValue: now,
+		NumberValue:              1,
+		TitleValue:               pr.Title,
+		AuthorValue:              pr.Author,
+		StateValue:               "open",
+		BranchBaseName:           pr.BaseBranch,
+		BranchHeadName:           pr.HeadBranch,
+		Draft:                    pr.Draft,
+		BodyValue: &pull.Body{
+			Body:   pr.Body,
+			Author: pr.Author,
+		},
+		ChangedFilesValue:   []*pull.File{},
+		CommitsValue:        []*pull.Commit{},
+		CommentsValue:       []*pull.Comment{},
+		ReviewsValue:        []*pull.Review{},
+		LabelsValue:         []string{},
+		LatestStatusesValue: pr.Statuses,
+		OrgMemberships:      pr.OrgMemberships,
+		TeamMemberships:     pr.TeamMemberships,
+	}
+
+	for _, f := range pr.Files {
+		var status pull.FileStatus
+		switch strings.ToLower(f.Status) {
+		case "", "modified":
+			status = pull.FileModified
+		case "added":
+			status = pull.FileAdded
+		case "deleted":
+			status = pull.FileDeleted
+		default:
+			return nil, errors.Errorf("invalid status for file %s: %q", f.Filename, f.Status)
+		}
+		prctx.ChangedFilesValue = append(prctx.ChangedFilesValue, &pull.File{
+			Filename:  f.Filename,
+			Status:    status,
+			Additions: f.Additions,
+			Deletions: f.Deletions,
+		})
+	}
+
+	for i, c := range pr.Commits {
+		sha := c.SHA
+		if sha == "" {
+			sha = fmt.Sprintf("%040x", i+1)
+		}
+
+		parents := c.Parents
+		if parents == nil && i > 0 {
+			parents = []string{prctx.CommitsValue[i-1].SHA}
+		}
+
+		prctx.CommitsValue = append(prctx.CommitsValue, &pull.Commit{
+			SHA:            sha,
+			Parents:        parents,
+			Author:         c.Author,
+			Committer:      c.Committer,
+			AuthorEmail:    c.AuthorEmail,
+			CommitterEmail: c.CommitterEmail,
+		})
+		prctx.HeadSHAValue = sha
+	}
+
+	events := len(pr.Comments) + len(pr.Reviews)
+	eventTime := func(i int, t time.Time) time.Time {
+		if t.IsZero() {
+			return now.Add(time.Duration(i-events+1) * time.Minute)
+		}
+		return t
+	}
+
+	for i, c := range pr.Comments {
+		prctx.CommentsValue = append(prctx.CommentsValue, &pull.Comment{
+			Author:    c.Author,
+			Body:      c.Body,
+			CreatedAt: eventTime(i, c.CreatedAt),
+		})
+	}
+
+	for i, r := range pr.Reviews {
+		state := pull.ReviewState(strings.ToLower(r.State))
+		switch state {
+		case pull.ReviewApproved, pull.ReviewChangesRequested, pull.ReviewCommented, pull.ReviewDismissed, pull.ReviewPending:
+		default:
+			return nil, errors.Errorf("invalid state for review by %s: %q", r.Author, r.State)
+		}
+		prctx.ReviewsValue = append(prctx.ReviewsValue, &pull.Review{
+			ID:        fmt.Sprintf("review-%d", i+1),
+			Author:    r.Author,
+			State:     state,
+			Body:      r.Body,
+			CreatedAt: eventTime(len(pr.Comments)+i, r.CreatedAt),
+			SHA:       prctx.HeadSHAValue,
+		})
+	}
+
+	for _, l := range pr.Labels {
+		prctx.LabelsValue = append(prctx.LabelsValue, strings.ToLower(l))
+	}
+
+	users := make([]string, 0, len(pr.Collaborators))
+	for user := range pr.Collaborators {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+
+	for _, user := range users {
+		prctx.CollaboratorsValue = append(prctx.CollaboratorsValue, &pull.Collaborator{
+			Name: user,
+			Permissions: []pull.CollaboratorPermission{
+				{Permission: pr.Collaborators[user], ViaRepo: true},
+			},
+		})
+	}
+
+	return prctx, nil
+}
+
+// printResult writes an indented tree of evaluation results.
+func printResult(w io.Writer, r *common.Result, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	desc := r.StatusDescription
+	if r.Error != nil {
+		desc = r.Error.Error()
+	}
+
+	if desc != "" {
+		fmt.Fprintf(w, "%s%s: %s (%s)\n", indent, r.Name, r.Status, desc)
+	} else {
+		fmt.Fprintf(w, "%s%s: %s\n", indent, r.Name, r.Status)
+	}
+
+	for _, c := range r.Children {
+		printResult(w, c, depth+1)
+	}
+}
+
+func lintCmd(cmd *cobra.Command, args []string) error {
+	config, err := readPolicyConfig(lintCmdConfig.PolicyPath)
+	if err != nil {
+		return err
+	}
+
+	evaluator, err := policy.ParsePolicy(config)
+	if err != nil {
+		return errors.Wrap(err, "invalid policy")
+	}
+
+	b, err := os.ReadFile(lintCmdConfig.PullPath)
+	if err != nil {
+		return errors.Wrapf(err, "failed reading pull request file: %s", lintCmdConfig.PullPath)
+	}
+
+	prctx, err := parseLintPullRequest(b, time.Now())
+	if err != nil {
+		return err
+	}
+
+	result := evaluator.Evaluate(context.Background(), prctx)
+	printResult(cmd.OutOrStdout(), &result, 0)
+
+	return errors.Wrap(result.Error, "evaluation failed")
+}
+
+func init() {
+	RootCmd.AddCommand(LintCmd)
+
+	LintCmd.Flags().StringVarP(&lintCmdConfig.PolicyPath, "policy", "p", ".policy.yml", "policy file to evaluate")
+	LintCmd.Flags().StringVarP(&lintCmdConfig.PullPath, "pull", "r", "pull.yml", "file describing the pull request")
+}
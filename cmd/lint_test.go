@@ -0,0 +1,185 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/palantir/policy-bot/policy"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestParseLintPullRequest(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	t.Run("full", func(t *testing.T) {
+		prctx, err := parseLintPullRequest([]byte(`
+title: "feat: add lint command"
+author: mhaypenny
+base_branch: develop
+files:
+  - filename: cmd/lint.go
+    status: added
+    additions: 100
+  - filename: README.md
+    additions: 5
+    deletions: 1
+commits:
+  - sha: "1111111111111111111111111111111111111111"
+    author: mhaypenny
+    committer: mhaypenny
+  - sha: "2222222222222222222222222222222222222222"
+    author: ttest
+    committer: mhaypenny
+    author_email: ttest@example.com
+    committer_email: noreply@github.com
+comments:
+  - author: ttest
+    body: ":+1:"
+reviews:
+  - author: santaclaus
+    state: APPROVED
+    created_at: 2026-01-01T00:00:00Z
+labels: ["Needs-Review"]
+statuses:
+  build: success
+team_memberships:
+  ttest: ["org/devtools"]
+collaborators:
+  ttest: write
+  mhaypenny: admin
+`), now)
+		require.NoError(t, err)
+
+		assert.Equal(t, "feat: add lint command", prctx.Title())
+		assert.Equal(t, "mhaypenny", prctx.Author())
+		assert.Equal(t, "2222222222222222222222222222222222222222", prctx.HeadSHA())
+
+		base, _ := prctx.Branches()
+		assert.Equal(t, "develop", base)
+
+		files, _ := prctx.ChangedFiles()
+		require.Len(t, files, 2)
+		assert.Equal(t, pull.FileAdded, files[0].Status)
+		assert.Equal(t, pull.FileModified, files[1].Status)
+		assert.Equal(t, 1, files[1].Deletions)
+
+		commits, _ := prctx.Commits()
+		require.Len(t, commits, 2)
+		assert.Empty(t, commits[0].Parents)
+		assert.Equal(t, []string{"1111111111111111111111111111111111111111"}, commits[1].Parents)
+		assert.Equal(t, "ttest@example.com", commits[1].AuthorEmail)
+		assert.Equal(t, "noreply@github.com", commits[1].CommitterEmail)
+
+		comments, _ := prctx.Comments()
+		require.Len(t, comments, 1)
+		assert.Equal(t, now.Add(-1*time.Minute), comments[0].CreatedAt)
+
+		reviews, _ := prctx.Reviews()
+		require.Len(t, reviews, 1)
+		assert.Equal(t, pull.ReviewApproved, reviews[0].State)
+		assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), reviews[0].CreatedAt)
+
+		labels, _ := prctx.Labels()
+		assert.Equal(t, []string{"needs-review"}, labels)
+
+		member, _ := prctx.IsTeamMember("org/devtools", "ttest")
+		assert.True(t, member, "ttest should be a team member")
+
+		perm, _ := prctx.CollaboratorPermission("ttest")
+		assert.Equal(t, pull.PermissionWrite, perm)
+	})
+
+	t.Run("invalidFileStatus", func(t *testing.T) {
+		_, err := parseLintPullRequest([]byte(`
+files:
+  - filename: README.md
+    status: renamed
+`), now)
+		assert.EqualError(t, err, `invalid status for file README.md: "renamed"`)
+	})
+
+	t.Run("invalidReviewState", func(t *testing.T) {
+		_, err := parseLintPullRequest([]byte(`
+reviews:
+  - author: ttest
+    state: lgtm
+`), now)
+		assert.EqualError(t, err, `invalid state for review by ttest: "lgtm"`)
+	})
+
+	t.Run("unknownField", func(t *testing.T) {
+		_, err := parseLintPullRequest([]byte(`
+reviewers: ["ttest"]
+`), now)
+		assert.Error(t, err)
+	})
+}
+
+func TestLintEvaluation(t *testing.T) {
+	var config policy.Config
+	require.NoError(t, yaml.UnmarshalStrict([]byte(`
+policy:
+  approval:
+    - review from devtools
+    - or:
+      - docs only
+approval_rules:
+  - name: review from devtools
+    requires:
+      count: 1
+      teams: ["org/devtools"]
+  - name: docs only
+    if:
+      only_changed_files:
+        paths: ["^docs/"]
+`), &config))
+
+	evaluator, err := policy.ParsePolicy(&config)
+	require.NoError(t, err)
+
+	prctx, err := parseLintPullRequest([]byte(`
+author: mhaypenny
+files:
+  - filename: cmd/lint.go
+reviews:
+  - author: ttest
+    state: approved
+team_memberships:
+  ttest: ["org/devtools"]
+`), time.Now())
+	require.NoError(t, err)
+
+	result := evaluator.Evaluate(context.Background(), prctx)
+	require.NoError(t, result.Error)
+
+	var out bytes.Buffer
+	printResult(&out, &result, 0)
+
+	expected := `policy: approved (All rules are approved)
+  approval: approved (All rules are approved)
+    review from devtools: approved (Approved by ttest)
+    or: skipped (All of the rules are skipped)
+      docs only: skipped (A changed file does not match the required pattern)
+  disapproval: skipped (No disapproval policy is specified or the policy is empty)
+`
+	assert.Equal(t, expected, out.String())
+}
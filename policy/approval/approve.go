@@ -17,23 +17,43 @@ package approval
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/palantir/go-baseapp/baseapp"
+	"github.com/palantir/policy-bot/policy/codeowners"
 	"github.com/palantir/policy-bot/policy/common"
 	"github.com/palantir/policy-bot/policy/predicate"
 	"github.com/palantir/policy-bot/pull"
 	"github.com/pkg/errors"
+	"github.com/rcrowley/go-metrics"
 	"github.com/rs/zerolog"
 )
 
+const (
+	// MetricsKeyRuleStatus counts rule evaluations by rule name and status.
+	// Rule names are reported with the "rule" label and statuses are reported
+	// with the "status" label.
+	MetricsKeyRuleStatus = "policy.rule.status"
+
+	maxRuleMetricNames      = 500
+	maxRuleMetricNameLength = 64
+	ruleMetricOtherName     = "other"
+)
+
 type Rule struct {
 	Name        string               `yaml:"name"`
 	Description string               `yaml:"description"`
 	Predicates  predicate.Predicates `yaml:"if"`
 	Options     Options              `yaml:"options"`
 	Requires    Requires             `yaml:"requires"`
+
+	// pool is the approval pool of the rule, or nil if the rule does not
+	// share approvals with other rules
+	pool *approvalPool
 }
 
 type Options struct {
@@ -41,11 +61,51 @@ type Options struct {
 	AllowContributor          bool `yaml:"allow_contributor"`
 	AllowNonAuthorContributor bool `yaml:"allow_non_author_contributor"`
 	InvalidateOnPush          bool `yaml:"invalidate_on_push"`
+	InvalidateOnReopen        bool `yaml:"invalidate_on_reopen"`
+	InvalidateOnForcePush     bool `yaml:"invalidate_on_force_push"`
+
+	// InvalidateOnPushPaths limits InvalidateOnPush to pushes with commits
+	// that change files matching at least one of the patterns. If empty, any
+	// push invalidates approvals.
+	InvalidateOnPushPaths []common.Regexp `yaml:"invalidate_on_push_paths"`
+
+	DisallowLastCommitterApproval bool `yaml:"disallow_last_committer_approval"`
+
+	// DisallowAuthorTeamApproval ignores approvals from users who are members
+	// of any team in the repository's organization that the author also
+	// belongs to. The author's own approval is still controlled by
+	// AllowAuthor.
+	DisallowAuthorTeamApproval bool `yaml:"disallow_author_team_approval"`
+
+	// CoolOff and ExpireAfter accept the same durations as the age
+	// predicates, including days like "7d".
+	CoolOff     predicate.Duration `yaml:"cool_off"`
+	ExpireAfter predicate.Duration `yaml:"expire_after"`
 
 	IgnoreEditedComments bool          `yaml:"ignore_edited_comments"`
 	IgnoreUpdateMerges   bool          `yaml:"ignore_update_merges"`
 	IgnoreCommitsBy      common.Actors `yaml:"ignore_commits_by"`
 
+	// IgnoreCommitsByEmail ignores commits where both the author and the
+	// committer email match any of the patterns. Unlike IgnoreCommitsBy, this
+	// works for commits with emails that are not associated with a user.
+	IgnoreCommitsByEmail []common.Regexp `yaml:"ignore_commits_by_email"`
+
+	// ApprovalPool names a pool of rules that share approvals. An approval
+	// that counts toward one rule in the pool does not count toward the
+	// others. Rules with no pool are evaluated independently.
+	ApprovalPool string `yaml:"approval_pool"`
+
+	// CacheResult reuses the result of the rule when it is evaluated again
+	// with the same head commit and the same values for the mutable inputs
+	// that the rule depends on, like reviews or statuses. This avoids
+	// repeating expensive predicates and membership checks, but the rule does
+	// not notice other changes, like changes to team membership, until the
+	// head commit or one of the inputs changes. Results that depend on the
+	// evaluation time or on other rules in an approval pool are not cached,
+	// and the option cannot be used with time-dependent predicates.
+	CacheResult bool `yaml:"cache_result"`
+
 	RequestReview RequestReview `yaml:"request_review"`
 
 	Methods *common.Methods `yaml:"methods"`
@@ -55,12 +115,23 @@ type RequestReview struct {
 	Enabled bool               `yaml:"enabled"`
 	Mode    common.RequestMode `yaml:"mode"`
 	Count   int                `yaml:"count"`
+
+	// Paths sets the reviewers to request when files matching specific
+	// paths change. If no paths match, the reviewers are selected from the
+	// actors allowed to approve the rule.
+	Paths []PathReviewers `yaml:"paths"`
+}
+
+type PathReviewers struct {
+	Paths  []common.Regexp `yaml:"paths"`
+	Actors common.Actors   `yaml:",inline"`
 }
 
 func (opts *Options) GetMethods() *common.Methods {
-	methods := opts.Methods
-	if methods == nil {
-		methods = &common.Methods{}
+	// Fill defaults in a copy because rules may be evaluated concurrently
+	var methods common.Methods
+	if opts.Methods != nil {
+		methods = *opts.Methods
 	}
 	if methods.Comments == nil {
 		methods.Comments = []string{
@@ -74,19 +145,156 @@ func (opts *Options) GetMethods() *common.Methods {
 	}
 
 	methods.GithubReviewState = pull.ReviewApproved
-	return methods
+	return &methods
 }
 
 type Requires struct {
 	Count      int                  `yaml:"count"`
 	Actors     common.Actors        `yaml:",inline"`
 	Conditions predicate.Predicates `yaml:"conditions"`
+
+	// TeamCounts maps team names to the number of approvals required from
+	// members of that team. Each team is evaluated independently of the other
+	// teams and of Count.
+	TeamCounts map[string]int `yaml:"team_counts"`
+
+	// CodeOwnerTeams requires at least one approval from each team that owns
+	// a changed file according to the CODEOWNERS file on the base branch. The
+	// teams are evaluated together with TeamCounts.
+	CodeOwnerTeams bool `yaml:"code_owner_teams"`
+
+	// MaxPerOrg limits the number of approvals from members of each of the
+	// organizations in Actors that count toward Count. Approvals from users
+	// who are not members of these organizations are not limited.
+	MaxPerOrg int `yaml:"max_per_org"`
+
+	// RequireDistinctTeams counts at most one approval from each of the teams
+	// in Actors toward Count. Approvals from users who are not members of
+	// these teams are not limited.
+	RequireDistinctTeams bool `yaml:"require_distinct_teams"`
+
+	// RequireIndependentApproval requires an additional approval if every
+	// approver that counts toward Count also contributed commits to the pull
+	// request. The additional approval must come from a user who did not
+	// contribute commits.
+	RequireIndependentApproval bool `yaml:"require_independent_approval"`
+
+	// MinPermission ignores approvals from users whose permission on the
+	// repository is lower than this level, even if they are otherwise allowed
+	// to approve. It applies to Count, TeamCounts, CodeOwnerTeams, and
+	// AllUsers. If not set, approvals are not limited by permission.
+	MinPermission pull.Permission `yaml:"min_permission"`
+
+	// AllUsers is a list of users who must each approve. The author of the
+	// pull request is never required to approve.
+	AllUsers []string `yaml:"all_users"`
+
+	// LabelCounts increases Count when the pull request has specific labels.
+	// If the pull request has all of the labels of one or more entries, the
+	// largest count of these entries replaces Count if it is larger.
+	LabelCounts []LabelCount `yaml:"label_counts"`
+
+	// SizeCounts increases Count based on the lines modified by the pull
+	// request. The largest count of the entries that match the pull request
+	// replaces Count if it is larger than Count and any label count.
+	SizeCounts []SizeCount `yaml:"size_counts"`
+}
+
+// LabelCount is the number of approvals required when a pull request has all
+// of the labels.
+type LabelCount struct {
+	Labels []string `yaml:"labels"`
+	Count  int      `yaml:"count"`
+}
+
+// SizeCount is the number of approvals required when the lines modified by a
+// pull request match the conditions, which use the same format as the
+// modified_lines predicate.
+type SizeCount struct {
+	predicate.ModifiedLines `yaml:",inline"`
+
+	Count int `yaml:"count"`
+}
+
+// requiresApprovals returns true if the rule requires approval from any users.
+func (r *Requires) requiresApprovals() bool {
+	if r.Count > 0 || len(r.AllUsers) > 0 || r.CodeOwnerTeams {
+		return true
+	}
+	for _, count := range r.TeamCounts {
+		if count > 0 {
+			return true
+		}
+	}
+	for _, lc := range r.LabelCounts {
+		if lc.Count > 0 {
+			return true
+		}
+	}
+	for _, sc := range r.SizeCounts {
+		if sc.Count > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// requiredCount returns the number of approvals required from Actors given
+// the labels and the size of the pull request. If a label count applies, it
+// also returns the labels of the entry that set the count. If a size count
+// applies, it instead returns the condition of the entry that set the count.
+func (r *Requires) requiredCount(ctx context.Context, prctx pull.Context) (int, []string, string, error) {
+	count := r.Count
+	var countLabels []string
+	var countSize string
+
+	if len(r.LabelCounts) > 0 {
+		labels, err := prctx.Labels()
+		if err != nil {
+			return 0, nil, "", errors.Wrap(err, "failed to list pull request labels")
+		}
+
+		for _, lc := range r.LabelCounts {
+			if lc.Count <= count {
+				continue
+			}
+
+			hasLabels := true
+			for _, label := range lc.Labels {
+				if !slices.Contains(labels, strings.ToLower(label)) {
+					hasLabels = false
+					break
+				}
+			}
+			if hasLabels {
+				count = lc.Count
+				countLabels = lc.Labels
+			}
+		}
+	}
+
+	for _, sc := range r.SizeCounts {
+		if sc.Count <= count {
+			continue
+		}
+
+		result, err := sc.ModifiedLines.Evaluate(ctx, prctx)
+		if err != nil {
+			return 0, nil, "", errors.WithMessage(err, "failed to evaluate size count")
+		}
+		if result.Satisfied {
+			count = sc.Count
+			countLabels = nil
+			countSize = strings.Join(result.ConditionValues, ", ")
+		}
+	}
+	return count, countLabels, countSize, nil
 }
 
 func (r *Rule) Trigger() common.Trigger {
 	t := common.TriggerCommit
 
-	if r.Requires.Count > 0 {
+	if r.Requires.requiresApprovals() {
 		m := r.Options.GetMethods()
 		if len(m.Comments) > 0 || len(m.CommentPatterns) > 0 {
 			t |= common.TriggerComment
@@ -99,6 +307,9 @@ func (r *Rule) Trigger() common.Trigger {
 		}
 	}
 
+	if len(r.Requires.LabelCounts) > 0 {
+		t |= common.TriggerLabel
+	}
 	for _, c := range r.Requires.Conditions.Predicates() {
 		t |= c.Trigger()
 	}
@@ -117,6 +328,30 @@ func (r *Rule) Evaluate(ctx context.Context, prctx pull.Context) (res common.Res
 	res.Status = common.StatusSkipped
 	res.Methods = r.Options.GetMethods()
 
+	defer func() {
+		countRuleStatus(ctx, r.Name, res)
+	}()
+
+	if cache := resultCacheFromContext(ctx); cache != nil && r.Options.CacheResult && r.pool == nil {
+		key, err := r.resultCacheKey(prctx)
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to compute rule result cache key, evaluating without cache")
+		} else {
+			if cached, ok := cache.GetRuleResult(key); ok {
+				if cachedRes, ok := cached.(common.Result); ok {
+					log.Debug().Msg("using cached rule result")
+					res = cachedRes
+					return
+				}
+			}
+			defer func() {
+				if res.Error == nil && res.ReevaluateAt.IsZero() {
+					cache.SetRuleResult(key, res)
+				}
+			}()
+		}
+	}
+
 	var predicateResults []*common.PredicateResult
 
 	for _, p := range r.Predicates.Predicates() {
@@ -126,6 +361,7 @@ func (r *Rule) Evaluate(ctx context.Context, prctx pull.Context) (res common.Res
 			return
 		}
 		predicateResults = append(predicateResults, result)
+		res.ReevaluateBy(result.ReevaluateAt)
 
 		if !result.Satisfied {
 			log.Debug().Msgf("skipping rule, predicate of type %T was not satisfied", p)
@@ -156,18 +392,136 @@ func (r *Rule) Evaluate(ctx context.Context, prctx pull.Context) (res common.Res
 	res.Requires = result
 	res.Dismissals = dismissals
 	res.StatusDescription = statusDescription(approved, result, candidates)
+	for _, c := range result.Conditions {
+		res.ReevaluateBy(c.ReevaluateAt)
+	}
 
 	if approved {
 		res.Status = common.StatusApproved
+
+		remaining, err := r.coolOffRemaining(prctx, allApprovers(result))
+		if err != nil {
+			res.Error = errors.Wrap(err, "failed to compute cool-off period")
+			return
+		}
+
+		if remaining > 0 {
+			log.Debug().Msgf("rule is approved, but cool-off period ends in %s", remaining)
+
+			res.Status = common.StatusPending
+			res.StatusDescription = fmt.Sprintf("%s; cool-off period ends in %s", res.StatusDescription, remaining)
+			res.ReevaluateBy(prctx.EvaluationTimestamp().Add(remaining))
+		} else {
+			res.ReevaluateBy(r.approvalExpiration(allApprovers(result)))
+		}
 	} else {
 		res.Status = common.StatusPending
-		res.ReviewRequestRule = r.getReviewRequestRule()
+		res.ReviewRequestRule = r.getReviewRequestRule(result.Count)
 	}
 
 	return
 }
 
-func (r *Rule) getReviewRequestRule() *common.ReviewRequestRule {
+type ruleMetricsContextKey struct{}
+
+// WithRuleMetrics returns a context that counts the status of each evaluated
+// rule. Only evaluations that post a result for a pull request should count
+// rules, so that previews like simulations do not skew the counts.
+func WithRuleMetrics(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ruleMetricsContextKey{}, true)
+}
+
+var ruleMetricNames = struct {
+	sync.Mutex
+	names map[string]struct{}
+}{names: make(map[string]struct{})}
+
+// ruleMetricName returns the value of the "rule" label for a rule name. Names
+// are truncated and, once maxRuleMetricNames distinct names are in use, new
+// names are reported as "other" to bound the number of counters.
+func ruleMetricName(name string) string {
+	// commas separate labels, so they cannot appear in label values
+	name = strings.ReplaceAll(name, ",", "")
+	if r := []rune(name); len(r) > maxRuleMetricNameLength {
+		name = string(r[:maxRuleMetricNameLength])
+	}
+
+	ruleMetricNames.Lock()
+	defer ruleMetricNames.Unlock()
+
+	if _, ok := ruleMetricNames.names[name]; ok {
+		return name
+	}
+	if len(ruleMetricNames.names) >= maxRuleMetricNames {
+		return ruleMetricOtherName
+	}
+	ruleMetricNames.names[name] = struct{}{}
+	return name
+}
+
+// countRuleStatus increments the counter for the status of a rule result if
+// the context enables rule metrics. Results with errors are counted with the
+// "error" status.
+func countRuleStatus(ctx context.Context, name string, res common.Result) {
+	if enabled, _ := ctx.Value(ruleMetricsContextKey{}).(bool); !enabled {
+		return
+	}
+
+	status := res.Status.String()
+	if res.Error != nil {
+		status = "error"
+	}
+	name = ruleMetricName(name)
+
+	key := fmt.Sprintf("%s[rule:%s,status:%s]", MetricsKeyRuleStatus, name, status)
+	metrics.GetOrRegisterCounter(key, baseapp.MetricsCtx(ctx)).Inc(1)
+}
+
+// coolOffRemaining returns the time left before the cool-off period following
+// the most recent approval ends, or zero if there is no cool-off period. The
+// cool-off period only applies if the author has permission to merge the pull
+// request themselves.
+func (r *Rule) coolOffRemaining(prctx pull.Context, approvers []*common.Candidate) (time.Duration, error) {
+	if r.Options.CoolOff <= 0 || len(approvers) == 0 {
+		return 0, nil
+	}
+
+	perm, err := prctx.CollaboratorPermission(prctx.Author())
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get author permission")
+	}
+	if perm < pull.PermissionWrite {
+		return 0, nil
+	}
+
+	var lastApproval time.Time
+	for _, c := range approvers {
+		if c.CreatedAt.After(lastApproval) {
+			lastApproval = c.CreatedAt
+		}
+	}
+
+	remaining := lastApproval.Add(time.Duration(r.Options.CoolOff)).Sub(prctx.EvaluationTimestamp())
+	return remaining.Round(time.Second), nil
+}
+
+// approvalExpiration returns the time at which the earliest of the approvals
+// expires, or the zero time if approvals do not expire.
+func (r *Rule) approvalExpiration(approvers []*common.Candidate) time.Time {
+	if r.Options.ExpireAfter <= 0 || len(approvers) == 0 {
+		return time.Time{}
+	}
+
+	firstApproval := approvers[0].CreatedAt
+	for _, c := range approvers[1:] {
+		if c.CreatedAt.Before(firstApproval) {
+			firstApproval = c.CreatedAt
+		}
+	}
+	return firstApproval.Add(time.Duration(r.Options.ExpireAfter))
+}
+
+func (r *Rule) getReviewRequestRule(requiredCount int) *common.ReviewRequestRule {
 	if !r.Options.RequestReview.Enabled {
 		return nil
 	}
@@ -179,7 +533,18 @@ func (r *Rule) getReviewRequestRule() *common.ReviewRequestRule {
 
 	requestedCount := r.Options.RequestReview.Count
 	if requestedCount == 0 {
-		requestedCount = r.Requires.Count
+		requestedCount = requiredCount
+	}
+
+	var pathReviewers []*common.PathReviewers
+	for _, p := range r.Options.RequestReview.Paths {
+		pathReviewers = append(pathReviewers, &common.PathReviewers{
+			Paths:         p.Paths,
+			Users:         p.Actors.Users,
+			Teams:         p.Actors.Teams,
+			Organizations: p.Actors.Organizations,
+			Permissions:   p.Actors.GetPermissions(),
+		})
 	}
 
 	return &common.ReviewRequestRule{
@@ -187,43 +552,510 @@ func (r *Rule) getReviewRequestRule() *common.ReviewRequestRule {
 		Teams:          r.Requires.Actors.Teams,
 		Organizations:  r.Requires.Actors.Organizations,
 		Permissions:    r.Requires.Actors.GetPermissions(),
-		RequiredCount:  r.Requires.Count,
+		Except:         r.Requires.Actors.Except,
+		RequiredCount:  requiredCount,
 		RequestedCount: requestedCount,
 		Mode:           mode,
+		PathReviewers:  pathReviewers,
 	}
 }
 
 func (r *Rule) IsApproved(ctx context.Context, prctx pull.Context, candidates []*common.Candidate) (bool, common.RequiresResult, error) {
-	approvedByActors, approvers, err := r.isApprovedByActors(ctx, prctx, candidates)
+	required, countLabels, countSize, err := r.Requires.requiredCount(ctx, prctx)
+	if err != nil {
+		return false, common.RequiresResult{}, err
+	}
+
+	approvedByActors, approvers, err := r.isApprovedByActors(ctx, prctx, candidates, required)
 	if err != nil {
 		return false, common.RequiresResult{}, err
 	}
 
+	var lowPermission []*common.Candidate
+	if r.Requires.MinPermission > pull.PermissionNone && len(approvers) > 0 {
+		approvers, lowPermission, err = r.limitApproversToPermission(ctx, prctx, approvers)
+		if err != nil {
+			return false, common.RequiresResult{}, err
+		}
+		approvedByActors = len(approvers) >= required
+	}
+
+	var authorTeam []*common.Candidate
+	if r.Options.DisallowAuthorTeamApproval && len(approvers) > 0 {
+		approvers, authorTeam, err = r.limitApproversToOtherTeams(ctx, prctx, approvers)
+		if err != nil {
+			return false, common.RequiresResult{}, err
+		}
+		approvedByActors = len(approvers) >= required
+	}
+
+	var pooled []*common.Candidate
+	if r.pool != nil && len(approvers) > 0 {
+		approvers, pooled, err = r.limitApproversToPool(ctx, prctx, approvers)
+		if err != nil {
+			return false, common.RequiresResult{}, err
+		}
+		approvedByActors = len(approvers) >= required
+	}
+
+	var excess []*common.Candidate
+	if r.Requires.MaxPerOrg > 0 && len(approvers) > 0 {
+		approvers, excess, err = r.limitApproversPerOrg(ctx, prctx, approvers)
+		if err != nil {
+			return false, common.RequiresResult{}, err
+		}
+		approvedByActors = len(approvers) >= required
+	}
+
+	var sameTeam []*common.Candidate
+	var approverTeams map[string]string
+	if r.Requires.RequireDistinctTeams && len(approvers) > 0 {
+		approvers, sameTeam, approverTeams, err = r.limitApproversToDistinctTeams(ctx, prctx, approvers)
+		if err != nil {
+			return false, common.RequiresResult{}, err
+		}
+		approvedByActors = len(approvers) >= required
+	}
+
+	count := required
+	var needsIndependent bool
+	if r.Requires.RequireIndependentApproval && approvedByActors && len(approvers) > 0 {
+		needsIndependent, err = r.allApproversContributed(ctx, prctx, approvers)
+		if err != nil {
+			return false, common.RequiresResult{}, err
+		}
+		if needsIndependent {
+			count = len(approvers) + 1
+			approvedByActors = false
+		}
+	}
+
 	approvedByConditions, conditions, err := r.isApprovedByConditions(ctx, prctx)
 	if err != nil {
 		return false, common.RequiresResult{}, err
 	}
 
+	// Team counts and required users only count approvals from users with the
+	// minimum permission, like approvals that count toward the count
+	permitted := candidates
+	hasTeamsOrUsers := len(r.Requires.TeamCounts) > 0 || r.Requires.CodeOwnerTeams || len(r.Requires.AllUsers) > 0
+	if r.Requires.MinPermission > pull.PermissionNone && hasTeamsOrUsers && len(candidates) > 0 {
+		permitted, _, err = r.limitApproversToPermission(ctx, prctx, candidates)
+		if err != nil {
+			return false, common.RequiresResult{}, err
+		}
+	}
+
+	approvedByTeams, teamCounts, err := r.isApprovedByTeamCounts(ctx, prctx, permitted)
+	if err != nil {
+		return false, common.RequiresResult{}, err
+	}
+
+	approvedByUsers, userApprovals, err := r.isApprovedByAllUsers(ctx, prctx, permitted)
+	if err != nil {
+		return false, common.RequiresResult{}, err
+	}
+
 	result := common.RequiresResult{
-		Count:      r.Requires.Count,
-		Actors:     r.Requires.Actors,
-		Approvers:  approvers,
-		Conditions: conditions,
+		Count:                       count,
+		CountLabels:                 countLabels,
+		CountSize:                   countSize,
+		Actors:                      r.Requires.Actors,
+		Approvers:                   approvers,
+		PooledApprovers:             pooled,
+		ExcessApprovers:             excess,
+		SameTeamApprovers:           sameTeam,
+		AuthorTeamApprovers:         authorTeam,
+		LowPermissionApprovers:      lowPermission,
+		ApproverTeams:               approverTeams,
+		RequiresIndependentApproval: needsIndependent,
+		Conditions:                  conditions,
+		TeamCounts:                  teamCounts,
+		UserApprovals:               userApprovals,
 	}
-	return approvedByActors && approvedByConditions, result, nil
+	return approvedByActors && approvedByConditions && approvedByTeams && approvedByUsers, result, nil
 }
 
-func (r *Rule) isApprovedByActors(ctx context.Context, prctx pull.Context, candidates []*common.Candidate) (bool, []*common.Candidate, error) {
+func (r *Rule) isApprovedByActors(ctx context.Context, prctx pull.Context, candidates []*common.Candidate, required int) (bool, []*common.Candidate, error) {
 	log := zerolog.Ctx(ctx)
 
-	if r.Requires.Count <= 0 {
+	if required <= 0 {
 		log.Debug().Msg("rule requires no approvals")
 		return true, nil, nil
 	}
 
 	log.Debug().Msgf("found %d candidates for approval", len(candidates))
 
-	// collect users "banned" by approval options
+	banned, err := r.bannedUsers(ctx, prctx)
+	if err != nil {
+		return false, nil, err
+	}
+
+	// filter real approvers using banned status and required membership
+	var approvers []*common.Candidate
+	for _, c := range candidates {
+		if banned[c.User] {
+			log.Debug().Str("user", c.User).Msg("rejecting approval by banned user")
+			continue
+		}
+
+		if common.IsBot(c.User) && !r.Requires.Actors.IsAllowedBot(c.User) {
+			log.Debug().Str("user", c.User).Msg("ignoring approval by bot that is not explicitly allowed")
+			continue
+		}
+
+		isApprover, err := r.Requires.Actors.IsActor(ctx, prctx, c.User)
+		if err != nil {
+			return false, nil, errors.Wrap(err, "failed to check candidate status")
+		}
+		if !isApprover {
+			log.Debug().Str("user", c.User).Msg("ignoring approval by non-required user")
+			continue
+		}
+
+		approvers = append(approvers, c)
+	}
+
+	log.Debug().Msgf("found %d/%d required approvers", len(approvers), required)
+	return len(approvers) >= required, approvers, nil
+}
+
+// allApproversContributed returns true if every approver is the author or
+// committer of a commit in the pull request.
+func (r *Rule) allApproversContributed(ctx context.Context, prctx pull.Context, approvers []*common.Candidate) (bool, error) {
+	commits, err := r.filteredCommits(ctx, prctx)
+	if err != nil {
+		return false, err
+	}
+
+	contributors := make(map[string]bool)
+	for _, c := range commits {
+		for _, u := range c.Users() {
+			contributors[u] = true
+		}
+	}
+
+	for _, c := range approvers {
+		if !contributors[c.User] {
+			return false, nil
+		}
+	}
+	zerolog.Ctx(ctx).Debug().Msg("all approvers contributed commits, requiring an independent approval")
+	return true, nil
+}
+
+// limitApproversToPermission returns the approvers who have at least the
+// minimum permission on the repository and the approvers who do not.
+func (r *Rule) limitApproversToPermission(ctx context.Context, prctx pull.Context, approvers []*common.Candidate) ([]*common.Candidate, []*common.Candidate, error) {
+	log := zerolog.Ctx(ctx)
+
+	var counted, lowPermission []*common.Candidate
+	for _, c := range approvers {
+		perm, err := prctx.CollaboratorPermission(c.User)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to get collaborator permission")
+		}
+		if perm < r.Requires.MinPermission {
+			log.Debug().Str("user", c.User).Msgf("ignoring approval by user with %s permission, %s is required", perm, r.Requires.MinPermission)
+			lowPermission = append(lowPermission, c)
+			continue
+		}
+		counted = append(counted, c)
+	}
+	return counted, lowPermission, nil
+}
+
+// limitApproversToOtherTeams returns the approvers who share no team with the
+// author and the approvers who share at least one team with the author.
+func (r *Rule) limitApproversToOtherTeams(ctx context.Context, prctx pull.Context, approvers []*common.Candidate) ([]*common.Candidate, []*common.Candidate, error) {
+	log := zerolog.Ctx(ctx)
+
+	author := prctx.Author()
+	authorTeams, err := prctx.UserTeams(prctx.RepositoryOwner(), author)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get teams of the author")
+	}
+	if len(authorTeams) == 0 {
+		return approvers, nil, nil
+	}
+
+	var counted, sameTeam []*common.Candidate
+	for _, c := range approvers {
+		shared := false
+		if c.User != author {
+			for _, team := range authorTeams {
+				member, err := prctx.IsTeamMember(team, c.User)
+				if err != nil {
+					return nil, nil, errors.Wrap(err, "failed to get team membership")
+				}
+				if member {
+					shared = true
+					break
+				}
+			}
+		}
+
+		if shared {
+			log.Debug().Str("user", c.User).Msg("ignoring approval from a member of the author's teams")
+			sameTeam = append(sameTeam, c)
+		} else {
+			counted = append(counted, c)
+		}
+	}
+	return counted, sameTeam, nil
+}
+
+// limitApproversPerOrg returns the approvers that count toward the rule when
+// at most MaxPerOrg approvers from each of the rule's organizations count, and
+// the approvers that do not count. Users who belong to several organizations
+// count toward whichever organization maximizes the number of approvals.
+func (r *Rule) limitApproversPerOrg(ctx context.Context, prctx pull.Context, approvers []*common.Candidate) ([]*common.Candidate, []*common.Candidate, error) {
+	log := zerolog.Ctx(ctx)
+
+	counted, excess, _, err := limitApprovers(approvers, r.Requires.Actors.Organizations, r.Requires.MaxPerOrg, func(org, user string) (bool, error) {
+		member, err := prctx.IsOrgMember(org, user)
+		return member, errors.Wrap(err, "failed to get org membership")
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, c := range excess {
+		log.Debug().Str("user", c.User).Msgf("ignoring approval exceeding the limit of %d per organization", r.Requires.MaxPerOrg)
+	}
+	return counted, excess, nil
+}
+
+// limitApproversToDistinctTeams returns the approvers that count toward the
+// rule when each of the rule's teams is represented by at most one approver,
+// the approvers that do not count, and the team chosen for each approver that
+// counts. Users who belong to several teams represent whichever team
+// maximizes the number of approvals.
+func (r *Rule) limitApproversToDistinctTeams(ctx context.Context, prctx pull.Context, approvers []*common.Candidate) ([]*common.Candidate, []*common.Candidate, map[string]string, error) {
+	log := zerolog.Ctx(ctx)
+
+	counted, excess, teams, err := limitApprovers(approvers, r.Requires.Actors.Teams, 1, func(team, user string) (bool, error) {
+		member, err := prctx.IsTeamMember(team, user)
+		return member, errors.Wrap(err, "failed to get team membership")
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	for _, c := range excess {
+		log.Debug().Str("user", c.User).Msg("ignoring approval from a team that is already represented")
+	}
+	return counted, excess, teams, nil
+}
+
+// limitApprovers assigns approvers to the groups they belong to so that each
+// group has at most limit approvers. It returns the approvers that count, in
+// their original order, the approvers that do not count, and the group
+// assigned to each approver. Approvers who are not members of any group always
+// count and have no assigned group.
+func limitApprovers(approvers []*common.Candidate, groups []string, limit int, isMember func(group, user string) (bool, error)) ([]*common.Candidate, []*common.Candidate, map[string]string, error) {
+	// memberships[i] contains the indices of the groups of approvers[i]
+	memberships := make([][]int, len(approvers))
+	for i, c := range approvers {
+		for j, group := range groups {
+			member, err := isMember(group, c.User)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			if member {
+				memberships[i] = append(memberships[i], j)
+			}
+		}
+	}
+
+	// assigned[j] contains the indices of the approvers counted for groups[j]
+	assigned := make([][]int, len(groups))
+
+	// assign tries to count approver i for one of their groups, moving other
+	// approvers to different groups if necessary
+	var assign func(i int, visited []bool) bool
+	assign = func(i int, visited []bool) bool {
+		for _, j := range memberships[i] {
+			if visited[j] {
+				continue
+			}
+			visited[j] = true
+
+			if len(assigned[j]) < limit {
+				assigned[j] = append(assigned[j], i)
+				return true
+			}
+			for k, other := range assigned[j] {
+				if assign(other, visited) {
+					assigned[j][k] = i
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	var counted, excess []*common.Candidate
+	for i, c := range approvers {
+		if len(memberships[i]) == 0 || assign(i, make([]bool, len(groups))) {
+			counted = append(counted, c)
+		} else {
+			excess = append(excess, c)
+		}
+	}
+
+	groupsByUser := make(map[string]string)
+	for j, members := range assigned {
+		for _, i := range members {
+			groupsByUser[approvers[i].User] = groups[j]
+		}
+	}
+	return counted, excess, groupsByUser, nil
+}
+
+func (r *Rule) isApprovedByTeamCounts(ctx context.Context, prctx pull.Context, candidates []*common.Candidate) (bool, []*common.TeamCountResult, error) {
+	log := zerolog.Ctx(ctx)
+
+	counts := make(map[string]int, len(r.Requires.TeamCounts))
+	for team, count := range r.Requires.TeamCounts {
+		counts[team] = count
+	}
+
+	ownedPaths, err := r.codeOwnerTeams(ctx, prctx)
+	if err != nil {
+		return false, nil, err
+	}
+	for team := range ownedPaths {
+		if counts[team] < 1 {
+			counts[team] = 1
+		}
+	}
+
+	if len(counts) == 0 {
+		return true, nil, nil
+	}
+
+	banned, err := r.bannedUsers(ctx, prctx)
+	if err != nil {
+		return false, nil, err
+	}
+
+	teams := make([]string, 0, len(counts))
+	for team := range counts {
+		teams = append(teams, team)
+	}
+	sort.Strings(teams)
+
+	approved := true
+	results := make([]*common.TeamCountResult, 0, len(teams))
+
+	for _, team := range teams {
+		result := &common.TeamCountResult{
+			Team:       team,
+			Count:      counts[team],
+			OwnedPaths: ownedPaths[team],
+		}
+
+		for _, c := range candidates {
+			if banned[c.User] {
+				continue
+			}
+
+			member, err := prctx.IsTeamMember(team, c.User)
+			if err != nil {
+				return false, nil, errors.Wrap(err, "failed to get team membership")
+			}
+			if member {
+				result.Approvers = append(result.Approvers, c)
+			}
+		}
+
+		log.Debug().Msgf("found %d/%d required approvers from team %s", len(result.Approvers), result.Count, team)
+
+		approved = approved && result.IsApproved()
+		results = append(results, result)
+	}
+
+	return approved, results, nil
+}
+
+// codeOwnerTeams returns the teams that own the changed files of the pull
+// request according to the CODEOWNERS file, mapped to the files each team
+// owns. It returns nil if the rule does not require approval from code owners
+// or if the repository has no CODEOWNERS file.
+func (r *Rule) codeOwnerTeams(ctx context.Context, prctx pull.Context) (map[string][]string, error) {
+	log := zerolog.Ctx(ctx)
+
+	if !r.Requires.CodeOwnerTeams {
+		return nil, nil
+	}
+
+	owners, err := codeowners.Load(prctx)
+	if err != nil {
+		return nil, err
+	}
+	if owners == nil {
+		log.Debug().Msg("no CODEOWNERS file found, no code owner approvals required")
+		return nil, nil
+	}
+
+	files, err := prctx.ChangedFiles()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list changed files")
+	}
+
+	teams := make(map[string][]string)
+	for _, f := range files {
+		for _, team := range owners.TeamOwners(f.Filename) {
+			teams[team] = append(teams[team], f.Filename)
+		}
+	}
+	return teams, nil
+}
+
+func (r *Rule) isApprovedByAllUsers(ctx context.Context, prctx pull.Context, candidates []*common.Candidate) (bool, []*common.UserApprovalResult, error) {
+	log := zerolog.Ctx(ctx)
+
+	if len(r.Requires.AllUsers) == 0 {
+		return true, nil, nil
+	}
+
+	banned, err := r.bannedUsers(ctx, prctx)
+	if err != nil {
+		return false, nil, err
+	}
+
+	approvals := make(map[string]*common.Candidate)
+	for _, c := range candidates {
+		if !banned[c.User] {
+			approvals[c.User] = c
+		}
+	}
+
+	author := prctx.Author()
+	approved := true
+
+	var results []*common.UserApprovalResult
+	for _, user := range r.Requires.AllUsers {
+		if user == author {
+			continue
+		}
+
+		result := &common.UserApprovalResult{
+			User:     user,
+			Approval: approvals[user],
+		}
+		if result.Approval == nil {
+			log.Debug().Str("user", user).Msg("waiting for approval by required user")
+			approved = false
+		}
+		results = append(results, result)
+	}
+
+	return approved, results, nil
+}
+
+// bannedUsers returns the users who cannot approve the pull request because
+// of the rule options.
+func (r *Rule) bannedUsers(ctx context.Context, prctx pull.Context) (map[string]bool, error) {
 	banned := make(map[string]bool)
 
 	// "author" is the user who opened the PR
@@ -238,7 +1070,7 @@ func (r *Rule) isApprovedByActors(ctx context.Context, prctx pull.Context, candi
 	if !r.Options.AllowContributor && !r.Options.AllowNonAuthorContributor {
 		commits, err := r.filteredCommits(ctx, prctx)
 		if err != nil {
-			return false, nil, err
+			return nil, err
 		}
 
 		for _, c := range commits {
@@ -250,28 +1082,22 @@ func (r *Rule) isApprovedByActors(ctx context.Context, prctx pull.Context, candi
 		}
 	}
 
-	// filter real approvers using banned status and required membership
-	var approvers []*common.Candidate
-	for _, c := range candidates {
-		if banned[c.User] {
-			log.Debug().Str("user", c.User).Msg("rejecting approval by banned user")
-			continue
-		}
-
-		isApprover, err := r.Requires.Actors.IsActor(ctx, prctx, c.User)
+	// "last committer" is the user who added the most recent commit to the PR
+	// this applies even if the author or contributors are otherwise allowed
+	if r.Options.DisallowLastCommitterApproval {
+		commits, err := r.filteredCommits(ctx, prctx)
 		if err != nil {
-			return false, nil, errors.Wrap(err, "failed to check candidate status")
-		}
-		if !isApprover {
-			log.Debug().Str("user", c.User).Msg("ignoring approval by non-required user")
-			continue
+			return nil, err
 		}
 
-		approvers = append(approvers, c)
+		if len(commits) > 0 {
+			if committer := lastCommitter(commits[0]); committer != "" {
+				banned[committer] = true
+			}
+		}
 	}
 
-	log.Debug().Msgf("found %d/%d required approvers", len(approvers), r.Requires.Count)
-	return len(approvers) >= r.Requires.Count, approvers, nil
+	return banned, nil
 }
 
 func (r *Rule) isApprovedByConditions(ctx context.Context, prctx pull.Context) (bool, []*common.PredicateResult, error) {
@@ -304,7 +1130,7 @@ func (r *Rule) isApprovedByConditions(ctx context.Context, prctx pull.Context) (
 // FilteredCandidates returns the potential approval candidates and any
 // candidates that should be dimissed due to rule options.
 func (r *Rule) FilteredCandidates(ctx context.Context, prctx pull.Context) ([]*common.Candidate, []*common.Dismissal, error) {
-	if r.Requires.Count <= 0 {
+	if !r.Requires.requiresApprovals() {
 		return nil, nil, nil
 	}
 
@@ -331,9 +1157,33 @@ func (r *Rule) FilteredCandidates(ctx context.Context, prctx pull.Context) ([]*c
 		}
 	}
 
+	var reopenDismissals []*common.Dismissal
+	if r.Options.InvalidateOnReopen {
+		candidates, reopenDismissals, err = r.filterReopenedCandidates(ctx, prctx, candidates)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var forcePushDismissals []*common.Dismissal
+	if r.Options.InvalidateOnForcePush {
+		candidates, forcePushDismissals, err = r.filterForcePushedCandidates(ctx, prctx, candidates)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var expiredDismissals []*common.Dismissal
+	if r.Options.ExpireAfter > 0 {
+		candidates, expiredDismissals = r.filterExpiredCandidates(ctx, prctx, candidates)
+	}
+
 	var dismissals []*common.Dismissal
 	dismissals = append(dismissals, editDismissals...)
 	dismissals = append(dismissals, pushDismissals...)
+	dismissals = append(dismissals, reopenDismissals...)
+	dismissals = append(dismissals, forcePushDismissals...)
+	dismissals = append(dismissals, expiredDismissals...)
 
 	return candidates, dismissals, nil
 }
@@ -375,6 +1225,17 @@ func (r *Rule) filterInvalidCandidates(ctx context.Context, prctx pull.Context,
 	}
 
 	sha := commits[0].SHA
+	if len(r.Options.InvalidateOnPushPaths) > 0 {
+		sha, err = r.lastMatchingCommit(ctx, prctx, commits)
+		if err != nil {
+			return nil, nil, err
+		}
+		if sha == "" {
+			log.Debug().Msg("no commits change files that invalidate approvals")
+			return candidates, nil, nil
+		}
+	}
+
 	lastPushedAt, err := prctx.PushedAt(sha)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "failed to get last push timestamp")
@@ -389,6 +1250,7 @@ func (r *Rule) filterInvalidCandidates(ctx context.Context, prctx pull.Context,
 			dismissed = append(dismissed, &common.Dismissal{
 				Candidate: c,
 				Reason:    fmt.Sprintf("Invalidated by push of %.7s", sha),
+				Commit:    sha,
 			})
 		}
 	}
@@ -401,6 +1263,116 @@ func (r *Rule) filterInvalidCandidates(ctx context.Context, prctx pull.Context,
 	return allowed, dismissed, nil
 }
 
+// lastMatchingCommit returns the SHA of the most recent commit that changes a
+// file matching InvalidateOnPushPaths, or the empty string if no commits match.
+// The commits must be sorted from newest to oldest.
+func (r *Rule) lastMatchingCommit(ctx context.Context, prctx pull.Context, commits []*pull.Commit) (string, error) {
+	for _, c := range commits {
+		files, err := prctx.CommitFiles(c.SHA)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to get files of commit %s", c.SHA)
+		}
+		for _, f := range files {
+			for _, path := range r.Options.InvalidateOnPushPaths {
+				if path.Matches(f.Filename) {
+					return c.SHA, nil
+				}
+			}
+		}
+	}
+	return "", nil
+}
+
+func (r *Rule) filterReopenedCandidates(ctx context.Context, prctx pull.Context, candidates []*common.Candidate) ([]*common.Candidate, []*common.Dismissal, error) {
+	log := zerolog.Ctx(ctx)
+
+	reopenedAt, err := prctx.ReopenedAt()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get reopened time")
+	}
+	if reopenedAt.IsZero() {
+		return candidates, nil, nil
+	}
+
+	var allowed []*common.Candidate
+	var dismissed []*common.Dismissal
+	for _, c := range candidates {
+		if c.CreatedAt.After(reopenedAt) {
+			allowed = append(allowed, c)
+		} else {
+			dismissed = append(dismissed, &common.Dismissal{
+				Candidate: c,
+				Reason:    fmt.Sprintf("Invalidated by reopening the pull request at %s", reopenedAt.UTC().Format(time.RFC3339)),
+			})
+		}
+	}
+
+	log.Debug().Msgf(
+		"discarded %d candidates invalidated by reopening the pull request at %s",
+		len(dismissed), reopenedAt.Format(time.RFC3339),
+	)
+
+	return allowed, dismissed, nil
+}
+
+func (r *Rule) filterForcePushedCandidates(ctx context.Context, prctx pull.Context, candidates []*common.Candidate) ([]*common.Candidate, []*common.Dismissal, error) {
+	log := zerolog.Ctx(ctx)
+
+	forcePushedAt, err := prctx.ForcePushedAt()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get force-push time")
+	}
+	if forcePushedAt.IsZero() {
+		return candidates, nil, nil
+	}
+
+	var allowed []*common.Candidate
+	var dismissed []*common.Dismissal
+	for _, c := range candidates {
+		if c.CreatedAt.After(forcePushedAt) {
+			allowed = append(allowed, c)
+		} else {
+			dismissed = append(dismissed, &common.Dismissal{
+				Candidate: c,
+				Reason:    "Invalidated by force-push",
+			})
+		}
+	}
+
+	log.Debug().Msgf(
+		"discarded %d candidates invalidated by force-push at %s",
+		len(dismissed), forcePushedAt.Format(time.RFC3339),
+	)
+
+	return allowed, dismissed, nil
+}
+
+func (r *Rule) filterExpiredCandidates(ctx context.Context, prctx pull.Context, candidates []*common.Candidate) ([]*common.Candidate, []*common.Dismissal) {
+	log := zerolog.Ctx(ctx)
+
+	expiredBefore := prctx.EvaluationTimestamp().Add(-time.Duration(r.Options.ExpireAfter))
+
+	var allowed []*common.Candidate
+	var dismissed []*common.Dismissal
+	for _, c := range candidates {
+		if c.CreatedAt.After(expiredBefore) {
+			allowed = append(allowed, c)
+		} else {
+			dismissed = append(dismissed, &common.Dismissal{
+				Candidate: c,
+				Reason:    fmt.Sprintf("Approval expired after %s", r.Options.ExpireAfter),
+			})
+		}
+	}
+
+	log.Debug().Msgf(
+		"discarded %d candidates that expired on or before %s",
+		len(dismissed), expiredBefore.Format(time.RFC3339),
+	)
+
+	return allowed, dismissed
+}
+
 // filteredCommits returns the relevant commits for the evaluation ordered in
 // history order, from most to least recent.
 func (r *Rule) filteredCommits(ctx context.Context, prctx pull.Context) ([]*pull.Commit, error) {
@@ -412,15 +1384,16 @@ func (r *Rule) filteredCommits(ctx context.Context, prctx pull.Context) ([]*pull
 
 	ignoreUpdates := r.Options.IgnoreUpdateMerges
 	ignoreCommits := !r.Options.IgnoreCommitsBy.IsEmpty()
+	ignoreEmails := len(r.Options.IgnoreCommitsByEmail) > 0
 
-	if !ignoreUpdates && !ignoreCommits {
+	if !ignoreUpdates && !ignoreCommits && !ignoreEmails {
 		return commits, nil
 	}
 
 	var filtered []*pull.Commit
 	for _, c := range commits {
 		if ignoreUpdates {
-			if isUpdateMerge(commits, c) {
+			if pull.IsUpdateMerge(commits, c) {
 				continue
 			}
 		}
@@ -435,6 +1408,12 @@ func (r *Rule) filteredCommits(ctx context.Context, prctx pull.Context) ([]*pull
 			}
 		}
 
+		if ignoreEmails {
+			if isIgnoredCommitEmail(r.Options.IgnoreCommitsByEmail, c) {
+				continue
+			}
+		}
+
 		filtered = append(filtered, c)
 	}
 	return filtered, nil
@@ -443,23 +1422,28 @@ func (r *Rule) filteredCommits(ctx context.Context, prctx pull.Context) ([]*pull
 func statusDescription(approved bool, result common.RequiresResult, candidates []*common.Candidate) string {
 	hasActors := result.Count > 0
 	hasConditions := len(result.Conditions) > 0
+	hasTeams := len(result.TeamCounts) > 0
+	hasUsers := len(result.UserApprovals) > 0
 
 	if approved {
-		if !hasActors && !hasConditions {
+		if !hasActors && !hasConditions && !hasTeams && !hasUsers {
 			return "No approval required"
 		}
 
 		var desc strings.Builder
 		desc.WriteString("Approved by ")
 
-		for i, c := range result.Approvers {
+		for i, c := range allApprovers(result) {
 			if i > 0 {
 				desc.WriteString(", ")
 			}
 			desc.WriteString(c.User)
+			if team, ok := result.ApproverTeams[c.User]; ok {
+				fmt.Fprintf(&desc, " (%s)", team)
+			}
 		}
 		if hasConditions {
-			if hasActors {
+			if hasActors || hasTeams || hasUsers {
 				desc.WriteString(" and ")
 			}
 			desc.WriteString("required conditions")
@@ -471,9 +1455,27 @@ func statusDescription(approved bool, result common.RequiresResult, candidates [
 	var desc strings.Builder
 	if hasActors {
 		fmt.Fprintf(&desc, "%d/%d required approvals", len(result.Approvers), result.Count)
+		if len(result.CountLabels) > 0 {
+			fmt.Fprintf(&desc, " (required by labels %s)", strings.Join(result.CountLabels, ", "))
+		}
+		if result.CountSize != "" {
+			fmt.Fprintf(&desc, " (required by %s)", result.CountSize)
+		}
+	}
+	for i, t := range result.TeamCounts {
+		if hasActors || i > 0 {
+			desc.WriteString(", ")
+		}
+		fmt.Fprintf(&desc, "%d/%d required approvals from %s", len(t.Approvers), t.Count, t.Team)
+	}
+	if missing := missingUsers(result); len(missing) > 0 {
+		if desc.Len() > 0 {
+			desc.WriteString(", ")
+		}
+		fmt.Fprintf(&desc, "waiting for approval from %s", strings.Join(missing, ", "))
 	}
 	if hasConditions {
-		if hasActors {
+		if desc.Len() > 0 {
 			desc.WriteString(" and ")
 		}
 
@@ -485,31 +1487,70 @@ func statusDescription(approved bool, result common.RequiresResult, candidates [
 		}
 		fmt.Fprintf(&desc, "%d/%d required conditions", successful, len(result.Conditions))
 	}
-	if disqualified := len(candidates) - len(result.Approvers); hasActors && disqualified > 0 {
+	if result.RequiresIndependentApproval {
+		desc.WriteString(". An approval from a user who did not contribute commits is required")
+	}
+	if pooled := len(result.PooledApprovers); hasActors && pooled > 0 {
+		fmt.Fprintf(&desc, ". Ignored %s counted by other rules in the same approval pool", numberOfApprovals(pooled))
+	}
+	if excess := len(result.ExcessApprovers); hasActors && excess > 0 {
+		fmt.Fprintf(&desc, ". Ignored %s exceeding the limit per organization", numberOfApprovals(excess))
+	}
+	if sameTeam := len(result.SameTeamApprovers); hasActors && sameTeam > 0 {
+		fmt.Fprintf(&desc, ". Ignored %s from teams that already approved", numberOfApprovals(sameTeam))
+	}
+	if authorTeam := len(result.AuthorTeamApprovers); hasActors && authorTeam > 0 {
+		fmt.Fprintf(&desc, ". Ignored %s from members of the author's teams", numberOfApprovals(authorTeam))
+	}
+	if lowPermission := len(result.LowPermissionApprovers); hasActors && lowPermission > 0 {
+		fmt.Fprintf(&desc, ". Ignored %s from users without the required permission", numberOfApprovals(lowPermission))
+	}
+	if disqualified := len(candidates) - len(result.Approvers) - len(result.PooledApprovers) - len(result.ExcessApprovers) - len(result.SameTeamApprovers) - len(result.AuthorTeamApprovers) - len(result.LowPermissionApprovers); hasActors && disqualified > 0 {
 		fmt.Fprintf(&desc, ". Ignored %s from disqualified users", numberOfApprovals(disqualified))
 	}
 	return desc.String()
 }
 
-func isUpdateMerge(commits []*pull.Commit, c *pull.Commit) bool {
-	// must be a simple merge commit (exactly 2 parents)
-	if len(c.Parents) != 2 {
-		return false
+// allApprovers returns the approvers that satisfy the actor and team
+// requirements of a result. Each user appears at most once, in the order they
+// are first found.
+func allApprovers(result common.RequiresResult) []*common.Candidate {
+	if len(result.TeamCounts) == 0 && len(result.UserApprovals) == 0 {
+		return result.Approvers
 	}
 
-	// must be created via the UI or the API (no local merges)
-	if !c.CommittedViaWeb {
-		return false
+	var approvers []*common.Candidate
+	seen := make(map[string]bool)
+	for _, c := range result.Approvers {
+		seen[c.User] = true
+		approvers = append(approvers, c)
 	}
-
-	shas := make(map[string]bool)
-	for _, c := range commits {
-		shas[c.SHA] = true
+	for _, t := range result.TeamCounts {
+		for _, c := range t.Approvers {
+			if !seen[c.User] {
+				seen[c.User] = true
+				approvers = append(approvers, c)
+			}
+		}
 	}
+	for _, u := range result.UserApprovals {
+		if c := u.Approval; c != nil && !seen[c.User] {
+			seen[c.User] = true
+			approvers = append(approvers, c)
+		}
+	}
+	return approvers
+}
 
-	// first parent must exist: it is a commit on the head branch
-	// second parent must not exist: it is already in the base branch
-	return shas[c.Parents[0]] && !shas[c.Parents[1]]
+// missingUsers returns the users who must individually approve but have not.
+func missingUsers(result common.RequiresResult) []string {
+	var missing []string
+	for _, u := range result.UserApprovals {
+		if u.Approval == nil {
+			missing = append(missing, u.User)
+		}
+	}
+	return missing
 }
 
 func isIgnoredCommit(ctx context.Context, prctx pull.Context, actors *common.Actors, c *pull.Commit) (bool, error) {
@@ -526,6 +1567,23 @@ func isIgnoredCommit(ctx context.Context, prctx pull.Context, actors *common.Act
 	return len(c.Users()) > 0, nil
 }
 
+// isIgnoredCommitEmail returns true if both the author and the committer email
+// of the commit match any of the patterns.
+func isIgnoredCommitEmail(patterns []common.Regexp, c *pull.Commit) bool {
+	matches := func(email string) bool {
+		if email == "" {
+			return false
+		}
+		for _, p := range patterns {
+			if p.Matches(email) {
+				return true
+			}
+		}
+		return false
+	}
+	return matches(c.AuthorEmail) && matches(c.CommitterEmail)
+}
+
 func numberOfApprovals(count int) string {
 	if count == 1 {
 		return "1 approval"
@@ -533,6 +1591,15 @@ func numberOfApprovals(count int) string {
 	return fmt.Sprintf("%d approvals", count)
 }
 
+// lastCommitter returns the user who pushed a commit, falling back to the
+// author if the committer is not associated with a GitHub user.
+func lastCommitter(c *pull.Commit) string {
+	if c.Committer != "" {
+		return c.Committer
+	}
+	return c.Author
+}
+
 // sortCommits orders commits in history order starting from head. It must be
 // called on the unfiltered set of commits.
 func sortCommits(commits []*pull.Commit, head string) []*pull.Commit {
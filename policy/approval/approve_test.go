@@ -17,15 +17,19 @@ package approval
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/palantir/go-baseapp/baseapp"
 	"github.com/palantir/policy-bot/policy/common"
 	"github.com/palantir/policy-bot/policy/predicate"
 	"github.com/palantir/policy-bot/pull"
 	"github.com/palantir/policy-bot/pull/pulltest"
+	"github.com/rcrowley/go-metrics"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -139,6 +143,11 @@ func TestIsApproved(t *testing.T) {
 				"comment-approver":      {"everyone", "cool-org"},
 				"review-approver":       {"everyone", "even-cooler-org"},
 			},
+			TeamMemberships: map[string][]string{
+				"contributor-author": {"everyone/platform"},
+				"comment-approver":   {"everyone/security"},
+				"review-approver":    {"everyone/platform"},
+			},
 			LatestStatusesValue: map[string]string{
 				"build":  "success",
 				"deploy": "pending",
@@ -309,6 +318,56 @@ func TestIsApproved(t *testing.T) {
 		assertApproved(t, prctx, r, "Approved by comment-approver, mhaypenny, contributor-author, contributor-committer, review-approver")
 	})
 
+	t.Run("lastCommitterCannotApprove", func(t *testing.T) {
+		prctx := basePullContext()
+		r := &Rule{
+			Options: Options{
+				AllowContributor:              true,
+				DisallowLastCommitterApproval: true,
+			},
+			Requires: Requires{
+				Count: 1,
+				Actors: common.Actors{
+					Organizations: []string{"everyone"},
+				},
+			},
+		}
+		assertApproved(t, prctx, r, "Approved by comment-approver, mhaypenny, contributor-author, review-approver")
+
+		r = &Rule{
+			Options: Options{
+				AllowContributor:              true,
+				DisallowLastCommitterApproval: true,
+			},
+			Requires: Requires{
+				Count: 1,
+				Actors: common.Actors{
+					Users: []string{"contributor-committer"},
+				},
+			},
+		}
+		assertPending(t, prctx, r, "0/1 required approvals. Ignored 7 approvals from disqualified users")
+	})
+
+	t.Run("lastCommitterFallsBackToAuthor", func(t *testing.T) {
+		prctx := basePullContext()
+		prctx.CommitsValue[2].Committer = ""
+
+		r := &Rule{
+			Options: Options{
+				AllowAuthor:                   true,
+				DisallowLastCommitterApproval: true,
+			},
+			Requires: Requires{
+				Count: 1,
+				Actors: common.Actors{
+					Organizations: []string{"everyone"},
+				},
+			},
+		}
+		assertApproved(t, prctx, r, "Approved by comment-approver, contributor-committer, review-approver")
+	})
+
 	t.Run("specificUserApproves", func(t *testing.T) {
 		prctx := basePullContext()
 		r := &Rule{
@@ -425,6 +484,252 @@ func TestIsApproved(t *testing.T) {
 		assertPending(t, prctx, r, "0/1 required approvals. Ignored 1 approval from disqualified users")
 	})
 
+	t.Run("invalidateOnPushPaths", func(t *testing.T) {
+		prctx := basePullContext()
+		prctx.PushedAtValue = map[string]time.Time{
+			"c6ade256ecfc755d8bc877ef22cc9e01745d46bb": now.Add(5 * time.Second),
+			"674832587eaaf416371b30f5bc5a47e377f534ec": now.Add(25 * time.Second),
+			"97d5ea26da319a987d80f6db0b7ef759f2f2e441": now.Add(35 * time.Second),
+		}
+		prctx.CommitFilesValue = map[string][]*pull.File{
+			"c6ade256ecfc755d8bc877ef22cc9e01745d46bb": {
+				{Filename: "src/main.go", Status: pull.FileModified},
+			},
+			"674832587eaaf416371b30f5bc5a47e377f534ec": {
+				{Filename: "docs/usage.md", Status: pull.FileModified},
+			},
+			"97d5ea26da319a987d80f6db0b7ef759f2f2e441": {
+				{Filename: "README.md", Status: pull.FileModified},
+			},
+		}
+
+		r := &Rule{
+			Options: Options{
+				InvalidateOnPush: true,
+			},
+			Requires: Requires{
+				Count: 1,
+				Actors: common.Actors{
+					Users: []string{"comment-approver"},
+				},
+			},
+		}
+		assertPending(t, prctx, r, "0/1 required approvals. Ignored 6 approvals from disqualified users")
+
+		// documentation pushes after the approval do not invalidate it
+		r.Options.InvalidateOnPushPaths = []common.Regexp{common.NewCompiledRegexp(regexp.MustCompile("^src/"))}
+		assertApproved(t, prctx, r, "Approved by comment-approver")
+
+		// a code push after the approval invalidates it
+		prctx.CommitFilesValue["97d5ea26da319a987d80f6db0b7ef759f2f2e441"] = []*pull.File{
+			{Filename: "README.md", Status: pull.FileModified},
+			{Filename: "src/app.go", Status: pull.FileAdded},
+		}
+		assertPending(t, prctx, r, "0/1 required approvals. Ignored 6 approvals from disqualified users")
+
+		_, dismissals, err := r.FilteredCandidates(ctx, prctx)
+		require.NoError(t, err)
+		require.Len(t, dismissals, 1)
+		assert.Equal(t, "Invalidated by push of 97d5ea2", dismissals[0].Reason)
+		assert.Equal(t, "97d5ea26da319a987d80f6db0b7ef759f2f2e441", dismissals[0].Commit)
+	})
+
+	t.Run("invalidateOnPushPathsNoMatches", func(t *testing.T) {
+		prctx := basePullContext()
+		prctx.PushedAtValue = map[string]time.Time{
+			"97d5ea26da319a987d80f6db0b7ef759f2f2e441": now.Add(85 * time.Second),
+		}
+		prctx.CommitFilesValue = map[string][]*pull.File{
+			"97d5ea26da319a987d80f6db0b7ef759f2f2e441": {
+				{Filename: "README.md", Status: pull.FileModified},
+			},
+		}
+
+		r := &Rule{
+			Options: Options{
+				InvalidateOnPush:      true,
+				InvalidateOnPushPaths: []common.Regexp{common.NewCompiledRegexp(regexp.MustCompile("^src/"))},
+			},
+			Requires: Requires{
+				Count: 2,
+				Actors: common.Actors{
+					Users: []string{"comment-approver", "review-approver"},
+				},
+			},
+		}
+		assertApproved(t, prctx, r, "Approved by comment-approver, review-approver")
+	})
+
+	t.Run("invalidateOnReopen", func(t *testing.T) {
+		prctx := basePullContext()
+		prctx.ReopenedAtValue = now.Add(75 * time.Second)
+
+		r := &Rule{
+			Requires: Requires{
+				Count: 2,
+				Actors: common.Actors{
+					Users: []string{"comment-approver", "review-approver"},
+				},
+			},
+		}
+		assertApproved(t, prctx, r, "Approved by comment-approver, review-approver")
+
+		r.Options.InvalidateOnReopen = true
+		assertPending(t, prctx, r, "1/2 required approvals. Ignored 1 approval from disqualified users")
+
+		_, dismissals, err := r.FilteredCandidates(ctx, prctx)
+		require.NoError(t, err)
+
+		reopenedAt := now.Add(75 * time.Second).UTC().Format(time.RFC3339)
+		if assert.NotEmpty(t, dismissals) {
+			assert.Equal(t, "comment-approver", dismissals[0].Candidate.User)
+			assert.Equal(t, "Invalidated by reopening the pull request at "+reopenedAt, dismissals[0].Reason)
+		}
+
+		prctx.ReopenedAtValue = time.Time{}
+		assertApproved(t, prctx, r, "Approved by comment-approver, review-approver")
+	})
+
+	t.Run("invalidateOnForcePush", func(t *testing.T) {
+		prctx := basePullContext()
+
+		// a normal push adds commits without a force-push event
+		prctx.PushedAtValue = map[string]time.Time{
+			"97d5ea26da319a987d80f6db0b7ef759f2f2e441": now.Add(75 * time.Second),
+		}
+
+		r := &Rule{
+			Options: Options{
+				InvalidateOnForcePush: true,
+			},
+			Requires: Requires{
+				Count: 2,
+				Actors: common.Actors{
+					Users: []string{"comment-approver", "review-approver"},
+				},
+			},
+		}
+		assertApproved(t, prctx, r, "Approved by comment-approver, review-approver")
+
+		// a rebase force-pushes the branch after the comment approval
+		prctx.ForcePushedAtValue = now.Add(75 * time.Second)
+		assertPending(t, prctx, r, "1/2 required approvals. Ignored 1 approval from disqualified users")
+
+		_, dismissals, err := r.FilteredCandidates(ctx, prctx)
+		require.NoError(t, err)
+
+		if assert.NotEmpty(t, dismissals) {
+			assert.Equal(t, "comment-approver", dismissals[0].Candidate.User)
+			assert.Equal(t, "Invalidated by force-push", dismissals[0].Reason)
+		}
+
+		r.Options.InvalidateOnForcePush = false
+		assertApproved(t, prctx, r, "Approved by comment-approver, review-approver")
+	})
+
+	t.Run("expireCommentApproval", func(t *testing.T) {
+		prctx := basePullContext()
+		prctx.EvaluationTimestampValue = now.Add(7*24*time.Hour + 45*time.Second)
+
+		r := &Rule{
+			Requires: Requires{
+				Count: 1,
+				Actors: common.Actors{
+					Users: []string{"comment-approver"},
+				},
+			},
+		}
+		assertApproved(t, prctx, r, "Approved by comment-approver")
+
+		r.Options.ExpireAfter = predicate.Duration(7 * 24 * time.Hour)
+		assertPending(t, prctx, r, "0/1 required approvals. Ignored 5 approvals from disqualified users")
+
+		_, dismissals, err := r.FilteredCandidates(ctx, prctx)
+		require.NoError(t, err)
+
+		if assert.Len(t, dismissals, 2) {
+			assert.Equal(t, "comment-approver", dismissals[0].Candidate.User)
+			assert.Equal(t, "Approval expired after 7d", dismissals[0].Reason)
+		}
+	})
+
+	t.Run("expireReviewApproval", func(t *testing.T) {
+		prctx := basePullContext()
+		prctx.EvaluationTimestampValue = now.Add(2*time.Hour + 85*time.Second)
+
+		r := &Rule{
+			Requires: Requires{
+				Count: 1,
+				Actors: common.Actors{
+					Users: []string{"review-approver"},
+				},
+			},
+		}
+		assertApproved(t, prctx, r, "Approved by review-approver")
+
+		r.Options.ExpireAfter = predicate.Duration(2 * time.Hour)
+		assertPending(t, prctx, r, "0/1 required approvals. Ignored 1 approval from disqualified users")
+
+		_, dismissals, err := r.FilteredCandidates(ctx, prctx)
+		require.NoError(t, err)
+
+		if assert.NotEmpty(t, dismissals) {
+			last := dismissals[len(dismissals)-1]
+			assert.Equal(t, "review-approver", last.Candidate.User)
+			assert.Equal(t, common.ReviewCandidate, last.Candidate.Type)
+			assert.Equal(t, "Approval expired after 2h", last.Reason)
+		}
+	})
+
+	t.Run("expireAndInvalidateOnPush", func(t *testing.T) {
+		prctx := basePullContext()
+		prctx.EvaluationTimestampValue = now.Add(time.Hour + 75*time.Second)
+		prctx.PushedAtValue = map[string]time.Time{
+			"c6ade256ecfc755d8bc877ef22cc9e01745d46bb": now.Add(25 * time.Second),
+		}
+		prctx.HeadSHAValue = "c6ade256ecfc755d8bc877ef22cc9e01745d46bb"
+		prctx.CommitsValue = []*pull.Commit{
+			{
+				SHA:       "c6ade256ecfc755d8bc877ef22cc9e01745d46bb",
+				Author:    "mhaypenny",
+				Committer: "mhaypenny",
+			},
+		}
+
+		r := &Rule{
+			Requires: Requires{
+				Count: 1,
+				Actors: common.Actors{
+					Users: []string{"comment-approver", "review-approver"},
+				},
+			},
+			Options: Options{
+				InvalidateOnPush: true,
+				ExpireAfter:      predicate.Duration(time.Hour),
+			},
+		}
+		assertApproved(t, prctx, r, "Approved by review-approver")
+
+		candidates, dismissals, err := r.FilteredCandidates(ctx, prctx)
+		require.NoError(t, err)
+
+		reasons := make(map[string]string)
+		for _, d := range dismissals {
+			reasons[d.Candidate.User] = d.Reason
+		}
+		assert.Equal(t, "Invalidated by push of c6ade25", reasons["comment-approver"])
+		assert.Equal(t, "Approval expired after 1h", reasons["mhaypenny"])
+
+		var users []string
+		for _, c := range candidates {
+			users = append(users, c.User)
+		}
+		assert.Equal(t, []string{"review-approver", "review-comment-editor"}, users)
+
+		r.Options.ExpireAfter = predicate.Duration(30 * time.Minute)
+		assertPending(t, prctx, r, "0/1 required approvals")
+	})
+
 	t.Run("ignoreUpdateMergeAfterReview", func(t *testing.T) {
 		prctx := basePullContext()
 		prctx.PushedAtValue = map[string]time.Time{
@@ -566,6 +871,43 @@ func TestIsApproved(t *testing.T) {
 		assertApproved(t, prctx, r, "Approved by comment-approver")
 	})
 
+	t.Run("ignoreCommitsByEmailInvalidateOnPush", func(t *testing.T) {
+		prctx := basePullContext()
+		prctx.PushedAtValue = map[string]time.Time{
+			"c6ade256ecfc755d8bc877ef22cc9e01745d46bb": now.Add(25 * time.Second),
+		}
+		prctx.HeadSHAValue = "c6ade256ecfc755d8bc877ef22cc9e01745d46bb"
+		prctx.CommitsValue = []*pull.Commit{
+			{
+				SHA:            "c6ade256ecfc755d8bc877ef22cc9e01745d46bb",
+				AuthorEmail:    "release@automation.example.com",
+				CommitterEmail: "release@automation.example.com",
+			},
+		}
+
+		r := &Rule{
+			Requires: Requires{
+				Count: 1,
+				Actors: common.Actors{
+					Users: []string{"comment-approver"},
+				},
+			},
+			Options: Options{
+				InvalidateOnPush: true,
+			},
+		}
+		assertPending(t, prctx, r, "0/1 required approvals. Ignored 6 approvals from disqualified users")
+
+		r.Options.IgnoreCommitsByEmail = []common.Regexp{
+			common.NewCompiledRegexp(regexp.MustCompile(`@automation\.example\.com$`)),
+		}
+		assertApproved(t, prctx, r, "Approved by comment-approver")
+
+		// both emails must match to ignore the commit
+		prctx.CommitsValue[0].CommitterEmail = "mhaypenny@example.com"
+		assertPending(t, prctx, r, "0/1 required approvals. Ignored 6 approvals from disqualified users")
+	})
+
 	t.Run("ignoreCommitsInvalidateOnPushBatches", func(t *testing.T) {
 		prctx := basePullContext()
 		prctx.PushedAtValue = map[string]time.Time{
@@ -729,26 +1071,864 @@ func TestIsApproved(t *testing.T) {
 		}
 		assertApproved(t, prctx, r, "Approved by comment-approver, review-approver and required conditions")
 	})
-}
-
-func TestTrigger(t *testing.T) {
-	t.Run("triggerCommitOnRules", func(t *testing.T) {
-		r := &Rule{}
 
-		assert.True(t, r.Trigger().Matches(common.TriggerCommit), "expected %s to match %", r.Trigger(), common.TriggerCommit)
+	t.Run("teamCountsPartiallySatisfied", func(t *testing.T) {
+		prctx := basePullContext()
+		r := &Rule{
+			Requires: Requires{
+				TeamCounts: map[string]int{
+					"everyone/security": 1,
+					"everyone/platform": 2,
+				},
+			},
+		}
+		assertPending(t, prctx, r, "1/2 required approvals from everyone/platform, 1/1 required approvals from everyone/security")
 	})
 
-	t.Run("triggerCommentOnComments", func(t *testing.T) {
+	t.Run("teamCountsSatisfied", func(t *testing.T) {
+		prctx := basePullContext()
 		r := &Rule{
-			Options: Options{
-				Methods: &common.Methods{
-					Comments: []string{
-						"lgtm",
-					},
+			Requires: Requires{
+				TeamCounts: map[string]int{
+					"everyone/security": 1,
+					"everyone/platform": 1,
 				},
 			},
+		}
+		assertApproved(t, prctx, r, "Approved by review-approver, comment-approver")
+	})
+
+	t.Run("teamCountsIgnoreBannedUsers", func(t *testing.T) {
+		prctx := basePullContext()
+		r := &Rule{
 			Requires: Requires{
-				Count: 1,
+				TeamCounts: map[string]int{
+					"everyone/platform": 2,
+				},
+			},
+		}
+		assertPending(t, prctx, r, "1/2 required approvals from everyone/platform")
+
+		r.Options.AllowContributor = true
+		assertApproved(t, prctx, r, "Approved by contributor-author, review-approver")
+	})
+
+	t.Run("teamCountsAndActorsRequired", func(t *testing.T) {
+		prctx := basePullContext()
+		r := &Rule{
+			Requires: Requires{
+				Count: 1,
+				Actors: common.Actors{
+					Users: []string{"does-not-exist"},
+				},
+				TeamCounts: map[string]int{
+					"everyone/security": 1,
+				},
+			},
+		}
+		assertPending(t, prctx, r, "0/1 required approvals, 1/1 required approvals from everyone/security. Ignored 7 approvals from disqualified users")
+
+		r.Requires.Actors.Users = []string{"review-approver"}
+		assertApproved(t, prctx, r, "Approved by review-approver, comment-approver")
+	})
+
+	t.Run("codeOwnerTeamsOverlappingOwnership", func(t *testing.T) {
+		prctx := basePullContext()
+		prctx.BaseFilesValue = map[string]string{
+			".github/CODEOWNERS": strings.Join([]string{
+				"*          @everyone/platform",
+				"/server/   @everyone/platform @everyone/security",
+				"*.md       @everyone/docs mhaypenny",
+			}, "\n"),
+		}
+		prctx.ChangedFilesValue = []*pull.File{
+			{Filename: "server/handler.go"},
+			{Filename: "policy/policy.go"},
+		}
+		r := &Rule{
+			Requires: Requires{
+				CodeOwnerTeams: true,
+			},
+		}
+		assertApproved(t, prctx, r, "Approved by review-approver, comment-approver")
+
+		prctx.ChangedFilesValue = append(prctx.ChangedFilesValue, &pull.File{Filename: "README.md"})
+		assertPending(t, prctx, r, "0/1 required approvals from everyone/docs, 1/1 required approvals from everyone/platform, 1/1 required approvals from everyone/security")
+	})
+
+	t.Run("codeOwnerTeamsWithTeamCounts", func(t *testing.T) {
+		prctx := basePullContext()
+		prctx.BaseFilesValue = map[string]string{
+			"CODEOWNERS": "* @everyone/platform",
+		}
+		prctx.ChangedFilesValue = []*pull.File{
+			{Filename: "server/handler.go"},
+		}
+		r := &Rule{
+			Requires: Requires{
+				TeamCounts: map[string]int{
+					"everyone/platform": 2,
+				},
+				CodeOwnerTeams: true,
+			},
+		}
+		assertPending(t, prctx, r, "1/2 required approvals from everyone/platform")
+
+		allowedCandidates, _, err := r.FilteredCandidates(ctx, prctx)
+		require.NoError(t, err)
+
+		_, result, err := r.IsApproved(ctx, prctx, allowedCandidates)
+		require.NoError(t, err)
+		require.Len(t, result.TeamCounts, 1)
+		assert.Equal(t, []string{"server/handler.go"}, result.TeamCounts[0].OwnedPaths)
+	})
+
+	t.Run("codeOwnerTeamsWithoutCodeOwners", func(t *testing.T) {
+		prctx := basePullContext()
+		prctx.ChangedFilesValue = []*pull.File{
+			{Filename: "server/handler.go"},
+		}
+		r := &Rule{
+			Requires: Requires{
+				CodeOwnerTeams: true,
+			},
+		}
+		assertApproved(t, prctx, r, "No approval required")
+	})
+
+	t.Run("allUsersApprove", func(t *testing.T) {
+		prctx := basePullContext()
+		r := &Rule{
+			Requires: Requires{
+				AllUsers: []string{"review-approver", "comment-approver"},
+			},
+		}
+		assertApproved(t, prctx, r, "Approved by review-approver, comment-approver")
+	})
+
+	t.Run("allUsersPartiallyApprove", func(t *testing.T) {
+		prctx := basePullContext()
+		r := &Rule{
+			Requires: Requires{
+				AllUsers: []string{"comment-approver", "does-not-exist", "other-user"},
+			},
+		}
+		assertPending(t, prctx, r, "waiting for approval from does-not-exist, other-user")
+	})
+
+	t.Run("allUsersExcludesAuthor", func(t *testing.T) {
+		prctx := basePullContext()
+		r := &Rule{
+			Requires: Requires{
+				AllUsers: []string{"mhaypenny", "comment-approver"},
+			},
+		}
+		assertApproved(t, prctx, r, "Approved by comment-approver")
+	})
+
+	t.Run("allUsersIgnoreBannedUsers", func(t *testing.T) {
+		prctx := basePullContext()
+		r := &Rule{
+			Requires: Requires{
+				AllUsers: []string{"contributor-author", "comment-approver"},
+			},
+		}
+		assertPending(t, prctx, r, "waiting for approval from contributor-author")
+	})
+
+	t.Run("allUsersAndCountRequired", func(t *testing.T) {
+		prctx := basePullContext()
+		r := &Rule{
+			Requires: Requires{
+				Count: 1,
+				Actors: common.Actors{
+					Organizations: []string{"even-cooler-org"},
+				},
+				AllUsers: []string{"comment-approver", "does-not-exist"},
+			},
+		}
+		assertPending(t, prctx, r, "1/1 required approvals, waiting for approval from does-not-exist. Ignored 6 approvals from disqualified users")
+
+		r.Requires.AllUsers = []string{"comment-approver"}
+		assertApproved(t, prctx, r, "Approved by review-approver, comment-approver")
+	})
+
+	t.Run("maxPerOrgExceeded", func(t *testing.T) {
+		prctx := basePullContext()
+		r := &Rule{
+			Requires: Requires{
+				Count: 2,
+				Actors: common.Actors{
+					Organizations: []string{"everyone"},
+				},
+				MaxPerOrg: 1,
+			},
+		}
+		assertPending(t, prctx, r, "1/2 required approvals. Ignored 1 approval exceeding the limit per organization. Ignored 5 approvals from disqualified users")
+
+		r.Requires.MaxPerOrg = 2
+		assertApproved(t, prctx, r, "Approved by comment-approver, review-approver")
+	})
+
+	t.Run("maxPerOrgAssignsUsersInMultipleOrgs", func(t *testing.T) {
+		prctx := basePullContext()
+		r := &Rule{
+			Requires: Requires{
+				Count: 2,
+				Actors: common.Actors{
+					Organizations: []string{"everyone", "cool-org"},
+				},
+				MaxPerOrg: 1,
+			},
+		}
+		assertApproved(t, prctx, r, "Approved by comment-approver, review-approver")
+	})
+
+	t.Run("distinctTeamsOverlappingMemberships", func(t *testing.T) {
+		prctx := basePullContext()
+		prctx.TeamMemberships = map[string][]string{
+			"comment-approver": {"everyone/platform", "everyone/security"},
+			"review-approver":  {"everyone/platform"},
+		}
+		r := &Rule{
+			Requires: Requires{
+				Count: 2,
+				Actors: common.Actors{
+					Teams: []string{"everyone/platform", "everyone/security"},
+				},
+				RequireDistinctTeams: true,
+			},
+		}
+		assertApproved(t, prctx, r, "Approved by comment-approver (everyone/security), review-approver (everyone/platform)")
+	})
+
+	t.Run("distinctTeamsSameTeam", func(t *testing.T) {
+		prctx := basePullContext()
+		prctx.TeamMemberships = map[string][]string{
+			"comment-approver": {"everyone/platform"},
+			"review-approver":  {"everyone/platform"},
+		}
+		r := &Rule{
+			Requires: Requires{
+				Count: 2,
+				Actors: common.Actors{
+					Teams: []string{"everyone/platform", "everyone/security"},
+				},
+				RequireDistinctTeams: true,
+			},
+		}
+		assertPending(t, prctx, r, "1/2 required approvals. Ignored 1 approval from teams that already approved. Ignored 5 approvals from disqualified users")
+
+		r.Requires.RequireDistinctTeams = false
+		assertApproved(t, prctx, r, "Approved by comment-approver, review-approver")
+	})
+
+	t.Run("distinctTeamsIgnoresUsersInNoTeam", func(t *testing.T) {
+		prctx := basePullContext()
+		prctx.TeamMemberships = map[string][]string{
+			"comment-approver": {"everyone/platform"},
+		}
+		r := &Rule{
+			Requires: Requires{
+				Count: 2,
+				Actors: common.Actors{
+					Users: []string{"review-approver"},
+					Teams: []string{"everyone/platform"},
+				},
+				RequireDistinctTeams: true,
+			},
+		}
+		assertApproved(t, prctx, r, "Approved by comment-approver (everyone/platform), review-approver")
+	})
+
+	t.Run("authorTeamSharedTeam", func(t *testing.T) {
+		prctx := basePullContext()
+		prctx.OwnerValue = "everyone"
+		prctx.TeamMemberships = map[string][]string{
+			"mhaypenny":        {"everyone/platform", "everyone/security"},
+			"comment-approver": {"everyone/security"},
+			"review-approver":  {"everyone/infra", "other-org/platform"},
+		}
+		r := &Rule{
+			Options: Options{
+				DisallowAuthorTeamApproval: true,
+			},
+			Requires: Requires{
+				Count: 2,
+				Actors: common.Actors{
+					Users: []string{"comment-approver", "review-approver"},
+				},
+			},
+		}
+		assertPending(t, prctx, r, "1/2 required approvals. Ignored 1 approval from members of the author's teams. Ignored 5 approvals from disqualified users")
+
+		r.Options.DisallowAuthorTeamApproval = false
+		assertApproved(t, prctx, r, "Approved by comment-approver, review-approver")
+	})
+
+	t.Run("authorTeamAllowsAuthor", func(t *testing.T) {
+		prctx := basePullContext()
+		prctx.OwnerValue = "everyone"
+		prctx.TeamMemberships = map[string][]string{
+			"mhaypenny":       {"everyone/platform"},
+			"review-approver": {"everyone/platform"},
+		}
+		prctx.CommentsValue = append(prctx.CommentsValue, &pull.Comment{
+			CreatedAt: now.Add(100 * time.Second),
+			Author:    "mhaypenny",
+			Body:      ":+1:",
+		})
+		r := &Rule{
+			Options: Options{
+				AllowAuthor:                true,
+				DisallowAuthorTeamApproval: true,
+			},
+			Requires: Requires{
+				Count: 1,
+				Actors: common.Actors{
+					Users: []string{"mhaypenny", "review-approver"},
+				},
+			},
+		}
+		assertApproved(t, prctx, r, "Approved by mhaypenny")
+	})
+
+	t.Run("authorTeamNoTeams", func(t *testing.T) {
+		prctx := basePullContext()
+		prctx.OwnerValue = "everyone"
+		prctx.TeamMemberships = map[string][]string{
+			"comment-approver": {"everyone/platform"},
+			"review-approver":  {"everyone/platform"},
+		}
+		r := &Rule{
+			Options: Options{
+				DisallowAuthorTeamApproval: true,
+			},
+			Requires: Requires{
+				Count: 2,
+				Actors: common.Actors{
+					Users: []string{"comment-approver", "review-approver"},
+				},
+			},
+		}
+		assertApproved(t, prctx, r, "Approved by comment-approver, review-approver")
+	})
+
+	t.Run("authorTeamError", func(t *testing.T) {
+		prctx := basePullContext()
+		prctx.TeamMembershipError = errors.New("membership failure")
+		r := &Rule{
+			Options: Options{
+				DisallowAuthorTeamApproval: true,
+			},
+			Requires: Requires{
+				Count: 1,
+				Actors: common.Actors{
+					Users: []string{"comment-approver"},
+				},
+			},
+		}
+		_, _, err := r.IsApproved(ctx, prctx, nil)
+		require.NoError(t, err, "no approvers should skip the team lookup")
+
+		candidates, _, err := r.FilteredCandidates(ctx, prctx)
+		require.NoError(t, err)
+		_, _, err = r.IsApproved(ctx, prctx, candidates)
+		require.Error(t, err)
+	})
+
+	t.Run("botApprovals", func(t *testing.T) {
+		prctx := basePullContext()
+		prctx.CommentsValue = append(prctx.CommentsValue, &pull.Comment{
+			CreatedAt: now.Add(100 * time.Second),
+			Author:    "dependabot[bot]",
+			Body:      ":+1:",
+		})
+
+		r := &Rule{
+			Requires: Requires{
+				Count: 2,
+				Actors: common.Actors{
+					Users: []string{"comment-approver", "dependabot[bot]"},
+				},
+			},
+		}
+		assertPending(t, prctx, r, "1/2 required approvals. Ignored 7 approvals from disqualified users")
+
+		r.Requires.Actors.Bots = []string{"dependabot"}
+		assertApproved(t, prctx, r, "Approved by comment-approver, dependabot[bot]")
+	})
+
+	t.Run("labelCounts", func(t *testing.T) {
+		r := &Rule{
+			Requires: Requires{
+				Count: 1,
+				Actors: common.Actors{
+					Users: []string{"comment-approver", "review-approver"},
+				},
+				LabelCounts: []LabelCount{
+					{Labels: []string{"risk:high"}, Count: 3},
+					{Labels: []string{"Risk:Medium"}, Count: 2},
+					{Labels: []string{"risk:medium", "security"}, Count: 3},
+					{Labels: []string{"trivial"}, Count: 0},
+				},
+			},
+		}
+
+		prctx := basePullContext()
+		assertApproved(t, prctx, r, "Approved by comment-approver, review-approver")
+
+		prctx.LabelsValue = []string{"trivial"}
+		assertApproved(t, prctx, r, "Approved by comment-approver, review-approver")
+
+		prctx.LabelsValue = []string{"risk:medium"}
+		assertApproved(t, prctx, r, "Approved by comment-approver, review-approver")
+
+		prctx.LabelsValue = []string{"risk:high"}
+		assertPending(t, prctx, r, "2/3 required approvals (required by labels risk:high). Ignored 5 approvals from disqualified users")
+
+		prctx.LabelsValue = []string{"risk:medium", "security"}
+		assertPending(t, prctx, r, "2/3 required approvals (required by labels risk:medium, security). Ignored 5 approvals from disqualified users")
+	})
+
+	t.Run("sizeCounts", func(t *testing.T) {
+		r := &Rule{
+			Requires: Requires{
+				Count: 1,
+				Actors: common.Actors{
+					Users: []string{"comment-approver", "review-approver"},
+				},
+				SizeCounts: []SizeCount{
+					{ModifiedLines: predicate.ModifiedLines{Total: predicate.ComparisonExpr{Op: predicate.OpGreaterThan, Value: 500}}, Count: 3},
+					{ModifiedLines: predicate.ModifiedLines{Total: predicate.ComparisonExpr{Op: predicate.OpGreaterThan, Value: 100}}, Count: 2},
+				},
+			},
+		}
+
+		withSize := func(additions, deletions int) *pulltest.Context {
+			prctx := basePullContext()
+			prctx.ChangedFilesValue = []*pull.File{
+				{Filename: "app.go", Status: pull.FileModified, Additions: additions, Deletions: deletions},
+			}
+			return prctx
+		}
+
+		assertApproved(t, withSize(10, 5), r, "Approved by comment-approver, review-approver")
+		assertApproved(t, withSize(60, 40), r, "Approved by comment-approver, review-approver")
+		assertApproved(t, withSize(61, 40), r, "Approved by comment-approver, review-approver")
+		assertApproved(t, withSize(300, 200), r, "Approved by comment-approver, review-approver")
+		assertPending(t, withSize(301, 200), r, "2/3 required approvals (required by total modifications > 500). Ignored 5 approvals from disqualified users")
+
+		r.Requires.Count = 3
+		assertPending(t, withSize(10, 5), r, "2/3 required approvals. Ignored 5 approvals from disqualified users")
+		r.Requires.Count = 1
+
+		r.Requires.LabelCounts = []LabelCount{{Labels: []string{"risk:high"}, Count: 3}}
+		prctx := withSize(301, 200)
+		prctx.LabelsValue = []string{"risk:high"}
+		assertPending(t, prctx, r, "2/3 required approvals (required by labels risk:high). Ignored 5 approvals from disqualified users")
+
+		r.Requires.SizeCounts[0].Count = 4
+		assertPending(t, prctx, r, "2/4 required approvals (required by total modifications > 500). Ignored 5 approvals from disqualified users")
+
+		prctx.ChangedFilesError = assert.AnError
+		_, _, err := r.IsApproved(context.Background(), prctx, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("minPermission", func(t *testing.T) {
+		withPermissions := func(comment, review pull.Permission) *pulltest.Context {
+			prctx := basePullContext()
+			prctx.CollaboratorsValue = []*pull.Collaborator{
+				{Name: "comment-approver", Permissions: []pull.CollaboratorPermission{{Permission: comment}}},
+				{Name: "review-approver", Permissions: []pull.CollaboratorPermission{{Permission: review}}},
+			}
+			return prctx
+		}
+
+		r := &Rule{
+			Requires: Requires{
+				Count: 2,
+				Actors: common.Actors{
+					Users: []string{"comment-approver", "review-approver"},
+				},
+				MinPermission: pull.PermissionWrite,
+			},
+		}
+
+		assertApproved(t, withPermissions(pull.PermissionWrite, pull.PermissionAdmin), r, "Approved by comment-approver, review-approver")
+		assertApproved(t, withPermissions(pull.PermissionMaintain, pull.PermissionWrite), r, "Approved by comment-approver, review-approver")
+		assertPending(t, withPermissions(pull.PermissionTriage, pull.PermissionWrite), r, "1/2 required approvals. Ignored 1 approval from users without the required permission. Ignored 5 approvals from disqualified users")
+		assertPending(t, withPermissions(pull.PermissionRead, pull.PermissionNone), r, "0/2 required approvals. Ignored 2 approvals from users without the required permission. Ignored 5 approvals from disqualified users")
+
+		r.Requires.MinPermission = pull.PermissionMaintain
+		assertPending(t, withPermissions(pull.PermissionMaintain, pull.PermissionWrite), r, "1/2 required approvals. Ignored 1 approval from users without the required permission. Ignored 5 approvals from disqualified users")
+
+		r.Requires.MinPermission = pull.PermissionTriage
+		assertApproved(t, withPermissions(pull.PermissionTriage, pull.PermissionMaintain), r, "Approved by comment-approver, review-approver")
+
+		r.Requires.MinPermission = pull.PermissionNone
+		assertApproved(t, withPermissions(pull.PermissionNone, pull.PermissionNone), r, "Approved by comment-approver, review-approver")
+
+		prctx := withPermissions(pull.PermissionWrite, pull.PermissionWrite)
+		prctx.CollaboratorsError = assert.AnError
+		r.Requires.MinPermission = pull.PermissionWrite
+		_, _, err := r.IsApproved(context.Background(), prctx, []*common.Candidate{{User: "comment-approver"}})
+		assert.Error(t, err)
+	})
+
+	t.Run("minPermissionTeamCountsAndAllUsers", func(t *testing.T) {
+		prctx := basePullContext()
+		prctx.CollaboratorsValue = []*pull.Collaborator{
+			{Name: "comment-approver", Permissions: []pull.CollaboratorPermission{{Permission: pull.PermissionWrite}}},
+			{Name: "review-approver", Permissions: []pull.CollaboratorPermission{{Permission: pull.PermissionRead}}},
+		}
+
+		r := &Rule{
+			Requires: Requires{
+				TeamCounts: map[string]int{
+					"everyone/security": 1,
+					"everyone/platform": 1,
+				},
+				MinPermission: pull.PermissionWrite,
+			},
+		}
+		assertPending(t, prctx, r, "0/1 required approvals from everyone/platform, 1/1 required approvals from everyone/security")
+
+		r = &Rule{
+			Requires: Requires{
+				AllUsers:      []string{"review-approver", "comment-approver"},
+				MinPermission: pull.PermissionWrite,
+			},
+		}
+		assertPending(t, prctx, r, "waiting for approval from review-approver")
+
+		r.Requires.MinPermission = pull.PermissionRead
+		assertApproved(t, prctx, r, "Approved by review-approver, comment-approver")
+	})
+
+	t.Run("independentApprovalSoleContributorApprover", func(t *testing.T) {
+		prctx := basePullContext()
+		r := &Rule{
+			Options: Options{
+				AllowNonAuthorContributor: true,
+			},
+			Requires: Requires{
+				Count: 1,
+				Actors: common.Actors{
+					Users: []string{"contributor-author"},
+				},
+				RequireIndependentApproval: true,
+			},
+		}
+		assertPending(t, prctx, r, "1/2 required approvals. An approval from a user who did not contribute commits is required. Ignored 6 approvals from disqualified users")
+
+		r.Requires.RequireIndependentApproval = false
+		assertApproved(t, prctx, r, "Approved by contributor-author")
+	})
+
+	t.Run("independentApprovalAllContributorApprovers", func(t *testing.T) {
+		prctx := basePullContext()
+		r := &Rule{
+			Options: Options{
+				AllowNonAuthorContributor: true,
+			},
+			Requires: Requires{
+				Count: 1,
+				Actors: common.Actors{
+					Users: []string{"contributor-author", "contributor-committer"},
+				},
+				RequireIndependentApproval: true,
+			},
+		}
+		assertPending(t, prctx, r, "2/3 required approvals. An approval from a user who did not contribute commits is required. Ignored 5 approvals from disqualified users")
+	})
+
+	t.Run("independentApprovalWithNonContributor", func(t *testing.T) {
+		prctx := basePullContext()
+		r := &Rule{
+			Options: Options{
+				AllowNonAuthorContributor: true,
+			},
+			Requires: Requires{
+				Count: 1,
+				Actors: common.Actors{
+					Users: []string{"contributor-author", "review-approver"},
+				},
+				RequireIndependentApproval: true,
+			},
+		}
+		assertApproved(t, prctx, r, "Approved by contributor-author, review-approver")
+	})
+
+	t.Run("independentApprovalNotEnoughApprovals", func(t *testing.T) {
+		prctx := basePullContext()
+		r := &Rule{
+			Options: Options{
+				AllowNonAuthorContributor: true,
+			},
+			Requires: Requires{
+				Count: 2,
+				Actors: common.Actors{
+					Users: []string{"contributor-author"},
+				},
+				RequireIndependentApproval: true,
+			},
+		}
+		assertPending(t, prctx, r, "1/2 required approvals. Ignored 6 approvals from disqualified users")
+	})
+
+	t.Run("maxPerOrgIgnoresUsersInNoOrg", func(t *testing.T) {
+		prctx := basePullContext()
+		r := &Rule{
+			Requires: Requires{
+				Count: 2,
+				Actors: common.Actors{
+					Users:         []string{"review-approver"},
+					Organizations: []string{"cool-org"},
+				},
+				MaxPerOrg: 1,
+			},
+		}
+		assertApproved(t, prctx, r, "Approved by comment-approver, review-approver")
+	})
+}
+
+func TestCoolOff(t *testing.T) {
+	logger := zerolog.New(os.Stdout)
+	ctx := logger.WithContext(context.Background())
+
+	now := time.Now()
+	basePullContext := func() *pulltest.Context {
+		return &pulltest.Context{
+			AuthorValue: "mhaypenny",
+			CommentsValue: []*pull.Comment{
+				{
+					CreatedAt: now.Add(-2 * time.Hour),
+					Author:    "comment-approver",
+					Body:      "LGTM :+1:",
+				},
+			},
+			ReviewsValue: []*pull.Review{
+				{
+					CreatedAt: now.Add(-30 * time.Minute),
+					Author:    "review-approver",
+					State:     pull.ReviewApproved,
+				},
+			},
+			CollaboratorsValue: []*pull.Collaborator{
+				{Name: "mhaypenny", Permissions: []pull.CollaboratorPermission{
+					{Permission: pull.PermissionWrite},
+				}},
+			},
+			EvaluationTimestampValue: now,
+		}
+	}
+
+	t.Run("pendingDuringCoolOff", func(t *testing.T) {
+		prctx := basePullContext()
+		r := &Rule{
+			Options: Options{
+				CoolOff: predicate.Duration(time.Hour),
+			},
+			Requires: Requires{
+				Count: 1,
+				Actors: common.Actors{
+					Users: []string{"comment-approver", "review-approver"},
+				},
+			},
+		}
+
+		res := r.Evaluate(ctx, prctx)
+		require.NoError(t, res.Error)
+
+		assert.Equal(t, common.StatusPending, res.Status)
+		assert.Equal(t, "Approved by comment-approver, review-approver; cool-off period ends in 30m0s", res.StatusDescription)
+		assert.Equal(t, now.Add(30*time.Minute), res.ReevaluateAt)
+		assert.Nil(t, res.ReviewRequestRule, "review requests should not be made during cool-off")
+	})
+
+	t.Run("approvedAfterCoolOff", func(t *testing.T) {
+		prctx := basePullContext()
+		r := &Rule{
+			Options: Options{
+				CoolOff: predicate.Duration(15 * time.Minute),
+			},
+			Requires: Requires{
+				Count: 1,
+				Actors: common.Actors{
+					Users: []string{"comment-approver", "review-approver"},
+				},
+			},
+		}
+
+		res := r.Evaluate(ctx, prctx)
+		require.NoError(t, res.Error)
+
+		assert.Equal(t, common.StatusApproved, res.Status)
+		assert.Equal(t, "Approved by comment-approver, review-approver", res.StatusDescription)
+		assert.True(t, res.ReevaluateAt.IsZero(), "no re-evaluation should be scheduled")
+	})
+
+	t.Run("reevaluateWhenApprovalExpires", func(t *testing.T) {
+		prctx := basePullContext()
+		r := &Rule{
+			Options: Options{
+				ExpireAfter: predicate.Duration(3 * time.Hour),
+			},
+			Requires: Requires{
+				Count: 1,
+				Actors: common.Actors{
+					Users: []string{"comment-approver", "review-approver"},
+				},
+			},
+		}
+
+		res := r.Evaluate(ctx, prctx)
+		require.NoError(t, res.Error)
+
+		assert.Equal(t, common.StatusApproved, res.Status)
+		assert.Equal(t, now.Add(time.Hour), res.ReevaluateAt)
+	})
+
+	t.Run("noCoolOffWhenAuthorCannotMerge", func(t *testing.T) {
+		prctx := basePullContext()
+		prctx.CollaboratorsValue = nil
+
+		r := &Rule{
+			Options: Options{
+				CoolOff: predicate.Duration(time.Hour),
+			},
+			Requires: Requires{
+				Count: 1,
+				Actors: common.Actors{
+					Users: []string{"comment-approver", "review-approver"},
+				},
+			},
+		}
+
+		res := r.Evaluate(ctx, prctx)
+		require.NoError(t, res.Error)
+
+		assert.Equal(t, common.StatusApproved, res.Status)
+		assert.True(t, res.ReevaluateAt.IsZero(), "no re-evaluation should be scheduled")
+	})
+
+	t.Run("noCoolOffWithoutApprovers", func(t *testing.T) {
+		prctx := basePullContext()
+		r := &Rule{
+			Options: Options{
+				CoolOff: predicate.Duration(time.Hour),
+			},
+		}
+
+		res := r.Evaluate(ctx, prctx)
+		require.NoError(t, res.Error)
+
+		assert.Equal(t, common.StatusApproved, res.Status)
+	})
+}
+
+func TestRuleStatusMetrics(t *testing.T) {
+	registry := metrics.NewRegistry()
+	ctx := baseapp.WithMetricsCtx(context.Background(), registry)
+
+	prctx := &pulltest.Context{
+		AuthorValue: "mhaypenny",
+		ReviewsValue: []*pull.Review{
+			{
+				Author: "review-approver",
+				State:  pull.ReviewApproved,
+			},
+		},
+	}
+
+	approved := &Rule{
+		Name: "approved, by a user",
+	}
+	pending := &Rule{
+		Name: "pending",
+		Requires: Requires{
+			Count: 2,
+			Actors: common.Actors{
+				Users: []string{"review-approver"},
+			},
+		},
+	}
+	skipped := &Rule{
+		Name: "skipped",
+		Predicates: predicate.Predicates{
+			HasLabels: &predicate.HasLabels{Labels: []string{"missing-label"}},
+		},
+	}
+
+	approved.Evaluate(baseapp.WithMetricsCtx(context.Background(), registry), prctx)
+
+	ctx = WithRuleMetrics(ctx)
+	approved.Evaluate(ctx, prctx)
+	approved.Evaluate(ctx, prctx)
+	pending.Evaluate(ctx, prctx)
+	skipped.Evaluate(ctx, prctx)
+
+	prctx.LabelsError = errors.New("failed to list labels")
+	skipped.Evaluate(ctx, prctx)
+
+	count := func(rule, status string) int64 {
+		key := MetricsKeyRuleStatus + "[rule:" + rule + ",status:" + status + "]"
+		if c, ok := registry.Get(key).(metrics.Counter); ok {
+			return c.Count()
+		}
+		return 0
+	}
+
+	assert.Equal(t, int64(2), count("approved by a user", "approved"))
+	assert.Equal(t, int64(1), count("pending", "pending"))
+	assert.Equal(t, int64(0), count("pending", "approved"))
+	assert.Equal(t, int64(1), count("skipped", "skipped"))
+	assert.Equal(t, int64(1), count("skipped", "error"))
+
+	t.Run("boundedNames", func(t *testing.T) {
+		long := strings.Repeat("a", 100)
+		assert.Equal(t, strings.Repeat("a", maxRuleMetricNameLength), ruleMetricName(long))
+
+		for i := 0; i < maxRuleMetricNames; i++ {
+			ruleMetricName(fmt.Sprintf("rule %d", i))
+		}
+		assert.Equal(t, "approved by a user", ruleMetricName("approved, by a user"))
+		assert.Equal(t, ruleMetricOtherName, ruleMetricName("a new rule"))
+	})
+}
+
+func TestTrigger(t *testing.T) {
+	t.Run("triggerCommitOnRules", func(t *testing.T) {
+		r := &Rule{}
+
+		assert.True(t, r.Trigger().Matches(common.TriggerCommit), "expected %s to match %", r.Trigger(), common.TriggerCommit)
+	})
+
+	t.Run("triggerLabelOnLabelCounts", func(t *testing.T) {
+		r := &Rule{
+			Requires: Requires{
+				LabelCounts: []LabelCount{
+					{Labels: []string{"risk:high"}, Count: 2},
+				},
+			},
+		}
+
+		assert.True(t, r.Trigger().Matches(common.TriggerLabel), "expected %s to match %s", r.Trigger(), common.TriggerLabel)
+	})
+
+	t.Run("triggerCommentOnComments", func(t *testing.T) {
+		r := &Rule{
+			Options: Options{
+				Methods: &common.Methods{
+					Comments: []string{
+						"lgtm",
+					},
+				},
+			},
+			Requires: Requires{
+				Count: 1,
 			},
 		}
 
@@ -928,3 +2108,26 @@ func TestSortCommits(t *testing.T) {
 func newTime(t time.Time) *time.Time {
 	return &t
 }
+
+func TestReevaluateForPredicates(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 12, 15, 0, 0, 0, time.UTC)
+
+	prctx := &pulltest.Context{
+		AuthorValue:              "mhaypenny",
+		CreatedAtValue:           now.Add(-time.Hour),
+		EvaluationTimestampValue: now,
+	}
+
+	r := &Rule{
+		Predicates: predicate.Predicates{
+			Age: &predicate.Age{OlderThan: predicate.Duration(2 * time.Hour)},
+		},
+	}
+
+	res := r.Evaluate(ctx, prctx)
+	require.NoError(t, res.Error)
+
+	assert.Equal(t, common.StatusSkipped, res.Status)
+	assert.Equal(t, now.Add(time.Hour+time.Second), res.ReevaluateAt)
+}
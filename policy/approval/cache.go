@@ -0,0 +1,187 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approval
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/policy/predicate"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/pkg/errors"
+)
+
+type resultCacheContextKey struct{}
+
+// WithResultCache returns a context that stores the results of rules with the
+// cache_result option in cache. Without a cache in the context, rules are
+// always evaluated.
+func WithResultCache(ctx context.Context, cache pull.GlobalCache) context.Context {
+	return context.WithValue(ctx, resultCacheContextKey{}, cache)
+}
+
+func resultCacheFromContext(ctx context.Context) pull.GlobalCache {
+	cache, _ := ctx.Value(resultCacheContextKey{}).(pull.GlobalCache)
+	return cache
+}
+
+// validateCacheResult returns an error if the rule sets the cache_result
+// option but uses predicates that depend on the evaluation time. Their results
+// can change without any change to the inputs in the cache key.
+func (r *Rule) validateCacheResult() error {
+	if !r.Options.CacheResult {
+		return nil
+	}
+	for _, p := range r.allPredicates() {
+		switch p.(type) {
+		case *predicate.Age, *predicate.AuthorAccountAge, *predicate.ReviewSLA:
+			return errors.New("cache_result cannot be used with the age, author_account_age, or review_sla predicates")
+		}
+	}
+	return nil
+}
+
+// allPredicates returns the predicates in the rule's "if" block and in its
+// required conditions.
+func (r *Rule) allPredicates() []predicate.Predicate {
+	return append(r.Predicates.Predicates(), r.Requires.Conditions.Predicates()...)
+}
+
+// resultCacheKey returns a key that identifies the rule definition and the
+// inputs that can change the result of the rule. Immutable inputs, like the
+// files and commits, are identified by the head SHA. Mutable inputs, like
+// reviews and statuses, are only loaded and included in the key if the rule
+// depends on them according to its trigger. Review thread resolution does
+// not create reviews or comments, so threads are included for rules that use
+// the has_resolved_review_threads predicate. Similarly, reopening or force
+// pushing does not change the trigger inputs, so the times of these events
+// are included for rules that invalidate approvals on them.
+func (r *Rule) resultCacheKey(prctx pull.Context) (string, error) {
+	base, _ := prctx.Branches()
+	inputs := map[string]interface{}{
+		"repository": prctx.RepositoryOwner() + "/" + prctx.RepositoryName(),
+		"number":     prctx.Number(),
+		"head":       prctx.HeadSHA(),
+		"base":       base,
+		"rule":       r,
+		"source":     prctx.EventSource(),
+	}
+
+	t := r.Trigger()
+	if t.Matches(common.TriggerComment) {
+		comments, err := prctx.Comments()
+		if err != nil {
+			return "", errors.Wrap(err, "failed to list comments")
+		}
+		inputs["comments"] = comments
+	}
+	if t.Matches(common.TriggerReview) {
+		reviews, err := prctx.Reviews()
+		if err != nil {
+			return "", errors.Wrap(err, "failed to list reviews")
+		}
+		inputs["reviews"] = reviews
+	}
+	for _, p := range r.allPredicates() {
+		if _, ok := p.(*predicate.HasResolvedReviewThreads); ok {
+			threads, err := prctx.ReviewThreads()
+			if err != nil {
+				return "", errors.Wrap(err, "failed to list review threads")
+			}
+			inputs["reviewThreads"] = threads
+			break
+		}
+	}
+	if t.Matches(common.TriggerLabel) {
+		labels, err := prctx.Labels()
+		if err != nil {
+			return "", errors.Wrap(err, "failed to list labels")
+		}
+		appliers, err := prctx.LabelAppliers()
+		if err != nil {
+			return "", errors.Wrap(err, "failed to list label appliers")
+		}
+		inputs["labels"] = labels
+		inputs["labelAppliers"] = appliers
+	}
+	if t.Matches(common.TriggerStatus) {
+		statuses, err := prctx.LatestStatuses()
+		if err != nil {
+			return "", errors.Wrap(err, "failed to list statuses")
+		}
+		runs, err := prctx.LatestWorkflowRuns()
+		if err != nil {
+			return "", errors.Wrap(err, "failed to list workflow runs")
+		}
+		inputs["statuses"] = statuses
+		inputs["workflowRuns"] = runs
+	}
+	for _, p := range r.allPredicates() {
+		if _, ok := p.(*predicate.HasSuccessfulDeployment); ok {
+			deployments, err := prctx.LatestDeployments()
+			if err != nil {
+				return "", errors.Wrap(err, "failed to list deployments")
+			}
+			inputs["deployments"] = deployments
+			break
+		}
+	}
+	if t.Matches(common.TriggerPullRequest) {
+		body, err := prctx.Body()
+		if err != nil {
+			return "", errors.Wrap(err, "failed to get body")
+		}
+		reviewers, err := prctx.RequestedReviewers()
+		if err != nil {
+			return "", errors.Wrap(err, "failed to list requested reviewers")
+		}
+		reopenedAt, err := prctx.ReopenedAt()
+		if err != nil {
+			return "", errors.Wrap(err, "failed to get reopened time")
+		}
+		inputs["title"] = prctx.Title()
+		inputs["body"] = body
+		inputs["draft"] = prctx.IsDraft()
+		inputs["autoMerge"] = prctx.AutoMerge()
+		inputs["open"] = prctx.IsOpen()
+		inputs["requestedReviewers"] = reviewers
+		inputs["reopenedAt"] = reopenedAt
+	}
+	if r.Options.InvalidateOnReopen {
+		reopenedAt, err := prctx.ReopenedAt()
+		if err != nil {
+			return "", errors.Wrap(err, "failed to get reopened time")
+		}
+		inputs["reopenedAt"] = reopenedAt
+	}
+	if r.Options.InvalidateOnForcePush {
+		forcePushedAt, err := prctx.ForcePushedAt()
+		if err != nil {
+			return "", errors.Wrap(err, "failed to get force push time")
+		}
+		inputs["forcePushedAt"] = forcePushedAt
+	}
+
+	// JSON encodes maps with sorted keys, so equal inputs have equal keys
+	b, err := json.Marshal(inputs)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode rule inputs")
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,263 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approval
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/policy/predicate"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/palantir/policy-bot/pull/pulltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleResultCache(t *testing.T) {
+	now := time.Now()
+	newContext := func() *pulltest.Context {
+		return &pulltest.Context{
+			AuthorValue:  "mhaypenny",
+			HeadSHAValue: "e05fcae367230ee709313dd2720da527d178ce43",
+			ChangedFilesValue: []*pull.File{
+				{Filename: "server/handler.go", Status: pull.FileModified},
+			},
+			ReviewsValue: []*pull.Review{
+				{CreatedAt: now, Author: "alice", State: pull.ReviewApproved},
+			},
+			OrgMemberships: map[string][]string{
+				"alice": {"everyone"},
+			},
+		}
+	}
+
+	newRule := func(options Options) *Rule {
+		return &Rule{
+			Name: "server",
+			Predicates: predicate.Predicates{
+				ChangedFiles: &predicate.ChangedFiles{
+					Paths: []common.Regexp{common.NewCompiledRegexp(regexp.MustCompile("^server/"))},
+				},
+			},
+			Options: options,
+			Requires: Requires{
+				Count:  1,
+				Actors: common.Actors{Organizations: []string{"everyone"}},
+			},
+		}
+	}
+
+	newCache := func(t *testing.T) *pull.LRUGlobalCache {
+		cache, err := pull.NewLRUGlobalCache(1, 10, 1, 0)
+		require.NoError(t, err)
+		return cache
+	}
+
+	// breakContext makes any evaluation that reaches the predicates or the
+	// membership checks fail, so a successful result must come from the cache
+	breakContext := func(prctx *pulltest.Context) {
+		prctx.ChangedFilesError = assert.AnError
+		prctx.OrgMembershipError = assert.AnError
+	}
+
+	t.Run("cachedResultAvoidsCalls", func(t *testing.T) {
+		ctx := WithResultCache(context.Background(), newCache(t))
+		r := newRule(Options{CacheResult: true})
+
+		prctx := newContext()
+		res := r.Evaluate(ctx, prctx)
+		require.NoError(t, res.Error)
+		assert.Equal(t, common.StatusApproved, res.Status)
+
+		breakContext(prctx)
+		cached := r.Evaluate(ctx, prctx)
+		require.NoError(t, cached.Error, "rule was evaluated instead of using the cached result")
+		assert.Equal(t, res, cached)
+	})
+
+	t.Run("changedInputs", func(t *testing.T) {
+		ctx := WithResultCache(context.Background(), newCache(t))
+		r := newRule(Options{CacheResult: true})
+
+		prctx := newContext()
+		res := r.Evaluate(ctx, prctx)
+		require.NoError(t, res.Error)
+
+		breakContext(prctx)
+		prctx.ReviewsValue = append(prctx.ReviewsValue, &pull.Review{
+			CreatedAt: now.Add(time.Minute),
+			Author:    "bob",
+			State:     pull.ReviewApproved,
+		})
+		res = r.Evaluate(ctx, prctx)
+		assert.Error(t, res.Error, "new review did not invalidate the cached result")
+	})
+
+	t.Run("changedHead", func(t *testing.T) {
+		ctx := WithResultCache(context.Background(), newCache(t))
+		r := newRule(Options{CacheResult: true})
+
+		prctx := newContext()
+		res := r.Evaluate(ctx, prctx)
+		require.NoError(t, res.Error)
+
+		breakContext(prctx)
+		prctx.HeadSHAValue = "674832587eaaf416371b30f5bc5a47e377f534ec"
+		res = r.Evaluate(ctx, prctx)
+		assert.Error(t, res.Error, "new head commit did not invalidate the cached result")
+	})
+
+	t.Run("changedRule", func(t *testing.T) {
+		ctx := WithResultCache(context.Background(), newCache(t))
+
+		prctx := newContext()
+		res := newRule(Options{CacheResult: true}).Evaluate(ctx, prctx)
+		require.NoError(t, res.Error)
+
+		breakContext(prctx)
+		r := newRule(Options{CacheResult: true})
+		r.Requires.Count = 2
+		res = r.Evaluate(ctx, prctx)
+		assert.Error(t, res.Error, "changed rule definition used the cached result")
+	})
+
+	t.Run("changedReviewThreads", func(t *testing.T) {
+		ctx := WithResultCache(context.Background(), newCache(t))
+		resolved := predicate.HasResolvedReviewThreads(true)
+		r := newRule(Options{CacheResult: true})
+		r.Requires.Conditions.HasResolvedReviewThreads = &resolved
+
+		prctx := newContext()
+		prctx.ReviewThreadsValue = []*pull.ReviewThread{
+			{Path: "server/handler.go", Author: "alice", CreatedAt: now},
+		}
+		res := r.Evaluate(ctx, prctx)
+		require.NoError(t, res.Error)
+
+		breakContext(prctx)
+		prctx.ReviewThreadsValue = []*pull.ReviewThread{
+			{Path: "server/handler.go", Author: "alice", CreatedAt: now, IsResolved: true},
+		}
+		res = r.Evaluate(ctx, prctx)
+		assert.Error(t, res.Error, "resolved thread did not invalidate the cached result")
+	})
+
+	t.Run("changedDeployments", func(t *testing.T) {
+		ctx := WithResultCache(context.Background(), newCache(t))
+		r := newRule(Options{CacheResult: true})
+		r.Requires.Conditions.HasSuccessfulDeployment = &predicate.HasSuccessfulDeployment{
+			Environments: []string{"production"},
+		}
+
+		prctx := newContext()
+		prctx.LatestDeploymentsValue = map[string]string{"production": "in_progress"}
+		res := r.Evaluate(ctx, prctx)
+		require.NoError(t, res.Error)
+
+		breakContext(prctx)
+		prctx.LatestDeploymentsValue = map[string]string{"production": "success"}
+		res = r.Evaluate(ctx, prctx)
+		assert.Error(t, res.Error, "new deployment did not invalidate the cached result")
+	})
+
+	t.Run("deploymentsNotLoaded", func(t *testing.T) {
+		ctx := WithResultCache(context.Background(), newCache(t))
+		r := newRule(Options{CacheResult: true})
+		r.Requires.Conditions.HasStatus = predicate.NewHasStatus([]string{"build"}, []string{"success"})
+
+		prctx := newContext()
+		prctx.LatestStatusesValue = map[string]string{"build": "success"}
+		prctx.LatestDeploymentsError = assert.AnError
+		res := r.Evaluate(ctx, prctx)
+		require.NoError(t, res.Error, "deployments were loaded for a rule that does not use them")
+
+		breakContext(prctx)
+		cached := r.Evaluate(ctx, prctx)
+		require.NoError(t, cached.Error, "rule was evaluated instead of using the cached result")
+		assert.Equal(t, res, cached)
+	})
+
+	t.Run("reopened", func(t *testing.T) {
+		ctx := WithResultCache(context.Background(), newCache(t))
+		r := newRule(Options{CacheResult: true, InvalidateOnReopen: true})
+
+		prctx := newContext()
+		res := r.Evaluate(ctx, prctx)
+		require.NoError(t, res.Error)
+		assert.Equal(t, common.StatusApproved, res.Status)
+
+		breakContext(prctx)
+		prctx.ReopenedAtValue = now.Add(time.Minute)
+		res = r.Evaluate(ctx, prctx)
+		assert.Error(t, res.Error, "reopening did not invalidate the cached result")
+	})
+
+	t.Run("forcePushed", func(t *testing.T) {
+		ctx := WithResultCache(context.Background(), newCache(t))
+		r := newRule(Options{CacheResult: true, InvalidateOnForcePush: true})
+
+		prctx := newContext()
+		res := r.Evaluate(ctx, prctx)
+		require.NoError(t, res.Error)
+		assert.Equal(t, common.StatusApproved, res.Status)
+
+		breakContext(prctx)
+		prctx.ForcePushedAtValue = now.Add(time.Minute)
+		res = r.Evaluate(ctx, prctx)
+		assert.Error(t, res.Error, "force push did not invalidate the cached result")
+	})
+
+	t.Run("optionDisabled", func(t *testing.T) {
+		ctx := WithResultCache(context.Background(), newCache(t))
+		r := newRule(Options{})
+
+		prctx := newContext()
+		res := r.Evaluate(ctx, prctx)
+		require.NoError(t, res.Error)
+
+		breakContext(prctx)
+		res = r.Evaluate(ctx, prctx)
+		assert.Error(t, res.Error, "result was cached without the cache_result option")
+	})
+
+	t.Run("timeDependentResult", func(t *testing.T) {
+		ctx := WithResultCache(context.Background(), newCache(t))
+		r := newRule(Options{CacheResult: true, ExpireAfter: predicate.Duration(time.Hour)})
+
+		prctx := newContext()
+		res := r.Evaluate(ctx, prctx)
+		require.NoError(t, res.Error)
+		require.False(t, res.ReevaluateAt.IsZero())
+
+		breakContext(prctx)
+		res = r.Evaluate(ctx, prctx)
+		assert.Error(t, res.Error, "result with a reevaluation time was cached")
+	})
+
+	t.Run("noCache", func(t *testing.T) {
+		r := newRule(Options{CacheResult: true})
+
+		prctx := newContext()
+		res := r.Evaluate(context.Background(), prctx)
+		require.NoError(t, res.Error)
+
+		breakContext(prctx)
+		res = r.Evaluate(context.Background(), prctx)
+		assert.Error(t, res.Error, "result was cached without a cache in the context")
+	})
+}
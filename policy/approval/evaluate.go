@@ -17,12 +17,75 @@ package approval
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/palantir/policy-bot/policy/common"
 	"github.com/palantir/policy-bot/pull"
+	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 )
 
+type ruleConcurrencyContextKey struct{}
+
+// WithRuleConcurrency returns a context that evaluates the rules in "and" and
+// "or" blocks in parallel, with at most n rules evaluating at once across the
+// whole policy. Without a limit in the context, or if n is less than 2, rules
+// are evaluated one at a time. The pull.Context used for evaluation must be
+// safe for concurrent use.
+func WithRuleConcurrency(ctx context.Context, n int) context.Context {
+	if n < 2 {
+		return ctx
+	}
+	// The evaluating goroutine counts toward the limit
+	return context.WithValue(ctx, ruleConcurrencyContextKey{}, make(chan struct{}, n-1))
+}
+
+// evaluateAll evaluates each requirement and returns the results in the same
+// order as the requirements. If the context allows concurrency, requirements
+// are evaluated in new goroutines while there are free slots and in the
+// calling goroutine otherwise. Never waiting for a slot means that nested
+// blocks cannot deadlock when their parents hold all of the slots.
+func evaluateAll(ctx context.Context, prctx pull.Context, requirements []common.Evaluator) []*common.Result {
+	results := make([]*common.Result, len(requirements))
+
+	slots, _ := ctx.Value(ruleConcurrencyContextKey{}).(chan struct{})
+
+	var wg sync.WaitGroup
+	for i, req := range requirements {
+		select {
+		case slots <- struct{}{}:
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-slots }()
+				results[i] = evaluateRecover(ctx, prctx, req)
+			}()
+		default:
+			res := req.Evaluate(ctx, prctx)
+			results[i] = &res
+		}
+	}
+	wg.Wait()
+
+	return results
+}
+
+// evaluateRecover evaluates a requirement, converting panics into errors so
+// that a failure in a separate goroutine does not crash the server.
+func evaluateRecover(ctx context.Context, prctx pull.Context, req common.Evaluator) (res *common.Result) {
+	defer func() {
+		if r := recover(); r != nil {
+			res = &common.Result{
+				Status: common.StatusSkipped,
+				Error:  errors.Errorf("panic during evaluation: %v", r),
+			}
+		}
+	}()
+
+	result := req.Evaluate(ctx, prctx)
+	return &result
+}
+
 type evaluator struct {
 	root common.Evaluator
 }
@@ -81,11 +144,7 @@ func (r *OrRequirement) Trigger() common.Trigger {
 }
 
 func (r *OrRequirement) Evaluate(ctx context.Context, prctx pull.Context) common.Result {
-	var children []*common.Result
-	for _, req := range r.requirements {
-		res := req.Evaluate(ctx, prctx)
-		children = append(children, &res)
-	}
+	children := evaluateAll(ctx, prctx, r.requirements)
 
 	var err error
 	var pending, approved, skipped int
@@ -141,11 +200,7 @@ func (r *AndRequirement) Trigger() common.Trigger {
 }
 
 func (r *AndRequirement) Evaluate(ctx context.Context, prctx pull.Context) common.Result {
-	var children []*common.Result
-	for _, req := range r.requirements {
-		res := req.Evaluate(ctx, prctx)
-		children = append(children, &res)
-	}
+	children := evaluateAll(ctx, prctx, r.requirements)
 
 	var err error
 	var pending, approved, skipped int
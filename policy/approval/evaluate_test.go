@@ -17,9 +17,12 @@ package approval
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
 	"regexp"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/palantir/policy-bot/policy/common"
 	"github.com/palantir/policy-bot/policy/predicate"
@@ -320,3 +323,122 @@ func TestOrRequirement(t *testing.T) {
 	assert.NoError(t, result.Error)
 	assert.Equal(t, common.StatusApproved, result.Status)
 }
+
+type concurrentRequirement struct {
+	name      string
+	active    *int32
+	maxActive *int32
+	panics    bool
+}
+
+func (r *concurrentRequirement) Trigger() common.Trigger {
+	return common.TriggerStatic
+}
+
+func (r *concurrentRequirement) Evaluate(ctx context.Context, prctx pull.Context) common.Result {
+	n := atomic.AddInt32(r.active, 1)
+	defer atomic.AddInt32(r.active, -1)
+
+	for {
+		m := atomic.LoadInt32(r.maxActive)
+		if n <= m || atomic.CompareAndSwapInt32(r.maxActive, m, n) {
+			break
+		}
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	if r.panics {
+		panic("evaluation failed")
+	}
+	return common.Result{Name: r.name, Status: common.StatusApproved}
+}
+
+func TestRequirementConcurrency(t *testing.T) {
+	prctx := &pulltest.Context{}
+
+	makeRequirements := func(active, maxActive *int32, names ...string) []common.Evaluator {
+		var requirements []common.Evaluator
+		for _, name := range names {
+			requirements = append(requirements, &concurrentRequirement{
+				name:      name,
+				active:    active,
+				maxActive: maxActive,
+			})
+		}
+		return requirements
+	}
+
+	childNames := func(res common.Result) []string {
+		var names []string
+		for _, c := range res.Children {
+			names = append(names, c.Name)
+		}
+		return names
+	}
+
+	t.Run("sequential", func(t *testing.T) {
+		var active, maxActive int32
+		or := &OrRequirement{
+			requirements: makeRequirements(&active, &maxActive, "a", "b", "c"),
+		}
+
+		result := or.Evaluate(context.Background(), prctx)
+		require.NoError(t, result.Error)
+		assert.Equal(t, common.StatusApproved, result.Status)
+		assert.Equal(t, []string{"a", "b", "c"}, childNames(result))
+		assert.Equal(t, int32(1), maxActive, "rules were evaluated concurrently")
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		var active, maxActive int32
+		and := &AndRequirement{
+			requirements: makeRequirements(&active, &maxActive, "a", "b", "c", "d", "e", "f"),
+		}
+
+		ctx := WithRuleConcurrency(context.Background(), 3)
+
+		result := and.Evaluate(ctx, prctx)
+		require.NoError(t, result.Error)
+		assert.Equal(t, common.StatusApproved, result.Status)
+		assert.Equal(t, []string{"a", "b", "c", "d", "e", "f"}, childNames(result), "children are not in order")
+		assert.Greater(t, maxActive, int32(1), "rules were not evaluated concurrently")
+		assert.LessOrEqual(t, maxActive, int32(3), "too many rules were evaluated concurrently")
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		var active, maxActive int32
+
+		var requirements []common.Evaluator
+		for i := 0; i < 4; i++ {
+			requirements = append(requirements, &OrRequirement{
+				requirements: makeRequirements(&active, &maxActive, fmt.Sprintf("%d-a", i), fmt.Sprintf("%d-b", i)),
+			})
+		}
+		and := &AndRequirement{requirements: requirements}
+
+		ctx := WithRuleConcurrency(context.Background(), 2)
+
+		result := and.Evaluate(ctx, prctx)
+		require.NoError(t, result.Error)
+		assert.Equal(t, common.StatusApproved, result.Status)
+		require.Len(t, result.Children, 4)
+		for i, c := range result.Children {
+			assert.Equal(t, []string{fmt.Sprintf("%d-a", i), fmt.Sprintf("%d-b", i)}, childNames(*c), "children are not in order")
+		}
+		assert.LessOrEqual(t, maxActive, int32(2), "too many rules were evaluated concurrently")
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		var active, maxActive int32
+		requirements := makeRequirements(&active, &maxActive, "a", "b")
+		requirements[0].(*concurrentRequirement).panics = true
+
+		and := &AndRequirement{requirements: requirements}
+
+		ctx := WithRuleConcurrency(context.Background(), 2)
+
+		result := and.Evaluate(ctx, prctx)
+		require.Error(t, result.Error)
+		assert.Contains(t, result.Error.Error(), "evaluation failed")
+	})
+}
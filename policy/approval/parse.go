@@ -35,11 +35,21 @@ func (p Policy) Parse(rules map[string]*Rule) (common.Evaluator, error) {
 		"and": []interface{}(p),
 	}
 
-	and, err := parsePolicyR(root, rules, 0)
+	// Link approval pools on copies of the rules because the rules belong to
+	// the config, which may be shared by concurrent parses
+	copies := make(map[string]*Rule, len(rules))
+	for name, r := range rules {
+		rule := *r
+		copies[name] = &rule
+	}
+
+	and, err := parsePolicyR(root, copies, 0)
 	if err != nil {
 		return nil, err
 	}
 
+	linkApprovalPools(and)
+
 	eval.root = and
 	return eval, nil
 }
@@ -52,6 +62,9 @@ func parsePolicyR(policy interface{}, rules map[string]*Rule, depth int) (common
 	// Base case
 	if ruleName, ok := policy.(string); ok {
 		if rule, ok := rules[ruleName]; ok {
+			if err := rule.validateCacheResult(); err != nil {
+				return nil, errors.WithMessage(err, fmt.Sprintf("invalid rule '%s'", ruleName))
+			}
 			req := &RuleRequirement{
 				rule: rule,
 			}
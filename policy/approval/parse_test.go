@@ -17,8 +17,10 @@ package approval
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/policy/predicate"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v2"
 )
@@ -195,6 +197,25 @@ func TestParsePolicyError_illegalType(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestParsePolicyError_timeDependentCache(t *testing.T) {
+	// Cached rule with a time-dependent predicate
+	policy := `
+- rule1
+`
+
+	rules := `
+- name: rule1
+  if:
+    age:
+      older_than: 1d
+  options:
+    cache_result: true
+`
+
+	_, err := loadAndParsePolicy(t, policy, rules)
+	require.Error(t, err)
+}
+
 func TestParsePolicyError_multikey(t *testing.T) {
 	// Multiple keys
 	policy := `
@@ -269,3 +290,17 @@ func loadAndParsePolicy(t *testing.T, policyText string, ruleText string) (commo
 
 	return policy.Parse(rulesByName)
 }
+
+func TestParseOptionDurations(t *testing.T) {
+	var rule Rule
+	err := yaml.UnmarshalStrict([]byte(`
+name: durations
+options:
+  cool_off: 1d12h
+  expire_after: 2w
+`), &rule)
+	require.NoError(t, err)
+
+	require.Equal(t, predicate.Duration(36*time.Hour), rule.Options.CoolOff)
+	require.Equal(t, predicate.Duration(14*24*time.Hour), rule.Options.ExpireAfter)
+}
@@ -0,0 +1,225 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approval
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// approvalPool is a set of rules that share approvals. Each approval counts
+// toward at most one of the rules in the pool.
+type approvalPool struct {
+	name  string
+	rules []*Rule
+}
+
+// linkApprovalPools groups the rules referenced by a policy into pools using
+// the approval_pool option. Rules in a pool are ordered by their first
+// reference in the policy.
+func linkApprovalPools(root common.Evaluator) {
+	pools := make(map[string]*approvalPool)
+	for _, r := range referencedRules(root, nil) {
+		name := r.Options.ApprovalPool
+		if name == "" {
+			r.pool = nil
+			continue
+		}
+
+		pool, ok := pools[name]
+		if !ok {
+			pool = &approvalPool{name: name}
+			pools[name] = pool
+		}
+		pool.rules = append(pool.rules, r)
+		r.pool = pool
+	}
+}
+
+// referencedRules appends the rules referenced by the policy tree rooted at e
+// to rules in depth-first order, skipping rules that were already found.
+func referencedRules(e common.Evaluator, rules []*Rule) []*Rule {
+	switch e := e.(type) {
+	case *RuleRequirement:
+		for _, r := range rules {
+			if r == e.rule {
+				return rules
+			}
+		}
+		return append(rules, e.rule)
+	case *AndRequirement:
+		for _, req := range e.requirements {
+			rules = referencedRules(req, rules)
+		}
+	case *OrRequirement:
+		for _, req := range e.requirements {
+			rules = referencedRules(req, rules)
+		}
+	}
+	return rules
+}
+
+// assign distributes approvals among the rules in the pool. Approvals are
+// considered in the order they were given and each is assigned round-robin
+// to the next rule that accepts the approver and still needs approvals. Rules
+// with unsatisfied predicates do not receive approvals. It returns the users
+// assigned to each rule.
+func (p *approvalPool) assign(ctx context.Context, prctx pull.Context) (map[*Rule]map[string]bool, error) {
+	log := zerolog.Ctx(ctx)
+
+	eligible := make([]map[string]bool, len(p.rules))
+	required := make([]int, len(p.rules))
+	firstApproval := make(map[string]time.Time)
+
+	var users []string
+	for i, r := range p.rules {
+		eligible[i] = make(map[string]bool)
+
+		applies, err := r.predicatesSatisfied(ctx, prctx)
+		if err != nil {
+			return nil, err
+		}
+		if !applies {
+			continue
+		}
+
+		candidates, _, err := r.FilteredCandidates(ctx, prctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to filter candidates for rule %q", r.Name)
+		}
+
+		required[i], _, _, err = r.Requires.requiredCount(ctx, prctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get required approvals for rule %q", r.Name)
+		}
+
+		_, approvers, err := r.isApprovedByActors(ctx, prctx, candidates, required[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find approvers for rule %q", r.Name)
+		}
+		approvers, err = r.limitPoolApprovers(ctx, prctx, approvers)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find approvers for rule %q", r.Name)
+		}
+
+		for _, c := range approvers {
+			eligible[i][c.User] = true
+			if t, ok := firstApproval[c.User]; !ok || c.CreatedAt.Before(t) {
+				if !ok {
+					users = append(users, c.User)
+				}
+				firstApproval[c.User] = c.CreatedAt
+			}
+		}
+	}
+
+	sort.SliceStable(users, func(i, j int) bool {
+		ti, tj := firstApproval[users[i]], firstApproval[users[j]]
+		if ti.Equal(tj) {
+			return users[i] < users[j]
+		}
+		return ti.Before(tj)
+	})
+
+	assigned := make(map[*Rule]map[string]bool, len(p.rules))
+	for _, r := range p.rules {
+		assigned[r] = make(map[string]bool)
+	}
+
+	next := 0
+	for _, user := range users {
+		for k := range p.rules {
+			i := (next + k) % len(p.rules)
+			if r := p.rules[i]; eligible[i][user] && len(assigned[r]) < required[i] {
+				log.Debug().Str("user", user).Msgf("assigning approval to rule %q in approval pool %q", r.Name, p.name)
+				assigned[r][user] = true
+				next = i + 1
+				break
+			}
+		}
+	}
+	return assigned, nil
+}
+
+// limitPoolApprovers applies the limits on approvers that the rule applies in
+// addition to its approval pool, so that the pool only assigns approvals that
+// count toward the rule.
+func (r *Rule) limitPoolApprovers(ctx context.Context, prctx pull.Context, approvers []*common.Candidate) ([]*common.Candidate, error) {
+	var err error
+	if r.Requires.MinPermission > pull.PermissionNone && len(approvers) > 0 {
+		if approvers, _, err = r.limitApproversToPermission(ctx, prctx, approvers); err != nil {
+			return nil, err
+		}
+	}
+	if r.Options.DisallowAuthorTeamApproval && len(approvers) > 0 {
+		if approvers, _, err = r.limitApproversToOtherTeams(ctx, prctx, approvers); err != nil {
+			return nil, err
+		}
+	}
+	if r.Requires.MaxPerOrg > 0 && len(approvers) > 0 {
+		if approvers, _, err = r.limitApproversPerOrg(ctx, prctx, approvers); err != nil {
+			return nil, err
+		}
+	}
+	if r.Requires.RequireDistinctTeams && len(approvers) > 0 {
+		if approvers, _, _, err = r.limitApproversToDistinctTeams(ctx, prctx, approvers); err != nil {
+			return nil, err
+		}
+	}
+	return approvers, nil
+}
+
+// limitApproversToPool returns the approvers assigned to the rule by its
+// approval pool and the approvers assigned to other rules or to no rule.
+func (r *Rule) limitApproversToPool(ctx context.Context, prctx pull.Context, approvers []*common.Candidate) ([]*common.Candidate, []*common.Candidate, error) {
+	log := zerolog.Ctx(ctx)
+
+	assigned, err := r.pool.assign(ctx, prctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var counted, pooled []*common.Candidate
+	for _, c := range approvers {
+		if assigned[r][c.User] {
+			counted = append(counted, c)
+		} else {
+			log.Debug().Str("user", c.User).Msgf("ignoring approval counted by another rule in approval pool %q", r.pool.name)
+			pooled = append(pooled, c)
+		}
+	}
+	return counted, pooled, nil
+}
+
+// predicatesSatisfied returns true if all of the predicates of the rule are
+// satisfied.
+func (r *Rule) predicatesSatisfied(ctx context.Context, prctx pull.Context) (bool, error) {
+	for _, p := range r.Predicates.Predicates() {
+		result, err := p.Evaluate(ctx, prctx)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to evaluate predicate for rule %q", r.Name)
+		}
+		if !result.Satisfied {
+			return false, nil
+		}
+	}
+	return true, nil
+}
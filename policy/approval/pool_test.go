@@ -0,0 +1,226 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approval
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/policy/predicate"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/palantir/policy-bot/pull/pulltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestApprovalPool(t *testing.T) {
+	now := time.Now()
+	prctx := func(approvers ...string) *pulltest.Context {
+		ctx := &pulltest.Context{
+			AuthorValue: "mhaypenny",
+			ChangedFilesValue: []*pull.File{
+				{Filename: "server/handler.go", Status: pull.FileModified},
+			},
+			OrgMemberships: map[string][]string{
+				"alice": {"everyone", "security"},
+				"bob":   {"everyone"},
+				"carol": {"everyone", "security"},
+			},
+		}
+		for i, user := range approvers {
+			ctx.ReviewsValue = append(ctx.ReviewsValue, &pull.Review{
+				CreatedAt: now.Add(time.Duration(i) * time.Minute),
+				Author:    user,
+				State:     pull.ReviewApproved,
+			})
+		}
+		return ctx
+	}
+
+	newRules := func(pool string) []*Rule {
+		return []*Rule{
+			{
+				Name:     "review",
+				Options:  Options{ApprovalPool: pool},
+				Requires: Requires{Count: 1, Actors: common.Actors{Organizations: []string{"everyone"}}},
+			},
+			{
+				Name:     "security",
+				Options:  Options{ApprovalPool: pool},
+				Requires: Requires{Count: 1, Actors: common.Actors{Organizations: []string{"security"}}},
+			},
+			{
+				Name:     "docs",
+				Options:  Options{ApprovalPool: pool},
+				Requires: Requires{Count: 1, Actors: common.Actors{Organizations: []string{"everyone"}}},
+			},
+		}
+	}
+
+	parseRules := func(t *testing.T, rules []*Rule) common.Evaluator {
+		rulesByName := make(map[string]*Rule)
+		for _, r := range rules {
+			rulesByName[r.Name] = r
+		}
+
+		var policy Policy
+		require.NoError(t, yaml.UnmarshalStrict([]byte("[review, security]"), &policy))
+
+		eval, err := policy.Parse(rulesByName)
+		require.NoError(t, err)
+		return eval
+	}
+
+	parse := func(t *testing.T, pool string, predicates ...predicate.Predicates) common.Evaluator {
+		rules := newRules(pool)
+		for i, p := range predicates {
+			rules[i].Predicates = p
+		}
+		return parseRules(t, rules)
+	}
+
+	statuses := func(res common.Result) map[string]common.EvaluationStatus {
+		s := make(map[string]common.EvaluationStatus)
+		for _, c := range res.Children {
+			s[c.Name] = c.Status
+		}
+		return s
+	}
+
+	t.Run("independent", func(t *testing.T) {
+		res := parse(t, "").Evaluate(context.Background(), prctx("alice"))
+		require.NoError(t, res.Error)
+
+		assert.Equal(t, common.StatusApproved, res.Status)
+		assert.Equal(t, map[string]common.EvaluationStatus{
+			"review":   common.StatusApproved,
+			"security": common.StatusApproved,
+		}, statuses(res))
+	})
+
+	t.Run("sharedSingleApproval", func(t *testing.T) {
+		res := parse(t, "gates").Evaluate(context.Background(), prctx("alice"))
+		require.NoError(t, res.Error)
+
+		assert.Equal(t, common.StatusPending, res.Status)
+		assert.Equal(t, map[string]common.EvaluationStatus{
+			"review":   common.StatusApproved,
+			"security": common.StatusPending,
+		}, statuses(res))
+
+		security := res.Children[1]
+		assert.Equal(t, "0/1 required approvals. Ignored 1 approval counted by other rules in the same approval pool", security.StatusDescription)
+	})
+
+	t.Run("sharedRoundRobin", func(t *testing.T) {
+		res := parse(t, "gates").Evaluate(context.Background(), prctx("alice", "carol"))
+		require.NoError(t, res.Error)
+
+		assert.Equal(t, common.StatusApproved, res.Status)
+		assert.Equal(t, "Approved by alice", res.Children[0].StatusDescription)
+		assert.Equal(t, "Approved by carol", res.Children[1].StatusDescription)
+	})
+
+	t.Run("sharedConcurrent", func(t *testing.T) {
+		// rules in a pool read each other while evaluating in parallel
+		ctx := WithRuleConcurrency(context.Background(), 4)
+
+		res := parse(t, "gates").Evaluate(ctx, prctx("alice", "carol"))
+		require.NoError(t, res.Error)
+
+		assert.Equal(t, common.StatusApproved, res.Status)
+		assert.Equal(t, "Approved by alice", res.Children[0].StatusDescription)
+		assert.Equal(t, "Approved by carol", res.Children[1].StatusDescription)
+	})
+
+	t.Run("sharedSkipsIneligibleRules", func(t *testing.T) {
+		// bob cannot approve the security rule, so alice's later approval
+		// goes to the security rule even though it is not next in order
+		res := parse(t, "gates").Evaluate(context.Background(), prctx("bob", "alice"))
+		require.NoError(t, res.Error)
+
+		assert.Equal(t, common.StatusApproved, res.Status)
+		assert.Equal(t, "Approved by bob", res.Children[0].StatusDescription)
+		assert.Equal(t, "Approved by alice", res.Children[1].StatusDescription)
+
+		// without alice, bob only counts for the review rule
+		res = parse(t, "gates").Evaluate(context.Background(), prctx("bob"))
+		require.NoError(t, res.Error)
+
+		assert.Equal(t, map[string]common.EvaluationStatus{
+			"review":   common.StatusApproved,
+			"security": common.StatusPending,
+		}, statuses(res))
+	})
+
+	t.Run("sharedSkipsRulesWithUnsatisfiedPredicates", func(t *testing.T) {
+		// the review rule does not apply, so it does not take alice's approval
+		res := parse(t, "gates", predicate.Predicates{
+			ChangedFiles: &predicate.ChangedFiles{
+				Paths: []common.Regexp{common.NewCompiledRegexp(regexp.MustCompile("^docs/"))},
+			},
+		}).Evaluate(context.Background(), prctx("alice"))
+		require.NoError(t, res.Error)
+
+		assert.Equal(t, common.StatusApproved, res.Status)
+		assert.Equal(t, map[string]common.EvaluationStatus{
+			"review":   common.StatusSkipped,
+			"security": common.StatusApproved,
+		}, statuses(res))
+	})
+
+	t.Run("sharedIgnoresUnreferencedRules", func(t *testing.T) {
+		// the "docs" rule is in the pool but not in the policy, so it never
+		// receives approvals
+		eval := parse(t, "gates").(*evaluator)
+		rules := referencedRules(eval.root, nil)
+
+		require.Len(t, rules, 2)
+		assert.Equal(t, []*Rule{rules[0], rules[1]}, rules[0].pool.rules)
+	})
+	t.Run("sharedSkipsApproversWithoutPermission", func(t *testing.T) {
+		// alice cannot approve the review rule because of her permission, so
+		// her approval goes to the security rule and carol's to the review rule
+		rules := newRules("gates")
+		rules[0].Requires.MinPermission = pull.PermissionWrite
+
+		ctx := prctx("alice", "carol")
+		ctx.CollaboratorsValue = []*pull.Collaborator{
+			{Name: "alice", Permissions: []pull.CollaboratorPermission{{Permission: pull.PermissionRead}}},
+			{Name: "carol", Permissions: []pull.CollaboratorPermission{{Permission: pull.PermissionWrite}}},
+		}
+
+		res := parseRules(t, rules).Evaluate(context.Background(), ctx)
+		require.NoError(t, res.Error)
+
+		assert.Equal(t, common.StatusApproved, res.Status)
+		assert.Equal(t, "Approved by carol", res.Children[0].StatusDescription)
+		assert.Equal(t, "Approved by alice", res.Children[1].StatusDescription)
+	})
+
+	t.Run("sharedRulesNotModified", func(t *testing.T) {
+		// pools are linked on copies of the rules, which belong to the config
+		rules := newRules("gates")
+		parseRules(t, rules)
+
+		for _, r := range rules {
+			assert.Nil(t, r.pool, "rule %q in the config was modified", r.Name)
+		}
+	})
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package breakglass
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// Policy allows authorized users to override the result of policy evaluation
+// in an emergency. An override applies to the head commit of the pull request
+// at the time it was made and is cleared by any later push.
+type Policy struct {
+	Options  Options  `yaml:"options"`
+	Requires Requires `yaml:"requires"`
+}
+
+type Options struct {
+	Methods *common.Methods `yaml:"methods"`
+}
+
+func (opts *Options) GetMethods() *common.Methods {
+	// Fill defaults in a copy because policies may be evaluated concurrently
+	var methods common.Methods
+	if opts.Methods != nil {
+		methods = *opts.Methods
+	} else {
+		methods.Comments = []string{
+			"/break-glass",
+		}
+	}
+
+	methods.GithubReviewState = pull.ReviewApproved
+	return &methods
+}
+
+type Requires struct {
+	common.Actors `yaml:",inline"`
+}
+
+func (p *Policy) Trigger() common.Trigger {
+	t := common.TriggerCommit
+
+	if !p.Requires.IsEmpty() {
+		m := p.Options.GetMethods()
+		if len(m.Comments) > 0 || len(m.CommentPatterns) > 0 {
+			t |= common.TriggerComment
+		}
+		if len(m.BodyPatterns) > 0 {
+			t |= common.TriggerPullRequest
+		}
+		if m.GithubReview != nil && *m.GithubReview || len(m.GithubReviewCommentPatterns) > 0 {
+			t |= common.TriggerReview
+		}
+	}
+
+	return t
+}
+
+func (p *Policy) Evaluate(ctx context.Context, prctx pull.Context) (res common.Result) {
+	log := zerolog.Ctx(ctx)
+
+	res.Name = "break glass"
+	res.Status = common.StatusSkipped
+	res.Methods = p.Options.GetMethods()
+	res.Requires = common.RequiresResult{
+		Count:  1,
+		Actors: p.Requires.Actors,
+	}
+
+	if p.Requires.IsEmpty() {
+		log.Debug().Msg("no users are allowed to override the policy; skipping")
+
+		res.StatusDescription = "No users are allowed to override the policy"
+		return
+	}
+
+	override, err := p.lastOverride(ctx, prctx)
+	if err != nil {
+		res.Error = errors.WithMessage(err, "failed to compute override status")
+		return
+	}
+
+	if override == nil {
+		res.StatusDescription = "No overrides for the current commit"
+		return
+	}
+
+	res.Status = common.StatusApproved
+	res.StatusDescription = fmt.Sprintf("Break-glass override by %s", override.User)
+	res.Requires.Approvers = []*common.Candidate{override}
+	return
+}
+
+// lastOverride returns the most recent valid override for the head commit of
+// the pull request, or nil if there is none.
+func (p *Policy) lastOverride(ctx context.Context, prctx pull.Context) (*common.Candidate, error) {
+	log := zerolog.Ctx(ctx)
+
+	candidates, err := p.Options.GetMethods().Candidates(ctx, prctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	pushedAt, err := prctx.PushedAt(prctx.HeadSHA())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get last push timestamp")
+	}
+
+	var overrides []*common.Candidate
+	for _, c := range candidates {
+		// Edited overrides are never valid, because the edit could have been
+		// made by a different user than the one who created the override
+		if !c.LastEditedAt.IsZero() {
+			log.Debug().Str("user", c.User).Msg("ignoring edited override")
+			continue
+		}
+
+		if !c.CreatedAt.After(pushedAt) {
+			log.Debug().Str("user", c.User).Msgf("ignoring override from before the push of %.7s", prctx.HeadSHA())
+			continue
+		}
+
+		ok, err := p.Requires.IsActor(ctx, prctx, c.User)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to check candidate status")
+		}
+		if !ok {
+			log.Debug().Str("user", c.User).Msg("ignoring override by unauthorized user")
+			continue
+		}
+
+		overrides = append(overrides, c)
+	}
+
+	if len(overrides) == 0 {
+		return nil, nil
+	}
+
+	sort.Stable(common.CandidatesByCreationTime(overrides))
+	return overrides[len(overrides)-1], nil
+}
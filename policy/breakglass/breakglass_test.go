@@ -0,0 +1,176 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package breakglass
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/palantir/policy-bot/pull/pulltest"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluate(t *testing.T) {
+	logger := zerolog.New(os.Stdout)
+	ctx := logger.WithContext(context.Background())
+
+	prctx := &pulltest.Context{
+		HeadSHAValue: "abcdef123456",
+		PushedAtValue: map[string]time.Time{
+			"abcdef123456": date(2),
+		},
+		CommentsValue: []*pull.Comment{
+			{
+				Author:    "emergency-1",
+				Body:      "/break-glass",
+				CreatedAt: date(1),
+			},
+			{
+				Author:    "emergency-2",
+				Body:      "/break-glass",
+				CreatedAt: date(3),
+			},
+			{
+				Author:       "emergency-3",
+				Body:         "/break-glass",
+				CreatedAt:    date(4),
+				LastEditedAt: date(5),
+			},
+			{
+				Author:    "contributor",
+				Body:      "/break-glass",
+				CreatedAt: date(6),
+			},
+		},
+		ReviewsValue: []*pull.Review{
+			{
+				Author:    "emergency-4",
+				State:     pull.ReviewApproved,
+				Body:      "/break-glass",
+				CreatedAt: date(7),
+			},
+		},
+	}
+
+	assertOverridden := func(t *testing.T, p *Policy, expectedUser string) {
+		res := p.Evaluate(ctx, prctx)
+
+		require.NoError(t, res.Error)
+
+		if assert.Equal(t, common.StatusApproved, res.Status, "pull request was not overridden") {
+			assert.Equal(t, "Break-glass override by "+expectedUser, res.StatusDescription)
+			if assert.Len(t, res.Requires.Approvers, 1) {
+				assert.Equal(t, expectedUser, res.Requires.Approvers[0].User)
+			}
+		}
+	}
+
+	assertSkipped := func(t *testing.T, p *Policy, expected string) {
+		res := p.Evaluate(ctx, prctx)
+
+		require.NoError(t, res.Error)
+
+		if assert.Equal(t, common.StatusSkipped, res.Status, "pull request was incorrectly overridden") {
+			assert.Equal(t, expected, res.StatusDescription)
+		}
+	}
+
+	t.Run("skippedWithNoRequires", func(t *testing.T) {
+		p := &Policy{}
+		assertSkipped(t, p, "No users are allowed to override the policy")
+	})
+
+	t.Run("authorizedUserOverrides", func(t *testing.T) {
+		p := &Policy{}
+		p.Requires.Users = []string{"emergency-2"}
+
+		assertOverridden(t, p, "emergency-2")
+	})
+
+	t.Run("overrideBeforePushIsIgnored", func(t *testing.T) {
+		p := &Policy{}
+		p.Requires.Users = []string{"emergency-1"}
+
+		assertSkipped(t, p, "No overrides for the current commit")
+	})
+
+	t.Run("editedOverrideIsIgnored", func(t *testing.T) {
+		p := &Policy{}
+		p.Requires.Users = []string{"emergency-3"}
+
+		assertSkipped(t, p, "No overrides for the current commit")
+	})
+
+	t.Run("unauthorizedUserIsIgnored", func(t *testing.T) {
+		p := &Policy{}
+		p.Requires.Users = []string{"emergency-5"}
+
+		assertSkipped(t, p, "No overrides for the current commit")
+	})
+
+	t.Run("latestOverrideWins", func(t *testing.T) {
+		p := &Policy{}
+		p.Requires.Users = []string{"emergency-1", "emergency-2", "emergency-3", "contributor"}
+
+		assertOverridden(t, p, "contributor")
+	})
+
+	t.Run("reviewsIgnoredByDefault", func(t *testing.T) {
+		p := &Policy{}
+		p.Requires.Users = []string{"emergency-4"}
+
+		assertSkipped(t, p, "No overrides for the current commit")
+	})
+
+	t.Run("customMethods", func(t *testing.T) {
+		githubReview := true
+
+		p := &Policy{}
+		p.Requires.Users = []string{"emergency-2", "emergency-4"}
+		p.Options.Methods = &common.Methods{
+			GithubReviewCommentPatterns: []common.Regexp{
+				common.NewCompiledRegexp(regexp.MustCompile(`^/break-glass`)),
+			},
+			GithubReview: &githubReview,
+		}
+
+		assertOverridden(t, p, "emergency-4")
+	})
+}
+
+func TestTrigger(t *testing.T) {
+	t.Run("noRequires", func(t *testing.T) {
+		p := &Policy{}
+		assert.Equal(t, common.TriggerCommit, p.Trigger())
+	})
+
+	t.Run("defaultMethods", func(t *testing.T) {
+		p := &Policy{}
+		p.Requires.Users = []string{"emergency-1"}
+
+		assert.Equal(t, common.TriggerCommit|common.TriggerComment, p.Trigger())
+	})
+}
+
+func date(hour int) time.Time {
+	return time.Date(2018, 6, 29, hour, 0, 0, 0, time.UTC)
+}
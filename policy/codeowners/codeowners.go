@@ -0,0 +1,167 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package codeowners parses GitHub CODEOWNERS files and finds the owners of
+// paths in a repository.
+package codeowners
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/palantir/policy-bot/pull"
+	"github.com/pkg/errors"
+)
+
+// Locations are the paths where GitHub looks for a CODEOWNERS file, in order
+// of precedence.
+var Locations = []string{
+	".github/CODEOWNERS",
+	"CODEOWNERS",
+	"docs/CODEOWNERS",
+}
+
+// File is a parsed CODEOWNERS file.
+type File struct {
+	rules []rule
+}
+
+type rule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+// Load parses the CODEOWNERS file on the base branch of the pull request. It
+// returns nil if the repository does not have a CODEOWNERS file.
+func Load(prctx pull.Context) (*File, error) {
+	for _, path := range Locations {
+		content, exists, err := prctx.BaseFileContent(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get content of %s", path)
+		}
+		if exists {
+			f, err := Parse(content)
+			return f, errors.Wrapf(err, "failed to parse %s", path)
+		}
+	}
+	return nil, nil
+}
+
+// Parse parses the content of a CODEOWNERS file.
+func Parse(content string) (*File, error) {
+	var f File
+	for i, line := range strings.Split(content, "\n") {
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		pattern, err := compilePattern(fields[0])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid pattern on line %d", i+1)
+		}
+
+		var owners []string
+		for _, owner := range fields[1:] {
+			owners = append(owners, strings.TrimPrefix(owner, "@"))
+		}
+		f.rules = append(f.rules, rule{pattern: pattern, owners: owners})
+	}
+	return &f, nil
+}
+
+// Owners returns the owners of path. Users are returned as logins, teams as
+// "org/team-slug", and email addresses are returned unchanged. As in GitHub,
+// the last matching rule takes precedence, so the result is empty if the last
+// matching rule has no owners.
+func (f *File) Owners(path string) []string {
+	for i := len(f.rules) - 1; i >= 0; i-- {
+		if f.rules[i].pattern.MatchString(path) {
+			return f.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// TeamOwners returns the teams that own path.
+func (f *File) TeamOwners(path string) []string {
+	var teams []string
+	for _, owner := range f.Owners(path) {
+		if IsTeam(owner) {
+			teams = append(teams, owner)
+		}
+	}
+	return teams
+}
+
+// IsTeam returns true if owner is a team in the "org/team-slug" format.
+func IsTeam(owner string) bool {
+	return strings.Contains(owner, "/")
+}
+
+// compilePattern converts a gitignore-style pattern to a regular expression
+// that matches the paths covered by the pattern, including all paths in
+// directories matched by the pattern.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if strings.HasPrefix(pattern, "!") || strings.Contains(pattern, "[") || strings.HasPrefix(pattern, `\`) {
+		return nil, errors.Errorf("unsupported pattern syntax: %q", pattern)
+	}
+
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	// patterns containing a slash are relative to the repository root,
+	// otherwise they match at any depth
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	if !anchored {
+		expr.WriteString("(?:.*/)?")
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case c == '*':
+			expr.WriteString("[^/]*")
+		case c == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	switch {
+	case dirOnly:
+		expr.WriteString("/.*$")
+	case strings.HasSuffix(pattern, "/*"):
+		// like GitHub, a trailing "/*" only matches files in the directory
+		// itself and not files in its subdirectories
+		expr.WriteString("$")
+	default:
+		expr.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(expr.String())
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codeowners
+
+import (
+	"testing"
+
+	"github.com/palantir/policy-bot/pull/pulltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testFile = `
+# default owners
+*                @palantir/devtools
+
+*.go             @palantir/go-reviewers mhaypenny
+/docs/           @palantir/docs # inline comment
+docs/*.md        @palantir/writers
+apps/            @palantir/apps
+/build/logs/     @palantir/build
+**/config/*.yml  @palantir/config
+scripts/**/test  @palantir/test
+/vendor/
+`
+
+func TestOwners(t *testing.T) {
+	f, err := Parse(testFile)
+	require.NoError(t, err)
+
+	tests := map[string][]string{
+		"README.md":                     {"palantir/devtools"},
+		"main.go":                       {"palantir/go-reviewers", "mhaypenny"},
+		"pull/github.go":                {"palantir/go-reviewers", "mhaypenny"},
+		"docs/install.txt":              {"palantir/docs"},
+		"docs/guide/install.txt":        {"palantir/docs"},
+		"docs/README.md":                {"palantir/writers"},
+		"docs/guide/README.md":          {"palantir/docs"},
+		"other/docs/install.txt":        {"palantir/devtools"},
+		"apps/server/main.js":           {"palantir/apps"},
+		"web/apps/server/main.js":       {"palantir/apps"},
+		"build/logs/out.log":            {"palantir/build"},
+		"src/build/logs/out.log":        {"palantir/devtools"},
+		"config/app.yml":                {"palantir/config"},
+		"server/config/app.yml":         {"palantir/config"},
+		"server/config/nested/app.yml":  {"palantir/devtools"},
+		"scripts/test":                  {"palantir/test"},
+		"scripts/a/b/test/run.sh":       {"palantir/test"},
+		"vendor/github.com/pkg/a.go":    nil,
+		"vendored/github.com/pkg/a.txt": {"palantir/devtools"},
+	}
+
+	for path, expected := range tests {
+		assert.Equal(t, expected, f.Owners(path), "incorrect owners for %s", path)
+	}
+}
+
+func TestTeamOwners(t *testing.T) {
+	f, err := Parse(testFile)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"palantir/go-reviewers"}, f.TeamOwners("main.go"))
+	assert.Nil(t, f.TeamOwners("vendor/a.go"))
+}
+
+func TestOwnersPrecedence(t *testing.T) {
+	f, err := Parse(`
+*.go       @palantir/go-reviewers dev@example.com
+server/    mhaypenny @palantir/server
+server/*.go
+`)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"palantir/go-reviewers", "dev@example.com"}, f.Owners("main.go"))
+	assert.Equal(t, []string{"mhaypenny", "palantir/server"}, f.Owners("server/views/index.tmpl"))
+	assert.Empty(t, f.Owners("server/main.go"), "the last matching rule has no owners")
+	assert.Equal(t, []string{"palantir/server"}, f.TeamOwners("server/handler/eval.go"))
+}
+
+func TestParseInvalid(t *testing.T) {
+	_, err := Parse("!*.go @palantir/devtools")
+	assert.Error(t, err, "expected error for negated pattern")
+
+	_, err = Parse("*.[ch] @palantir/devtools")
+	assert.Error(t, err, "expected error for character range")
+}
+
+func TestLoad(t *testing.T) {
+	prctx := &pulltest.Context{
+		BaseFilesValue: map[string]string{
+			"CODEOWNERS":      "* @palantir/root",
+			"docs/CODEOWNERS": "* @palantir/docs",
+		},
+	}
+
+	f, err := Load(prctx)
+	require.NoError(t, err)
+	require.NotNil(t, f)
+	assert.Equal(t, []string{"palantir/root"}, f.Owners("main.go"))
+
+	f, err = Load(&pulltest.Context{})
+	require.NoError(t, err)
+	assert.Nil(t, f)
+
+	_, err = Load(&pulltest.Context{BaseFilesError: assert.AnError})
+	assert.Error(t, err)
+}
@@ -17,6 +17,7 @@ package common
 import (
 	"context"
 	"sort"
+	"strings"
 
 	"github.com/palantir/policy-bot/pull"
 	"github.com/pkg/errors"
@@ -26,6 +27,9 @@ import (
 // team and organization memberships. The set of allowed actors is the union of
 // all conditions in this structure.
 type Actors struct {
+	// A list of users that are allowed. Entries containing "*" or "?" are
+	// glob patterns, where "*" matches any sequence of characters and "?"
+	// matches any single character. Other entries must match exactly.
 	Users         []string `yaml:"users" json:"users"`
 	Teams         []string `yaml:"teams" json:"teams"`
 	Organizations []string `yaml:"organizations" json:"organizations"`
@@ -37,12 +41,126 @@ type Actors struct {
 	// A list of GitHub collaborator permissions that are allowed. Values may
 	// be any of "admin", "maintain", "write", "triage", and "read".
 	Permissions []pull.Permission `yaml:"permissions" json:"permissions"`
+
+	// A list of other repositories, in "owner/name" format. Users with write
+	// or higher permission on any of these repositories are allowed.
+	WriteCollaboratorsOf []string `yaml:"write_collaborators_of" json:"write_collaborators_of"`
+
+	// A list of GitHub App bot accounts that are allowed, with or without
+	// the "[bot]" suffix. Approvals from bots are ignored unless the bot is
+	// listed here.
+	Bots []string `yaml:"bots" json:"bots"`
+
+	// Except lists users who are not allowed even if they satisfy one of the
+	// other conditions. Exclusions always take precedence over inclusions.
+	Except *ExcludedActors `yaml:"except" json:"except,omitempty"`
+}
+
+// ExcludedActors specifies users who are excluded from a set of Actors based
+// on their username or team and organization memberships.
+type ExcludedActors struct {
+	// A list of excluded users. Entries support the same patterns as
+	// Actors.Users.
+	Users         []string `yaml:"users" json:"users"`
+	Teams         []string `yaml:"teams" json:"teams"`
+	Organizations []string `yaml:"organizations" json:"organizations"`
+}
+
+// IsExcluded returns true if the given user satisfies at least one of the
+// conditions in this structure.
+func (e *ExcludedActors) IsExcluded(prctx pull.Context, user string) (bool, error) {
+	if e == nil {
+		return false, nil
+	}
+
+	for _, u := range e.Users {
+		if MatchesUser(u, user) {
+			return true, nil
+		}
+	}
+
+	for _, t := range e.Teams {
+		member, err := prctx.IsTeamMember(t, user)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to get team membership")
+		}
+		if member {
+			return true, nil
+		}
+	}
+
+	for _, o := range e.Organizations {
+		member, err := prctx.IsOrgMember(o, user)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to get org membership")
+		}
+		if member {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// IsUserPattern returns true if the entry is a glob pattern instead of a
+// literal user name.
+func IsUserPattern(entry string) bool {
+	return strings.ContainsAny(entry, "*?")
+}
+
+// MatchesUser returns true if the user matches an entry in a list of users.
+// See Actors.Users for the supported patterns.
+func MatchesUser(entry, user string) bool {
+	if !IsUserPattern(entry) {
+		return entry == user
+	}
+
+	// match the pattern greedily, backtracking to the most recent "*" when a
+	// character does not match
+	var p, u int
+	star, next := -1, 0
+	for u < len(user) {
+		switch {
+		case p < len(entry) && (entry[p] == '?' || entry[p] == user[u]):
+			p++
+			u++
+		case p < len(entry) && entry[p] == '*':
+			star, next = p, u
+			p++
+		case star >= 0:
+			next++
+			p, u = star+1, next
+		default:
+			return false
+		}
+	}
+	for p < len(entry) && entry[p] == '*' {
+		p++
+	}
+	return p == len(entry)
+}
+
+// IsBot returns true if the user is a GitHub App bot account.
+func IsBot(user string) bool {
+	return strings.HasSuffix(user, "[bot]")
 }
 
 // IsEmpty returns true if no conditions for actors are defined.
 func (a *Actors) IsEmpty() bool {
 	return a == nil || (len(a.Users) == 0 && len(a.Teams) == 0 && len(a.Organizations) == 0 &&
-		len(a.Permissions) == 0 && !a.Admins && !a.WriteCollaborators)
+		len(a.Permissions) == 0 && len(a.WriteCollaboratorsOf) == 0 && len(a.Bots) == 0 && !a.Admins && !a.WriteCollaborators)
+}
+
+// IsAllowedBot returns true if the user is a bot listed in Bots.
+func (a *Actors) IsAllowedBot(user string) bool {
+	if !IsBot(user) {
+		return false
+	}
+	for _, b := range a.Bots {
+		if user == b || user == b+"[bot]" {
+			return true
+		}
+	}
+	return false
 }
 
 // GetPermissions returns unique permissions ordered from most to least
@@ -71,10 +189,28 @@ func (a *Actors) GetPermissions() []pull.Permission {
 }
 
 // IsActor returns true if the given user satisfies at least one of the
-// conditions in this structure.
+// conditions in this structure and is not excluded by Except. Exclusions are
+// only checked for users who satisfy a condition.
 func (a *Actors) IsActor(ctx context.Context, prctx pull.Context, user string) (bool, error) {
+	included, err := a.isIncluded(prctx, user)
+	if err != nil || !included {
+		return false, err
+	}
+
+	excluded, err := a.Except.IsExcluded(prctx, user)
+	if err != nil {
+		return false, err
+	}
+	return !excluded, nil
+}
+
+func (a *Actors) isIncluded(prctx pull.Context, user string) (bool, error) {
+	if a.IsAllowedBot(user) {
+		return true, nil
+	}
+
 	for _, u := range a.Users {
-		if user == u {
+		if MatchesUser(u, user) {
 			return true, nil
 		}
 	}
@@ -99,6 +235,21 @@ func (a *Actors) IsActor(ctx context.Context, prctx pull.Context, user string) (
 		}
 	}
 
+	for _, r := range a.WriteCollaboratorsOf {
+		owner, repo, ok := strings.Cut(r, "/")
+		if !ok {
+			return false, errors.Errorf("invalid repository %q: must be in owner/name format", r)
+		}
+
+		perm, err := prctx.CollaboratorPermissionOn(owner, repo, user)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to get repository permission")
+		}
+		if perm >= pull.PermissionWrite {
+			return true, nil
+		}
+	}
+
 	userPerm, err := prctx.CollaboratorPermission(user)
 	if err != nil {
 		return false, err
@@ -47,6 +47,12 @@ func TestIsActor(t *testing.T) {
 				},
 			},
 		},
+		OtherPermissions: map[string]map[string]pull.Permission{
+			"other-org/other-repo": {
+				"ttest":        pull.PermissionMaintain,
+				"jstrawnickel": pull.PermissionTriage,
+			},
+		},
 	}
 
 	assertActor := func(t *testing.T, a *Actors, user string) {
@@ -70,6 +76,72 @@ func TestIsActor(t *testing.T) {
 		assertNotActor(t, a, "ttest")
 	})
 
+	t.Run("userPatterns", func(t *testing.T) {
+		a := &Actors{
+			Users: []string{"svc-*", "bot-?", "mhaypenny"},
+		}
+
+		assertActor(t, a, "mhaypenny")
+		assertActor(t, a, "svc-deploy")
+		assertActor(t, a, "svc-")
+		assertActor(t, a, "bot-1")
+		assertNotActor(t, a, "bot-12")
+		assertNotActor(t, a, "my-svc-deploy")
+		assertNotActor(t, a, "ttest")
+	})
+
+	t.Run("userPatternsWithMemberships", func(t *testing.T) {
+		a := &Actors{
+			Users: []string{"svc-*"},
+			Teams: []string{"regular-org/team2"},
+		}
+
+		assertActor(t, a, "svc-deploy")
+		assertActor(t, a, "mhaypenny")
+		assertNotActor(t, a, "ttest")
+	})
+
+	t.Run("except", func(t *testing.T) {
+		a := &Actors{
+			Users:         []string{"ttest", "svc-deploy"},
+			Organizations: []string{"cool-org"},
+			Except: &ExcludedActors{
+				Users: []string{"svc-*"},
+				Teams: []string{"regular-org/team2"},
+			},
+		}
+
+		assertNotActor(t, a, "mhaypenny")
+		assertNotActor(t, a, "svc-deploy")
+		assertActor(t, a, "ttest")
+		assertNotActor(t, a, "jstrawnickel")
+	})
+
+	t.Run("exceptOrganization", func(t *testing.T) {
+		a := &Actors{
+			Teams: []string{"cool-org/team1"},
+			Except: &ExcludedActors{
+				Organizations: []string{"regular-org"},
+			},
+		}
+
+		assertNotActor(t, a, "mhaypenny")
+
+		a.Except.Organizations = []string{"other-org"}
+		assertActor(t, a, "mhaypenny")
+	})
+
+	t.Run("exceptWithoutInclusion", func(t *testing.T) {
+		a := &Actors{
+			Except: &ExcludedActors{
+				Users: []string{"ttest"},
+			},
+		}
+
+		assertNotActor(t, a, "mhaypenny")
+		assertNotActor(t, a, "ttest")
+	})
+
 	t.Run("teams", func(t *testing.T) {
 		a := &Actors{
 			Teams: []string{"regular-org/team2"},
@@ -113,6 +185,36 @@ func TestIsActor(t *testing.T) {
 		assertActor(t, a, "jstrawnickel")
 		assertNotActor(t, a, "ttest")
 	})
+
+	t.Run("writeCollaboratorsOf", func(t *testing.T) {
+		a := &Actors{
+			WriteCollaboratorsOf: []string{"other-org/other-repo"},
+		}
+
+		assertActor(t, a, "ttest")
+		assertNotActor(t, a, "jstrawnickel")
+		assertNotActor(t, a, "mhaypenny")
+	})
+
+	t.Run("writeCollaboratorsOfInvalid", func(t *testing.T) {
+		a := &Actors{
+			WriteCollaboratorsOf: []string{"other-repo"},
+		}
+
+		_, err := a.IsActor(ctx, prctx, "ttest")
+		assert.Error(t, err, "expected error for invalid repository")
+	})
+
+	t.Run("bots", func(t *testing.T) {
+		a := &Actors{
+			Bots: []string{"dependabot", "renovate[bot]"},
+		}
+
+		assertActor(t, a, "dependabot[bot]")
+		assertActor(t, a, "renovate[bot]")
+		assertNotActor(t, a, "dependabot")
+		assertNotActor(t, a, "github-actions[bot]")
+	})
 }
 
 func TestIsEmpty(t *testing.T) {
@@ -128,6 +230,40 @@ func TestIsEmpty(t *testing.T) {
 	a = &Actors{Organizations: []string{"org"}}
 	assert.False(t, a.IsEmpty(), "Actors struct was empty")
 
+	a = &Actors{WriteCollaboratorsOf: []string{"org/repo"}}
+	assert.False(t, a.IsEmpty(), "Actors struct was empty")
+
+	a = &Actors{Bots: []string{"dependabot"}}
+	assert.False(t, a.IsEmpty(), "Actors struct was empty")
+
 	a = nil
 	assert.True(t, a.IsEmpty(), "nil struct was not empty")
 }
+
+func TestMatchesUser(t *testing.T) {
+	tests := []struct {
+		Entry   string
+		User    string
+		Matches bool
+	}{
+		{"mhaypenny", "mhaypenny", true},
+		{"mhaypenny", "mhaypenny2", false},
+		{"dependabot[bot]", "dependabot[bot]", true},
+		{"dependabot[bot]", "dependabotb", false},
+		{"svc-*", "svc-deploy", true},
+		{"svc-*", "svc", false},
+		{"*-bot", "release-bot", true},
+		{"*-bot", "release-bot-2", false},
+		{"svc-*-prod", "svc-deploy-prod", true},
+		{"svc-*-prod", "svc-deploy-prod-prod", true},
+		{"svc-*-prod", "svc-deploy-staging", false},
+		{"user?", "user1", true},
+		{"user?", "user", false},
+		{"*", "anyone", true},
+		{"**", "", true},
+	}
+
+	for _, test := range tests {
+		assert.Equalf(t, test.Matches, MatchesUser(test.Entry, test.User), "incorrect result matching %q against %q", test.User, test.Entry)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/palantir/policy-bot/pull"
 )
@@ -29,6 +30,21 @@ type Methods struct {
 	GithubReviewCommentPatterns []Regexp `yaml:"github_review_comment_patterns,omitempty"`
 	BodyPatterns                []Regexp `yaml:"body_patterns,omitempty"`
 
+	// CommentsWholeLine requires strings in Comments to match an entire line
+	// of a comment, ignoring leading and trailing whitespace, instead of any
+	// substring of the comment. It does not affect CommentPatterns.
+	CommentsWholeLine bool `yaml:"comments_whole_line,omitempty"`
+
+	// CaseSensitive controls if strings in Comments must match the case of
+	// the comment. If nil, matching is case-sensitive. It does not affect
+	// CommentPatterns, which can use the "(?i)" flag instead.
+	CaseSensitive *bool `yaml:"case_sensitive,omitempty"`
+
+	// MinBodyLength is the minimum number of characters, ignoring leading and
+	// trailing whitespace, that a comment or review body must have to be
+	// considered a candidate.
+	MinBodyLength int `yaml:"min_body_length,omitempty"`
+
 	// If GithubReview is true, GithubReviewState is the state a review must
 	// have to be considered a candidated. It is currently excluded from
 	// serialized forms and should be set by the application.
@@ -72,7 +88,7 @@ func (m *Methods) Candidates(ctx context.Context, prctx pull.Context) ([]*Candid
 		}
 
 		for _, c := range comments {
-			if m.CommentMatches(c.Body) {
+			if m.CommentMatches(c.Body) && m.bodyLongEnough(c.Body) {
 				candidates = append(candidates, &Candidate{
 					Type:         CommentCandidate,
 					User:         c.Author,
@@ -104,7 +120,7 @@ func (m *Methods) Candidates(ctx context.Context, prctx pull.Context) ([]*Candid
 		}
 
 		for _, r := range reviews {
-			if r.State == m.GithubReviewState {
+			if r.State == m.GithubReviewState && m.bodyLongEnough(r.Body) {
 				if len(m.GithubReviewCommentPatterns) > 0 {
 					if m.githubReviewCommentMatches(r.Body) {
 						candidates = append(candidates, &Candidate{
@@ -149,8 +165,19 @@ func deduplicateCandidates(all []*Candidate) []*Candidate {
 }
 
 func (m *Methods) CommentMatches(commentBody string) bool {
+	body := commentBody
+	if !m.caseSensitive() {
+		body = strings.ToLower(body)
+	}
 	for _, comment := range m.Comments {
-		if strings.Contains(commentBody, comment) {
+		if !m.caseSensitive() {
+			comment = strings.ToLower(comment)
+		}
+		if m.CommentsWholeLine {
+			if containsLine(body, comment) {
+				return true
+			}
+		} else if strings.Contains(body, comment) {
 			return true
 		}
 	}
@@ -162,6 +189,22 @@ func (m *Methods) CommentMatches(commentBody string) bool {
 	return false
 }
 
+func (m *Methods) caseSensitive() bool {
+	return m.CaseSensitive == nil || *m.CaseSensitive
+}
+
+// containsLine returns true if any line of body is equal to s after removing
+// leading and trailing whitespace from both.
+func containsLine(body, s string) bool {
+	s = strings.TrimSpace(s)
+	for _, line := range strings.Split(body, "\n") {
+		if strings.TrimSpace(line) == s {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *Methods) githubReviewCommentMatches(commentBody string) bool {
 	for _, pattern := range m.GithubReviewCommentPatterns {
 		if pattern.Matches(commentBody) {
@@ -171,6 +214,10 @@ func (m *Methods) githubReviewCommentMatches(commentBody string) bool {
 	return false
 }
 
+func (m *Methods) bodyLongEnough(body string) bool {
+	return utf8.RuneCountInString(strings.TrimSpace(body)) >= m.MinBodyLength
+}
+
 func (m *Methods) BodyMatches(prBody string) bool {
 	for _, pattern := range m.BodyPatterns {
 		if pattern.Matches(prBody) {
@@ -101,6 +101,111 @@ func TestCandidates(t *testing.T) {
 		assert.Equal(t, "ttest", cs[1].User)
 	})
 
+	t.Run("commentsWholeLine", func(t *testing.T) {
+		prctx := &pulltest.Context{
+			CommentsValue: []*pull.Comment{
+				{
+					CreatedAt: now.Add(0 * time.Minute),
+					Body:      "Looks good to me :+1:",
+					Author:    "mhaypenny",
+				},
+				{
+					CreatedAt: now.Add(1 * time.Minute),
+					Body:      "> :+1:\r\nPlease don't do this yet",
+					Author:    "rrandom",
+				},
+				{
+					CreatedAt: now.Add(2 * time.Minute),
+					Body:      "`:+1:` don't do this",
+					Author:    "wstrawmoney",
+				},
+				{
+					CreatedAt: now.Add(3 * time.Minute),
+					Body:      "Thanks for the fix!\r\n  :+1:  \r\n",
+					Author:    "ttest",
+				},
+				{
+					CreatedAt: now.Add(4 * time.Minute),
+					Body:      ":lgtm:",
+					Author:    "santaclaus",
+				},
+			},
+		}
+
+		m := &Methods{
+			Comments:          []string{":+1:", ":lgtm:"},
+			CommentsWholeLine: true,
+		}
+
+		cs, err := m.Candidates(ctx, prctx)
+		require.NoError(t, err)
+
+		sort.Sort(CandidatesByCreationTime(cs))
+
+		require.Len(t, cs, 2, "incorrect number of candidates found")
+		assert.Equal(t, "ttest", cs[0].User)
+		assert.Equal(t, "santaclaus", cs[1].User)
+	})
+
+	t.Run("caseSensitive", func(t *testing.T) {
+		prctx := &pulltest.Context{
+			CommentsValue: []*pull.Comment{
+				{
+					CreatedAt: now.Add(0 * time.Minute),
+					Body:      "LGTM",
+					Author:    "mhaypenny",
+				},
+				{
+					CreatedAt: now.Add(1 * time.Minute),
+					Body:      "I think this is lgtm once the tests pass",
+					Author:    "rrandom",
+				},
+				{
+					CreatedAt: now.Add(2 * time.Minute),
+					Body:      "Lgtm",
+					Author:    "ttest",
+				},
+			},
+		}
+
+		sensitive := &Methods{
+			Comments: []string{"LGTM"},
+		}
+
+		cs, err := sensitive.Candidates(ctx, prctx)
+		require.NoError(t, err)
+
+		require.Len(t, cs, 1, "incorrect number of candidates found")
+		assert.Equal(t, "mhaypenny", cs[0].User)
+
+		caseSensitive := false
+		insensitive := &Methods{
+			Comments:      []string{"LGTM"},
+			CaseSensitive: &caseSensitive,
+		}
+
+		cs, err = insensitive.Candidates(ctx, prctx)
+		require.NoError(t, err)
+
+		sort.Sort(CandidatesByCreationTime(cs))
+
+		require.Len(t, cs, 3, "incorrect number of candidates found")
+		assert.Equal(t, "mhaypenny", cs[0].User)
+		assert.Equal(t, "rrandom", cs[1].User)
+		assert.Equal(t, "ttest", cs[2].User)
+
+		insensitive.CommentsWholeLine = true
+
+		cs, err = insensitive.Candidates(ctx, prctx)
+		require.NoError(t, err)
+
+		sort.Sort(CandidatesByCreationTime(cs))
+
+		require.Len(t, cs, 2, "incorrect number of candidates found")
+		assert.Equal(t, "mhaypenny", cs[0].User)
+		assert.Equal(t, "ttest", cs[1].User)
+	})
+
 	t.Run("commentPatterns", func(t *testing.T) {
 		m := &Methods{
 			CommentPatterns: []Regexp{
@@ -153,6 +258,56 @@ func TestCandidates(t *testing.T) {
 		assert.Equal(t, "mhaypenny", cs[0].User)
 	})
 
+	t.Run("minBodyLength", func(t *testing.T) {
+		githubReview := true
+		m := &Methods{
+			Comments:          []string{":+1:", ":lgtm:"},
+			GithubReview:      &githubReview,
+			GithubReviewState: pull.ReviewApproved,
+			MinBodyLength:     6,
+		}
+
+		cs, err := m.Candidates(ctx, prctx)
+		require.NoError(t, err)
+
+		sort.Sort(CandidatesByCreationTime(cs))
+
+		require.Len(t, cs, 2, "incorrect number of candidates found")
+		assert.Equal(t, "mhaypenny", cs[0].User)
+		assert.Equal(t, "ttest", cs[1].User)
+		assert.Equal(t, CommentCandidate, cs[1].Type)
+	})
+
+	t.Run("minBodyLengthIgnoresWhitespace", func(t *testing.T) {
+		githubReview := true
+		m := &Methods{
+			GithubReview:      &githubReview,
+			GithubReviewState: pull.ReviewApproved,
+			MinBodyLength:     4,
+		}
+
+		cs, err := m.Candidates(ctx, &pulltest.Context{
+			ReviewsValue: []*pull.Review{
+				{
+					CreatedAt: now,
+					Author:    "rrandom",
+					Body:      "  ok  ",
+					State:     pull.ReviewApproved,
+				},
+				{
+					CreatedAt: now,
+					Author:    "mhaypenny",
+					Body:      "ship it",
+					State:     pull.ReviewApproved,
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		require.Len(t, cs, 1, "incorrect number of candidates found")
+		assert.Equal(t, "mhaypenny", cs[0].User)
+	})
+
 	t.Run("deduplicate", func(t *testing.T) {
 		githubReview := true
 		m := &Methods{
@@ -14,6 +14,10 @@
 
 package common
 
+import (
+	"time"
+)
+
 type PredicateResult struct {
 	Satisfied bool
 
@@ -33,4 +37,10 @@ type PredicateResult struct {
 	// If non-empty, use the map, otherwise, use the regular list
 	ConditionsMap   map[string][]string
 	ConditionValues []string
+
+	// ReevaluateAt is the time at which the predicate may become satisfied or
+	// unsatisfied without any new activity on the pull request, for instance
+	// when the pull request reaches a configured age. It is zero if no such
+	// time exists.
+	ReevaluateAt time.Time
 }
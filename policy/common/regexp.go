@@ -60,6 +60,10 @@ func (r *Regexp) UnmarshalYAML(unmarshal func(interface{}) error) (err error) {
 	return err
 }
 
+func (r Regexp) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
 func (r *Regexp) UnmarshalJSON(data []byte) (err error) {
 	var pattern string
 	if err := json.Unmarshal(data, &pattern); err != nil {
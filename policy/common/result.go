@@ -15,6 +15,8 @@
 package common
 
 import (
+	"time"
+
 	"github.com/palantir/policy-bot/pull"
 )
 
@@ -44,9 +46,11 @@ func (s EvaluationStatus) String() string {
 type RequestMode string
 
 const (
-	RequestModeAllUsers    RequestMode = "all-users"
-	RequestModeRandomUsers RequestMode = "random-users"
-	RequestModeTeams       RequestMode = "teams"
+	RequestModeAllUsers         RequestMode = "all-users"
+	RequestModeRandomUsers      RequestMode = "random-users"
+	RequestModeLeastLoadedUsers RequestMode = "least-loaded-users"
+	RequestModeTeams            RequestMode = "teams"
+	RequestModeCodeOwners       RequestMode = "codeowners"
 )
 
 type ReviewRequestRule struct {
@@ -57,7 +61,24 @@ type ReviewRequestRule struct {
 	RequiredCount  int
 	RequestedCount int
 
+	// Except excludes users who would otherwise be selected for review
+	Except *ExcludedActors
+
 	Mode RequestMode
+
+	// PathReviewers are reviewers requested instead of the reviewers above
+	// when the pull request changes files that match specific paths.
+	PathReviewers []*PathReviewers
+}
+
+// PathReviewers is a set of reviewers for changes to files that match any of
+// the paths.
+type PathReviewers struct {
+	Paths         []Regexp
+	Teams         []string
+	Users         []string
+	Organizations []string
+	Permissions   []pull.Permission
 }
 
 type Result struct {
@@ -79,9 +100,39 @@ type Result struct {
 
 	ReviewRequestRule *ReviewRequestRule
 
+	// ReevaluateAt is the time at which the status of a result may change
+	// without any new activity on the pull request, for instance when an
+	// approval cool-off period ends or an approval expires. It is zero if no
+	// such time exists.
+	ReevaluateAt time.Time
+
+	// OverriddenBy is the user who forced this result to pass using a
+	// break-glass override. It is empty if the result was not overridden.
+	OverriddenBy string
+
 	Children []*Result
 }
 
+// ReevaluateBy sets ReevaluateAt to t if t is non-zero and is before the
+// current ReevaluateAt.
+func (r *Result) ReevaluateBy(t time.Time) {
+	if !t.IsZero() && (r.ReevaluateAt.IsZero() || t.Before(r.ReevaluateAt)) {
+		r.ReevaluateAt = t
+	}
+}
+
+// NextReevaluation returns the earliest non-zero ReevaluateAt time of any
+// result in the tree rooted at r, or the zero time if there is none.
+func (r *Result) NextReevaluation() time.Time {
+	next := r.ReevaluateAt
+	for _, c := range r.Children {
+		if t := c.NextReevaluation(); !t.IsZero() && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+	return next
+}
+
 type RequiresResult struct {
 	// Count is the number of required approvals from Actors
 	// Actors is the set of actors allowed to approve
@@ -90,11 +141,88 @@ type RequiresResult struct {
 	Actors    Actors
 	Approvers []*Candidate
 
+	// CountLabels contains the labels that set Count, if Count was increased
+	// because of the labels of the pull request
+	CountLabels []string
+
+	// CountSize describes the modified lines condition that set Count, if
+	// Count was increased because of the size of the pull request
+	CountSize string
+
+	// PooledApprovers contains approvers who are allowed to approve but did
+	// not count because their approval counted toward another rule in the
+	// same approval pool
+	PooledApprovers []*Candidate
+
+	// ExcessApprovers contains approvers who are allowed to approve but did
+	// not count because of a limit on approvals from each organization
+	ExcessApprovers []*Candidate
+
+	// SameTeamApprovers contains approvers who are allowed to approve but did
+	// not count because another approver already represented their team
+	SameTeamApprovers []*Candidate
+
+	// AuthorTeamApprovers contains approvers who are allowed to approve but
+	// did not count because they are members of one of the author's teams
+	AuthorTeamApprovers []*Candidate
+
+	// LowPermissionApprovers contains approvers who are allowed to approve
+	// but did not count because their permission on the repository is lower
+	// than the minimum permission
+	LowPermissionApprovers []*Candidate
+
+	// ApproverTeams maps approvers to the team they represent when approvals
+	// must come from distinct teams
+	ApproverTeams map[string]string
+
+	// RequiresIndependentApproval is true if Count was increased because all
+	// approvers contributed commits to the pull request
+	RequiresIndependentApproval bool
+
 	// Conditions contains the results of all required conditions
 	Conditions []*PredicateResult
+
+	// TeamCounts contains the results of all per-team approval requirements,
+	// sorted by team name
+	TeamCounts []*TeamCountResult
+
+	// UserApprovals contains the status of each user who must individually
+	// approve, in the order the users are configured
+	UserApprovals []*UserApprovalResult
+}
+
+// TeamCountResult is the result of requiring a number of approvals from the
+// members of a specific team.
+type TeamCountResult struct {
+	Team      string
+	Count     int
+	Approvers []*Candidate
+
+	// OwnedPaths contains the changed files owned by the team according to
+	// the CODEOWNERS file, if the team is required because it owns files
+	OwnedPaths []string
+}
+
+func (r *TeamCountResult) IsApproved() bool {
+	return len(r.Approvers) >= r.Count
+}
+
+// UserApprovalResult is the result of requiring approval from a specific
+// user. Approval is nil if the user has not approved.
+type UserApprovalResult struct {
+	User     string
+	Approval *Candidate
 }
 
 type Dismissal struct {
 	Candidate *Candidate
 	Reason    string
+
+	// Commit is the SHA of the commit whose push invalidated the candidate, if
+	// the candidate was dismissed because of a push.
+	Commit string
+
+	// Files are the names of the files changed by Commit. Evaluation does not
+	// set this field; it is loaded on demand when rendering the details page.
+	Files []string
 }
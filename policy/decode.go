@@ -0,0 +1,98 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	FormatYAML = "YAML"
+	FormatJSON = "JSON"
+)
+
+// DetectFormat returns the format of a policy file. Files with a ".json"
+// extension are always JSON. Other files are JSON if the content is a valid
+// JSON object and YAML otherwise.
+func DetectFormat(filePath string, content []byte) string {
+	if strings.EqualFold(path.Ext(filePath), ".json") {
+		return FormatJSON
+	}
+	if trimmed := bytes.TrimSpace(content); len(trimmed) > 0 && trimmed[0] == '{' && json.Valid(trimmed) {
+		return FormatJSON
+	}
+	return FormatYAML
+}
+
+// UnmarshalConfig parses a policy file in either YAML or JSON format, using
+// DetectFormat to choose the format. Unknown fields are an error in both
+// formats.
+func UnmarshalConfig(filePath string, content []byte) (*Config, error) {
+	format := DetectFormat(filePath, content)
+
+	if format == FormatJSON {
+		var v interface{}
+		if err := json.Unmarshal(content, &v); err != nil {
+			return nil, errors.Wrap(err, "invalid JSON policy")
+		}
+		if _, ok := v.(map[string]interface{}); !ok {
+			return nil, errors.New("invalid JSON policy: must be an object")
+		}
+
+		// Convert the decoded JSON to YAML so that both formats share the
+		// custom unmarshaling logic of the policy types. Decoding the JSON
+		// directly with the YAML decoder fails for valid JSON that is not
+		// valid YAML, like strings containing the "\/" escape.
+		b, err := yaml.Marshal(jsonToYAML(v))
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid JSON policy")
+		}
+		content = b
+	}
+
+	var c Config
+	if err := yaml.UnmarshalStrict(content, &c); err != nil {
+		return nil, errors.Wrapf(err, "invalid %s policy", format)
+	}
+	return &c, nil
+}
+
+// jsonToYAML prepares a value decoded by encoding/json for encoding as YAML.
+// Numbers that are integers are converted from float64 to int so that they
+// can be decoded into integer fields.
+func jsonToYAML(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = jsonToYAML(e)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = jsonToYAML(e)
+		}
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return int64(v)
+		}
+	}
+	return v
+}
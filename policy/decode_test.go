@@ -0,0 +1,172 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPolicyYAML = `
+# the same policy as testPolicyJSON
+policy:
+  approval:
+    - or:
+      - docs only
+      - two approvals
+  disapproval:
+    requires:
+      teams: ["org/security"]
+approval_rules:
+  - name: docs only
+    if:
+      only_changed_files:
+        paths:
+          - "^docs/.*\\.md$"
+  - name: two approvals
+    options:
+      invalidate_on_push: true
+      cool_off: 30m
+    requires:
+      count: 2
+      organizations: ["org"]
+options:
+  disable_push_batching: true
+`
+
+const testPolicyJSON = `{
+	"policy": {
+		"approval": [
+			{"or": ["docs only", "two approvals"]}
+		],
+		"disapproval": {
+			"requires": {"teams": ["org/security"]}
+		}
+	},
+	"approval_rules": [
+		{
+			"name": "docs only",
+			"if": {
+				"only_changed_files": {
+					"paths": ["^docs/.*\\.md$"]
+				}
+			}
+		},
+		{
+			"name": "two approvals",
+			"options": {"invalidate_on_push": true, "cool_off": "30m"},
+			"requires": {"count": 2, "organizations": ["org"]}
+		}
+	],
+	"options": {"disable_push_batching": true}
+}`
+
+func TestDetectFormat(t *testing.T) {
+	assert.Equal(t, FormatYAML, DetectFormat(".policy.yml", []byte(testPolicyYAML)))
+	assert.Equal(t, FormatJSON, DetectFormat(".policy.yml", []byte(testPolicyJSON)))
+	assert.Equal(t, FormatJSON, DetectFormat(".policy.json", []byte(testPolicyJSON)))
+	assert.Equal(t, FormatJSON, DetectFormat(".policy.JSON", []byte(testPolicyYAML)))
+	assert.Equal(t, FormatYAML, DetectFormat("", []byte(`{policy: {approval: [rule]}}`)))
+	assert.Equal(t, FormatYAML, DetectFormat("", nil))
+}
+
+func TestUnmarshalConfig(t *testing.T) {
+	t.Run("roundTrip", func(t *testing.T) {
+		fromYAML, err := UnmarshalConfig(".policy.yml", []byte(testPolicyYAML))
+		require.NoError(t, err)
+
+		fromJSON, err := UnmarshalConfig(".policy.json", []byte(testPolicyJSON))
+		require.NoError(t, err)
+
+		sniffedJSON, err := UnmarshalConfig(".policy.yml", []byte(testPolicyJSON))
+		require.NoError(t, err)
+
+		assert.Equal(t, fromYAML, fromJSON)
+		assert.Equal(t, fromYAML, sniffedJSON)
+
+		require.Len(t, fromJSON.ApprovalRules, 2)
+		assert.Equal(t, "^docs/.*\\.md$", fromJSON.ApprovalRules[0].Predicates.OnlyChangedFiles.Paths[0].String())
+		assert.Equal(t, 2, fromJSON.ApprovalRules[1].Requires.Count)
+
+		_, err = ParsePolicy(fromJSON)
+		assert.NoError(t, err)
+	})
+
+	t.Run("jsonEscapes", func(t *testing.T) {
+		c, err := UnmarshalConfig(".policy.json", []byte(`{
+			"approval_rules": [
+				{
+					"name": "docs\/config",
+					"if": {"changed_files": {"paths": ["^docs\/.*\\.md$", "\u00e9"]}},
+					"requires": {"count": 1}
+				}
+			]
+		}`))
+		require.NoError(t, err)
+
+		require.Len(t, c.ApprovalRules, 1)
+		assert.Equal(t, "docs/config", c.ApprovalRules[0].Name)
+		assert.Equal(t, "^docs/.*\\.md$", c.ApprovalRules[0].Predicates.ChangedFiles.Paths[0].String())
+		assert.Equal(t, "\u00e9", c.ApprovalRules[0].Predicates.ChangedFiles.Paths[1].String())
+		assert.Equal(t, 1, c.ApprovalRules[0].Requires.Count)
+	})
+
+	t.Run("invalidJSON", func(t *testing.T) {
+		_, err := UnmarshalConfig(".policy.json", []byte(`{"policy": {"approval": ["rule"]},}`))
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "invalid JSON policy: ")
+		}
+	})
+
+	t.Run("jsonNotObject", func(t *testing.T) {
+		_, err := UnmarshalConfig(".policy.json", []byte(`["rule"]`))
+		assert.EqualError(t, err, "invalid JSON policy: must be an object")
+	})
+
+	t.Run("unknownFieldJSON", func(t *testing.T) {
+		_, err := UnmarshalConfig(".policy.json", []byte(`{"policy": {"aproval": ["rule"]}}`))
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "invalid JSON policy: ")
+			assert.Contains(t, err.Error(), "field aproval not found")
+		}
+	})
+
+	t.Run("invalidLabelsMatch", func(t *testing.T) {
+		_, err := UnmarshalConfig(".policy.yml", []byte(`
+policy:
+  approval: [rule]
+approval_rules:
+  - name: rule
+    if:
+      has_labels:
+        labels: [release]
+        match: some
+`))
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "invalid YAML policy: ")
+			assert.Contains(t, err.Error(), `invalid labels match "some"`)
+		}
+	})
+
+	t.Run("unknownFieldYAML", func(t *testing.T) {
+		_, err := UnmarshalConfig(".policy.yml", []byte("policy:\n  aproval: [rule]\n"))
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "invalid YAML policy: ")
+			assert.Contains(t, err.Error(), "field aproval not found")
+		}
+	})
+}
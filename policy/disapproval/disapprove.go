@@ -17,7 +17,9 @@ package disapproval
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sort"
+	"strings"
 
 	"github.com/palantir/policy-bot/policy/common"
 	"github.com/palantir/policy-bot/policy/predicate"
@@ -34,6 +36,26 @@ type Policy struct {
 
 type Options struct {
 	Methods Methods `yaml:"methods"`
+
+	// GithubChangesRequested disapproves the pull request while the most
+	// recent review of any allowed user requests changes. Unlike other
+	// disapprovals, it is only cleared when the same user approves or the
+	// review is dismissed.
+	GithubChangesRequested bool `yaml:"github_changes_requested"`
+
+	// Dismiss allows users who may not disapprove to clear a disapproval set
+	// by a comment or review. It does not clear disapprovals from predicates
+	// or from GithubChangesRequested.
+	Dismiss *Dismiss `yaml:"dismiss"`
+}
+
+// Dismiss defines the methods and the actors that can dismiss a disapproval.
+// A dismissal clears a disapproval if it is more recent than the last
+// disapproval.
+type Dismiss struct {
+	Methods *common.Methods `yaml:"methods"`
+
+	common.Actors `yaml:",inline"`
 }
 
 type Methods struct {
@@ -42,10 +64,14 @@ type Methods struct {
 }
 
 func (opts *Options) GetDisapproveMethods() *common.Methods {
-	m := opts.Methods.Disapprove
-	if m == nil {
+	// Set the review state in a copy because policies may be evaluated
+	// concurrently
+	var m common.Methods
+	if opts.Methods.Disapprove != nil {
+		m = *opts.Methods.Disapprove
+	} else {
 		githubReview := true
-		m = &common.Methods{
+		m = common.Methods{
 			Comments: []string{
 				":-1:",
 				"👎",
@@ -55,14 +81,18 @@ func (opts *Options) GetDisapproveMethods() *common.Methods {
 	}
 
 	m.GithubReviewState = pull.ReviewChangesRequested
-	return m
+	return &m
 }
 
 func (opts *Options) GetRevokeMethods() *common.Methods {
-	m := opts.Methods.Revoke
-	if m == nil {
+	// Set the review state in a copy because policies may be evaluated
+	// concurrently
+	var m common.Methods
+	if opts.Methods.Revoke != nil {
+		m = *opts.Methods.Revoke
+	} else {
 		githubReview := true
-		m = &common.Methods{
+		m = common.Methods{
 			Comments: []string{
 				":+1:",
 				"👍",
@@ -72,13 +102,89 @@ func (opts *Options) GetRevokeMethods() *common.Methods {
 	}
 
 	m.GithubReviewState = pull.ReviewApproved
-	return m
+	return &m
+}
+
+// GetDismissMethods returns the methods for dismissing disapproval or nil if
+// dismissal is not configured.
+func (opts *Options) GetDismissMethods() *common.Methods {
+	if opts.Dismiss == nil || opts.Dismiss.Methods == nil {
+		return nil
+	}
+
+	// Set the review state in a copy because policies may be evaluated
+	// concurrently
+	m := *opts.Dismiss.Methods
+	m.GithubReviewState = pull.ReviewApproved
+	return &m
 }
 
 type Requires struct {
 	common.Actors `yaml:",inline"`
 }
 
+// Merge returns a policy that disapproves if either p or other disapproves.
+// The predicates of both policies are combined and the actors of both
+// policies may disapprove. The methods of other, if set, take precedence over
+// the methods of p.
+func (p *Policy) Merge(other *Policy) (*Policy, error) {
+	switch {
+	case p == nil:
+		return other, nil
+	case other == nil:
+		return p, nil
+	}
+
+	predicates, err := p.Predicates.Merge(other.Predicates)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to merge disapproval predicates")
+	}
+
+	merged := &Policy{
+		Predicates: predicates,
+		Options:    p.Options,
+		Requires: Requires{
+			Actors: common.Actors{
+				Users:                slices.Concat(p.Requires.Users, other.Requires.Users),
+				Teams:                slices.Concat(p.Requires.Teams, other.Requires.Teams),
+				Organizations:        slices.Concat(p.Requires.Organizations, other.Requires.Organizations),
+				Admins:               p.Requires.Admins || other.Requires.Admins,
+				WriteCollaborators:   p.Requires.WriteCollaborators || other.Requires.WriteCollaborators,
+				Permissions:          slices.Concat(p.Requires.Permissions, other.Requires.Permissions),
+				WriteCollaboratorsOf: slices.Concat(p.Requires.WriteCollaboratorsOf, other.Requires.WriteCollaboratorsOf),
+				Bots:                 slices.Concat(p.Requires.Bots, other.Requires.Bots),
+				Except:               mergeExcept(p.Requires.Except, other.Requires.Except),
+			},
+		},
+	}
+	if other.Options.Methods.Disapprove != nil {
+		merged.Options.Methods.Disapprove = other.Options.Methods.Disapprove
+	}
+	if other.Options.Methods.Revoke != nil {
+		merged.Options.Methods.Revoke = other.Options.Methods.Revoke
+	}
+	merged.Options.GithubChangesRequested = p.Options.GithubChangesRequested || other.Options.GithubChangesRequested
+	if other.Options.Dismiss != nil {
+		merged.Options.Dismiss = other.Options.Dismiss
+	}
+	return merged, nil
+}
+
+// mergeExcept returns the users excluded by either a or b.
+func mergeExcept(a, b *common.ExcludedActors) *common.ExcludedActors {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	}
+	return &common.ExcludedActors{
+		Users:         slices.Concat(a.Users, b.Users),
+		Teams:         slices.Concat(a.Teams, b.Teams),
+		Organizations: slices.Concat(a.Organizations, b.Organizations),
+	}
+}
+
 func (p *Policy) Trigger() common.Trigger {
 	t := common.TriggerCommit
 
@@ -89,9 +195,17 @@ func (p *Policy) Trigger() common.Trigger {
 		if len(dm.Comments) > 0 || len(rm.Comments) > 0 {
 			t |= common.TriggerComment
 		}
-		if dm.GithubReview != nil && *dm.GithubReview || rm.GithubReview != nil && *rm.GithubReview {
+		if dm.GithubReview != nil && *dm.GithubReview || rm.GithubReview != nil && *rm.GithubReview || p.Options.GithubChangesRequested {
 			t |= common.TriggerReview
 		}
+		if xm := p.Options.GetDismissMethods(); xm != nil {
+			if len(xm.Comments) > 0 || len(xm.CommentPatterns) > 0 {
+				t |= common.TriggerComment
+			}
+			if xm.GithubReview != nil && *xm.GithubReview || len(xm.GithubReviewCommentPatterns) > 0 {
+				t |= common.TriggerReview
+			}
+		}
 	}
 
 	for _, predicate := range p.Predicates.Predicates() {
@@ -120,6 +234,7 @@ func (p *Policy) Evaluate(ctx context.Context, prctx pull.Context) (res common.R
 			return
 		}
 		predicateResults = append(predicateResults, result)
+		res.ReevaluateBy(result.ReevaluateAt)
 
 		if result.Satisfied {
 			log.Debug().Msgf("disapproving, predicate of type %T was satisfied", p)
@@ -159,6 +274,16 @@ func (p *Policy) Evaluate(ctx context.Context, prctx pull.Context) (res common.R
 }
 
 func (p *Policy) IsDisapproved(ctx context.Context, prctx pull.Context) (disapproved bool, msg string, err error) {
+	if p.Options.GithubChangesRequested {
+		requesters, err := p.changesRequestedBy(ctx, prctx)
+		if err != nil {
+			return false, "", errors.WithMessage(err, "failed to get users requesting changes")
+		}
+		if len(requesters) > 0 {
+			return true, fmt.Sprintf("Changes requested by %s", strings.Join(requesters, ", ")), nil
+		}
+	}
+
 	disapproveMethods := p.Options.GetDisapproveMethods()
 	revokeMethods := p.Options.GetRevokeMethods()
 
@@ -178,7 +303,17 @@ func (p *Policy) IsDisapproved(ctx context.Context, prctx pull.Context) (disappr
 		return false, "", errors.WithMessage(err, "failed to get last revoker")
 	}
 
+	dismisser, err := p.lastDismisser(ctx, prctx)
+	if err != nil {
+		return false, "", errors.WithMessage(err, "failed to get last dismisser")
+	}
+
 	switch {
+	// the disapproval was dismissed and not revoked later
+	case dismisser != nil && !disapprover.CreatedAt.After(dismisser.CreatedAt) &&
+		(revoker == nil || dismisser.CreatedAt.After(revoker.CreatedAt)):
+		msg = fmt.Sprintf("Disapproval dismissed by %s", dismisser.User)
+
 	// someone disapproved, but nobody has revoked
 	case revoker == nil:
 		disapproved = true
@@ -196,6 +331,46 @@ func (p *Policy) IsDisapproved(ctx context.Context, prctx pull.Context) (disappr
 	return
 }
 
+// changesRequestedBy returns the allowed users whose most recent review
+// requests changes, sorted by name. Approving reviews and dismissed reviews
+// replace earlier reviews by the same user, while comment reviews are ignored.
+func (p *Policy) changesRequestedBy(ctx context.Context, prctx pull.Context) ([]string, error) {
+	reviews, err := prctx.Reviews()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list reviews")
+	}
+
+	sorted := slices.Clone(reviews)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	latest := make(map[string]pull.ReviewState)
+	for _, r := range sorted {
+		switch r.State {
+		case pull.ReviewApproved, pull.ReviewChangesRequested, pull.ReviewDismissed:
+			latest[r.Author] = r.State
+		}
+	}
+
+	var requesters []string
+	for user, state := range latest {
+		if state != pull.ReviewChangesRequested {
+			continue
+		}
+
+		ok, err := p.Requires.IsActor(ctx, prctx, user)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to check candidate status")
+		}
+		if ok {
+			requesters = append(requesters, user)
+		}
+	}
+	sort.Strings(requesters)
+	return requesters, nil
+}
+
 func (p *Policy) lastActor(ctx context.Context, prctx pull.Context, methods *common.Methods, kind string) (*common.Candidate, error) {
 	log := zerolog.Ctx(ctx)
 
@@ -216,6 +391,43 @@ func (p *Policy) lastActor(ctx context.Context, prctx pull.Context, methods *com
 	return last(candidates), nil
 }
 
+// lastDismisser returns the most recent dismissal by a user allowed to dismiss
+// disapprovals or nil if there are no dismissals.
+func (p *Policy) lastDismisser(ctx context.Context, prctx pull.Context) (*common.Candidate, error) {
+	log := zerolog.Ctx(ctx)
+
+	methods := p.Options.GetDismissMethods()
+	if methods == nil || p.Options.Dismiss.IsEmpty() {
+		return nil, nil
+	}
+
+	candidates, err := methods.Candidates(ctx, prctx)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debug().Msgf("found %d dismissal candidates", len(candidates))
+
+	var filtered []*common.Candidate
+	for _, c := range candidates {
+		ok, err := p.Options.Dismiss.IsActor(ctx, prctx, c.User)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to check candidate status")
+		}
+
+		if !ok {
+			log.Debug().Str("user", c.User).Msg("ignoring dismissal by non-whitelisted user")
+			continue
+		}
+
+		filtered = append(filtered, c)
+	}
+
+	sort.Stable(common.CandidatesByCreationTime(filtered))
+
+	return last(filtered), nil
+}
+
 func (p *Policy) filter(ctx context.Context, prctx pull.Context, candidates []*common.Candidate) ([]*common.Candidate, error) {
 	log := zerolog.Ctx(ctx)
 
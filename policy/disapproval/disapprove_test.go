@@ -18,6 +18,7 @@ import (
 	"context"
 	"os"
 	"regexp"
+	"slices"
 	"testing"
 	"time"
 
@@ -178,6 +179,242 @@ func TestIsDisapproved(t *testing.T) {
 	})
 }
 
+func TestIsDisapprovedChangesRequested(t *testing.T) {
+	logger := zerolog.New(os.Stdout)
+	ctx := logger.WithContext(context.Background())
+
+	prctx := &pulltest.Context{
+		ReviewsValue: []*pull.Review{
+			{
+				Author:    "reviewer-1",
+				State:     pull.ReviewChangesRequested,
+				CreatedAt: date(0),
+			},
+			{
+				Author:    "reviewer-2",
+				State:     pull.ReviewChangesRequested,
+				CreatedAt: date(1),
+			},
+			{
+				// GitHub changes the state of dismissed reviews
+				Author:    "reviewer-3",
+				State:     pull.ReviewDismissed,
+				CreatedAt: date(2),
+			},
+			{
+				Author:    "reviewer-1",
+				State:     pull.ReviewApproved,
+				CreatedAt: date(3),
+			},
+			{
+				Author:    "reviewer-2",
+				State:     pull.ReviewCommented,
+				CreatedAt: date(4),
+			},
+			{
+				Author:    "approver",
+				State:     pull.ReviewApproved,
+				CreatedAt: date(6),
+			},
+		},
+	}
+
+	newPolicy := func(users ...string) *Policy {
+		p := &Policy{}
+		p.Options.GithubChangesRequested = true
+		p.Requires.Users = users
+		return p
+	}
+
+	tests := map[string]struct {
+		Policy      *Policy
+		Status      common.EvaluationStatus
+		Description string
+	}{
+		"clearedByApproval": {
+			Policy:      newPolicy("reviewer-1"),
+			Status:      common.StatusSkipped,
+			Description: "Disapproval revoked by reviewer-1",
+		},
+		"notClearedByComment": {
+			Policy:      newPolicy("reviewer-2"),
+			Status:      common.StatusDisapproved,
+			Description: "Changes requested by reviewer-2",
+		},
+		"clearedByDismissal": {
+			Policy:      newPolicy("reviewer-3"),
+			Status:      common.StatusSkipped,
+			Description: "No disapprovals",
+		},
+		"notClearedByOtherApproval": {
+			Policy:      newPolicy("reviewer-1", "reviewer-2", "reviewer-3", "approver"),
+			Status:      common.StatusDisapproved,
+			Description: "Changes requested by reviewer-2",
+		},
+		"ignoresOtherUsers": {
+			Policy:      newPolicy("approver"),
+			Status:      common.StatusSkipped,
+			Description: "No disapprovals",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			res := test.Policy.Evaluate(ctx, prctx)
+
+			require.NoError(t, res.Error)
+			assert.Equal(t, test.Status, res.Status)
+			assert.Equal(t, test.Description, res.StatusDescription)
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		p := &Policy{}
+		p.Requires.Users = []string{"reviewer-2", "approver"}
+
+		res := p.Evaluate(ctx, prctx)
+
+		require.NoError(t, res.Error)
+		assert.Equal(t, common.StatusSkipped, res.Status)
+		assert.Equal(t, "Disapproval revoked by approver", res.StatusDescription)
+	})
+}
+
+func TestIsDisapprovedDismiss(t *testing.T) {
+	logger := zerolog.New(os.Stdout)
+	ctx := logger.WithContext(context.Background())
+
+	prctx := &pulltest.Context{
+		CommentsValue: []*pull.Comment{
+			{
+				Author:    "disapprover",
+				Body:      "this breaks prod :-1:",
+				CreatedAt: date(0),
+			},
+			{
+				Author:    "contributor",
+				Body:      "/dismiss-disapproval",
+				CreatedAt: date(1),
+			},
+			{
+				Author:    "lead",
+				Body:      "/dismiss-disapproval",
+				CreatedAt: date(2),
+			},
+		},
+	}
+
+	newPolicy := func(users ...string) *Policy {
+		p := &Policy{}
+		p.Requires.Users = []string{"disapprover"}
+		p.Options.Dismiss = &Dismiss{
+			Methods: &common.Methods{
+				Comments: []string{"/dismiss-disapproval"},
+			},
+			Actors: common.Actors{
+				Users: users,
+			},
+		}
+		return p
+	}
+
+	tests := map[string]struct {
+		Policy      *Policy
+		Comments    []*pull.Comment
+		Status      common.EvaluationStatus
+		Description string
+	}{
+		"ignoresUnauthorizedDismissal": {
+			Policy:      newPolicy("other-lead"),
+			Status:      common.StatusDisapproved,
+			Description: "Disapproved by disapprover",
+		},
+		"authorizedDismissal": {
+			Policy:      newPolicy("lead"),
+			Status:      common.StatusSkipped,
+			Description: "Disapproval dismissed by lead",
+		},
+		"ignoresDismissalBeforeDisapproval": {
+			Policy: newPolicy("lead"),
+			Comments: []*pull.Comment{
+				{
+					Author:    "disapprover",
+					Body:      "still broken :-1:",
+					CreatedAt: date(3),
+				},
+			},
+			Status:      common.StatusDisapproved,
+			Description: "Disapproved by disapprover",
+		},
+		"revokedAfterDismissal": {
+			Policy: newPolicy("lead"),
+			Comments: []*pull.Comment{
+				{
+					Author:    "disapprover",
+					Body:      "fixed :+1:",
+					CreatedAt: date(3),
+				},
+			},
+			Status:      common.StatusSkipped,
+			Description: "Disapproval revoked by disapprover",
+		},
+		"disabledWithoutActors": {
+			Policy:      newPolicy(),
+			Status:      common.StatusDisapproved,
+			Description: "Disapproved by disapprover",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			testctx := *prctx
+			testctx.CommentsValue = append(slices.Clone(prctx.CommentsValue), test.Comments...)
+
+			res := test.Policy.Evaluate(ctx, &testctx)
+
+			require.NoError(t, res.Error)
+			assert.Equal(t, test.Status, res.Status)
+			assert.Equal(t, test.Description, res.StatusDescription)
+		})
+	}
+
+	t.Run("trigger", func(t *testing.T) {
+		p := newPolicy("lead")
+		p.Options.Methods.Disapprove = &common.Methods{}
+		p.Options.Methods.Revoke = &common.Methods{}
+
+		assert.True(t, p.Trigger().Matches(common.TriggerComment))
+	})
+}
+
+func TestGetMethodsCopiesOptions(t *testing.T) {
+	opts := Options{
+		Methods: Methods{
+			Disapprove: &common.Methods{Comments: []string{"block"}},
+			Revoke:     &common.Methods{Comments: []string{"unblock"}},
+		},
+		Dismiss: &Dismiss{
+			Methods: &common.Methods{Comments: []string{"dismiss"}},
+		},
+	}
+
+	disapprove := opts.GetDisapproveMethods()
+	assert.Equal(t, []string{"block"}, disapprove.Comments)
+	assert.Equal(t, pull.ReviewChangesRequested, disapprove.GithubReviewState)
+
+	revoke := opts.GetRevokeMethods()
+	assert.Equal(t, []string{"unblock"}, revoke.Comments)
+	assert.Equal(t, pull.ReviewApproved, revoke.GithubReviewState)
+
+	dismiss := opts.GetDismissMethods()
+	assert.Equal(t, []string{"dismiss"}, dismiss.Comments)
+	assert.Equal(t, pull.ReviewApproved, dismiss.GithubReviewState)
+
+	assert.Empty(t, opts.Methods.Disapprove.GithubReviewState, "disapprove methods in the options were modified")
+	assert.Empty(t, opts.Methods.Revoke.GithubReviewState, "revoke methods in the options were modified")
+	assert.Empty(t, opts.Dismiss.Methods.GithubReviewState, "dismiss methods in the options were modified")
+}
+
 func date(hour int) time.Time {
 	return time.Date(2018, 6, 29, hour, 0, 0, 0, time.UTC)
 }
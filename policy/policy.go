@@ -16,8 +16,10 @@ package policy
 
 import (
 	"context"
+	"slices"
 
 	"github.com/palantir/policy-bot/policy/approval"
+	"github.com/palantir/policy-bot/policy/breakglass"
 	"github.com/palantir/policy-bot/policy/common"
 	"github.com/palantir/policy-bot/policy/disapproval"
 	"github.com/palantir/policy-bot/pull"
@@ -34,17 +36,88 @@ type RemoteConfig struct {
 	Ref    string `yaml:"ref"`
 }
 
+// ExtendsOrgDefault is the value of Config.Extends for policies that extend
+// the organization's default policy.
+const ExtendsOrgDefault = "org-default"
+
 type Config struct {
 	Policy        Policy           `yaml:"policy"`
 	ApprovalRules []*approval.Rule `yaml:"approval_rules"`
+	Options       Options          `yaml:"options"`
+
+	// Extends is set to ExtendsOrgDefault if this policy adds to the
+	// organization's default policy instead of replacing it.
+	Extends string `yaml:"extends"`
+}
+
+// Extend returns a configuration that combines base with c. The approval
+// rules of both configurations are concatenated and the pull request must
+// satisfy both approval policies. The disapproval policies are merged so that
+// either can disapprove. If c defines a break glass policy, it replaces the
+// policy in base. Rule names must be unique across both configurations.
+func (c *Config) Extend(base *Config) (*Config, error) {
+	if base.Extends != "" {
+		return nil, errors.New("an extended policy cannot itself extend another policy")
+	}
+
+	names := make(map[string]bool)
+	for _, r := range base.ApprovalRules {
+		names[r.Name] = true
+	}
+	for _, r := range c.ApprovalRules {
+		if names[r.Name] {
+			return nil, errors.Errorf("approval rule %q is defined in both the extended policy and this policy", r.Name)
+		}
+	}
+
+	disapproval, err := base.Policy.Disapproval.Merge(c.Policy.Disapproval)
+	if err != nil {
+		return nil, err
+	}
+
+	breakGlass := base.Policy.BreakGlass
+	if c.Policy.BreakGlass != nil {
+		breakGlass = c.Policy.BreakGlass
+	}
+
+	return &Config{
+		Policy: Policy{
+			Approval:    slices.Concat(base.Policy.Approval, c.Policy.Approval),
+			Disapproval: disapproval,
+			BreakGlass:  breakGlass,
+		},
+		ApprovalRules: slices.Concat(base.ApprovalRules, c.ApprovalRules),
+		Options: Options{
+			DisablePushBatching:     base.Options.DisablePushBatching || c.Options.DisablePushBatching,
+			DirectCollaboratorsOnly: base.Options.DirectCollaboratorsOnly || c.Options.DirectCollaboratorsOnly,
+		},
+	}, nil
+}
+
+// Options control how policy-bot loads information about pull requests in a
+// repository. They apply to all rules in the policy.
+type Options struct {
+	// DisablePushBatching disables the heuristic that assigns commits without
+	// a known push time the push time of a later commit in the same push.
+	DisablePushBatching bool `yaml:"disable_push_batching"`
+
+	// DirectCollaboratorsOnly limits the collaborators considered for review
+	// requests to direct collaborators and members of repository teams,
+	// which avoids listing all collaborators in large organizations.
+	DirectCollaboratorsOnly bool `yaml:"direct_collaborators_only"`
 }
 
 type Policy struct {
 	Approval    approval.Policy     `yaml:"approval"`
 	Disapproval *disapproval.Policy `yaml:"disapproval"`
+	BreakGlass  *breakglass.Policy  `yaml:"break_glass"`
 }
 
 func ParsePolicy(c *Config) (common.Evaluator, error) {
+	if c.Extends != "" && c.Extends != ExtendsOrgDefault {
+		return nil, errors.Errorf("invalid extends value %q, must be %q", c.Extends, ExtendsOrgDefault)
+	}
+
 	rulesByName := make(map[string]*approval.Rule)
 	for _, r := range c.ApprovalRules {
 		rulesByName[r.Name] = r
@@ -60,19 +133,30 @@ func ParsePolicy(c *Config) (common.Evaluator, error) {
 		evalDisapproval = &disapproval.Policy{}
 	}
 
-	return evaluator{
+	eval := evaluator{
 		approval:    evalApproval,
 		disapproval: evalDisapproval,
-	}, nil
+	}
+	if c.Policy.BreakGlass != nil {
+		eval.breakGlass = c.Policy.BreakGlass
+	}
+	return eval, nil
 }
 
 type evaluator struct {
 	approval    common.Evaluator
 	disapproval common.Evaluator
+
+	// breakGlass is nil if the policy does not allow overrides
+	breakGlass common.Evaluator
 }
 
 func (e evaluator) Trigger() common.Trigger {
-	return e.approval.Trigger() | e.disapproval.Trigger()
+	t := e.approval.Trigger() | e.disapproval.Trigger()
+	if e.breakGlass != nil {
+		t |= e.breakGlass.Trigger()
+	}
+	return t
 }
 
 func (e evaluator) Evaluate(ctx context.Context, prctx pull.Context) (res common.Result) {
@@ -82,6 +166,13 @@ func (e evaluator) Evaluate(ctx context.Context, prctx pull.Context) (res common
 	res.Name = "policy"
 	res.Children = []*common.Result{&approval, &disapproval}
 
+	var breakGlass *common.Result
+	if e.breakGlass != nil {
+		r := e.breakGlass.Evaluate(ctx, prctx)
+		breakGlass = &r
+		res.Children = append(res.Children, breakGlass)
+	}
+
 	for _, r := range res.Children {
 		if r.Error != nil {
 			res.Error = r.Error
@@ -90,6 +181,13 @@ func (e evaluator) Evaluate(ctx context.Context, prctx pull.Context) (res common
 
 	switch {
 	case res.Error != nil:
+	case breakGlass != nil && breakGlass.Status == common.StatusApproved:
+		// A break-glass override takes precedence over all other results
+		res.Status = common.StatusApproved
+		res.StatusDescription = breakGlass.StatusDescription
+		if len(breakGlass.Requires.Approvers) > 0 {
+			res.OverriddenBy = breakGlass.Requires.Approvers[0].User
+		}
 	case disapproval.Status == common.StatusDisapproved:
 		res.Status = common.StatusDisapproved
 		res.StatusDescription = disapproval.StatusDescription
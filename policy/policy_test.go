@@ -24,6 +24,7 @@ import (
 	"github.com/palantir/policy-bot/pull/pulltest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
 )
 
 type StaticEvaluator common.Result
@@ -92,6 +93,75 @@ func TestEvaluator(t *testing.T) {
 		assert.Equal(t, common.StatusSkipped, r.Status)
 	})
 
+	t.Run("breakGlassWins", func(t *testing.T) {
+		eval := evaluator{
+			approval: &StaticEvaluator{
+				Status: common.StatusPending,
+			},
+			disapproval: &StaticEvaluator{
+				Status: common.StatusDisapproved,
+			},
+			breakGlass: &StaticEvaluator{
+				Status:            common.StatusApproved,
+				StatusDescription: "Break-glass override by emergency",
+				Requires: common.RequiresResult{
+					Approvers: []*common.Candidate{{User: "emergency"}},
+				},
+			},
+		}
+
+		r := eval.Evaluate(ctx, prctx)
+		require.NoError(t, r.Error)
+
+		assert.Equal(t, common.StatusApproved, r.Status)
+		assert.Equal(t, "Break-glass override by emergency", r.StatusDescription)
+		assert.Equal(t, "emergency", r.OverriddenBy)
+		if assert.Len(t, r.Children, 3) {
+			assert.Equal(t, castToResult(eval.breakGlass), r.Children[2])
+		}
+	})
+
+	t.Run("breakGlassSkipped", func(t *testing.T) {
+		eval := evaluator{
+			approval: &StaticEvaluator{
+				Status:            common.StatusPending,
+				StatusDescription: "2 approvals needed",
+			},
+			disapproval: &StaticEvaluator{
+				Status: common.StatusSkipped,
+			},
+			breakGlass: &StaticEvaluator{
+				Status: common.StatusSkipped,
+			},
+		}
+
+		r := eval.Evaluate(ctx, prctx)
+		require.NoError(t, r.Error)
+
+		assert.Equal(t, common.StatusPending, r.Status)
+		assert.Equal(t, "2 approvals needed", r.StatusDescription)
+		assert.Empty(t, r.OverriddenBy)
+	})
+
+	t.Run("breakGlassDoesNotHideErrors", func(t *testing.T) {
+		eval := evaluator{
+			approval: &StaticEvaluator{
+				Error: errors.New("approval failed"),
+			},
+			disapproval: &StaticEvaluator{
+				Status: common.StatusSkipped,
+			},
+			breakGlass: &StaticEvaluator{
+				Status: common.StatusApproved,
+			},
+		}
+
+		r := eval.Evaluate(ctx, prctx)
+
+		assert.EqualError(t, r.Error, "approval failed")
+		assert.Equal(t, common.StatusSkipped, r.Status)
+	})
+
 	t.Run("setsProperties", func(t *testing.T) {
 		eval := evaluator{
 			approval: &StaticEvaluator{
@@ -116,3 +186,147 @@ func TestEvaluator(t *testing.T) {
 func castToResult(e common.Evaluator) *common.Result {
 	return (*common.Result)(e.(*StaticEvaluator))
 }
+
+func TestConfigExtend(t *testing.T) {
+	parse := func(t *testing.T, s string) *Config {
+		var c Config
+		require.NoError(t, yaml.UnmarshalStrict([]byte(s), &c))
+		return &c
+	}
+
+	base := `
+policy:
+  approval:
+    - security review
+  disapproval:
+    if:
+      title:
+        matches:
+          - "^DO NOT MERGE"
+    requires:
+      teams:
+        - org/security
+  break_glass:
+    requires:
+      teams:
+        - org/admins
+approval_rules:
+  - name: security review
+    requires:
+      count: 1
+      teams:
+        - org/security
+`
+
+	t.Run("merge", func(t *testing.T) {
+		c := parse(t, `
+extends: org-default
+policy:
+  approval:
+    - or:
+      - team review
+      - docs only
+  disapproval:
+    if:
+      has_labels:
+        - blocked
+    requires:
+      users:
+        - mhaypenny
+    options:
+      methods:
+        disapprove:
+          comments:
+            - "blocked"
+approval_rules:
+  - name: team review
+    requires:
+      count: 1
+  - name: docs only
+    if:
+      only_changed_files:
+        paths:
+          - "^docs/"
+options:
+  disable_push_batching: true
+  direct_collaborators_only: true
+`)
+
+		extended, err := c.Extend(parse(t, base))
+		require.NoError(t, err)
+
+		assert.Empty(t, extended.Extends)
+		assert.True(t, extended.Options.DisablePushBatching)
+		assert.True(t, extended.Options.DirectCollaboratorsOnly)
+
+		var names []string
+		for _, r := range extended.ApprovalRules {
+			names = append(names, r.Name)
+		}
+		assert.Equal(t, []string{"security review", "team review", "docs only"}, names)
+		assert.Len(t, extended.Policy.Approval, 2)
+		assert.Equal(t, "security review", extended.Policy.Approval[0])
+
+		disapproval := extended.Policy.Disapproval
+		require.NotNil(t, disapproval)
+		assert.NotNil(t, disapproval.Predicates.Title)
+		assert.NotNil(t, disapproval.Predicates.HasLabels)
+		assert.Equal(t, []string{"mhaypenny"}, disapproval.Requires.Users)
+		assert.Equal(t, []string{"org/security"}, disapproval.Requires.Teams)
+		assert.Equal(t, []string{"blocked"}, disapproval.Options.Methods.Disapprove.Comments)
+
+		require.NotNil(t, extended.Policy.BreakGlass)
+		assert.Equal(t, []string{"org/admins"}, extended.Policy.BreakGlass.Requires.Teams)
+
+		_, err = ParsePolicy(extended)
+		assert.NoError(t, err)
+	})
+
+	t.Run("onlyBase", func(t *testing.T) {
+		c := parse(t, `extends: org-default`)
+
+		extended, err := c.Extend(parse(t, base))
+		require.NoError(t, err)
+
+		assert.Len(t, extended.ApprovalRules, 1)
+		assert.Equal(t, []string{"org/security"}, extended.Policy.Disapproval.Requires.Teams)
+	})
+
+	t.Run("duplicateRule", func(t *testing.T) {
+		c := parse(t, `
+extends: org-default
+approval_rules:
+  - name: security review
+`)
+
+		_, err := c.Extend(parse(t, base))
+		assert.EqualError(t, err, `approval rule "security review" is defined in both the extended policy and this policy`)
+	})
+
+	t.Run("duplicateDisapprovalPredicate", func(t *testing.T) {
+		c := parse(t, `
+extends: org-default
+policy:
+  disapproval:
+    if:
+      title:
+        matches:
+          - "^WIP"
+`)
+
+		_, err := c.Extend(parse(t, base))
+		assert.EqualError(t, err, `failed to merge disapproval predicates: predicate "title" is defined more than once`)
+	})
+
+	t.Run("baseExtends", func(t *testing.T) {
+		c := parse(t, `extends: org-default`)
+
+		_, err := c.Extend(parse(t, "extends: org-default"))
+		assert.Error(t, err)
+	})
+
+	t.Run("invalidExtends", func(t *testing.T) {
+		_, err := ParsePolicy(parse(t, "extends: other"))
+		assert.EqualError(t, err, `invalid extends value "other", must be "org-default"`)
+	})
+}
@@ -0,0 +1,238 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/pkg/errors"
+)
+
+// Age is satisfied if the time since the pull request was created is within
+// the configured bounds. Bounds that are not configured are not checked.
+//
+// The result of this predicate changes with time alone, so results set
+// ReevaluateAt to the next time the pull request crosses one of the bounds.
+type Age struct {
+	OlderThan   Duration `yaml:"older_than"`
+	YoungerThan Duration `yaml:"younger_than"`
+}
+
+var _ Predicate = &Age{}
+
+func (pred *Age) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	age := prctx.EvaluationTimestamp().Sub(prctx.CreatedAt())
+
+	var conditions []string
+	if pred.OlderThan > 0 {
+		conditions = append(conditions, fmt.Sprintf("older than %s", pred.OlderThan))
+	}
+	if pred.YoungerThan > 0 {
+		conditions = append(conditions, fmt.Sprintf("younger than %s", pred.YoungerThan))
+	}
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "pull request age",
+		Values:          []string{Duration(age).String()},
+		ConditionPhrase: "is",
+		ConditionValues: conditions,
+	}
+
+	predicateResult.ReevaluateAt = nextAgeBoundary(prctx.CreatedAt(), prctx.EvaluationTimestamp(), pred.OlderThan, pred.YoungerThan)
+
+	switch {
+	case pred.OlderThan > 0 && age <= time.Duration(pred.OlderThan):
+		predicateResult.Description = fmt.Sprintf("The pull request was created %s ago, not more than %s ago", Duration(age), pred.OlderThan)
+	case pred.YoungerThan > 0 && age >= time.Duration(pred.YoungerThan):
+		predicateResult.Description = fmt.Sprintf("The pull request was created %s ago, not less than %s ago", Duration(age), pred.YoungerThan)
+	default:
+		predicateResult.Satisfied = true
+	}
+	return &predicateResult, nil
+}
+
+func (pred *Age) Trigger() common.Trigger {
+	return common.TriggerCommit
+}
+
+// AuthorAccountAge is satisfied if the time since the GitHub account of the
+// pull request author was created is within the configured bounds. Bounds that
+// are not configured are not checked. Bots and deleted accounts have no
+// account age and never satisfy the predicate.
+//
+// Like Age, the result of this predicate changes with time alone and results
+// set ReevaluateAt to the next time the account crosses one of the bounds.
+type AuthorAccountAge struct {
+	OlderThan   Duration `yaml:"older_than"`
+	YoungerThan Duration `yaml:"younger_than"`
+}
+
+var _ Predicate = &AuthorAccountAge{}
+
+func (pred *AuthorAccountAge) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	createdAt, err := prctx.AuthorCreatedAt()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get author account creation time")
+	}
+
+	var conditions []string
+	if pred.OlderThan > 0 {
+		conditions = append(conditions, fmt.Sprintf("older than %s", pred.OlderThan))
+	}
+	if pred.YoungerThan > 0 {
+		conditions = append(conditions, fmt.Sprintf("younger than %s", pred.YoungerThan))
+	}
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "author account age",
+		ConditionPhrase: "is",
+		ConditionValues: conditions,
+	}
+
+	if createdAt.IsZero() {
+		predicateResult.Description = fmt.Sprintf("The author %s is a bot or does not have a GitHub account", prctx.Author())
+		return &predicateResult, nil
+	}
+
+	age := prctx.EvaluationTimestamp().Sub(createdAt)
+	predicateResult.Values = []string{Duration(age).String()}
+	predicateResult.ReevaluateAt = nextAgeBoundary(createdAt, prctx.EvaluationTimestamp(), pred.OlderThan, pred.YoungerThan)
+
+	switch {
+	case pred.OlderThan > 0 && age <= time.Duration(pred.OlderThan):
+		predicateResult.Description = fmt.Sprintf("The author's account was created %s ago, not more than %s ago", Duration(age), pred.OlderThan)
+	case pred.YoungerThan > 0 && age >= time.Duration(pred.YoungerThan):
+		predicateResult.Description = fmt.Sprintf("The author's account was created %s ago, not less than %s ago", Duration(age), pred.YoungerThan)
+	default:
+		predicateResult.Satisfied = true
+	}
+	return &predicateResult, nil
+}
+
+func (pred *AuthorAccountAge) Trigger() common.Trigger {
+	return common.TriggerCommit
+}
+
+// nextAgeBoundary returns the first time after now at which something created
+// at createdAt is no longer younger than youngerThan or becomes older than
+// olderThan, or the zero time if neither happens after now.
+func nextAgeBoundary(createdAt, now time.Time, olderThan, youngerThan Duration) time.Time {
+	var next time.Time
+	for _, t := range []time.Time{
+		// ages equal to the bound are not older, so wait until just after it
+		boundary(createdAt, olderThan, time.Second),
+		boundary(createdAt, youngerThan, 0),
+	} {
+		if !t.IsZero() && t.After(now) && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+	return next
+}
+
+func boundary(createdAt time.Time, d Duration, offset time.Duration) time.Time {
+	if d <= 0 {
+		return time.Time{}
+	}
+	return createdAt.Add(time.Duration(d) + offset)
+}
+
+// Duration is a time.Duration that also accepts days ("d") and weeks ("w")
+// when parsed from text, as in "1d" or "2w3d12h".
+type Duration time.Duration
+
+var durationUnits = map[byte]time.Duration{
+	'd': 24 * time.Hour,
+	'w': 7 * 24 * time.Hour,
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+	if s == "" {
+		*d = 0
+		return nil
+	}
+
+	// consume leading components with day and week units, then parse the
+	// remainder as a standard duration
+	var total time.Duration
+	for {
+		i := 0
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		if i == 0 || i == len(s) {
+			break
+		}
+		unit, ok := durationUnits[s[i]]
+		if !ok {
+			break
+		}
+		n, err := strconv.ParseInt(s[:i], 10, 64)
+		if err != nil {
+			return errors.Wrapf(err, "invalid duration %q", text)
+		}
+		total += time.Duration(n) * unit
+		s = s[i+1:]
+	}
+
+	if s != "" {
+		rest, err := time.ParseDuration(s)
+		if err != nil {
+			return errors.Wrapf(err, "invalid duration %q", text)
+		}
+		total += rest
+	}
+
+	if total < 0 {
+		return errors.Errorf("invalid duration %q: must not be negative", text)
+	}
+	*d = Duration(total)
+	return nil
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// String formats the duration with days, hours, and minutes, omitting
+// components that are zero, like "1d 2h" or "45m". Durations less than a
+// minute are formatted in seconds.
+func (d Duration) String() string {
+	td := time.Duration(d)
+	if td < time.Minute {
+		return fmt.Sprintf("%ds", int64(td/time.Second))
+	}
+
+	var parts []string
+	if days := td / (24 * time.Hour); days > 0 {
+		parts = append(parts, fmt.Sprintf("%dd", days))
+		td -= days * 24 * time.Hour
+	}
+	if hours := td / time.Hour; hours > 0 {
+		parts = append(parts, fmt.Sprintf("%dh", hours))
+		td -= hours * time.Hour
+	}
+	if minutes := td / time.Minute; minutes > 0 {
+		parts = append(parts, fmt.Sprintf("%dm", minutes))
+	}
+	return strings.Join(parts, " ")
+}
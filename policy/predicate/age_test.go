@@ -0,0 +1,203 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull/pulltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestAge(t *testing.T) {
+	now := time.Date(2024, 6, 12, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		Name     string
+		Pred     *Age
+		Created  time.Time
+		Expected *common.PredicateResult
+	}{
+		{
+			Name:    "olderThanSatisfied",
+			Pred:    &Age{OlderThan: Duration(24 * time.Hour)},
+			Created: now.Add(-26 * time.Hour),
+			Expected: &common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"1d 2h"},
+				ConditionValues: []string{"older than 1d"},
+			},
+		},
+		{
+			Name:    "olderThanNotSatisfied",
+			Pred:    &Age{OlderThan: Duration(24 * time.Hour)},
+			Created: now.Add(-90 * time.Minute),
+			Expected: &common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"1h 30m"},
+				ConditionValues: []string{"older than 1d"},
+				ReevaluateAt:    now.Add(22*time.Hour + 30*time.Minute + time.Second),
+			},
+		},
+		{
+			Name:    "youngerThanSatisfied",
+			Pred:    &Age{YoungerThan: Duration(time.Hour)},
+			Created: now.Add(-30 * time.Second),
+			Expected: &common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"30s"},
+				ConditionValues: []string{"younger than 1h"},
+				ReevaluateAt:    now.Add(59*time.Minute + 30*time.Second),
+			},
+		},
+		{
+			Name:    "youngerThanNotSatisfied",
+			Pred:    &Age{YoungerThan: Duration(time.Hour)},
+			Created: now.Add(-time.Hour),
+			Expected: &common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"1h"},
+				ConditionValues: []string{"younger than 1h"},
+			},
+		},
+		{
+			Name:    "range",
+			Pred:    &Age{OlderThan: Duration(time.Hour), YoungerThan: Duration(7 * 24 * time.Hour)},
+			Created: now.Add(-50 * time.Hour),
+			Expected: &common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"2d 2h"},
+				ConditionValues: []string{"older than 1h", "younger than 7d"},
+				ReevaluateAt:    now.Add(118 * time.Hour),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			prctx := &pulltest.Context{
+				EvaluationTimestampValue: now,
+				CreatedAtValue:           test.Created,
+			}
+
+			result, err := test.Pred.Evaluate(context.Background(), prctx)
+			require.NoError(t, err)
+			assertPredicateResult(t, test.Expected, result)
+			assert.Equal(t, test.Expected.ReevaluateAt, result.ReevaluateAt, "reevaluation time was not correct")
+		})
+	}
+}
+
+func TestAuthorAccountAge(t *testing.T) {
+	now := time.Date(2024, 6, 12, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		Name     string
+		Pred     *AuthorAccountAge
+		Created  time.Time
+		Expected *common.PredicateResult
+	}{
+		{
+			Name:    "oldAccount",
+			Pred:    &AuthorAccountAge{OlderThan: Duration(30 * 24 * time.Hour)},
+			Created: now.Add(-400 * 24 * time.Hour),
+			Expected: &common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"400d"},
+				ConditionValues: []string{"older than 30d"},
+			},
+		},
+		{
+			Name:    "newAccount",
+			Pred:    &AuthorAccountAge{OlderThan: Duration(30 * 24 * time.Hour)},
+			Created: now.Add(-50 * time.Hour),
+			Expected: &common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"2d 2h"},
+				ConditionValues: []string{"older than 30d"},
+				ReevaluateAt:    now.Add(670*time.Hour + time.Second),
+			},
+		},
+		{
+			Name:    "youngerThan",
+			Pred:    &AuthorAccountAge{YoungerThan: Duration(7 * 24 * time.Hour)},
+			Created: now.Add(-50 * time.Hour),
+			Expected: &common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"2d 2h"},
+				ConditionValues: []string{"younger than 7d"},
+				ReevaluateAt:    now.Add(118 * time.Hour),
+			},
+		},
+		{
+			Name: "bot",
+			Pred: &AuthorAccountAge{YoungerThan: Duration(7 * 24 * time.Hour)},
+			Expected: &common.PredicateResult{
+				Satisfied:       false,
+				ConditionValues: []string{"younger than 7d"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			prctx := &pulltest.Context{
+				AuthorValue:              "mhaypenny",
+				EvaluationTimestampValue: now,
+				AuthorCreatedAtValue:     test.Created,
+			}
+
+			result, err := test.Pred.Evaluate(context.Background(), prctx)
+			require.NoError(t, err)
+			assertPredicateResult(t, test.Expected, result)
+			assert.Equal(t, test.Expected.ReevaluateAt, result.ReevaluateAt, "reevaluation time was not correct")
+		})
+	}
+}
+
+func TestDurationUnmarshal(t *testing.T) {
+	tests := map[string]time.Duration{
+		"24h":       24 * time.Hour,
+		"1d":        24 * time.Hour,
+		"1w2d":      9 * 24 * time.Hour,
+		"1d12h30m":  36*time.Hour + 30*time.Minute,
+		"1.5h":      90 * time.Minute,
+		" 15m ":     15 * time.Minute,
+		"not-valid": 0,
+		"10":        0,
+	}
+
+	for text, expected := range tests {
+		var d Duration
+		err := d.UnmarshalText([]byte(text))
+		if expected == 0 {
+			assert.Error(t, err, "expected error parsing %q", text)
+			continue
+		}
+		if assert.NoError(t, err, "failed to parse %q", text) {
+			assert.Equal(t, expected, time.Duration(d), "incorrect value for %q", text)
+		}
+	}
+
+	var pred Age
+	require.NoError(t, yaml.UnmarshalStrict([]byte("older_than: 1d\nyounger_than: 2w"), &pred))
+	assert.Equal(t, Duration(24*time.Hour), pred.OlderThan)
+	assert.Equal(t, Duration(14*24*time.Hour), pred.YoungerThan)
+}
@@ -17,11 +17,15 @@ package predicate
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sort"
+	"strings"
 
+	"github.com/palantir/policy-bot/policy/codeowners"
 	"github.com/palantir/policy-bot/policy/common"
 	"github.com/palantir/policy-bot/pull"
 	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
 )
 
 type HasAuthorIn struct {
@@ -58,6 +62,101 @@ func (pred *HasAuthorIn) Trigger() common.Trigger {
 	return common.TriggerStatic
 }
 
+// DefaultMaintainersPath is the default file used by the HasAuthorInFile
+// predicate.
+const DefaultMaintainersPath = "MAINTAINERS"
+
+// HasAuthorInFile is satisfied if the author of the pull request is listed in
+// a file on the base branch of the repository. Each line of the file contains
+// a single user or team, optionally prefixed by "@". Teams use the "org/team"
+// format and match all members of the team. Blank lines and text after a "#"
+// are ignored.
+type HasAuthorInFile struct {
+	Path string `yaml:"path"`
+}
+
+var _ Predicate = &HasAuthorInFile{}
+
+func (pred *HasAuthorInFile) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	author := prctx.Author()
+
+	path := pred.Path
+	if path == "" {
+		path = DefaultMaintainersPath
+	}
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "authors",
+		Values:          []string{author},
+		ConditionPhrase: "are listed in the file",
+		ConditionValues: []string{path},
+	}
+
+	content, exists, err := prctx.BaseFileContent(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get content of %s", path)
+	}
+	if !exists {
+		predicateResult.Description = fmt.Sprintf("The file %s does not exist", path)
+		predicateResult.Satisfied = false
+		return &predicateResult, nil
+	}
+
+	for _, entry := range parseMaintainers(content) {
+		if !strings.Contains(entry, "/") {
+			if entry == author {
+				predicateResult.Satisfied = true
+				return &predicateResult, nil
+			}
+			continue
+		}
+
+		member, err := prctx.IsTeamMember(entry, author)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get team membership")
+		}
+		if member {
+			predicateResult.Satisfied = true
+			return &predicateResult, nil
+		}
+	}
+
+	predicateResult.Description = fmt.Sprintf("The pull request author %q is not listed in %s", author, path)
+	predicateResult.Satisfied = false
+	return &predicateResult, nil
+}
+
+func (pred *HasAuthorInFile) Trigger() common.Trigger {
+	return common.TriggerStatic
+}
+
+// parseMaintainers returns the users and teams listed in a maintainers file.
+func parseMaintainers(content string) []string {
+	var entries []string
+	for _, entry := range parseListFile(content) {
+		entries = append(entries, strings.TrimPrefix(entry, "@"))
+	}
+	return entries
+}
+
+// parseListFile returns the first field of each line in a file. Blank lines
+// and text after a "#" are ignored.
+func parseListFile(content string) []string {
+	var entries []string
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		entries = append(entries, fields[0])
+	}
+	return entries
+}
+
 type OnlyHasContributorsIn struct {
 	common.Actors `yaml:",inline"`
 }
@@ -224,3 +323,330 @@ func (pred AuthorIsOnlyContributor) Evaluate(ctx context.Context, prctx pull.Con
 func (pred AuthorIsOnlyContributor) Trigger() common.Trigger {
 	return common.TriggerCommit
 }
+
+// AuthorIsCodeOwner, when true, is satisfied if the author of the pull request
+// owns every changed file according to the CODEOWNERS file on the base branch,
+// either directly or as a member of an owning team. When false, it is
+// satisfied if at least one changed file is not owned by the author.
+type AuthorIsCodeOwner bool
+
+var _ Predicate = AuthorIsCodeOwner(false)
+
+func (pred AuthorIsCodeOwner) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	author := prctx.Author()
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "authors",
+		Values:          []string{author},
+		ConditionPhrase: "meet the condition",
+	}
+	if pred {
+		predicateResult.ConditionValues = []string{"they own all changed files"}
+	} else {
+		predicateResult.ConditionValues = []string{"they do not own all changed files"}
+	}
+
+	owned, unowned, err := authorOwnedFiles(prctx, author)
+	if err != nil {
+		return nil, err
+	}
+	ownsAll := len(unowned) == 0 && owned > 0
+
+	switch {
+	case bool(pred) && !ownsAll:
+		if len(unowned) == 0 {
+			predicateResult.Description = fmt.Sprintf("The pull request author %q does not own any changed files", author)
+		} else {
+			predicateResult.Description = fmt.Sprintf("The pull request author %q does not own %s", author, unowned[0])
+		}
+	case !bool(pred) && ownsAll:
+		predicateResult.Description = fmt.Sprintf("The pull request author %q owns all changed files", author)
+	default:
+		predicateResult.Satisfied = true
+	}
+	return &predicateResult, nil
+}
+
+func (pred AuthorIsCodeOwner) Trigger() common.Trigger {
+	return common.TriggerCommit
+}
+
+// authorOwnedFiles returns the number of changed files that the author owns
+// according to the CODEOWNERS file and the names of the files that the author
+// does not own. If the repository has no CODEOWNERS file, the author does not
+// own any files.
+func authorOwnedFiles(prctx pull.Context, author string) (int, []string, error) {
+	files, err := prctx.ChangedFiles()
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "failed to list changed files")
+	}
+
+	owners, err := codeowners.Load(prctx)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var owned int
+	var unowned []string
+	member := make(map[string]bool)
+
+	for _, f := range files {
+		isOwner := false
+		if owners != nil {
+			for _, owner := range owners.Owners(f.Filename) {
+				if !codeowners.IsTeam(owner) {
+					if strings.EqualFold(owner, author) {
+						isOwner = true
+						break
+					}
+					continue
+				}
+
+				isMember, ok := member[owner]
+				if !ok {
+					isMember, err = prctx.IsTeamMember(owner, author)
+					if err != nil {
+						return 0, nil, errors.Wrap(err, "failed to get team membership")
+					}
+					member[owner] = isMember
+				}
+				if isMember {
+					isOwner = true
+					break
+				}
+			}
+		}
+
+		if isOwner {
+			owned++
+		} else {
+			unowned = append(unowned, f.Filename)
+		}
+	}
+	return owned, unowned, nil
+}
+
+// HasAuthorPermission is satisfied if the author of the pull request has at
+// least the configured permissions on the base repository and on the head
+// repository. For pull requests that are not from forks, the head repository
+// is the base repository. Permissions that are not configured are not checked.
+//
+// The installation usually cannot read the collaborators of forks. Authors
+// have admin permission on forks they own and have no permission on forks
+// that the installation cannot access.
+type HasAuthorPermission struct {
+	Base pull.Permission `yaml:"base"`
+	Head pull.Permission `yaml:"head"`
+}
+
+var _ Predicate = &HasAuthorPermission{}
+
+func (pred *HasAuthorPermission) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	author := prctx.Author()
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "author permissions",
+		ConditionPhrase: "meet the minimum permissions",
+	}
+	if pred.Base != pull.PermissionNone {
+		predicateResult.ConditionValues = append(predicateResult.ConditionValues, "base "+pred.Base.String())
+	}
+	if pred.Head != pull.PermissionNone {
+		predicateResult.ConditionValues = append(predicateResult.ConditionValues, "head "+pred.Head.String())
+	}
+
+	baseOwner, baseRepo := prctx.RepositoryOwner(), prctx.RepositoryName()
+	basePerm, err := prctx.CollaboratorPermission(author)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get base repository permission")
+	}
+	predicateResult.Values = append(predicateResult.Values, fmt.Sprintf("%s/%s (base): %s", baseOwner, baseRepo, basePerm))
+
+	headOwner, headRepo := prctx.HeadRepository()
+	headPerm := basePerm
+	switch {
+	case headOwner == "" || headRepo == "":
+		headPerm = pull.PermissionNone
+		predicateResult.Values = append(predicateResult.Values, "deleted repository (head): none")
+	case headOwner != baseOwner || headRepo != baseRepo:
+		switch {
+		case pred.Head == pull.PermissionNone:
+			// Avoid looking up permissions on forks when they are not needed
+		case strings.EqualFold(headOwner, author):
+			headPerm = pull.PermissionAdmin
+			predicateResult.Values = append(predicateResult.Values, fmt.Sprintf("%s/%s (head): %s", headOwner, headRepo, headPerm))
+		default:
+			headPerm, err = prctx.CollaboratorPermissionOn(headOwner, headRepo, author)
+			if err != nil {
+				zerolog.Ctx(ctx).Debug().Err(err).Msgf("Failed to get permission on head repository %s/%s, assuming none", headOwner, headRepo)
+				headPerm = pull.PermissionNone
+				predicateResult.Values = append(predicateResult.Values, fmt.Sprintf("%s/%s (head): unknown", headOwner, headRepo))
+			} else {
+				predicateResult.Values = append(predicateResult.Values, fmt.Sprintf("%s/%s (head): %s", headOwner, headRepo, headPerm))
+			}
+		}
+	}
+
+	if basePerm < pred.Base {
+		predicateResult.Description = fmt.Sprintf("The pull request author %q has %s permission on the base repository, but %s is required", author, basePerm, pred.Base)
+		predicateResult.Satisfied = false
+		return &predicateResult, nil
+	}
+	if headPerm < pred.Head {
+		predicateResult.Description = fmt.Sprintf("The pull request author %q has %s permission on the head repository, but %s is required", author, headPerm, pred.Head)
+		predicateResult.Satisfied = false
+		return &predicateResult, nil
+	}
+
+	predicateResult.Satisfied = true
+	return &predicateResult, nil
+}
+
+func (pred *HasAuthorPermission) Trigger() common.Trigger {
+	return common.TriggerStatic
+}
+
+// AuthorIsRequestedReviewer is satisfied if the author of the pull request is
+// also a requested reviewer, either directly or as a member of a requested
+// team. Authors cannot review their own pull requests, so this usually means
+// review requests are misconfigured. When false, it is satisfied if the author
+// is not a requested reviewer.
+type AuthorIsRequestedReviewer bool
+
+var _ Predicate = AuthorIsRequestedReviewer(false)
+
+func (pred AuthorIsRequestedReviewer) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	author := prctx.Author()
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "authors",
+		Values:          []string{author},
+		ConditionPhrase: "meet the condition",
+	}
+	if pred {
+		predicateResult.ConditionValues = []string{"they are requested reviewers"}
+	} else {
+		predicateResult.ConditionValues = []string{"they are not requested reviewers"}
+	}
+
+	reviewers, err := prctx.RequestedReviewers()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get requested reviewers")
+	}
+
+	requestedBy := ""
+	for _, r := range reviewers {
+		if r.Removed {
+			continue
+		}
+
+		switch r.Type {
+		case pull.ReviewerUser:
+			if r.Name == author {
+				requestedBy = "directly"
+			}
+		case pull.ReviewerTeam:
+			team := prctx.RepositoryOwner() + "/" + r.Name
+			member, err := prctx.IsTeamMember(team, author)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to get team membership")
+			}
+			if member {
+				requestedBy = fmt.Sprintf("through team %s", team)
+			}
+		}
+		if requestedBy != "" {
+			break
+		}
+	}
+
+	if requestedBy != "" {
+		if pred {
+			predicateResult.Satisfied = true
+			return &predicateResult, nil
+		}
+		predicateResult.Description = fmt.Sprintf("The pull request author %q is a requested reviewer %s", author, requestedBy)
+		predicateResult.Satisfied = false
+		return &predicateResult, nil
+	}
+
+	if pred {
+		predicateResult.Description = fmt.Sprintf("The pull request author %q is not a requested reviewer", author)
+		predicateResult.Satisfied = false
+		return &predicateResult, nil
+	}
+	predicateResult.Satisfied = true
+	return &predicateResult, nil
+}
+
+func (pred AuthorIsRequestedReviewer) Trigger() common.Trigger {
+	return common.TriggerPullRequest
+}
+
+// AuthorRemovedReviewers is satisfied if the author of the pull request
+// removed a review request for a reviewer matching the actors. Team reviewers
+// match if the team is listed in the teams of the actors. If no actors are
+// set, the removal of any review request satisfies the predicate. This can
+// flag pull requests where the author removed a required reviewer.
+type AuthorRemovedReviewers struct {
+	common.Actors `yaml:",inline"`
+}
+
+var _ Predicate = &AuthorRemovedReviewers{}
+
+func (pred *AuthorRemovedReviewers) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	author := prctx.Author()
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "review requests",
+		ConditionPhrase: "meet the condition",
+		ConditionValues: []string{"they were removed by the author"},
+	}
+
+	reviewers, err := prctx.RequestedReviewers()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get requested reviewers")
+	}
+
+	removed := make(map[string]struct{})
+	for _, r := range reviewers {
+		if !r.Removed || r.RemovedBy != author {
+			continue
+		}
+
+		name := r.Name
+		matches := pred.IsEmpty()
+		switch r.Type {
+		case pull.ReviewerUser:
+			if !matches {
+				if matches, err = pred.IsActor(ctx, prctx, r.Name); err != nil {
+					return nil, err
+				}
+			}
+		case pull.ReviewerTeam:
+			name = prctx.RepositoryOwner() + "/" + r.Name
+			matches = matches || slices.Contains(pred.Teams, name)
+		}
+		if matches {
+			removed[name] = struct{}{}
+		}
+	}
+
+	if len(removed) == 0 {
+		predicateResult.Description = fmt.Sprintf("The pull request author %q did not remove any matching review requests", author)
+		predicateResult.Satisfied = false
+		return &predicateResult, nil
+	}
+
+	for name := range removed {
+		predicateResult.Values = append(predicateResult.Values, name)
+	}
+	sort.Strings(predicateResult.Values)
+
+	predicateResult.Satisfied = true
+	return &predicateResult, nil
+}
+
+func (pred *AuthorRemovedReviewers) Trigger() common.Trigger {
+	return common.TriggerPullRequest
+}
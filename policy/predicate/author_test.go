@@ -16,12 +16,15 @@ package predicate
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/palantir/policy-bot/policy/common"
 	"github.com/palantir/policy-bot/pull"
 	"github.com/palantir/policy-bot/pull/pulltest"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHasAuthorIn(t *testing.T) {
@@ -119,6 +122,92 @@ func TestHasAuthorIn(t *testing.T) {
 	})
 }
 
+func TestHasAuthorInFile(t *testing.T) {
+	maintainers := map[string]string{
+		"MAINTAINERS": "# core maintainers\n@mhaypenny\n\ntestorg/team # all members\n",
+		"OWNERS":      "ttest\n",
+	}
+
+	p := &HasAuthorInFile{}
+
+	runAuthorTests(t, p, []AuthorTestCase{
+		{
+			"noMatch",
+			&pulltest.Context{
+				AuthorValue:    "ttest",
+				BaseFilesValue: maintainers,
+				TeamMemberships: map[string][]string{
+					"ttest": {
+						"boringorg/testers",
+					},
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"ttest"},
+				ConditionValues: []string{"MAINTAINERS"},
+			},
+		},
+		{
+			"authorInUsers",
+			&pulltest.Context{
+				AuthorValue:    "mhaypenny",
+				BaseFilesValue: maintainers,
+			},
+			&common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"mhaypenny"},
+				ConditionValues: []string{"MAINTAINERS"},
+			},
+		},
+		{
+			"authorInTeams",
+			&pulltest.Context{
+				AuthorValue:    "mortonh",
+				BaseFilesValue: maintainers,
+				TeamMemberships: map[string][]string{
+					"mortonh": {
+						"testorg/team",
+					},
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"mortonh"},
+				ConditionValues: []string{"MAINTAINERS"},
+			},
+		},
+		{
+			"missingFile",
+			&pulltest.Context{
+				AuthorValue: "mhaypenny",
+			},
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"mhaypenny"},
+				ConditionValues: []string{"MAINTAINERS"},
+			},
+		},
+	})
+
+	p = &HasAuthorInFile{Path: "OWNERS"}
+
+	runAuthorTests(t, p, []AuthorTestCase{
+		{
+			"customPath",
+			&pulltest.Context{
+				AuthorValue:    "ttest",
+				BaseFilesValue: maintainers,
+			},
+			&common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"ttest"},
+				ConditionValues: []string{"OWNERS"},
+			},
+		},
+	})
+}
+
 func TestHasContributorIn(t *testing.T) {
 	p := &HasContributorIn{
 		common.Actors{
@@ -574,6 +663,256 @@ func TestAuthorIsNotOnlyContributor(t *testing.T) {
 	})
 }
 
+func TestHasAuthorPermission(t *testing.T) {
+	p := &HasAuthorPermission{
+		Base: pull.PermissionWrite,
+		Head: pull.PermissionWrite,
+	}
+
+	runAuthorTests(t, p, []AuthorTestCase{
+		{
+			"maintainerInRepository",
+			&pulltest.Context{
+				AuthorValue: "mhaypenny",
+				OwnerValue:  "testorg",
+				RepoValue:   "testrepo",
+				CollaboratorsValue: []*pull.Collaborator{
+					{Name: "mhaypenny", Permissions: []pull.CollaboratorPermission{{Permission: pull.PermissionWrite}}},
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"testorg/testrepo (base): write"},
+				ConditionValues: []string{"base write", "head write"},
+			},
+		},
+		{
+			"maintainerInFork",
+			&pulltest.Context{
+				AuthorValue:    "mhaypenny",
+				OwnerValue:     "testorg",
+				RepoValue:      "testrepo",
+				HeadOwnerValue: "mhaypenny",
+				HeadRepoValue:  "testrepo",
+				CollaboratorsValue: []*pull.Collaborator{
+					{Name: "mhaypenny", Permissions: []pull.CollaboratorPermission{{Permission: pull.PermissionAdmin}}},
+				},
+				OtherPermissions: map[string]map[string]pull.Permission{
+					"mhaypenny/testrepo": {"mhaypenny": pull.PermissionAdmin},
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"testorg/testrepo (base): admin", "mhaypenny/testrepo (head): admin"},
+				ConditionValues: []string{"base write", "head write"},
+			},
+		},
+		{
+			"externalContributorInFork",
+			&pulltest.Context{
+				AuthorValue:    "ttest",
+				OwnerValue:     "testorg",
+				RepoValue:      "testrepo",
+				HeadOwnerValue: "ttest",
+				HeadRepoValue:  "testrepo",
+				OtherPermissions: map[string]map[string]pull.Permission{
+					"ttest/testrepo": {"ttest": pull.PermissionAdmin},
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"testorg/testrepo (base): none", "ttest/testrepo (head): admin"},
+				ConditionValues: []string{"base write", "head write"},
+			},
+		},
+		{
+			"maintainerInOtherFork",
+			&pulltest.Context{
+				AuthorValue:    "mhaypenny",
+				OwnerValue:     "testorg",
+				RepoValue:      "testrepo",
+				HeadOwnerValue: "ttest",
+				HeadRepoValue:  "testrepo",
+				CollaboratorsValue: []*pull.Collaborator{
+					{Name: "mhaypenny", Permissions: []pull.CollaboratorPermission{{Permission: pull.PermissionWrite}}},
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"testorg/testrepo (base): write", "ttest/testrepo (head): none"},
+				ConditionValues: []string{"base write", "head write"},
+			},
+		},
+		{
+			"ownerOfInaccessibleFork",
+			&pulltest.Context{
+				AuthorValue:    "mhaypenny",
+				OwnerValue:     "testorg",
+				RepoValue:      "testrepo",
+				HeadOwnerValue: "mhaypenny",
+				HeadRepoValue:  "testrepo",
+				CollaboratorsValue: []*pull.Collaborator{
+					{Name: "mhaypenny", Permissions: []pull.CollaboratorPermission{{Permission: pull.PermissionWrite}}},
+				},
+				OtherPermissionsError: errors.New("Could not resolve to a Repository with the name 'mhaypenny/testrepo'."),
+			},
+			&common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"testorg/testrepo (base): write", "mhaypenny/testrepo (head): admin"},
+				ConditionValues: []string{"base write", "head write"},
+			},
+		},
+		{
+			"inaccessibleOrgFork",
+			&pulltest.Context{
+				AuthorValue:    "mhaypenny",
+				OwnerValue:     "testorg",
+				RepoValue:      "testrepo",
+				HeadOwnerValue: "otherorg",
+				HeadRepoValue:  "testrepo",
+				CollaboratorsValue: []*pull.Collaborator{
+					{Name: "mhaypenny", Permissions: []pull.CollaboratorPermission{{Permission: pull.PermissionWrite}}},
+				},
+				OtherPermissionsError: errors.New("Could not resolve to a Repository with the name 'otherorg/testrepo'."),
+			},
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"testorg/testrepo (base): write", "otherorg/testrepo (head): unknown"},
+				ConditionValues: []string{"base write", "head write"},
+			},
+		},
+	})
+
+	runAuthorTests(t, &HasAuthorPermission{Base: pull.PermissionWrite}, []AuthorTestCase{
+		{
+			"baseOnly",
+			&pulltest.Context{
+				AuthorValue:    "mhaypenny",
+				OwnerValue:     "testorg",
+				RepoValue:      "testrepo",
+				HeadOwnerValue: "ttest",
+				HeadRepoValue:  "testrepo",
+				CollaboratorsValue: []*pull.Collaborator{
+					{Name: "mhaypenny", Permissions: []pull.CollaboratorPermission{{Permission: pull.PermissionWrite}}},
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"testorg/testrepo (base): write"},
+				ConditionValues: []string{"base write"},
+			},
+		},
+	})
+}
+
+func TestAuthorIsRequestedReviewer(t *testing.T) {
+	p := AuthorIsRequestedReviewer(true)
+
+	runAuthorTests(t, p, []AuthorTestCase{
+		{
+			"authorRequestedDirectly",
+			&pulltest.Context{
+				AuthorValue: "mhaypenny",
+				RequestedReviewersValue: []*pull.Reviewer{
+					{Type: pull.ReviewerUser, Name: "ttest"},
+					{Type: pull.ReviewerUser, Name: "mhaypenny"},
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"mhaypenny"},
+				ConditionValues: []string{"they are requested reviewers"},
+			},
+		},
+		{
+			"authorRequestedByTeam",
+			&pulltest.Context{
+				AuthorValue: "mhaypenny",
+				OwnerValue:  "testorg",
+				RequestedReviewersValue: []*pull.Reviewer{
+					{Type: pull.ReviewerTeam, Name: "platform"},
+				},
+				TeamMemberships: map[string][]string{
+					"mhaypenny": {"testorg/platform"},
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"mhaypenny"},
+				ConditionValues: []string{"they are requested reviewers"},
+			},
+		},
+		{
+			"authorRequestRemoved",
+			&pulltest.Context{
+				AuthorValue: "mhaypenny",
+				RequestedReviewersValue: []*pull.Reviewer{
+					{Type: pull.ReviewerUser, Name: "mhaypenny", Removed: true},
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"mhaypenny"},
+				ConditionValues: []string{"they are requested reviewers"},
+			},
+		},
+		{
+			"authorNotRequested",
+			&pulltest.Context{
+				AuthorValue: "mhaypenny",
+				OwnerValue:  "testorg",
+				RequestedReviewersValue: []*pull.Reviewer{
+					{Type: pull.ReviewerUser, Name: "ttest"},
+					{Type: pull.ReviewerTeam, Name: "security"},
+				},
+				TeamMemberships: map[string][]string{
+					"mhaypenny": {"testorg/platform"},
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"mhaypenny"},
+				ConditionValues: []string{"they are requested reviewers"},
+			},
+		},
+	})
+}
+
+func TestAuthorIsNotRequestedReviewer(t *testing.T) {
+	p := AuthorIsRequestedReviewer(false)
+
+	runAuthorTests(t, p, []AuthorTestCase{
+		{
+			"authorRequested",
+			&pulltest.Context{
+				AuthorValue: "mhaypenny",
+				RequestedReviewersValue: []*pull.Reviewer{
+					{Type: pull.ReviewerUser, Name: "mhaypenny"},
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"mhaypenny"},
+				ConditionValues: []string{"they are not requested reviewers"},
+			},
+		},
+		{
+			"authorNotRequested",
+			&pulltest.Context{
+				AuthorValue: "mhaypenny",
+				RequestedReviewersValue: []*pull.Reviewer{
+					{Type: pull.ReviewerUser, Name: "ttest"},
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"mhaypenny"},
+				ConditionValues: []string{"they are not requested reviewers"},
+			},
+		},
+	})
+}
+
 type AuthorTestCase struct {
 	Name                    string
 	Context                 pull.Context
@@ -592,3 +931,201 @@ func runAuthorTests(t *testing.T, p Predicate, cases []AuthorTestCase) {
 		})
 	}
 }
+
+func TestAuthorRemovedReviewers(t *testing.T) {
+	reviewers := []*pull.Reviewer{
+		{Type: pull.ReviewerUser, Name: "ttest"},
+		{Type: pull.ReviewerUser, Name: "bkeyes", Removed: true, RemovedBy: "mhaypenny"},
+		{Type: pull.ReviewerUser, Name: "jgiannuzzi", Removed: true, RemovedBy: "ttest"},
+		{Type: pull.ReviewerTeam, Name: "platform", Removed: true, RemovedBy: "mhaypenny"},
+		{Type: pull.ReviewerTeam, Name: "docs", Removed: true, RemovedBy: "mhaypenny"},
+	}
+
+	t.Run("anyReviewer", func(t *testing.T) {
+		p := &AuthorRemovedReviewers{}
+
+		runAuthorTests(t, p, []AuthorTestCase{
+			{
+				"authorRemovedReviewers",
+				&pulltest.Context{
+					AuthorValue:             "mhaypenny",
+					OwnerValue:              "testorg",
+					RequestedReviewersValue: reviewers,
+				},
+				&common.PredicateResult{
+					Satisfied:       true,
+					Values:          []string{"bkeyes", "testorg/docs", "testorg/platform"},
+					ConditionValues: []string{"they were removed by the author"},
+				},
+			},
+			{
+				"otherUserRemovedReviewers",
+				&pulltest.Context{
+					AuthorValue:             "bkeyes",
+					OwnerValue:              "testorg",
+					RequestedReviewersValue: reviewers,
+				},
+				&common.PredicateResult{
+					Satisfied:       false,
+					ConditionValues: []string{"they were removed by the author"},
+				},
+			},
+		})
+	})
+
+	t.Run("requiredReviewers", func(t *testing.T) {
+		p := &AuthorRemovedReviewers{
+			Actors: common.Actors{
+				Users: []string{"jgiannuzzi"},
+				Teams: []string{"testorg/platform"},
+			},
+		}
+
+		runAuthorTests(t, p, []AuthorTestCase{
+			{
+				"authorRemovedRequiredTeam",
+				&pulltest.Context{
+					AuthorValue:             "mhaypenny",
+					OwnerValue:              "testorg",
+					RequestedReviewersValue: reviewers,
+				},
+				&common.PredicateResult{
+					Satisfied:       true,
+					Values:          []string{"testorg/platform"},
+					ConditionValues: []string{"they were removed by the author"},
+				},
+			},
+			{
+				"authorRemovedOtherReviewers",
+				&pulltest.Context{
+					AuthorValue: "mhaypenny",
+					OwnerValue:  "testorg",
+					RequestedReviewersValue: []*pull.Reviewer{
+						{Type: pull.ReviewerUser, Name: "bkeyes", Removed: true, RemovedBy: "mhaypenny"},
+						{Type: pull.ReviewerUser, Name: "jgiannuzzi", Removed: true, RemovedBy: "ttest"},
+					},
+				},
+				&common.PredicateResult{
+					Satisfied:       false,
+					ConditionValues: []string{"they were removed by the author"},
+				},
+			},
+		})
+	})
+}
+
+func TestAuthorIsCodeOwner(t *testing.T) {
+	baseFiles := map[string]string{
+		".github/CODEOWNERS": strings.Join([]string{
+			"*          @palantir/platform",
+			"/docs/     @mhaypenny",
+			"/server/   @ttest @palantir/backend",
+			"/vendor/",
+		}, "\n"),
+	}
+
+	newContext := func(files ...string) *pulltest.Context {
+		prctx := &pulltest.Context{
+			AuthorValue:    "mhaypenny",
+			BaseFilesValue: baseFiles,
+			TeamMemberships: map[string][]string{
+				"mhaypenny": {"palantir/backend"},
+			},
+		}
+		for _, f := range files {
+			prctx.ChangedFilesValue = append(prctx.ChangedFilesValue, &pull.File{Filename: f, Status: pull.FileModified})
+		}
+		return prctx
+	}
+
+	runAuthorTests(t, AuthorIsCodeOwner(true), []AuthorTestCase{
+		{
+			"fullOwnership",
+			newContext("docs/README.md", "server/server.go", "docs/api/index.md"),
+			&common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"mhaypenny"},
+				ConditionValues: []string{"they own all changed files"},
+			},
+		},
+		{
+			"partialOwnership",
+			newContext("docs/README.md", "pull/github.go"),
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"mhaypenny"},
+				ConditionValues: []string{"they own all changed files"},
+			},
+		},
+		{
+			"noOwnership",
+			newContext("pull/github.go", "vendor/modules.txt"),
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"mhaypenny"},
+				ConditionValues: []string{"they own all changed files"},
+			},
+		},
+		{
+			"noCodeOwnersFile",
+			&pulltest.Context{
+				AuthorValue: "mhaypenny",
+				ChangedFilesValue: []*pull.File{
+					{Filename: "docs/README.md", Status: pull.FileModified},
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"mhaypenny"},
+				ConditionValues: []string{"they own all changed files"},
+			},
+		},
+		{
+			"noChangedFiles",
+			newContext(),
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"mhaypenny"},
+				ConditionValues: []string{"they own all changed files"},
+			},
+		},
+	})
+
+	runAuthorTests(t, AuthorIsCodeOwner(false), []AuthorTestCase{
+		{
+			"invertedFullOwnership",
+			newContext("docs/README.md", "server/server.go"),
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"mhaypenny"},
+				ConditionValues: []string{"they do not own all changed files"},
+			},
+		},
+		{
+			"invertedPartialOwnership",
+			newContext("docs/README.md", "pull/github.go"),
+			&common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"mhaypenny"},
+				ConditionValues: []string{"they do not own all changed files"},
+			},
+		},
+	})
+
+	t.Run("teamOwnership", func(t *testing.T) {
+		prctx := newContext("pull/github.go")
+		prctx.TeamMemberships["mhaypenny"] = append(prctx.TeamMemberships["mhaypenny"], "palantir/platform")
+
+		result, err := AuthorIsCodeOwner(true).Evaluate(context.Background(), prctx)
+		require.NoError(t, err)
+		assert.True(t, result.Satisfied, "author should own files through team membership")
+	})
+
+	t.Run("changedFilesError", func(t *testing.T) {
+		prctx := newContext()
+		prctx.ChangedFilesError = assert.AnError
+
+		_, err := AuthorIsCodeOwner(true).Evaluate(context.Background(), prctx)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull"
+)
+
+// HasAutoMerge is satisfied if auto-merge is enabled on the pull request. If
+// false, it is satisfied if auto-merge is not enabled.
+type HasAutoMerge bool
+
+var _ Predicate = HasAutoMerge(false)
+
+func (pred HasAutoMerge) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "auto-merge",
+		ConditionPhrase: "meet the condition",
+	}
+	if pred {
+		predicateResult.ConditionValues = []string{"it is enabled"}
+	} else {
+		predicateResult.ConditionValues = []string{"it is disabled"}
+	}
+
+	am := prctx.AutoMerge()
+	switch {
+	case am == nil:
+		predicateResult.Values = []string{"disabled"}
+	case am.EnabledBy != "":
+		predicateResult.Values = []string{fmt.Sprintf("enabled by %s (%s)", am.EnabledBy, am.MergeMethod)}
+	default:
+		predicateResult.Values = []string{fmt.Sprintf("enabled (%s)", am.MergeMethod)}
+	}
+
+	switch {
+	case am != nil && !bool(pred):
+		predicateResult.Description = "Auto-merge is enabled on the pull request"
+	case am == nil && bool(pred):
+		predicateResult.Description = "Auto-merge is not enabled on the pull request"
+	default:
+		predicateResult.Satisfied = true
+	}
+	return &predicateResult, nil
+}
+
+func (pred HasAutoMerge) Trigger() common.Trigger {
+	return common.TriggerPullRequest
+}
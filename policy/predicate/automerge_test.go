@@ -0,0 +1,95 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/palantir/policy-bot/pull/pulltest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHasAutoMerge(t *testing.T) {
+	enabled := &pull.AutoMerge{EnabledBy: "mhaypenny", MergeMethod: "squash"}
+
+	tests := []struct {
+		Name      string
+		Pred      HasAutoMerge
+		AutoMerge *pull.AutoMerge
+		Expected  *common.PredicateResult
+	}{
+		{
+			Name:      "enabled",
+			Pred:      true,
+			AutoMerge: enabled,
+			Expected: &common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"enabled by mhaypenny (squash)"},
+				ConditionValues: []string{"it is enabled"},
+			},
+		},
+		{
+			Name:      "enabledUnknownUser",
+			Pred:      true,
+			AutoMerge: &pull.AutoMerge{MergeMethod: "merge"},
+			Expected: &common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"enabled (merge)"},
+				ConditionValues: []string{"it is enabled"},
+			},
+		},
+		{
+			Name: "disabled",
+			Pred: true,
+			Expected: &common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"disabled"},
+				ConditionValues: []string{"it is enabled"},
+			},
+		},
+		{
+			Name:      "invertedEnabled",
+			Pred:      false,
+			AutoMerge: enabled,
+			Expected: &common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"enabled by mhaypenny (squash)"},
+				ConditionValues: []string{"it is disabled"},
+			},
+		},
+		{
+			Name: "invertedDisabled",
+			Pred: false,
+			Expected: &common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"disabled"},
+				ConditionValues: []string{"it is disabled"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			prctx := &pulltest.Context{AutoMergeValue: test.AutoMerge}
+
+			result, err := test.Pred.Evaluate(context.Background(), prctx)
+			require.NoError(t, err)
+			assertPredicateResult(t, test.Expected, result)
+		})
+	}
+}
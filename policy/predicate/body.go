@@ -0,0 +1,132 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/pkg/errors"
+)
+
+const (
+	BodyCheckboxesAllChecked = "all_checked"
+)
+
+// Body is satisfied if the description of the pull request meets all of the
+// configured conditions. Length is compared with the number of characters in
+// the description, ignoring leading and trailing whitespace. If Checkboxes is
+// "all_checked", every Markdown task list item in the description must be
+// checked; descriptions without any items satisfy this condition.
+type Body struct {
+	Length     ComparisonExpr `yaml:"length"`
+	Checkboxes string         `yaml:"checkboxes"`
+}
+
+var _ Predicate = &Body{}
+
+var (
+	checkboxPattern = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+\[([ xX])\]`)
+	commentPattern  = regexp.MustCompile(`(?s)<!--.*?-->`)
+)
+
+func (pred *Body) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	switch pred.Checkboxes {
+	case "", BodyCheckboxesAllChecked:
+	default:
+		return nil, errors.Errorf("invalid checkboxes value %q", pred.Checkboxes)
+	}
+
+	body, err := prctx.Body()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get pull request body")
+	}
+
+	var text string
+	if body != nil {
+		text = body.Body
+	}
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "pull request description",
+		ConditionPhrase: "meets the conditions",
+	}
+
+	var descriptions []string
+
+	if !pred.Length.IsEmpty() {
+		length := int64(utf8.RuneCountInString(strings.TrimSpace(text)))
+		predicateResult.Values = append(predicateResult.Values, fmt.Sprintf("length %d", length))
+		predicateResult.ConditionValues = append(predicateResult.ConditionValues, fmt.Sprintf("length %s", pred.Length))
+
+		if !pred.Length.Evaluate(length) {
+			descriptions = append(descriptions, fmt.Sprintf("the length (%d) does not match the condition %s", length, pred.Length))
+		}
+	}
+
+	if pred.Checkboxes == BodyCheckboxesAllChecked {
+		checked, total := countCheckboxes(text)
+		predicateResult.Values = append(predicateResult.Values, fmt.Sprintf("%d/%d checkboxes checked", checked, total))
+		predicateResult.ConditionValues = append(predicateResult.ConditionValues, "all checkboxes checked")
+
+		if checked < total {
+			descriptions = append(descriptions, fmt.Sprintf("%d of %d checkboxes are not checked", total-checked, total))
+		}
+	}
+
+	if len(descriptions) > 0 {
+		predicateResult.Description = "The pull request description does not meet the conditions: " + strings.Join(descriptions, ", ")
+		return &predicateResult, nil
+	}
+
+	predicateResult.Satisfied = true
+	return &predicateResult, nil
+}
+
+func (pred *Body) Trigger() common.Trigger {
+	return common.TriggerPullRequest
+}
+
+// countCheckboxes returns the number of checked task list items and the total
+// number of task list items in the Markdown text. Items in HTML comments and
+// fenced code blocks are ignored.
+func countCheckboxes(text string) (checked int, total int) {
+	text = commentPattern.ReplaceAllString(text, "")
+
+	inFence := false
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+
+		if m := checkboxPattern.FindStringSubmatch(line); m != nil {
+			total++
+			if m[1] != " " {
+				checked++
+			}
+		}
+	}
+	return checked, total
+}
@@ -0,0 +1,93 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/palantir/policy-bot/pull/pulltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBody(t *testing.T) {
+	p := &Body{
+		Length:     ComparisonExpr{Op: OpGreaterThan, Value: 0},
+		Checkboxes: BodyCheckboxesAllChecked,
+	}
+
+	tests := []struct {
+		Name     string
+		Body     string
+		Expected *common.PredicateResult
+	}{
+		{
+			Name: "empty",
+			Body: "  \n",
+			Expected: &common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"length 0", "0/0 checkboxes checked"},
+				ConditionValues: []string{"length > 0", "all checkboxes checked"},
+			},
+		},
+		{
+			Name: "partialChecklist",
+			Body: "Adds a feature.\n\n- [x] Added tests\n- [ ] Updated docs\n* [X] Ran linter\n1. [ ] Checked migration",
+			Expected: &common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"length 95", "2/4 checkboxes checked"},
+				ConditionValues: []string{"length > 0", "all checkboxes checked"},
+			},
+		},
+		{
+			Name: "completeChecklist",
+			Body: "Adds a feature.\r\n\r\n- [x] Added tests\r\n  - [x] Updated docs",
+			Expected: &common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"length 58", "2/2 checkboxes checked"},
+				ConditionValues: []string{"length > 0", "all checkboxes checked"},
+			},
+		},
+		{
+			Name: "ignoresCommentsAndCode",
+			Body: "<!--\n- [ ] Remove this comment\n-->\nExample:\n```\n- [ ] not a task\n```\n- [x] Done",
+			Expected: &common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"length 79", "1/1 checkboxes checked"},
+				ConditionValues: []string{"length > 0", "all checkboxes checked"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			prctx := &pulltest.Context{
+				BodyValue: &pull.Body{Body: test.Body},
+			}
+
+			result, err := p.Evaluate(context.Background(), prctx)
+			require.NoError(t, err)
+			assertPredicateResult(t, test.Expected, result)
+		})
+	}
+
+	t.Run("invalidCheckboxes", func(t *testing.T) {
+		_, err := (&Body{Checkboxes: "some_checked"}).Evaluate(context.Background(), &pulltest.Context{})
+		assert.Error(t, err)
+	})
+}
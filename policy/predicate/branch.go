@@ -20,8 +20,11 @@ import (
 
 	"github.com/palantir/policy-bot/policy/common"
 	"github.com/palantir/policy-bot/pull"
+	"github.com/pkg/errors"
 )
 
+// TargetsBranch is satisfied if the base branch of the pull request matches
+// the pattern.
 type TargetsBranch struct {
 	Pattern common.Regexp `yaml:"pattern"`
 }
@@ -53,6 +56,50 @@ func (pred *TargetsBranch) Trigger() common.Trigger {
 	return common.TriggerPullRequest
 }
 
+// TargetsProtectedBranch is satisfied if the base branch of the pull request
+// has branch protection enabled. If false, it is satisfied if the base branch
+// is not protected.
+type TargetsProtectedBranch bool
+
+var _ Predicate = TargetsProtectedBranch(false)
+
+func (pred TargetsProtectedBranch) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	targetName, _ := prctx.Branches()
+
+	protected, err := prctx.IsProtectedBranch(targetName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to determine if the target branch is protected")
+	}
+
+	predicateResult := common.PredicateResult{
+		Values:          []string{targetName},
+		ValuePhrase:     "target branches",
+		ConditionPhrase: "meet the condition",
+	}
+	if pred {
+		predicateResult.ConditionValues = []string{"they are protected"}
+	} else {
+		predicateResult.ConditionValues = []string{"they are not protected"}
+	}
+
+	switch {
+	case protected && !bool(pred):
+		predicateResult.Description = fmt.Sprintf("Target branch %q is protected", targetName)
+	case !protected && bool(pred):
+		predicateResult.Description = fmt.Sprintf("Target branch %q is not protected", targetName)
+	default:
+		predicateResult.Satisfied = true
+	}
+	return &predicateResult, nil
+}
+
+func (pred TargetsProtectedBranch) Trigger() common.Trigger {
+	return common.TriggerPullRequest
+}
+
+// FromBranch is satisfied if the head branch of the pull request matches the
+// pattern. For pull requests from forks, the branch name is prefixed by the
+// owner of the fork, as in "owner:branch".
 type FromBranch struct {
 	Pattern common.Regexp `yaml:"pattern"`
 }
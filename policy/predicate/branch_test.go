@@ -22,6 +22,7 @@ import (
 	"github.com/palantir/policy-bot/policy/common"
 	"github.com/palantir/policy-bot/pull/pulltest"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBranches(t *testing.T) {
@@ -107,6 +108,61 @@ func TestBranches(t *testing.T) {
 	})
 }
 
+func TestFromBranchFork(t *testing.T) {
+	ctx := context.Background()
+
+	// head branches from forks include the owner of the fork as a prefix
+	prctx := &pulltest.Context{
+		BranchBaseName: "main",
+		BranchHeadName: "contributor:release/1.0",
+	}
+
+	t.Run("prefix not matched", func(t *testing.T) {
+		p := &FromBranch{
+			Pattern: common.NewCompiledRegexp(regexp.MustCompile("^release/")),
+		}
+
+		predicateResult, err := p.Evaluate(ctx, prctx)
+		if assert.NoError(t, err, "from_branch predicate evaluation failed") {
+			assertPredicateResult(t, &common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"contributor:release/1.0"},
+				ConditionValues: []string{"^release/"},
+			}, predicateResult)
+		}
+	})
+
+	t.Run("prefix matched", func(t *testing.T) {
+		p := &FromBranch{
+			Pattern: common.NewCompiledRegexp(regexp.MustCompile("^([^:]+:)?release/")),
+		}
+
+		predicateResult, err := p.Evaluate(ctx, prctx)
+		if assert.NoError(t, err, "from_branch predicate evaluation failed") {
+			assertPredicateResult(t, &common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"contributor:release/1.0"},
+				ConditionValues: []string{"^([^:]+:)?release/"},
+			}, predicateResult)
+		}
+	})
+
+	t.Run("target has no prefix", func(t *testing.T) {
+		p := &TargetsBranch{
+			Pattern: common.NewCompiledRegexp(regexp.MustCompile("^main$")),
+		}
+
+		predicateResult, err := p.Evaluate(ctx, prctx)
+		if assert.NoError(t, err, "targets_branch predicate evaluation failed") {
+			assertPredicateResult(t, &common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"main"},
+				ConditionValues: []string{"^main$"},
+			}, predicateResult)
+		}
+	})
+}
+
 // TODO: generalize this and use it all our test cases
 type branchesTestCase struct {
 	name                    string
@@ -149,3 +205,57 @@ func runBranchesTestCase(t *testing.T, regex string, cases []branchesTestCase) {
 		})
 	}
 }
+
+func TestTargetsProtectedBranch(t *testing.T) {
+	ctx := context.Background()
+
+	prctx := &pulltest.Context{
+		BranchBaseName:         "main",
+		ProtectedBranchesValue: []string{"main"},
+	}
+
+	t.Run("protectedSatisfied", func(t *testing.T) {
+		result, err := TargetsProtectedBranch(true).Evaluate(ctx, prctx)
+		require.NoError(t, err)
+		assertPredicateResult(t, &common.PredicateResult{
+			Satisfied:       true,
+			Values:          []string{"main"},
+			ConditionValues: []string{"they are protected"},
+		}, result)
+	})
+
+	t.Run("protectedNotSatisfied", func(t *testing.T) {
+		result, err := TargetsProtectedBranch(false).Evaluate(ctx, prctx)
+		require.NoError(t, err)
+		assertPredicateResult(t, &common.PredicateResult{
+			Satisfied:       false,
+			Values:          []string{"main"},
+			ConditionValues: []string{"they are not protected"},
+		}, result)
+	})
+
+	unprotected := &pulltest.Context{
+		BranchBaseName:         "develop",
+		ProtectedBranchesValue: []string{"main"},
+	}
+
+	t.Run("unprotectedSatisfied", func(t *testing.T) {
+		result, err := TargetsProtectedBranch(false).Evaluate(ctx, unprotected)
+		require.NoError(t, err)
+		assertPredicateResult(t, &common.PredicateResult{
+			Satisfied:       true,
+			Values:          []string{"develop"},
+			ConditionValues: []string{"they are not protected"},
+		}, result)
+	})
+
+	t.Run("unprotectedNotSatisfied", func(t *testing.T) {
+		result, err := TargetsProtectedBranch(true).Evaluate(ctx, unprotected)
+		require.NoError(t, err)
+		assertPredicateResult(t, &common.PredicateResult{
+			Satisfied:       false,
+			Values:          []string{"develop"},
+			ConditionValues: []string{"they are protected"},
+		}, result)
+	})
+}
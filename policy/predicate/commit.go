@@ -0,0 +1,408 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/pkg/errors"
+)
+
+// CommitsSinceApproval compares the number of commits pushed after the
+// earliest approving review with an expression. Reviews by the pull request
+// author do not count as approvals. If there are no approvals, the number of
+// commits is zero.
+type CommitsSinceApproval struct {
+	Count ComparisonExpr `yaml:"count"`
+}
+
+var _ Predicate = &CommitsSinceApproval{}
+
+func (pred *CommitsSinceApproval) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "commits since approval",
+		ConditionPhrase: "meet the condition",
+		ConditionValues: []string{pred.Count.String()},
+	}
+
+	reviews, err := prctx.Reviews()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list reviews")
+	}
+
+	var approvedAt time.Time
+	for _, r := range reviews {
+		if r.State != pull.ReviewApproved || r.Author == prctx.Author() {
+			continue
+		}
+		if approvedAt.IsZero() || r.CreatedAt.Before(approvedAt) {
+			approvedAt = r.CreatedAt
+		}
+	}
+
+	var count int64
+	if !approvedAt.IsZero() {
+		commits, err := prctx.Commits()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list commits")
+		}
+
+		for _, c := range commits {
+			pushedAt, err := prctx.PushedAt(c.SHA)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to get push timestamp")
+			}
+			if pushedAt.After(approvedAt) {
+				count++
+			}
+		}
+	}
+
+	predicateResult.Values = []string{strconv.FormatInt(count, 10)}
+	if pred.Count.Evaluate(count) {
+		predicateResult.Satisfied = true
+		return &predicateResult, nil
+	}
+
+	predicateResult.Description = fmt.Sprintf("The number of commits since the first approval (%d) does not match the condition %s", count, pred.Count)
+	predicateResult.Satisfied = false
+	return &predicateResult, nil
+}
+
+func (pred *CommitsSinceApproval) Trigger() common.Trigger {
+	return common.TriggerCommit | common.TriggerReview
+}
+
+// CommitCount compares the number of commits in the pull request with an
+// expression.
+type CommitCount struct {
+	Count ComparisonExpr `yaml:"count"`
+}
+
+var _ Predicate = &CommitCount{}
+
+func (pred *CommitCount) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	commits, err := prctx.Commits()
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "commits",
+		ConditionPhrase: "meet the condition",
+		ConditionValues: []string{fmt.Sprintf("commit count %s", pred.Count.String())},
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list commits")
+	}
+
+	count := int64(len(commits))
+	predicateResult.Values = []string{fmt.Sprintf("commits %d", count)}
+
+	if pred.Count.Evaluate(count) {
+		predicateResult.Satisfied = true
+		return &predicateResult, nil
+	}
+
+	predicateResult.Description = fmt.Sprintf("The number of commits (%d) does not match the condition %s", count, pred.Count)
+	predicateResult.Satisfied = false
+	return &predicateResult, nil
+}
+
+func (pred *CommitCount) Trigger() common.Trigger {
+	return common.TriggerCommit
+}
+
+// HasChronologicalCommits is satisfied if the author and committer timestamps
+// of the commits in the pull request increase in history order. Commits with
+// timestamps earlier than their parent can indicate a rebase that reordered
+// commits or a commit with falsified metadata.
+type HasChronologicalCommits bool
+
+var _ Predicate = HasChronologicalCommits(false)
+
+func (pred HasChronologicalCommits) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	commits, err := prctx.Commits()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get commits")
+	}
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "commits",
+		ConditionPhrase: "have",
+		ConditionValues: []string{"timestamps in history order"},
+	}
+
+	ordered := historyOrder(commits, prctx.HeadSHA())
+
+	var unordered, all []string
+	for i, c := range ordered {
+		all = append(all, c.SHA)
+		if i == 0 {
+			continue
+		}
+
+		parent := ordered[i-1]
+		if isBefore(c.AuthoredAt, parent.AuthoredAt) || isBefore(c.CommittedAt, parent.CommittedAt) {
+			unordered = append(unordered, c.SHA)
+		}
+	}
+
+	if len(unordered) > 0 {
+		predicateResult.Values = unordered
+		if pred {
+			predicateResult.Description = fmt.Sprintf("Commit %.10s has a timestamp earlier than its parent", unordered[0])
+			predicateResult.Satisfied = false
+			return &predicateResult, nil
+		}
+		predicateResult.Satisfied = true
+		return &predicateResult, nil
+	}
+
+	predicateResult.Values = all
+	if pred {
+		predicateResult.Satisfied = true
+		return &predicateResult, nil
+	}
+	predicateResult.Description = "All commits have timestamps in history order"
+	predicateResult.Satisfied = false
+	return &predicateResult, nil
+}
+
+func (pred HasChronologicalCommits) Trigger() common.Trigger {
+	return common.TriggerCommit
+}
+
+// HasLocalCommits is satisfied if any commit in the pull request was not
+// created through the GitHub web interface or API. Local commits are pushed
+// from a developer's machine instead of created by GitHub on their behalf. Use
+// false to require that all commits are created through GitHub, for example
+// by a bot or other tooling that uses the API.
+type HasLocalCommits bool
+
+var _ Predicate = HasLocalCommits(false)
+
+func (pred HasLocalCommits) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	commits, err := prctx.Commits()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get commits")
+	}
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "commits",
+		ConditionPhrase: "were",
+		ConditionValues: []string{"not created via the web or API"},
+	}
+
+	var local []string
+	for _, c := range commits {
+		if !c.CommittedViaWeb {
+			local = append(local, c.SHA)
+		}
+	}
+	predicateResult.Values = local
+
+	switch {
+	case bool(pred) && len(local) == 0:
+		predicateResult.Description = "All commits were created via the web or API"
+		predicateResult.Satisfied = false
+	case !bool(pred) && len(local) > 0:
+		predicateResult.Description = fmt.Sprintf("Commit %.10s was not created via the web or API", local[0])
+		predicateResult.Satisfied = false
+	default:
+		predicateResult.Satisfied = true
+	}
+	return &predicateResult, nil
+}
+
+func (pred HasLocalCommits) Trigger() common.Trigger {
+	return common.TriggerCommit
+}
+
+// HasLinearHistory is satisfied if no commit in the pull request is a merge
+// commit, meaning a commit with more than one parent. If IgnoreUpdateMerges
+// is true, merge commits that update the pull request with changes from the
+// target branch are allowed.
+type HasLinearHistory struct {
+	IgnoreUpdateMerges bool `yaml:"ignore_update_merges"`
+}
+
+var _ Predicate = &HasLinearHistory{}
+
+func (pred *HasLinearHistory) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	commits, err := prctx.Commits()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get commits")
+	}
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "merge commits",
+		ConditionPhrase: "exist in the pull request",
+		ConditionValues: []string{"none"},
+	}
+
+	var merges []string
+	for _, c := range commits {
+		if len(c.Parents) <= 1 {
+			continue
+		}
+		if pred.IgnoreUpdateMerges && pull.IsUpdateMerge(commits, c) {
+			continue
+		}
+		merges = append(merges, c.SHA)
+	}
+	predicateResult.Values = merges
+
+	if len(merges) > 0 {
+		predicateResult.Description = fmt.Sprintf("Commit %.10s is a merge commit", merges[0])
+		if len(merges) > 1 {
+			predicateResult.Description = fmt.Sprintf("%d commits are merge commits", len(merges))
+		}
+		predicateResult.Satisfied = false
+		return &predicateResult, nil
+	}
+
+	predicateResult.Satisfied = true
+	return &predicateResult, nil
+}
+
+func (pred *HasLinearHistory) Trigger() common.Trigger {
+	return common.TriggerCommit
+}
+
+const (
+	CommitMessagesAll = "all"
+	CommitMessagesAny = "any"
+)
+
+// CommitMessages is satisfied if the messages of the commits in the pull
+// request match any of the patterns. In "all" mode, the default, every commit
+// must match. In "any" mode, at least one commit must match. Commits with
+// messages that match any of the ignore patterns are not considered.
+type CommitMessages struct {
+	Patterns       []common.Regexp `yaml:"patterns"`
+	IgnorePatterns []common.Regexp `yaml:"ignore"`
+	Mode           string          `yaml:"mode"`
+}
+
+var _ Predicate = &CommitMessages{}
+
+func (pred *CommitMessages) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	var patterns, ignorePatterns []string
+
+	for _, pattern := range pred.Patterns {
+		patterns = append(patterns, pattern.String())
+	}
+
+	for _, ignorePattern := range pred.IgnorePatterns {
+		ignorePatterns = append(ignorePatterns, ignorePattern.String())
+	}
+
+	mode := pred.Mode
+	if mode == "" {
+		mode = CommitMessagesAll
+	}
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase: "commits",
+		ConditionsMap: map[string][]string{
+			"message patterns": patterns,
+			"while ignoring":   ignorePatterns,
+		},
+	}
+
+	switch mode {
+	case CommitMessagesAll:
+		predicateResult.ConditionPhrase = "all have messages that match"
+	case CommitMessagesAny:
+		predicateResult.ConditionPhrase = "have at least one message that matches"
+	default:
+		return nil, errors.Errorf("invalid commit messages mode %q", pred.Mode)
+	}
+
+	commits, err := prctx.Commits()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get commits")
+	}
+
+	considered := []string{}
+	for _, c := range commits {
+		if anyMatches(pred.IgnorePatterns, c.Message) {
+			continue
+		}
+		considered = append(considered, c.SHA)
+
+		matches := anyMatches(pred.Patterns, c.Message)
+		if mode == CommitMessagesAll && !matches {
+			predicateResult.Values = []string{c.SHA}
+			predicateResult.Description = fmt.Sprintf("The message of commit %.10s does not match the required patterns", c.SHA)
+			predicateResult.Satisfied = false
+			return &predicateResult, nil
+		}
+		if mode == CommitMessagesAny && matches {
+			predicateResult.Values = []string{c.SHA}
+			predicateResult.Satisfied = true
+			return &predicateResult, nil
+		}
+	}
+
+	predicateResult.Values = considered
+	if mode == CommitMessagesAny {
+		predicateResult.Description = "No commit messages match the required patterns"
+		predicateResult.Satisfied = false
+		return &predicateResult, nil
+	}
+	predicateResult.Satisfied = true
+	return &predicateResult, nil
+}
+
+func (pred *CommitMessages) Trigger() common.Trigger {
+	return common.TriggerCommit
+}
+
+// historyOrder returns the commits reachable from head by following first
+// parents, ordered from oldest to newest.
+func historyOrder(commits []*pull.Commit, head string) []*pull.Commit {
+	commitsBySHA := make(map[string]*pull.Commit)
+	for _, c := range commits {
+		commitsBySHA[c.SHA] = c
+	}
+
+	var ordered []*pull.Commit
+	for {
+		c, ok := commitsBySHA[head]
+		if !ok {
+			break
+		}
+		ordered = append(ordered, c)
+		if len(c.Parents) == 0 {
+			break
+		}
+		head = c.Parents[0]
+	}
+
+	slices.Reverse(ordered)
+	return ordered
+}
+
+// isBefore returns true if t is before u. Zero times are never before or
+// after any other time.
+func isBefore(t, u time.Time) bool {
+	return !t.IsZero() && !u.IsZero() && t.Before(u)
+}
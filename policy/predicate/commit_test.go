@@ -0,0 +1,602 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/palantir/policy-bot/pull/pulltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommitsSinceApproval(t *testing.T) {
+	now := time.Now()
+
+	p := &CommitsSinceApproval{
+		Count: ComparisonExpr{Op: OpGreaterThan, Value: 1},
+	}
+
+	prctx := func(reviews []*pull.Review) *pulltest.Context {
+		return &pulltest.Context{
+			AuthorValue: "mhaypenny",
+			CommitsValue: []*pull.Commit{
+				{SHA: "a6f3f69b64eaafece5a0d854eb4af11c0d64394c"},
+				{SHA: "1fc89f1cedf8e3f3ce516ab75b5952295c8ea5e9"},
+				{SHA: "e05fcae367230ee709313dd2720da527d178ce43"},
+			},
+			PushedAtValue: map[string]time.Time{
+				"a6f3f69b64eaafece5a0d854eb4af11c0d64394c": now.Add(-3 * time.Hour),
+				"1fc89f1cedf8e3f3ce516ab75b5952295c8ea5e9": now.Add(-1 * time.Hour),
+				"e05fcae367230ee709313dd2720da527d178ce43": now.Add(-30 * time.Minute),
+			},
+			ReviewsValue: reviews,
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		context  pull.Context
+		expected *common.PredicateResult
+	}{
+		{
+			"noApprovals",
+			prctx(nil),
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"0"},
+				ConditionValues: []string{"> 1"},
+			},
+		},
+		{
+			"commitsAfterEarliestApproval",
+			prctx([]*pull.Review{
+				{
+					CreatedAt: now.Add(-10 * time.Minute),
+					Author:    "ttest",
+					State:     pull.ReviewApproved,
+				},
+				{
+					CreatedAt: now.Add(-2 * time.Hour),
+					Author:    "jstrawnickel",
+					State:     pull.ReviewApproved,
+				},
+			}),
+			&common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"2"},
+				ConditionValues: []string{"> 1"},
+			},
+		},
+		{
+			"ignoresAuthorAndNonApprovingReviews",
+			prctx([]*pull.Review{
+				{
+					CreatedAt: now.Add(-4 * time.Hour),
+					Author:    "mhaypenny",
+					State:     pull.ReviewApproved,
+				},
+				{
+					CreatedAt: now.Add(-4 * time.Hour),
+					Author:    "ttest",
+					State:     pull.ReviewCommented,
+				},
+				{
+					CreatedAt: now.Add(-45 * time.Minute),
+					Author:    "jstrawnickel",
+					State:     pull.ReviewApproved,
+				},
+			}),
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"1"},
+				ConditionValues: []string{"> 1"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := p.Evaluate(context.Background(), tc.context)
+			if assert.NoError(t, err, "evaluation failed") {
+				assertPredicateResult(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestCommitCount(t *testing.T) {
+	commits := func(n int) pull.Context {
+		prctx := &pulltest.Context{
+			CommitsValue: []*pull.Commit{},
+		}
+		for i := 0; i < n; i++ {
+			prctx.CommitsValue = append(prctx.CommitsValue, &pull.Commit{
+				SHA: fmt.Sprintf("%040d", i),
+			})
+		}
+		return prctx
+	}
+
+	testCases := []struct {
+		name      string
+		predicate *CommitCount
+		context   pull.Context
+		expected  *common.PredicateResult
+	}{
+		{
+			"singleCommit",
+			&CommitCount{Count: ComparisonExpr{Op: OpLessThan, Value: 2}},
+			commits(1),
+			&common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"commits 1"},
+				ConditionValues: []string{"commit count < 2"},
+			},
+		},
+		{
+			"tooManyCommits",
+			&CommitCount{Count: ComparisonExpr{Op: OpLessThan, Value: 2}},
+			commits(3),
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"commits 3"},
+				ConditionValues: []string{"commit count < 2"},
+			},
+		},
+		{
+			"greaterThan",
+			&CommitCount{Count: ComparisonExpr{Op: OpGreaterThan, Value: 2}},
+			commits(3),
+			&common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"commits 3"},
+				ConditionValues: []string{"commit count > 2"},
+			},
+		},
+		{
+			"equals",
+			&CommitCount{Count: ComparisonExpr{Op: OpEquals, Value: 0}},
+			commits(0),
+			&common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"commits 0"},
+				ConditionValues: []string{"commit count = 0"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := tc.predicate.Evaluate(context.Background(), tc.context)
+			if assert.NoError(t, err, "evaluation failed") {
+				assertPredicateResult(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestHasChronologicalCommits(t *testing.T) {
+	date := func(hour int) time.Time {
+		return time.Date(2020, 9, 30, hour, 0, 0, 0, time.UTC)
+	}
+
+	prctx := func(commits []*pull.Commit) *pulltest.Context {
+		return &pulltest.Context{
+			HeadSHAValue: "e05fcae367230ee709313dd2720da527d178ce43",
+			CommitsValue: commits,
+		}
+	}
+
+	ordered := []*pull.Commit{
+		{
+			SHA:         "a6f3f69b64eaafece5a0d854eb4af11c0d64394c",
+			AuthoredAt:  date(1),
+			CommittedAt: date(1),
+		},
+		{
+			SHA:         "1fc89f1cedf8e3f3ce516ab75b5952295c8ea5e9",
+			Parents:     []string{"a6f3f69b64eaafece5a0d854eb4af11c0d64394c"},
+			AuthoredAt:  date(2),
+			CommittedAt: date(3),
+		},
+		{
+			SHA:         "e05fcae367230ee709313dd2720da527d178ce43",
+			Parents:     []string{"1fc89f1cedf8e3f3ce516ab75b5952295c8ea5e9"},
+			AuthoredAt:  date(3),
+			CommittedAt: date(3),
+		},
+	}
+
+	unordered := []*pull.Commit{
+		{
+			SHA:         "e05fcae367230ee709313dd2720da527d178ce43",
+			Parents:     []string{"1fc89f1cedf8e3f3ce516ab75b5952295c8ea5e9"},
+			AuthoredAt:  date(1),
+			CommittedAt: date(4),
+		},
+		{
+			SHA:         "a6f3f69b64eaafece5a0d854eb4af11c0d64394c",
+			AuthoredAt:  date(2),
+			CommittedAt: date(4),
+		},
+		{
+			SHA:         "1fc89f1cedf8e3f3ce516ab75b5952295c8ea5e9",
+			Parents:     []string{"a6f3f69b64eaafece5a0d854eb4af11c0d64394c"},
+			AuthoredAt:  date(3),
+			CommittedAt: date(4),
+		},
+	}
+
+	missingTimestamps := []*pull.Commit{
+		{
+			SHA:        "a6f3f69b64eaafece5a0d854eb4af11c0d64394c",
+			AuthoredAt: date(2),
+		},
+		{
+			SHA:     "e05fcae367230ee709313dd2720da527d178ce43",
+			Parents: []string{"a6f3f69b64eaafece5a0d854eb4af11c0d64394c"},
+		},
+	}
+
+	testCases := []struct {
+		name      string
+		predicate HasChronologicalCommits
+		context   pull.Context
+		expected  *common.PredicateResult
+	}{
+		{
+			"ordered",
+			true,
+			prctx(ordered),
+			&common.PredicateResult{
+				Satisfied: true,
+				Values: []string{
+					"a6f3f69b64eaafece5a0d854eb4af11c0d64394c",
+					"1fc89f1cedf8e3f3ce516ab75b5952295c8ea5e9",
+					"e05fcae367230ee709313dd2720da527d178ce43",
+				},
+				ConditionValues: []string{"timestamps in history order"},
+			},
+		},
+		{
+			"unordered",
+			true,
+			prctx(unordered),
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"e05fcae367230ee709313dd2720da527d178ce43"},
+				ConditionValues: []string{"timestamps in history order"},
+			},
+		},
+		{
+			"missingTimestamps",
+			true,
+			prctx(missingTimestamps),
+			&common.PredicateResult{
+				Satisfied: true,
+				Values: []string{
+					"a6f3f69b64eaafece5a0d854eb4af11c0d64394c",
+					"e05fcae367230ee709313dd2720da527d178ce43",
+				},
+				ConditionValues: []string{"timestamps in history order"},
+			},
+		},
+		{
+			"invertedOrdered",
+			false,
+			prctx(ordered),
+			&common.PredicateResult{
+				Satisfied: false,
+				Values: []string{
+					"a6f3f69b64eaafece5a0d854eb4af11c0d64394c",
+					"1fc89f1cedf8e3f3ce516ab75b5952295c8ea5e9",
+					"e05fcae367230ee709313dd2720da527d178ce43",
+				},
+				ConditionValues: []string{"timestamps in history order"},
+			},
+		},
+		{
+			"invertedUnordered",
+			false,
+			prctx(unordered),
+			&common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"e05fcae367230ee709313dd2720da527d178ce43"},
+				ConditionValues: []string{"timestamps in history order"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := tc.predicate.Evaluate(context.Background(), tc.context)
+			if assert.NoError(t, err, "evaluation failed") {
+				assertPredicateResult(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestHasLocalCommits(t *testing.T) {
+	web := []*pull.Commit{
+		{SHA: "a6f3f69b64eaafece5a0d854eb4af11c0d64394c", CommittedViaWeb: true},
+		{SHA: "1fc89f1cedf8e3f3ce516ab75b5952295c8ea5e9", CommittedViaWeb: true},
+	}
+
+	mixed := []*pull.Commit{
+		{SHA: "a6f3f69b64eaafece5a0d854eb4af11c0d64394c", CommittedViaWeb: true},
+		{SHA: "1fc89f1cedf8e3f3ce516ab75b5952295c8ea5e9"},
+		{SHA: "e05fcae367230ee709313dd2720da527d178ce43"},
+	}
+
+	testCases := []struct {
+		name      string
+		predicate HasLocalCommits
+		commits   []*pull.Commit
+		expected  *common.PredicateResult
+	}{
+		{
+			"webOnly",
+			true,
+			web,
+			&common.PredicateResult{
+				Satisfied:       false,
+				ConditionValues: []string{"not created via the web or API"},
+			},
+		},
+		{
+			"mixed",
+			true,
+			mixed,
+			&common.PredicateResult{
+				Satisfied: true,
+				Values: []string{
+					"1fc89f1cedf8e3f3ce516ab75b5952295c8ea5e9",
+					"e05fcae367230ee709313dd2720da527d178ce43",
+				},
+				ConditionValues: []string{"not created via the web or API"},
+			},
+		},
+		{
+			"invertedWebOnly",
+			false,
+			web,
+			&common.PredicateResult{
+				Satisfied:       true,
+				ConditionValues: []string{"not created via the web or API"},
+			},
+		},
+		{
+			"invertedMixed",
+			false,
+			mixed,
+			&common.PredicateResult{
+				Satisfied: false,
+				Values: []string{
+					"1fc89f1cedf8e3f3ce516ab75b5952295c8ea5e9",
+					"e05fcae367230ee709313dd2720da527d178ce43",
+				},
+				ConditionValues: []string{"not created via the web or API"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prctx := &pulltest.Context{CommitsValue: tc.commits}
+
+			result, err := tc.predicate.Evaluate(context.Background(), prctx)
+			if assert.NoError(t, err, "evaluation failed") {
+				assertPredicateResult(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestHasLinearHistory(t *testing.T) {
+	linear := []*pull.Commit{
+		{SHA: "a6f3f69b64eaafece5a0d854eb4af11c0d64394c", Parents: []string{"7f1e3c9d2b8a4f6e0c5d1a9b3e7f2c8d4a6b0e1f"}},
+		{SHA: "1fc89f1cedf8e3f3ce516ab75b5952295c8ea5e9", Parents: []string{"a6f3f69b64eaafece5a0d854eb4af11c0d64394c"}},
+	}
+
+	// the update merge brings in a commit from the base branch and the local
+	// merge joins a commit from another branch
+	merges := []*pull.Commit{
+		{SHA: "a6f3f69b64eaafece5a0d854eb4af11c0d64394c", Parents: []string{"7f1e3c9d2b8a4f6e0c5d1a9b3e7f2c8d4a6b0e1f"}},
+		{SHA: "5e2c8a1f9d3b7e4a6c0f2d8b1e5a9c3f7d4b6e0a", Parents: []string{"7f1e3c9d2b8a4f6e0c5d1a9b3e7f2c8d4a6b0e1f"}},
+		{
+			SHA:             "1fc89f1cedf8e3f3ce516ab75b5952295c8ea5e9",
+			Parents:         []string{"a6f3f69b64eaafece5a0d854eb4af11c0d64394c", "9b4d2f8e1a6c3e7b5d0f4a2c8e6b1d9f3a7c5e2b"},
+			CommittedViaWeb: true,
+		},
+		{
+			SHA:     "e05fcae367230ee709313dd2720da527d178ce43",
+			Parents: []string{"1fc89f1cedf8e3f3ce516ab75b5952295c8ea5e9", "5e2c8a1f9d3b7e4a6c0f2d8b1e5a9c3f7d4b6e0a"},
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		predicate   *HasLinearHistory
+		commits     []*pull.Commit
+		expected    *common.PredicateResult
+		description string
+	}{
+		{
+			"linear",
+			&HasLinearHistory{},
+			linear,
+			&common.PredicateResult{
+				Satisfied:       true,
+				ConditionValues: []string{"none"},
+			},
+			"",
+		},
+		{
+			"mergeCommits",
+			&HasLinearHistory{},
+			merges,
+			&common.PredicateResult{
+				Satisfied: false,
+				Values: []string{
+					"1fc89f1cedf8e3f3ce516ab75b5952295c8ea5e9",
+					"e05fcae367230ee709313dd2720da527d178ce43",
+				},
+				ConditionValues: []string{"none"},
+			},
+			"2 commits are merge commits",
+		},
+		{
+			"ignoreUpdateMerges",
+			&HasLinearHistory{IgnoreUpdateMerges: true},
+			merges,
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"e05fcae367230ee709313dd2720da527d178ce43"},
+				ConditionValues: []string{"none"},
+			},
+			"Commit e05fcae367 is a merge commit",
+		},
+		{
+			"ignoreUpdateMergesOnly",
+			&HasLinearHistory{IgnoreUpdateMerges: true},
+			merges[:3],
+			&common.PredicateResult{
+				Satisfied:       true,
+				ConditionValues: []string{"none"},
+			},
+			"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prctx := &pulltest.Context{CommitsValue: tc.commits}
+
+			result, err := tc.predicate.Evaluate(context.Background(), prctx)
+			if assert.NoError(t, err, "evaluation failed") {
+				assertPredicateResult(t, tc.expected, result)
+				assert.Equal(t, tc.description, result.Description)
+			}
+		})
+	}
+}
+
+func TestCommitMessages(t *testing.T) {
+	ticket := []common.Regexp{
+		common.NewCompiledRegexp(regexp.MustCompile(`^[A-Z]+-[0-9]+: `)),
+	}
+	merges := []common.Regexp{
+		common.NewCompiledRegexp(regexp.MustCompile(`^Merge branch `)),
+	}
+
+	prctx := &pulltest.Context{
+		CommitsValue: []*pull.Commit{
+			{
+				SHA:     "a6f3f69b64eaafece5a0d854eb4af11c0d64394c",
+				Message: "PROJ-123: add feature",
+			},
+			{
+				SHA:     "1fc89f1cedf8e3f3ce516ab75b5952295c8ea5e9",
+				Message: "Merge branch 'develop' into feature",
+			},
+			{
+				SHA:     "e05fcae367230ee709313dd2720da527d178ce43",
+				Message: "fix tests",
+			},
+		},
+	}
+
+	conditions := func(ignore []string) map[string][]string {
+		return map[string][]string{
+			"message patterns": {"^[A-Z]+-[0-9]+: "},
+			"while ignoring":   ignore,
+		}
+	}
+
+	testCases := []struct {
+		name      string
+		predicate *CommitMessages
+		expected  *common.PredicateResult
+	}{
+		{
+			"allNotMatching",
+			&CommitMessages{Patterns: ticket},
+			&common.PredicateResult{
+				Satisfied:     false,
+				Values:        []string{"1fc89f1cedf8e3f3ce516ab75b5952295c8ea5e9"},
+				ConditionsMap: conditions(nil),
+			},
+		},
+		{
+			"allWithIgnoredCommits",
+			&CommitMessages{
+				Patterns:       ticket,
+				IgnorePatterns: append(merges, common.NewCompiledRegexp(regexp.MustCompile(`^fix`))),
+			},
+			&common.PredicateResult{
+				Satisfied:     true,
+				Values:        []string{"a6f3f69b64eaafece5a0d854eb4af11c0d64394c"},
+				ConditionsMap: conditions([]string{"^Merge branch ", "^fix"}),
+			},
+		},
+		{
+			"anyMatching",
+			&CommitMessages{Patterns: ticket, Mode: CommitMessagesAny},
+			&common.PredicateResult{
+				Satisfied:     true,
+				Values:        []string{"a6f3f69b64eaafece5a0d854eb4af11c0d64394c"},
+				ConditionsMap: conditions(nil),
+			},
+		},
+		{
+			"anyNotMatching",
+			&CommitMessages{
+				Patterns:       ticket,
+				IgnorePatterns: []common.Regexp{common.NewCompiledRegexp(regexp.MustCompile(`^PROJ`))},
+				Mode:           CommitMessagesAny,
+			},
+			&common.PredicateResult{
+				Satisfied: false,
+				Values: []string{
+					"1fc89f1cedf8e3f3ce516ab75b5952295c8ea5e9",
+					"e05fcae367230ee709313dd2720da527d178ce43",
+				},
+				ConditionsMap: conditions([]string{"^PROJ"}),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := tc.predicate.Evaluate(context.Background(), prctx)
+			if assert.NoError(t, err, "evaluation failed") {
+				assertPredicateResult(t, tc.expected, result)
+			}
+		})
+	}
+
+	t.Run("invalidMode", func(t *testing.T) {
+		p := &CommitMessages{Patterns: ticket, Mode: "most"}
+		_, err := p.Evaluate(context.Background(), prctx)
+		assert.EqualError(t, err, `invalid commit messages mode "most"`)
+	})
+}
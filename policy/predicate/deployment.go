@@ -0,0 +1,86 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/pkg/errors"
+)
+
+// HasSuccessfulDeployment is satisfied if the latest deployments of the head
+// commit to all of the environments succeeded. Environment names are compared
+// without case.
+type HasSuccessfulDeployment struct {
+	Environments []string `yaml:"environments"`
+}
+
+var _ Predicate = HasSuccessfulDeployment{}
+
+func (pred HasSuccessfulDeployment) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	deployments, err := prctx.LatestDeployments()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list latest deployments")
+	}
+
+	states := make(map[string]string, len(deployments))
+	for env, state := range deployments {
+		states[strings.ToLower(env)] = state
+	}
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "deployments",
+		ConditionPhrase: "exist and have state \"success\"",
+	}
+
+	var missingEnvironments []string
+	var failingEnvironments []string
+	for _, env := range pred.Environments {
+		state, ok := states[strings.ToLower(env)]
+		switch {
+		case !ok:
+			missingEnvironments = append(missingEnvironments, env)
+		case state != "success":
+			failingEnvironments = append(failingEnvironments, fmt.Sprintf("%s (%s)", env, state))
+		}
+	}
+
+	if len(missingEnvironments) > 0 {
+		predicateResult.Values = missingEnvironments
+		predicateResult.Description = "One or more deployments are missing: " + strings.Join(missingEnvironments, ", ")
+		predicateResult.Satisfied = false
+		return &predicateResult, nil
+	}
+
+	if len(failingEnvironments) > 0 {
+		predicateResult.Values = failingEnvironments
+		predicateResult.Description = "One or more deployments have not succeeded: " + strings.Join(failingEnvironments, ", ")
+		predicateResult.Satisfied = false
+		return &predicateResult, nil
+	}
+
+	predicateResult.Values = pred.Environments
+	predicateResult.Satisfied = true
+
+	return &predicateResult, nil
+}
+
+func (pred HasSuccessfulDeployment) Trigger() common.Trigger {
+	return common.TriggerStatus
+}
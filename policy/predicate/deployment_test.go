@@ -0,0 +1,110 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull/pulltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHasSuccessfulDeployment(t *testing.T) {
+	deployments := map[string]string{
+		"staging":    "success",
+		"production": "failure",
+		"qa":         "in_progress",
+	}
+
+	tests := []struct {
+		name      string
+		predicate HasSuccessfulDeployment
+		expected  *common.PredicateResult
+	}{
+		{
+			name:      "successful",
+			predicate: HasSuccessfulDeployment{Environments: []string{"staging"}},
+			expected: &common.PredicateResult{
+				Satisfied: true,
+				Values:    []string{"staging"},
+			},
+		},
+		{
+			name:      "differentCase",
+			predicate: HasSuccessfulDeployment{Environments: []string{"Staging"}},
+			expected: &common.PredicateResult{
+				Satisfied: true,
+				Values:    []string{"Staging"},
+			},
+		},
+		{
+			name:      "failed",
+			predicate: HasSuccessfulDeployment{Environments: []string{"production"}},
+			expected: &common.PredicateResult{
+				Satisfied: false,
+				Values:    []string{"production (failure)"},
+			},
+		},
+		{
+			name:      "inProgress",
+			predicate: HasSuccessfulDeployment{Environments: []string{"qa"}},
+			expected: &common.PredicateResult{
+				Satisfied: false,
+				Values:    []string{"qa (in_progress)"},
+			},
+		},
+		{
+			name:      "missing",
+			predicate: HasSuccessfulDeployment{Environments: []string{"staging", "preview"}},
+			expected: &common.PredicateResult{
+				Satisfied: false,
+				Values:    []string{"preview"},
+			},
+		},
+		{
+			name:      "oneFailed",
+			predicate: HasSuccessfulDeployment{Environments: []string{"staging", "production", "qa"}},
+			expected: &common.PredicateResult{
+				Satisfied: false,
+				Values:    []string{"production (failure)", "qa (in_progress)"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			prctx := &pulltest.Context{
+				LatestDeploymentsValue: deployments,
+			}
+
+			result, err := test.predicate.Evaluate(context.Background(), prctx)
+			require.NoError(t, err)
+			assertPredicateResult(t, test.expected, result)
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		prctx := &pulltest.Context{
+			LatestDeploymentsError: errors.New("failed to list deployments"),
+		}
+
+		_, err := HasSuccessfulDeployment{Environments: []string{"staging"}}.Evaluate(context.Background(), prctx)
+		assert.Error(t, err)
+	})
+}
@@ -18,7 +18,11 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"path"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/palantir/policy-bot/policy/common"
 	"github.com/palantir/policy-bot/pull"
@@ -85,6 +89,70 @@ func (pred *ChangedFiles) Trigger() common.Trigger {
 	return common.TriggerCommit
 }
 
+// ChangedBinaryFiles is satisfied if the pull request adds or modifies a
+// binary file with a path that matches any of the patterns, or any binary file
+// if there are no patterns. Paths that match an ignore pattern are skipped.
+// Deleted binary files do not satisfy the predicate.
+type ChangedBinaryFiles struct {
+	Paths       []common.Regexp `yaml:"paths"`
+	IgnorePaths []common.Regexp `yaml:"ignore"`
+}
+
+var _ Predicate = &ChangedBinaryFiles{}
+
+func (pred *ChangedBinaryFiles) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	var paths, ignorePaths []string
+
+	for _, path := range pred.Paths {
+		paths = append(paths, path.String())
+	}
+
+	for _, ignorePath := range pred.IgnorePaths {
+		ignorePaths = append(ignorePaths, ignorePath.String())
+	}
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "changed binary files",
+		ConditionPhrase: "match",
+		ConditionsMap: map[string][]string{
+			"path patterns":  paths,
+			"while ignoring": ignorePaths,
+		},
+	}
+
+	files, err := prctx.ChangedFiles()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list changed files")
+	}
+
+	var binaryFiles []string
+	for _, f := range files {
+		if !f.IsBinary || f.Status == pull.FileDeleted {
+			continue
+		}
+		if anyMatches(pred.IgnorePaths, f.Filename) {
+			continue
+		}
+		if len(pred.Paths) == 0 || anyMatches(pred.Paths, f.Filename) {
+			binaryFiles = append(binaryFiles, f.Filename)
+		}
+	}
+
+	if len(binaryFiles) == 0 {
+		predicateResult.Description = "No changed binary files match the required patterns"
+		return &predicateResult, nil
+	}
+
+	predicateResult.Values = binaryFiles
+	predicateResult.Description = fmt.Sprintf("%d binary files were changed", len(binaryFiles))
+	predicateResult.Satisfied = true
+	return &predicateResult, nil
+}
+
+func (pred *ChangedBinaryFiles) Trigger() common.Trigger {
+	return common.TriggerCommit
+}
+
 type OnlyChangedFiles struct {
 	Paths []common.Regexp `yaml:"paths"`
 }
@@ -181,6 +249,257 @@ func (pred *NoChangedFiles) Trigger() common.Trigger {
 	return common.TriggerCommit
 }
 
+// HasSingleTopLevelDirectory is satisfied if all changed files are in the
+// same top-level directory of the repository. Files in the root of the
+// repository are not in any directory, so changing them does not satisfy the
+// predicate. If false, it is satisfied if the changed files are in more than
+// one top-level directory or in the root.
+type HasSingleTopLevelDirectory bool
+
+var _ Predicate = HasSingleTopLevelDirectory(false)
+
+func (pred HasSingleTopLevelDirectory) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	files, err := prctx.ChangedFiles()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list changed files")
+	}
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "changed files",
+		ConditionPhrase: "meet the condition",
+	}
+	if pred {
+		predicateResult.ConditionValues = []string{"they are in a single top-level directory"}
+	} else {
+		predicateResult.ConditionValues = []string{"they are not in a single top-level directory"}
+	}
+
+	// group files by directory, using the empty string for the root
+	var dirs []string
+	filesByDir := make(map[string][]string)
+	for _, f := range files {
+		dir, _, found := strings.Cut(f.Filename, "/")
+		if !found {
+			dir = ""
+		}
+		if _, ok := filesByDir[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+		filesByDir[dir] = append(filesByDir[dir], f.Filename)
+	}
+
+	single := len(dirs) == 1 && dirs[0] != ""
+	if single {
+		predicateResult.Values = []string{dirs[0] + "/"}
+	} else {
+		predicateResult.Values = filesOutsideLargestDir(dirs, filesByDir)
+	}
+
+	switch {
+	case single && !bool(pred):
+		predicateResult.Description = "All changed files are in " + dirs[0] + "/"
+	case !single && bool(pred):
+		if len(dirs) == 0 {
+			predicateResult.Description = "No files changed"
+		} else {
+			predicateResult.Description = "Changed files are not in a single top-level directory"
+		}
+	default:
+		predicateResult.Satisfied = true
+	}
+	return &predicateResult, nil
+}
+
+func (pred HasSingleTopLevelDirectory) Trigger() common.Trigger {
+	return common.TriggerCommit
+}
+
+// filesOutsideLargestDir returns the files that are not in the directory with
+// the most files. Files in the root are never in the largest directory. Ties
+// are broken by the order of the directories.
+func filesOutsideLargestDir(dirs []string, filesByDir map[string][]string) []string {
+	largest, count := "", 0
+	for _, dir := range dirs {
+		if dir != "" && len(filesByDir[dir]) > count {
+			largest, count = dir, len(filesByDir[dir])
+		}
+	}
+
+	var files []string
+	for _, dir := range dirs {
+		if dir != largest || dir == "" {
+			files = append(files, filesByDir[dir]...)
+		}
+	}
+	return files
+}
+
+// defaultDependencyManifests are the manifest files checked by
+// HasNewDependencies if no manifests are configured.
+var defaultDependencyManifests = []common.Regexp{
+	common.NewCompiledRegexp(regexp.MustCompile(`(^|/)go\.mod$`)),
+	common.NewCompiledRegexp(regexp.MustCompile(`(^|/)package\.json$`)),
+	common.NewCompiledRegexp(regexp.MustCompile(`(^|/)requirements[^/]*\.txt$`)),
+	common.NewCompiledRegexp(regexp.MustCompile(`(^|/)pyproject\.toml$`)),
+	common.NewCompiledRegexp(regexp.MustCompile(`(^|/)Gemfile$`)),
+	common.NewCompiledRegexp(regexp.MustCompile(`(^|/)Cargo\.toml$`)),
+	common.NewCompiledRegexp(regexp.MustCompile(`(^|/)pom\.xml$`)),
+	common.NewCompiledRegexp(regexp.MustCompile(`(^|/)build\.gradle(\.kts)?$`)),
+}
+
+// HasNewDependencies is satisfied if the pull request adds lines to any
+// dependency manifest file.
+type HasNewDependencies struct {
+	Manifests []common.Regexp `yaml:"manifests"`
+}
+
+var _ Predicate = &HasNewDependencies{}
+
+func (pred *HasNewDependencies) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	manifests := pred.Manifests
+	if len(manifests) == 0 {
+		manifests = defaultDependencyManifests
+	}
+
+	var patterns []string
+	for _, m := range manifests {
+		patterns = append(patterns, m.String())
+	}
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "dependency manifests",
+		ConditionPhrase: "have added lines and match",
+		ConditionValues: patterns,
+	}
+
+	files, err := prctx.ChangedFiles()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list changed files")
+	}
+
+	var changed []string
+	for _, f := range files {
+		if f.Status == pull.FileDeleted || !anyMatches(manifests, f.Filename) {
+			continue
+		}
+		if hasAddedLines(f) {
+			changed = append(changed, f.Filename)
+		}
+	}
+
+	if len(changed) == 0 {
+		predicateResult.Description = "No dependency manifests have added lines"
+		predicateResult.Satisfied = false
+		return &predicateResult, nil
+	}
+
+	predicateResult.Values = changed
+	predicateResult.Description = "Dependencies were added in " + strings.Join(changed, ", ")
+	predicateResult.Satisfied = true
+	return &predicateResult, nil
+}
+
+func (pred *HasNewDependencies) Trigger() common.Trigger {
+	return common.TriggerCommit
+}
+
+// ChangedFileContent is satisfied if the content of any added or modified
+// file that matches Paths matches any of Patterns at the head commit of the
+// pull request. Binary files are ignored. Files that are too large to retrieve
+// are treated as matching, so a rule cannot be bypassed by growing a file.
+type ChangedFileContent struct {
+	Paths    []common.Regexp `yaml:"paths"`
+	Patterns []common.Regexp `yaml:"patterns"`
+}
+
+var _ Predicate = &ChangedFileContent{}
+
+func (pred *ChangedFileContent) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	var paths, patterns []string
+	for _, path := range pred.Paths {
+		paths = append(paths, path.String())
+	}
+	for _, pattern := range pred.Patterns {
+		patterns = append(patterns, pattern.String())
+	}
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "changed files",
+		ConditionPhrase: "match",
+		ConditionsMap: map[string][]string{
+			"path patterns":    paths,
+			"content patterns": patterns,
+		},
+	}
+
+	files, err := prctx.ChangedFiles()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list changed files")
+	}
+
+	for _, f := range files {
+		if f.Status == pull.FileDeleted || !anyMatches(pred.Paths, f.Filename) {
+			continue
+		}
+
+		content, exists, err := prctx.FileContent(f.Filename, prctx.HeadSHA())
+		if err != nil {
+			if errors.Is(err, pull.ErrFileTooLarge) {
+				predicateResult.Values = []string{f.Filename}
+				predicateResult.Description = "The content of " + f.Filename + " is too large to check"
+				predicateResult.Satisfied = true
+				return &predicateResult, nil
+			}
+			return nil, errors.Wrapf(err, "failed to get content of %s", f.Filename)
+		}
+		if !exists || isBinary(content) {
+			continue
+		}
+
+		if anyMatches(pred.Patterns, content) {
+			predicateResult.Values = []string{f.Filename}
+			predicateResult.Description = "The content of " + f.Filename + " matches a pattern"
+			predicateResult.Satisfied = true
+			return &predicateResult, nil
+		}
+	}
+
+	predicateResult.Description = "No changed files have content matching the required patterns"
+	predicateResult.Satisfied = false
+	return &predicateResult, nil
+}
+
+func (pred *ChangedFileContent) Trigger() common.Trigger {
+	return common.TriggerCommit
+}
+
+// isBinary returns true if content appears to be binary. Like git, it checks
+// for a NUL byte in the first 8000 bytes.
+func isBinary(content string) bool {
+	if len(content) > 8000 {
+		content = content[:8000]
+	}
+	return strings.IndexByte(content, 0) >= 0
+}
+
+// hasAddedLines returns true if the patch for a file contains added lines that
+// are not blank. If there is no patch, it uses the number of additions.
+func hasAddedLines(f *pull.File) bool {
+	if f.Patch == "" {
+		return f.Additions > 0
+	}
+
+	for _, line := range strings.Split(f.Patch, "\n") {
+		if !strings.HasPrefix(line, "+") || strings.HasPrefix(line, "+++") {
+			continue
+		}
+		if strings.TrimSpace(line[1:]) != "" {
+			return true
+		}
+	}
+	return false
+}
+
 type ModifiedLines struct {
 	Additions ComparisonExpr `yaml:"additions"`
 	Deletions ComparisonExpr `yaml:"deletions"`
@@ -333,3 +652,166 @@ func (pred *ModifiedLines) Trigger() common.Trigger {
 }
 
 var _ Predicate = &ModifiedLines{}
+
+// FileExtensionCount is satisfied if the number of distinct file extensions
+// among the files changed by the pull request matches the expression.
+// Extensions are compared without case and files without an extension count
+// as a single extension.
+type FileExtensionCount struct {
+	Count ComparisonExpr `yaml:"count"`
+}
+
+var _ Predicate = &FileExtensionCount{}
+
+func (pred *FileExtensionCount) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	files, err := prctx.ChangedFiles()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list changed files")
+	}
+
+	extensions := make(map[string]struct{})
+	for _, f := range files {
+		extensions[fileExtension(f.Filename)] = struct{}{}
+	}
+
+	var values []string
+	for ext := range extensions {
+		values = append(values, ext)
+	}
+	sort.Strings(values)
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "file extensions",
+		Values:          values,
+		ConditionPhrase: "meet the condition",
+		ConditionValues: []string{fmt.Sprintf("extension count %s", pred.Count.String())},
+	}
+
+	count := int64(len(values))
+	if pred.Count.Evaluate(count) {
+		predicateResult.Satisfied = true
+		return &predicateResult, nil
+	}
+
+	predicateResult.Description = fmt.Sprintf("The number of distinct file extensions (%d) does not match the condition %s", count, pred.Count)
+	return &predicateResult, nil
+}
+
+func (pred *FileExtensionCount) Trigger() common.Trigger {
+	return common.TriggerCommit
+}
+
+// ChangedFileCount is satisfied if the number of files changed by the pull
+// request matches the expression. Renamed files are listed as a deleted file
+// and an added file, so they count twice unless CountRenamesOnce is set.
+type ChangedFileCount struct {
+	Count            ComparisonExpr `yaml:"count"`
+	CountRenamesOnce bool           `yaml:"count_renames_once"`
+}
+
+var _ Predicate = &ChangedFileCount{}
+
+func (pred *ChangedFileCount) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	files, err := prctx.ChangedFiles()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list changed files")
+	}
+
+	count := int64(len(files))
+	if pred.CountRenamesOnce {
+		for _, f := range files {
+			if f.PreviousFilename != "" {
+				count--
+			}
+		}
+	}
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "changed file count",
+		Values:          []string{strconv.FormatInt(count, 10)},
+		ConditionPhrase: "meets the condition",
+		ConditionValues: []string{pred.Count.String()},
+	}
+
+	if pred.Count.Evaluate(count) {
+		predicateResult.Satisfied = true
+		return &predicateResult, nil
+	}
+
+	predicateResult.Description = fmt.Sprintf("The number of changed files (%d) does not match the condition %s", count, pred.Count)
+	return &predicateResult, nil
+}
+
+func (pred *ChangedFileCount) Trigger() common.Trigger {
+	return common.TriggerCommit
+}
+
+// fileExtension returns the lowercase extension of the file, including the
+// leading dot, or "(none)" if the file has no extension. Names that only
+// start with a dot, like ".gitignore", have no extension.
+func fileExtension(filename string) string {
+	base := path.Base(filename)
+	ext := path.Ext(base)
+	if ext == "" || ext == base {
+		return "(none)"
+	}
+	return strings.ToLower(ext)
+}
+
+// FilesPerReviewer is satisfied if the number of files changed by the pull
+// request divided by the number of requested reviewers matches the
+// expression. Users and teams both count as reviewers and removed review
+// requests are ignored. If there are no requested reviewers, the ratio is the
+// number of changed files.
+type FilesPerReviewer struct {
+	Ratio ComparisonExpr `yaml:"ratio"`
+}
+
+var _ Predicate = &FilesPerReviewer{}
+
+func (pred *FilesPerReviewer) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	files, err := prctx.ChangedFiles()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list changed files")
+	}
+
+	reviewers, err := prctx.RequestedReviewers()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list requested reviewers")
+	}
+
+	var requested int64
+	for _, r := range reviewers {
+		if !r.Removed {
+			requested++
+		}
+	}
+
+	fileCount := int64(len(files))
+	ratio := float64(fileCount)
+	if requested > 0 {
+		ratio /= float64(requested)
+	}
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "files per reviewer",
+		Values:          []string{fmt.Sprintf("%.2f", ratio)},
+		ConditionPhrase: "meet the condition",
+		ConditionValues: []string{fmt.Sprintf("files per reviewer %s", pred.Ratio.String())},
+	}
+
+	// Compare the file count to the scaled expression value instead of
+	// comparing the ratio directly to avoid rounding
+	expr := ComparisonExpr{Op: pred.Ratio.Op, Value: pred.Ratio.Value * max(requested, 1)}
+	if expr.Evaluate(fileCount) {
+		predicateResult.Satisfied = true
+		return &predicateResult, nil
+	}
+
+	predicateResult.Description = fmt.Sprintf("The number of files per reviewer (%d files, %d reviewers) does not match the condition %s", fileCount, requested, pred.Ratio)
+	return &predicateResult, nil
+}
+
+func (pred *FilesPerReviewer) Trigger() common.Trigger {
+	return common.TriggerCommit | common.TriggerPullRequest
+}
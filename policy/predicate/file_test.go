@@ -22,6 +22,7 @@ import (
 	"github.com/palantir/policy-bot/policy/common"
 	"github.com/palantir/policy-bot/pull"
 	"github.com/palantir/policy-bot/pull/pulltest"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -589,6 +590,357 @@ func TestComparisonExpr(t *testing.T) {
 	}
 }
 
+func TestHasNewDependencies(t *testing.T) {
+	p := &HasNewDependencies{}
+
+	var defaultPatterns []string
+	for _, m := range defaultDependencyManifests {
+		defaultPatterns = append(defaultPatterns, m.String())
+	}
+
+	runFileTests(t, p, []FileTestCase{
+		{
+			"empty",
+			[]*pull.File{},
+			&common.PredicateResult{
+				Satisfied:       false,
+				ConditionValues: defaultPatterns,
+			},
+		},
+		{
+			"noManifests",
+			[]*pull.File{
+				{
+					Filename:  "app/main.go",
+					Status:    pull.FileModified,
+					Additions: 10,
+					Patch:     "@@ -1,1 +1,2 @@\n package main\n+import \"fmt\"",
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:       false,
+				ConditionValues: defaultPatterns,
+			},
+		},
+		{
+			"addedDependency",
+			[]*pull.File{
+				{
+					Filename:  "go.mod",
+					Status:    pull.FileModified,
+					Additions: 1,
+					Patch:     "@@ -3,3 +3,4 @@ require (\n \tgithub.com/pkg/errors v0.9.1\n+\tgithub.com/rs/zerolog v1.33.0\n )",
+				},
+				{
+					Filename:  "web/package.json",
+					Status:    pull.FileModified,
+					Additions: 1,
+					Deletions: 1,
+					Patch:     "@@ -2,3 +2,3 @@\n-  \"lodash\": \"^4.17.20\"\n+  \"lodash\": \"^4.17.21\"",
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:       true,
+				ConditionValues: defaultPatterns,
+				Values:          []string{"go.mod", "web/package.json"},
+			},
+		},
+		{
+			"onlyRemovedLines",
+			[]*pull.File{
+				{
+					Filename:  "go.mod",
+					Status:    pull.FileModified,
+					Additions: 1,
+					Deletions: 1,
+					Patch:     "@@ -3,4 +3,4 @@ require (\n-\tgithub.com/rs/zerolog v1.33.0\n+\n )",
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:       false,
+				ConditionValues: defaultPatterns,
+			},
+		},
+		{
+			"noPatch",
+			[]*pull.File{
+				{
+					Filename:  "requirements.txt",
+					Status:    pull.FileAdded,
+					Additions: 200,
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:       true,
+				ConditionValues: defaultPatterns,
+				Values:          []string{"requirements.txt"},
+			},
+		},
+		{
+			"deletedManifest",
+			[]*pull.File{
+				{
+					Filename:  "Gemfile",
+					Status:    pull.FileDeleted,
+					Deletions: 20,
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:       false,
+				ConditionValues: defaultPatterns,
+			},
+		},
+	})
+
+	custom := &HasNewDependencies{
+		Manifests: []common.Regexp{
+			common.NewCompiledRegexp(regexp.MustCompile(`^deps\.bzl$`)),
+		},
+	}
+
+	runFileTests(t, custom, []FileTestCase{
+		{
+			"customManifest",
+			[]*pull.File{
+				{
+					Filename:  "go.mod",
+					Status:    pull.FileModified,
+					Additions: 1,
+				},
+				{
+					Filename:  "deps.bzl",
+					Status:    pull.FileModified,
+					Additions: 1,
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:       true,
+				ConditionValues: []string{`^deps\.bzl$`},
+				Values:          []string{"deps.bzl"},
+			},
+		},
+	})
+}
+
+func TestHasSingleTopLevelDirectory(t *testing.T) {
+	p := HasSingleTopLevelDirectory(true)
+
+	runFileTests(t, p, []FileTestCase{
+		{
+			"singleDirectory",
+			[]*pull.File{
+				{Filename: "services/api/main.go", Status: pull.FileModified},
+				{Filename: "services/web/index.js", Status: pull.FileAdded},
+			},
+			&common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"services/"},
+				ConditionValues: []string{"they are in a single top-level directory"},
+			},
+		},
+		{
+			"multipleDirectories",
+			[]*pull.File{
+				{Filename: "services/api/main.go", Status: pull.FileModified},
+				{Filename: "libs/auth/token.go", Status: pull.FileModified},
+				{Filename: "services/api/handler.go", Status: pull.FileModified},
+			},
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"libs/auth/token.go"},
+				ConditionValues: []string{"they are in a single top-level directory"},
+			},
+		},
+		{
+			"rootFiles",
+			[]*pull.File{
+				{Filename: "go.mod", Status: pull.FileModified},
+				{Filename: "services/api/main.go", Status: pull.FileModified},
+			},
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"go.mod"},
+				ConditionValues: []string{"they are in a single top-level directory"},
+			},
+		},
+		{
+			"onlyRootFiles",
+			[]*pull.File{
+				{Filename: "go.mod", Status: pull.FileModified},
+				{Filename: "README.md", Status: pull.FileModified},
+			},
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"go.mod", "README.md"},
+				ConditionValues: []string{"they are in a single top-level directory"},
+			},
+		},
+		{
+			"noFiles",
+			[]*pull.File{},
+			&common.PredicateResult{
+				Satisfied:       false,
+				ConditionValues: []string{"they are in a single top-level directory"},
+			},
+		},
+	})
+
+	runFileTests(t, HasSingleTopLevelDirectory(false), []FileTestCase{
+		{
+			"invertedSingleDirectory",
+			[]*pull.File{
+				{Filename: "services/api/main.go", Status: pull.FileModified},
+			},
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"services/"},
+				ConditionValues: []string{"they are not in a single top-level directory"},
+			},
+		},
+		{
+			"invertedMultipleDirectories",
+			[]*pull.File{
+				{Filename: "services/api/main.go", Status: pull.FileModified},
+				{Filename: "libs/auth/token.go", Status: pull.FileModified},
+			},
+			&common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"libs/auth/token.go"},
+				ConditionValues: []string{"they are not in a single top-level directory"},
+			},
+		},
+	})
+}
+
+func TestChangedFileContent(t *testing.T) {
+	p := &ChangedFileContent{
+		Paths: []common.Regexp{
+			common.NewCompiledRegexp(regexp.MustCompile(`^app/.*\.go$`)),
+		},
+		Patterns: []common.Regexp{
+			common.NewCompiledRegexp(regexp.MustCompile(`flags\.Enable\("new-ui"\)`)),
+		},
+	}
+
+	conditions := map[string][]string{
+		"path patterns":    {`^app/.*\.go$`},
+		"content patterns": {`flags\.Enable\("new-ui"\)`},
+	}
+
+	headSHA := "97d5ea26da319a987d80f6db0b7ef759f2f2e441"
+	contents := map[string]string{
+		"app/main.go":   "package main\n\nfunc main() {\n\tflags.Enable(\"new-ui\")\n}\n",
+		"app/server.go": "package main\n\nfunc serve() {}\n",
+		"app/data.go":   "\x00\x01flags.Enable(\"new-ui\")",
+		"docs/flags.md": "Use flags.Enable(\"new-ui\") to enable the new UI",
+	}
+
+	tests := []struct {
+		Name     string
+		Files    []*pull.File
+		Expected *common.PredicateResult
+	}{
+		{
+			Name: "matchingContent",
+			Files: []*pull.File{
+				{Filename: "app/server.go", Status: pull.FileModified},
+				{Filename: "app/main.go", Status: pull.FileModified},
+			},
+			Expected: &common.PredicateResult{
+				Satisfied:     true,
+				Values:        []string{"app/main.go"},
+				ConditionsMap: conditions,
+			},
+		},
+		{
+			Name: "noMatchingContent",
+			Files: []*pull.File{
+				{Filename: "app/server.go", Status: pull.FileModified},
+			},
+			Expected: &common.PredicateResult{
+				Satisfied:     false,
+				ConditionsMap: conditions,
+			},
+		},
+		{
+			Name: "noMatchingPaths",
+			Files: []*pull.File{
+				{Filename: "docs/flags.md", Status: pull.FileAdded},
+			},
+			Expected: &common.PredicateResult{
+				Satisfied:     false,
+				ConditionsMap: conditions,
+			},
+		},
+		{
+			Name: "ignoresDeletedFiles",
+			Files: []*pull.File{
+				{Filename: "app/removed.go", Status: pull.FileDeleted},
+			},
+			Expected: &common.PredicateResult{
+				Satisfied:     false,
+				ConditionsMap: conditions,
+			},
+		},
+		{
+			Name: "ignoresBinaryFiles",
+			Files: []*pull.File{
+				{Filename: "app/data.go", Status: pull.FileAdded},
+			},
+			Expected: &common.PredicateResult{
+				Satisfied:     false,
+				ConditionsMap: conditions,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			prctx := &pulltest.Context{
+				HeadSHAValue:      headSHA,
+				ChangedFilesValue: test.Files,
+				FilesValue: map[string]map[string]string{
+					headSHA: contents,
+				},
+			}
+
+			result, err := p.Evaluate(context.Background(), prctx)
+			if assert.NoError(t, err, "evaluation failed") {
+				assertPredicateResult(t, test.Expected, result)
+			}
+		})
+	}
+
+	t.Run("matchesLargeFiles", func(t *testing.T) {
+		prctx := &pulltest.Context{
+			HeadSHAValue: headSHA,
+			ChangedFilesValue: []*pull.File{
+				{Filename: "app/main.go", Status: pull.FileModified},
+			},
+			FilesError: errors.Wrap(pull.ErrFileTooLarge, "failed to get content"),
+		}
+
+		result, err := p.Evaluate(context.Background(), prctx)
+		if assert.NoError(t, err, "evaluation failed") {
+			assert.True(t, result.Satisfied, "predicate was not correct")
+			assert.Equal(t, []string{"app/main.go"}, result.Values)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		prctx := &pulltest.Context{
+			HeadSHAValue: headSHA,
+			ChangedFilesValue: []*pull.File{
+				{Filename: "app/main.go", Status: pull.FileModified},
+			},
+			FilesError: errors.New("failed to get content"),
+		}
+
+		_, err := p.Evaluate(context.Background(), prctx)
+		assert.Error(t, err)
+	})
+}
+
 type FileTestCase struct {
 	Name                    string
 	Files                   []*pull.File
@@ -611,3 +963,269 @@ func runFileTests(t *testing.T, p Predicate, cases []FileTestCase) {
 		})
 	}
 }
+
+func TestChangedFileCount(t *testing.T) {
+	renamed := []*pull.File{
+		{Filename: "app/client.go", Status: pull.FileModified},
+		{Filename: "app/old.go", Status: pull.FileDeleted},
+		{Filename: "app/new.go", Status: pull.FileAdded, PreviousFilename: "app/old.go"},
+	}
+
+	p := &ChangedFileCount{
+		Count: ComparisonExpr{Op: OpGreaterThan, Value: 2},
+	}
+
+	runFileTests(t, p, []FileTestCase{
+		{
+			"noFiles",
+			[]*pull.File{},
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"0"},
+				ConditionValues: []string{"> 2"},
+			},
+		},
+		{
+			"fewFiles",
+			[]*pull.File{
+				{Filename: "app/client.go", Status: pull.FileModified},
+				{Filename: "app/server.go", Status: pull.FileAdded},
+			},
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"2"},
+				ConditionValues: []string{"> 2"},
+			},
+		},
+		{
+			"renameCountsTwice",
+			renamed,
+			&common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"3"},
+				ConditionValues: []string{"> 2"},
+			},
+		},
+	})
+
+	p = &ChangedFileCount{
+		Count:            ComparisonExpr{Op: OpGreaterThan, Value: 2},
+		CountRenamesOnce: true,
+	}
+
+	runFileTests(t, p, []FileTestCase{
+		{
+			"renameCountsOnce",
+			renamed,
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"2"},
+				ConditionValues: []string{"> 2"},
+			},
+		},
+	})
+}
+
+func TestChangedBinaryFiles(t *testing.T) {
+	p := &ChangedBinaryFiles{
+		Paths: []common.Regexp{
+			common.NewCompiledRegexp(regexp.MustCompile("^bin/.*$")),
+		},
+		IgnorePaths: []common.Regexp{
+			common.NewCompiledRegexp(regexp.MustCompile("^bin/docs/.*$")),
+		},
+	}
+
+	conditions := map[string][]string{
+		"path patterns":  {"^bin/.*$"},
+		"while ignoring": {"^bin/docs/.*$"},
+	}
+
+	runFileTests(t, p, []FileTestCase{
+		{
+			"textFiles",
+			[]*pull.File{
+				{Filename: "bin/build.sh", Status: pull.FileModified, Additions: 2, Patch: "@@ -1 +1,2 @@"},
+				{Filename: "app/client.go", Status: pull.FileAdded, Additions: 10, Patch: "@@ -0,0 +1,10 @@"},
+			},
+			&common.PredicateResult{
+				Satisfied:     false,
+				ConditionsMap: conditions,
+			},
+		},
+		{
+			"binaryFiles",
+			[]*pull.File{
+				{Filename: "bin/tool", Status: pull.FileAdded, IsBinary: true},
+				{Filename: "bin/helper", Status: pull.FileModified, IsBinary: true},
+				{Filename: "bin/build.sh", Status: pull.FileModified, Additions: 2, Patch: "@@ -1 +1,2 @@"},
+			},
+			&common.PredicateResult{
+				Satisfied:     true,
+				Values:        []string{"bin/tool", "bin/helper"},
+				ConditionsMap: conditions,
+			},
+		},
+		{
+			"binaryFilesNotMatching",
+			[]*pull.File{
+				{Filename: "assets/logo.png", Status: pull.FileAdded, IsBinary: true},
+				{Filename: "bin/docs/diagram.png", Status: pull.FileAdded, IsBinary: true},
+			},
+			&common.PredicateResult{
+				Satisfied:     false,
+				ConditionsMap: conditions,
+			},
+		},
+		{
+			"deletedBinaryFiles",
+			[]*pull.File{
+				{Filename: "bin/tool", Status: pull.FileDeleted, IsBinary: true},
+			},
+			&common.PredicateResult{
+				Satisfied:     false,
+				ConditionsMap: conditions,
+			},
+		},
+	})
+
+	runFileTests(t, &ChangedBinaryFiles{}, []FileTestCase{
+		{
+			"anyPath",
+			[]*pull.File{
+				{Filename: "assets/logo.png", Status: pull.FileAdded, IsBinary: true},
+			},
+			&common.PredicateResult{
+				Satisfied: true,
+				Values:    []string{"assets/logo.png"},
+				ConditionsMap: map[string][]string{
+					"path patterns":  nil,
+					"while ignoring": nil,
+				},
+			},
+		},
+	})
+}
+
+func TestFileExtensionCount(t *testing.T) {
+	p := &FileExtensionCount{
+		Count: ComparisonExpr{Op: OpGreaterThan, Value: 2},
+	}
+
+	runFileTests(t, p, []FileTestCase{
+		{
+			"noFiles",
+			[]*pull.File{},
+			&common.PredicateResult{
+				Satisfied:       false,
+				ConditionValues: []string{"extension count > 2"},
+			},
+		},
+		{
+			"fewExtensions",
+			[]*pull.File{
+				{Filename: "app/client.go", Status: pull.FileModified},
+				{Filename: "app/server.go", Status: pull.FileModified},
+				{Filename: "docs/README.md", Status: pull.FileAdded},
+			},
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{".go", ".md"},
+				ConditionValues: []string{"extension count > 2"},
+			},
+		},
+		{
+			"manyExtensions",
+			[]*pull.File{
+				{Filename: "app/client.go", Status: pull.FileModified},
+				{Filename: "web/index.js", Status: pull.FileModified},
+				{Filename: "web/style.CSS", Status: pull.FileModified},
+				{Filename: "web/theme.css", Status: pull.FileDeleted},
+			},
+			&common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{".css", ".go", ".js"},
+				ConditionValues: []string{"extension count > 2"},
+			},
+		},
+		{
+			"noExtension",
+			[]*pull.File{
+				{Filename: "Makefile", Status: pull.FileModified},
+				{Filename: ".gitignore", Status: pull.FileModified},
+				{Filename: "release.d/notes", Status: pull.FileAdded},
+				{Filename: "app/client.go", Status: pull.FileModified},
+			},
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"(none)", ".go"},
+				ConditionValues: []string{"extension count > 2"},
+			},
+		},
+	})
+}
+
+func TestFilesPerReviewer(t *testing.T) {
+	p := &FilesPerReviewer{
+		Ratio: ComparisonExpr{Op: OpGreaterThan, Value: 2},
+	}
+
+	files := []*pull.File{
+		{Filename: "app/client.go", Status: pull.FileModified},
+		{Filename: "app/server.go", Status: pull.FileModified},
+		{Filename: "docs/README.md", Status: pull.FileAdded},
+		{Filename: "web/index.js", Status: pull.FileModified},
+		{Filename: "web/style.css", Status: pull.FileDeleted},
+	}
+
+	tests := map[string]struct {
+		Reviewers []*pull.Reviewer
+		Expected  *common.PredicateResult
+	}{
+		"noReviewers": {
+			Expected: &common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"5.00"},
+				ConditionValues: []string{"files per reviewer > 2"},
+			},
+		},
+		"fewReviewers": {
+			Reviewers: []*pull.Reviewer{
+				{Type: pull.ReviewerUser, Name: "mhaypenny"},
+				{Type: pull.ReviewerTeam, Name: "devtools"},
+				{Type: pull.ReviewerUser, Name: "ttest", Removed: true},
+			},
+			Expected: &common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"2.50"},
+				ConditionValues: []string{"files per reviewer > 2"},
+			},
+		},
+		"manyReviewers": {
+			Reviewers: []*pull.Reviewer{
+				{Type: pull.ReviewerUser, Name: "mhaypenny"},
+				{Type: pull.ReviewerUser, Name: "ttest"},
+				{Type: pull.ReviewerTeam, Name: "devtools"},
+			},
+			Expected: &common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"1.67"},
+				ConditionValues: []string{"files per reviewer > 2"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			prctx := &pulltest.Context{
+				ChangedFilesValue:       files,
+				RequestedReviewersValue: test.Reviewers,
+			}
+
+			res, err := p.Evaluate(context.Background(), prctx)
+			if assert.NoError(t, err, "evaluation failed") {
+				assertPredicateResult(t, test.Expected, res)
+			}
+		})
+	}
+}
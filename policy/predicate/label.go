@@ -16,6 +16,7 @@ package predicate
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/palantir/policy-bot/policy/common"
@@ -23,23 +24,87 @@ import (
 	"github.com/pkg/errors"
 )
 
-type HasLabels []string
+const (
+	LabelsMatchAll = "all"
+	LabelsMatchAny = "any"
+)
+
+// HasLabels is satisfied if the pull request has the labels. In "all" mode,
+// the default, the pull request must have every label. In "any" mode, it must
+// have at least one of the labels. Labels are compared without case.
+//
+// For compatibility, the predicate may also be configured with a list of
+// labels, which uses "all" mode.
+type HasLabels struct {
+	Labels []string `yaml:"labels"`
+	Match  string   `yaml:"match"`
+}
 
-var _ Predicate = HasLabels([]string{})
+var _ Predicate = &HasLabels{}
 
-func (pred HasLabels) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+func (pred *HasLabels) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var labels []string
+	if err := unmarshal(&labels); err == nil {
+		*pred = HasLabels{Labels: labels}
+		return nil
+	}
+
+	type rawHasLabels HasLabels
+	var raw rawHasLabels
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+
+	switch raw.Match {
+	case "", LabelsMatchAll, LabelsMatchAny:
+	default:
+		return errors.Errorf("invalid labels match %q, must be %q or %q", raw.Match, LabelsMatchAll, LabelsMatchAny)
+	}
+
+	*pred = HasLabels(raw)
+	return nil
+}
+
+func (pred *HasLabels) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	match := pred.Match
+	if match == "" {
+		match = LabelsMatchAll
+	}
 
 	predicateResult := common.PredicateResult{
-		ValuePhrase:     "labels",
-		ConditionPhrase: "contain the labels",
+		ValuePhrase: "labels",
+	}
+
+	switch match {
+	case LabelsMatchAll:
+		predicateResult.ConditionPhrase = "contain the labels"
+	case LabelsMatchAny:
+		predicateResult.ConditionPhrase = "contain at least one of the labels"
+	default:
+		return nil, errors.Errorf("invalid labels match %q", pred.Match)
 	}
-	if len(pred) > 0 {
+
+	if len(pred.Labels) > 0 {
 		labels, err := prctx.Labels()
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to list pull request labels")
 		}
 		predicateResult.Values = labels
-		for _, requiredLabel := range pred {
+
+		if match == LabelsMatchAny {
+			predicateResult.ConditionValues = pred.Labels
+			for _, label := range pred.Labels {
+				if contains(labels, strings.ToLower(label)) {
+					predicateResult.Satisfied = true
+					return &predicateResult, nil
+				}
+			}
+			predicateResult.Description = "Missing all labels: " + strings.Join(pred.Labels, ", ")
+			predicateResult.Satisfied = false
+			return &predicateResult, nil
+		}
+
+		for _, requiredLabel := range pred.Labels {
 			if !contains(labels, strings.ToLower(requiredLabel)) {
 				predicateResult.ConditionValues = []string{requiredLabel}
 				predicateResult.Description = "Missing label: " + requiredLabel
@@ -48,12 +113,87 @@ func (pred HasLabels) Evaluate(ctx context.Context, prctx pull.Context) (*common
 			}
 		}
 	}
-	predicateResult.ConditionValues = pred
+	predicateResult.ConditionValues = pred.Labels
+	predicateResult.Satisfied = true
+	return &predicateResult, nil
+}
+
+func (pred *HasLabels) Trigger() common.Trigger {
+	return common.TriggerLabel
+}
+
+// HasLabelAppliedBy is satisfied if the pull request has a label that was
+// most recently applied by a user who meets the membership conditions. If no
+// conditions are set, the label may be applied by any user.
+type HasLabelAppliedBy struct {
+	Label         string `yaml:"label"`
+	common.Actors `yaml:",inline"`
+}
+
+var _ Predicate = &HasLabelAppliedBy{}
+
+func (pred *HasLabelAppliedBy) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	label := strings.ToLower(pred.Label)
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "labels",
+		ConditionPhrase: "were applied by users who meet the required membership conditions",
+		ConditionsMap: map[string][]string{
+			"Organizations": pred.Organizations,
+			"Teams":         pred.Teams,
+			"Users":         pred.Users,
+		},
+	}
+
+	labels, err := prctx.Labels()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list pull request labels")
+	}
+	if !contains(labels, label) {
+		predicateResult.Values = []string{pred.Label}
+		predicateResult.Description = "Missing label: " + pred.Label
+		predicateResult.Satisfied = false
+		return &predicateResult, nil
+	}
+
+	appliers, err := prctx.LabelAppliers()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get label appliers")
+	}
+
+	applier := appliers[label]
+	if applier != "" {
+		predicateResult.Values = []string{fmt.Sprintf("%s (applied by %s)", pred.Label, applier)}
+	} else {
+		predicateResult.Values = []string{pred.Label}
+	}
+
+	if pred.IsEmpty() {
+		predicateResult.Satisfied = true
+		return &predicateResult, nil
+	}
+
+	if applier == "" {
+		predicateResult.Description = fmt.Sprintf("Could not determine who applied the label %q", pred.Label)
+		predicateResult.Satisfied = false
+		return &predicateResult, nil
+	}
+
+	eligible, err := pred.IsActor(ctx, prctx, applier)
+	if err != nil {
+		return nil, err
+	}
+	if !eligible {
+		predicateResult.Description = fmt.Sprintf("The label %q was applied by %q, who does not meet the required membership conditions", pred.Label, applier)
+		predicateResult.Satisfied = false
+		return &predicateResult, nil
+	}
+
 	predicateResult.Satisfied = true
 	return &predicateResult, nil
 }
 
-func (pred HasLabels) Trigger() common.Trigger {
+func (pred *HasLabelAppliedBy) Trigger() common.Trigger {
 	return common.TriggerLabel
 }
 
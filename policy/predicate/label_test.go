@@ -22,10 +22,12 @@ import (
 	"github.com/palantir/policy-bot/pull"
 	"github.com/palantir/policy-bot/pull/pulltest"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
 )
 
 func TestHasLabels(t *testing.T) {
-	p := HasLabels([]string{"foo", "bar"})
+	p := &HasLabels{Labels: []string{"foo", "bar"}}
 
 	runLabelsTestCase(t, p, []HasLabelsTestCase{
 		{
@@ -64,6 +66,171 @@ func TestHasLabels(t *testing.T) {
 	})
 }
 
+func TestHasLabelsAny(t *testing.T) {
+	p := &HasLabels{Labels: []string{"Needs-Security-Review", "risk:high"}, Match: LabelsMatchAny}
+
+	runLabelsTestCase(t, p, []HasLabelsTestCase{
+		{
+			"one label",
+			&pulltest.Context{
+				LabelsValue: []string{"docs", "risk:high"},
+			},
+			&common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"docs", "risk:high"},
+				ConditionValues: []string{"Needs-Security-Review", "risk:high"},
+			},
+		},
+		{
+			"all labels with different case",
+			&pulltest.Context{
+				LabelsValue: []string{"needs-security-review", "risk:high"},
+			},
+			&common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"needs-security-review", "risk:high"},
+				ConditionValues: []string{"Needs-Security-Review", "risk:high"},
+			},
+		},
+		{
+			"no matching labels",
+			&pulltest.Context{
+				LabelsValue: []string{"docs"},
+			},
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"docs"},
+				ConditionValues: []string{"Needs-Security-Review", "risk:high"},
+			},
+		},
+	})
+
+	t.Run("invalidMatch", func(t *testing.T) {
+		p := &HasLabels{Labels: []string{"docs"}, Match: "some"}
+		_, err := p.Evaluate(context.Background(), &pulltest.Context{})
+		assert.Error(t, err)
+	})
+}
+
+func TestHasLabelsUnmarshal(t *testing.T) {
+	var list HasLabels
+	require.NoError(t, yaml.UnmarshalStrict([]byte("- foo\n- bar"), &list))
+	assert.Equal(t, HasLabels{Labels: []string{"foo", "bar"}}, list)
+
+	var object HasLabels
+	require.NoError(t, yaml.UnmarshalStrict([]byte("labels: [foo, bar]\nmatch: any"), &object))
+	assert.Equal(t, HasLabels{Labels: []string{"foo", "bar"}, Match: LabelsMatchAny}, object)
+
+	var invalid HasLabels
+	assert.Error(t, yaml.UnmarshalStrict([]byte("names: [foo]"), &invalid))
+
+	var invalidMatch HasLabels
+	assert.EqualError(t, yaml.UnmarshalStrict([]byte("labels: [foo]\nmatch: some"), &invalidMatch), `invalid labels match "some", must be "all" or "any"`)
+}
+
+func TestHasLabelAppliedBy(t *testing.T) {
+	p := &HasLabelAppliedBy{
+		Label: "Security-Approved",
+		Actors: common.Actors{
+			Teams: []string{"testorg/security"},
+		},
+	}
+
+	conditions := map[string][]string{
+		"Organizations": p.Organizations,
+		"Teams":         p.Teams,
+		"Users":         p.Users,
+	}
+
+	runLabelsTestCase(t, p, []HasLabelsTestCase{
+		{
+			"applied by eligible user",
+			&pulltest.Context{
+				LabelsValue: []string{"security-approved"},
+				LabelAppliersValue: map[string]string{
+					"security-approved": "mhaypenny",
+				},
+				TeamMemberships: map[string][]string{
+					"mhaypenny": {"testorg/security"},
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:     true,
+				Values:        []string{"Security-Approved (applied by mhaypenny)"},
+				ConditionsMap: conditions,
+			},
+		},
+		{
+			"applied by ineligible user",
+			&pulltest.Context{
+				LabelsValue: []string{"security-approved"},
+				LabelAppliersValue: map[string]string{
+					"security-approved": "ttest",
+				},
+				TeamMemberships: map[string][]string{
+					"mhaypenny": {"testorg/security"},
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:     false,
+				Values:        []string{"Security-Approved (applied by ttest)"},
+				ConditionsMap: conditions,
+			},
+		},
+		{
+			"label removed",
+			&pulltest.Context{
+				LabelsValue: []string{"foo"},
+				LabelAppliersValue: map[string]string{
+					"security-approved": "mhaypenny",
+				},
+				TeamMemberships: map[string][]string{
+					"mhaypenny": {"testorg/security"},
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:     false,
+				Values:        []string{"Security-Approved"},
+				ConditionsMap: conditions,
+			},
+		},
+		{
+			"unknown applier",
+			&pulltest.Context{
+				LabelsValue:        []string{"security-approved"},
+				LabelAppliersValue: map[string]string{},
+			},
+			&common.PredicateResult{
+				Satisfied:     false,
+				Values:        []string{"Security-Approved"},
+				ConditionsMap: conditions,
+			},
+		},
+	})
+
+	anyone := &HasLabelAppliedBy{Label: "security-approved"}
+	runLabelsTestCase(t, anyone, []HasLabelsTestCase{
+		{
+			"applied by any user",
+			&pulltest.Context{
+				LabelsValue: []string{"security-approved"},
+				LabelAppliersValue: map[string]string{
+					"security-approved": "ttest",
+				},
+			},
+			&common.PredicateResult{
+				Satisfied: true,
+				Values:    []string{"security-approved (applied by ttest)"},
+				ConditionsMap: map[string][]string{
+					"Organizations": nil,
+					"Teams":         nil,
+					"Users":         nil,
+				},
+			},
+		},
+	})
+}
+
 type HasLabelsTestCase struct {
 	name                    string
 	context                 pull.Context
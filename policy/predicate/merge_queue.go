@@ -0,0 +1,59 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate
+
+import (
+	"context"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull"
+)
+
+// InMergeQueue is satisfied if the pull request is evaluated for a merge
+// group created by a merge queue. If false, it is satisfied if the pull
+// request is evaluated for any other event.
+type InMergeQueue bool
+
+var _ Predicate = InMergeQueue(false)
+
+func (pred InMergeQueue) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	source := prctx.EventSource()
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "evaluation source",
+		ConditionPhrase: "match",
+		Values:          []string{string(source)},
+	}
+	if pred {
+		predicateResult.ConditionValues = []string{string(pull.EventSourceMergeGroup)}
+	} else {
+		predicateResult.ConditionValues = []string{"not " + string(pull.EventSourceMergeGroup)}
+	}
+
+	inQueue := source == pull.EventSourceMergeGroup
+	switch {
+	case inQueue && !bool(pred):
+		predicateResult.Description = "The pull request is evaluated for a merge group"
+	case !inQueue && bool(pred):
+		predicateResult.Description = "The pull request is not evaluated for a merge group"
+	default:
+		predicateResult.Satisfied = true
+	}
+	return &predicateResult, nil
+}
+
+func (pred InMergeQueue) Trigger() common.Trigger {
+	return common.TriggerStatic
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/palantir/policy-bot/pull/pulltest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMergeQueue(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Pred     InMergeQueue
+		Source   pull.EventSource
+		Expected *common.PredicateResult
+	}{
+		{
+			Name:   "mergeGroup",
+			Pred:   true,
+			Source: pull.EventSourceMergeGroup,
+			Expected: &common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"merge_group"},
+				ConditionValues: []string{"merge_group"},
+			},
+		},
+		{
+			Name:   "pullRequest",
+			Pred:   true,
+			Source: pull.EventSourcePullRequest,
+			Expected: &common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"pull_request"},
+				ConditionValues: []string{"merge_group"},
+			},
+		},
+		{
+			Name: "defaultSource",
+			Pred: true,
+			Expected: &common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"pull_request"},
+				ConditionValues: []string{"merge_group"},
+			},
+		},
+		{
+			Name:   "invertedMergeGroup",
+			Pred:   false,
+			Source: pull.EventSourceMergeGroup,
+			Expected: &common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"merge_group"},
+				ConditionValues: []string{"not merge_group"},
+			},
+		},
+		{
+			Name:   "invertedPullRequest",
+			Pred:   false,
+			Source: pull.EventSourcePullRequest,
+			Expected: &common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"pull_request"},
+				ConditionValues: []string{"not merge_group"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			prctx := &pulltest.Context{
+				EventSourceValue: test.Source,
+			}
+
+			result, err := test.Pred.Evaluate(context.Background(), prctx)
+			require.NoError(t, err)
+			assertPredicateResult(t, test.Expected, result)
+		})
+	}
+}
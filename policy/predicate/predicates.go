@@ -14,20 +14,56 @@
 
 package predicate
 
+import (
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
 type Predicates struct {
 	ChangedFiles     *ChangedFiles     `yaml:"changed_files"`
 	NoChangedFiles   *NoChangedFiles   `yaml:"no_changed_files"`
 	OnlyChangedFiles *OnlyChangedFiles `yaml:"only_changed_files"`
 
+	ChangedBinaryFiles *ChangedBinaryFiles `yaml:"changed_binary_files"`
+
+	HasSingleTopLevelDirectory *HasSingleTopLevelDirectory `yaml:"has_single_top_level_directory"`
+
+	HasNewDependencies *HasNewDependencies `yaml:"has_new_dependencies"`
+	ChangedFileContent *ChangedFileContent `yaml:"changed_file_content"`
+
 	HasAuthorIn             *HasAuthorIn             `yaml:"has_author_in"`
+	HasAuthorInFile         *HasAuthorInFile         `yaml:"has_author_in_file"`
 	HasContributorIn        *HasContributorIn        `yaml:"has_contributor_in"`
 	OnlyHasContributorsIn   *OnlyHasContributorsIn   `yaml:"only_has_contributors_in"`
 	AuthorIsOnlyContributor *AuthorIsOnlyContributor `yaml:"author_is_only_contributor"`
+	AuthorIsCodeOwner       *AuthorIsCodeOwner       `yaml:"author_is_code_owner"`
+
+	AuthorIsRequestedReviewer *AuthorIsRequestedReviewer `yaml:"author_is_requested_reviewer"`
+	AuthorRemovedReviewers    *AuthorRemovedReviewers    `yaml:"author_removed_reviewers"`
+	HasAuthorPermission       *HasAuthorPermission       `yaml:"has_author_permission"`
+
+	TargetsBranch          *TargetsBranch          `yaml:"targets_branch"`
+	TargetsProtectedBranch *TargetsProtectedBranch `yaml:"targets_protected_branch"`
+	FromBranch             *FromBranch             `yaml:"from_branch"`
+
+	ModifiedLines      *ModifiedLines      `yaml:"modified_lines"`
+	ChangedFileCount   *ChangedFileCount   `yaml:"changed_file_count"`
+	FileExtensionCount *FileExtensionCount `yaml:"file_extension_count"`
+	FilesPerReviewer   *FilesPerReviewer   `yaml:"files_per_reviewer"`
 
-	TargetsBranch *TargetsBranch `yaml:"targets_branch"`
-	FromBranch    *FromBranch    `yaml:"from_branch"`
+	CommitsSinceApproval    *CommitsSinceApproval    `yaml:"commits_since_approval"`
+	CommitCount             *CommitCount             `yaml:"commit_count"`
+	HasChronologicalCommits *HasChronologicalCommits `yaml:"has_chronological_commits"`
+	CommitMessages          *CommitMessages          `yaml:"commit_messages"`
+	HasLocalCommits         *HasLocalCommits         `yaml:"has_local_commits"`
+	HasLinearHistory        *HasLinearHistory        `yaml:"has_linear_history"`
 
-	ModifiedLines *ModifiedLines `yaml:"modified_lines"`
+	HasResolvedReviewThreads *HasResolvedReviewThreads `yaml:"has_resolved_review_threads"`
+	ReviewSLA                *ReviewSLA                `yaml:"review_sla"`
+	HasApprovingReviews      *HasApprovingReviews      `yaml:"has_approving_reviews"`
+	HasApproverInRegion      *HasApproverInRegion      `yaml:"has_approver_in_region"`
 
 	HasStatus *HasStatus `yaml:"has_status"`
 	// `has_successful_status` is a deprecated field that is kept for backwards
@@ -35,16 +71,32 @@ type Predicates struct {
 	// rather than just "success".
 	HasSuccessfulStatus *HasSuccessfulStatus `yaml:"has_successful_status"`
 
+	HasCompletedStatuses *HasCompletedStatuses `yaml:"has_completed_statuses"`
+
+	HasCheckResult *HasCheckResult `yaml:"has_check_result"`
+
 	HasWorkflowResult *HasWorkflowResult `yaml:"has_workflow_result"`
 
-	HasLabels *HasLabels `yaml:"has_labels"`
+	HasSuccessfulDeployment *HasSuccessfulDeployment `yaml:"has_successful_deployment"`
+
+	HasLabels         *HasLabels         `yaml:"has_labels"`
+	HasLabelAppliedBy *HasLabelAppliedBy `yaml:"has_label_applied_by"`
 
 	Repository *Repository `yaml:"repository"`
 	Title      *Title      `yaml:"title"`
+	Body       *Body       `yaml:"body"`
+
+	Age              *Age              `yaml:"age"`
+	AuthorAccountAge *AuthorAccountAge `yaml:"author_account_age"`
+	IsReopened       *IsReopened       `yaml:"is_reopened"`
+	HasAutoMerge     *HasAutoMerge     `yaml:"has_auto_merge"`
+	InMergeQueue     *InMergeQueue     `yaml:"in_merge_queue"`
 
 	HasValidSignatures       *HasValidSignatures       `yaml:"has_valid_signatures"`
 	HasValidSignaturesBy     *HasValidSignaturesBy     `yaml:"has_valid_signatures_by"`
 	HasValidSignaturesByKeys *HasValidSignaturesByKeys `yaml:"has_valid_signatures_by_keys"`
+	HasValidSignaturesFrom   *HasValidSignaturesFrom   `yaml:"has_valid_signatures_from"`
+	HeadCommitVerified       *HeadCommitVerified       `yaml:"head_commit_verified"`
 }
 
 func (p *Predicates) Predicates() []Predicate {
@@ -59,10 +111,26 @@ func (p *Predicates) Predicates() []Predicate {
 	if p.OnlyChangedFiles != nil {
 		ps = append(ps, Predicate(p.OnlyChangedFiles))
 	}
+	if p.ChangedBinaryFiles != nil {
+		ps = append(ps, Predicate(p.ChangedBinaryFiles))
+	}
+	if p.HasSingleTopLevelDirectory != nil {
+		ps = append(ps, Predicate(p.HasSingleTopLevelDirectory))
+	}
+
+	if p.HasNewDependencies != nil {
+		ps = append(ps, Predicate(p.HasNewDependencies))
+	}
+	if p.ChangedFileContent != nil {
+		ps = append(ps, Predicate(p.ChangedFileContent))
+	}
 
 	if p.HasAuthorIn != nil {
 		ps = append(ps, Predicate(p.HasAuthorIn))
 	}
+	if p.HasAuthorInFile != nil {
+		ps = append(ps, Predicate(p.HasAuthorInFile))
+	}
 	if p.HasContributorIn != nil {
 		ps = append(ps, Predicate(p.HasContributorIn))
 	}
@@ -72,10 +140,25 @@ func (p *Predicates) Predicates() []Predicate {
 	if p.AuthorIsOnlyContributor != nil {
 		ps = append(ps, Predicate(p.AuthorIsOnlyContributor))
 	}
+	if p.AuthorIsCodeOwner != nil {
+		ps = append(ps, Predicate(p.AuthorIsCodeOwner))
+	}
+	if p.AuthorIsRequestedReviewer != nil {
+		ps = append(ps, Predicate(p.AuthorIsRequestedReviewer))
+	}
+	if p.AuthorRemovedReviewers != nil {
+		ps = append(ps, Predicate(p.AuthorRemovedReviewers))
+	}
+	if p.HasAuthorPermission != nil {
+		ps = append(ps, Predicate(p.HasAuthorPermission))
+	}
 
 	if p.TargetsBranch != nil {
 		ps = append(ps, Predicate(p.TargetsBranch))
 	}
+	if p.TargetsProtectedBranch != nil {
+		ps = append(ps, Predicate(p.TargetsProtectedBranch))
+	}
 	if p.FromBranch != nil {
 		ps = append(ps, Predicate(p.FromBranch))
 	}
@@ -83,6 +166,47 @@ func (p *Predicates) Predicates() []Predicate {
 	if p.ModifiedLines != nil {
 		ps = append(ps, Predicate(p.ModifiedLines))
 	}
+	if p.ChangedFileCount != nil {
+		ps = append(ps, Predicate(p.ChangedFileCount))
+	}
+	if p.FileExtensionCount != nil {
+		ps = append(ps, Predicate(p.FileExtensionCount))
+	}
+	if p.FilesPerReviewer != nil {
+		ps = append(ps, Predicate(p.FilesPerReviewer))
+	}
+
+	if p.CommitsSinceApproval != nil {
+		ps = append(ps, Predicate(p.CommitsSinceApproval))
+	}
+	if p.CommitCount != nil {
+		ps = append(ps, Predicate(p.CommitCount))
+	}
+	if p.HasChronologicalCommits != nil {
+		ps = append(ps, Predicate(p.HasChronologicalCommits))
+	}
+	if p.CommitMessages != nil {
+		ps = append(ps, Predicate(p.CommitMessages))
+	}
+	if p.HasLocalCommits != nil {
+		ps = append(ps, Predicate(p.HasLocalCommits))
+	}
+	if p.HasLinearHistory != nil {
+		ps = append(ps, Predicate(p.HasLinearHistory))
+	}
+
+	if p.HasResolvedReviewThreads != nil {
+		ps = append(ps, Predicate(p.HasResolvedReviewThreads))
+	}
+	if p.ReviewSLA != nil {
+		ps = append(ps, Predicate(p.ReviewSLA))
+	}
+	if p.HasApprovingReviews != nil {
+		ps = append(ps, Predicate(p.HasApprovingReviews))
+	}
+	if p.HasApproverInRegion != nil {
+		ps = append(ps, Predicate(p.HasApproverInRegion))
+	}
 
 	if p.HasStatus != nil {
 		ps = append(ps, Predicate(p.HasStatus))
@@ -92,13 +216,28 @@ func (p *Predicates) Predicates() []Predicate {
 		ps = append(ps, Predicate(p.HasSuccessfulStatus))
 	}
 
+	if p.HasCompletedStatuses != nil {
+		ps = append(ps, Predicate(p.HasCompletedStatuses))
+	}
+
+	if p.HasCheckResult != nil {
+		ps = append(ps, Predicate(p.HasCheckResult))
+	}
+
 	if p.HasWorkflowResult != nil {
 		ps = append(ps, Predicate(p.HasWorkflowResult))
 	}
 
+	if p.HasSuccessfulDeployment != nil {
+		ps = append(ps, Predicate(p.HasSuccessfulDeployment))
+	}
+
 	if p.HasLabels != nil {
 		ps = append(ps, Predicate(p.HasLabels))
 	}
+	if p.HasLabelAppliedBy != nil {
+		ps = append(ps, Predicate(p.HasLabelAppliedBy))
+	}
 
 	if p.Repository != nil {
 		ps = append(ps, Predicate(p.Repository))
@@ -107,6 +246,25 @@ func (p *Predicates) Predicates() []Predicate {
 	if p.Title != nil {
 		ps = append(ps, Predicate(p.Title))
 	}
+	if p.Body != nil {
+		ps = append(ps, Predicate(p.Body))
+	}
+
+	if p.Age != nil {
+		ps = append(ps, Predicate(p.Age))
+	}
+	if p.AuthorAccountAge != nil {
+		ps = append(ps, Predicate(p.AuthorAccountAge))
+	}
+	if p.IsReopened != nil {
+		ps = append(ps, Predicate(p.IsReopened))
+	}
+	if p.HasAutoMerge != nil {
+		ps = append(ps, Predicate(p.HasAutoMerge))
+	}
+	if p.InMergeQueue != nil {
+		ps = append(ps, Predicate(p.InMergeQueue))
+	}
 
 	if p.HasValidSignatures != nil {
 		ps = append(ps, Predicate(p.HasValidSignatures))
@@ -120,5 +278,33 @@ func (p *Predicates) Predicates() []Predicate {
 		ps = append(ps, Predicate(p.HasValidSignaturesByKeys))
 	}
 
+	if p.HasValidSignaturesFrom != nil {
+		ps = append(ps, Predicate(p.HasValidSignaturesFrom))
+	}
+
+	if p.HeadCommitVerified != nil {
+		ps = append(ps, Predicate(p.HeadCommitVerified))
+	}
+
 	return ps
 }
+
+// Merge returns predicates that contain every predicate set in p or in other.
+// It returns an error if p and other both set the same type of predicate.
+func (p Predicates) Merge(other Predicates) (Predicates, error) {
+	merged := p
+
+	mv := reflect.ValueOf(&merged).Elem()
+	ov := reflect.ValueOf(other)
+	for i := 0; i < ov.NumField(); i++ {
+		if ov.Field(i).IsNil() {
+			continue
+		}
+		if !mv.Field(i).IsNil() {
+			name, _, _ := strings.Cut(mv.Type().Field(i).Tag.Get("yaml"), ",")
+			return Predicates{}, errors.Errorf("predicate %q is defined more than once", name)
+		}
+		mv.Field(i).Set(ov.Field(i))
+	}
+	return merged, nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/pkg/errors"
+)
+
+// HasApproverInRegion is satisfied if at least one user who approved the pull
+// request with a GitHub review is in one of the regions. If no regions are
+// set, it is satisfied if at least one approver is in a region other than the
+// region of the author. Regions are assigned to users in the server
+// configuration. Approvals by the author are ignored.
+type HasApproverInRegion struct {
+	Regions []string `yaml:"regions"`
+}
+
+var _ Predicate = &HasApproverInRegion{}
+
+func (pred *HasApproverInRegion) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	reviews, err := prctx.Reviews()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list reviews")
+	}
+
+	userRegions := prctx.UserRegions()
+	author := prctx.Author()
+	authorRegion := userRegions[strings.ToLower(author)]
+
+	approvers := make(map[string]bool)
+	for _, r := range reviews {
+		if r.State == pull.ReviewApproved && r.Author != author {
+			approvers[r.Author] = true
+		}
+	}
+
+	names := make([]string, 0, len(approvers))
+	for name := range approvers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "approvers",
+		ConditionPhrase: "include an approver in",
+	}
+	switch {
+	case len(pred.Regions) > 0:
+		predicateResult.ConditionValues = pred.Regions
+	case authorRegion == "":
+		predicateResult.ConditionValues = []string{"any region"}
+	default:
+		predicateResult.ConditionValues = []string{"a region other than " + authorRegion}
+	}
+
+	var qualifying, others []string
+	for _, name := range names {
+		region := userRegions[strings.ToLower(name)]
+		value := fmt.Sprintf("%s (%s)", name, describeRegion(region))
+		if pred.inRegion(region, authorRegion) {
+			qualifying = append(qualifying, value)
+		} else {
+			others = append(others, value)
+		}
+	}
+
+	if len(qualifying) > 0 {
+		predicateResult.Satisfied = true
+		predicateResult.Values = qualifying
+		return &predicateResult, nil
+	}
+
+	predicateResult.Values = others
+	predicateResult.Description = "No approver is in a qualifying region"
+	return &predicateResult, nil
+}
+
+func (pred *HasApproverInRegion) inRegion(region, authorRegion string) bool {
+	if region == "" {
+		return false
+	}
+	if len(pred.Regions) == 0 {
+		return region != authorRegion
+	}
+	for _, r := range pred.Regions {
+		if r == region {
+			return true
+		}
+	}
+	return false
+}
+
+func (pred *HasApproverInRegion) Trigger() common.Trigger {
+	return common.TriggerReview
+}
+
+func describeRegion(region string) string {
+	if region == "" {
+		return "no region"
+	}
+	return region
+}
@@ -0,0 +1,153 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/palantir/policy-bot/pull/pulltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHasApproverInRegion(t *testing.T) {
+	regions := map[string]string{
+		"mhaypenny": "amer",
+		"alice":     "emea",
+		"bob":       "apac",
+		"carol":     "amer",
+	}
+
+	approvals := func(users ...string) []*pull.Review {
+		var reviews []*pull.Review
+		for _, u := range users {
+			reviews = append(reviews, &pull.Review{Author: u, State: pull.ReviewApproved})
+		}
+		return reviews
+	}
+
+	tests := []struct {
+		Name     string
+		Pred     *HasApproverInRegion
+		Author   string
+		Reviews  []*pull.Review
+		Expected *common.PredicateResult
+	}{
+		{
+			Name:    "approverInRegion",
+			Pred:    &HasApproverInRegion{Regions: []string{"emea", "apac"}},
+			Author:  "mhaypenny",
+			Reviews: approvals("carol", "Alice"),
+			Expected: &common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"Alice (emea)"},
+				ConditionValues: []string{"emea", "apac"},
+			},
+		},
+		{
+			Name:    "noApproverInRegion",
+			Pred:    &HasApproverInRegion{Regions: []string{"apac"}},
+			Author:  "mhaypenny",
+			Reviews: approvals("carol", "dave"),
+			Expected: &common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"carol (amer)", "dave (no region)"},
+				ConditionValues: []string{"apac"},
+			},
+		},
+		{
+			Name:   "ignoresOtherReviews",
+			Pred:   &HasApproverInRegion{Regions: []string{"emea"}},
+			Author: "mhaypenny",
+			Reviews: []*pull.Review{
+				{Author: "alice", State: pull.ReviewCommented},
+				{Author: "alice", State: pull.ReviewChangesRequested},
+			},
+			Expected: &common.PredicateResult{
+				Satisfied:       false,
+				ConditionValues: []string{"emea"},
+			},
+		},
+		{
+			Name:    "ignoresAuthor",
+			Pred:    &HasApproverInRegion{Regions: []string{"emea"}},
+			Author:  "alice",
+			Reviews: approvals("alice"),
+			Expected: &common.PredicateResult{
+				Satisfied:       false,
+				ConditionValues: []string{"emea"},
+			},
+		},
+		{
+			Name:    "crossRegion",
+			Pred:    &HasApproverInRegion{},
+			Author:  "mhaypenny",
+			Reviews: approvals("carol", "bob", "alice"),
+			Expected: &common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"alice (emea)", "bob (apac)"},
+				ConditionValues: []string{"a region other than amer"},
+			},
+		},
+		{
+			Name:    "crossRegionSameRegion",
+			Pred:    &HasApproverInRegion{},
+			Author:  "mhaypenny",
+			Reviews: approvals("carol", "dave"),
+			Expected: &common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"carol (amer)", "dave (no region)"},
+				ConditionValues: []string{"a region other than amer"},
+			},
+		},
+		{
+			Name:    "crossRegionAuthorWithoutRegion",
+			Pred:    &HasApproverInRegion{},
+			Author:  "dave",
+			Reviews: approvals("carol"),
+			Expected: &common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"carol (amer)"},
+				ConditionValues: []string{"any region"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			prctx := &pulltest.Context{
+				AuthorValue:      test.Author,
+				ReviewsValue:     test.Reviews,
+				UserRegionsValue: regions,
+			}
+
+			result, err := test.Pred.Evaluate(context.Background(), prctx)
+			require.NoError(t, err)
+			assertPredicateResult(t, test.Expected, result)
+		})
+	}
+}
+
+func TestHasApproverInRegionError(t *testing.T) {
+	prctx := &pulltest.Context{
+		ReviewsError: assert.AnError,
+	}
+
+	_, err := (&HasApproverInRegion{}).Evaluate(context.Background(), prctx)
+	assert.Error(t, err)
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate
+
+import (
+	"context"
+	"time"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/pkg/errors"
+)
+
+// IsReopened is satisfied if the pull request was closed and reopened at
+// least once. If false, it is satisfied if the pull request was never
+// reopened.
+type IsReopened bool
+
+var _ Predicate = IsReopened(false)
+
+func (pred IsReopened) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	reopenedAt, err := prctx.ReopenedAt()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get reopened time")
+	}
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "pull request",
+		ConditionPhrase: "meet the condition",
+	}
+	if pred {
+		predicateResult.ConditionValues = []string{"it was reopened"}
+	} else {
+		predicateResult.ConditionValues = []string{"it was never reopened"}
+	}
+
+	reopened := !reopenedAt.IsZero()
+	if reopened {
+		predicateResult.Values = []string{"reopened at " + reopenedAt.UTC().Format(time.RFC3339)}
+	} else {
+		predicateResult.Values = []string{"never reopened"}
+	}
+
+	switch {
+	case reopened && !bool(pred):
+		predicateResult.Description = "The pull request was reopened at " + reopenedAt.UTC().Format(time.RFC3339)
+	case !reopened && bool(pred):
+		predicateResult.Description = "The pull request was never reopened"
+	default:
+		predicateResult.Satisfied = true
+	}
+	return &predicateResult, nil
+}
+
+func (pred IsReopened) Trigger() common.Trigger {
+	return common.TriggerPullRequest
+}
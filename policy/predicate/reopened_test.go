@@ -0,0 +1,91 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull/pulltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsReopened(t *testing.T) {
+	reopenedAt := time.Date(2024, 6, 12, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		Name       string
+		Pred       IsReopened
+		ReopenedAt time.Time
+		Expected   *common.PredicateResult
+	}{
+		{
+			Name:       "reopened",
+			Pred:       true,
+			ReopenedAt: reopenedAt,
+			Expected: &common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"reopened at 2024-06-12T15:04:05Z"},
+				ConditionValues: []string{"it was reopened"},
+			},
+		},
+		{
+			Name: "neverReopened",
+			Pred: true,
+			Expected: &common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"never reopened"},
+				ConditionValues: []string{"it was reopened"},
+			},
+		},
+		{
+			Name:       "invertedReopened",
+			Pred:       false,
+			ReopenedAt: reopenedAt,
+			Expected: &common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"reopened at 2024-06-12T15:04:05Z"},
+				ConditionValues: []string{"it was never reopened"},
+			},
+		},
+		{
+			Name: "invertedNeverReopened",
+			Pred: false,
+			Expected: &common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"never reopened"},
+				ConditionValues: []string{"it was never reopened"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			prctx := &pulltest.Context{
+				ReopenedAtValue: test.ReopenedAt,
+			}
+
+			result, err := test.Pred.Evaluate(context.Background(), prctx)
+			require.NoError(t, err)
+			assertPredicateResult(t, test.Expected, result)
+		})
+	}
+
+	_, err := IsReopened(true).Evaluate(context.Background(), &pulltest.Context{ReopenedAtError: assert.AnError})
+	assert.Error(t, err)
+}
@@ -0,0 +1,250 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/pkg/errors"
+)
+
+// HasResolvedReviewThreads is satisfied if all review threads on the pull
+// request are resolved. If false, it is satisfied if any review thread is
+// unresolved.
+type HasResolvedReviewThreads bool
+
+var _ Predicate = HasResolvedReviewThreads(false)
+
+func (pred HasResolvedReviewThreads) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	threads, err := prctx.ReviewThreads()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get review threads")
+	}
+
+	var unresolved []string
+	for _, t := range threads {
+		if !t.IsResolved {
+			unresolved = append(unresolved, t.Path)
+		}
+	}
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "unresolved review threads",
+		Values:          unresolved,
+		ConditionPhrase: "meet the condition",
+	}
+	if pred {
+		predicateResult.ConditionValues = []string{"there are none"}
+	} else {
+		predicateResult.ConditionValues = []string{"there is at least one"}
+	}
+
+	switch {
+	case len(unresolved) > 0 && bool(pred):
+		predicateResult.Description = fmt.Sprintf("%d review thread(s) are not resolved", len(unresolved))
+	case len(unresolved) == 0 && !bool(pred):
+		predicateResult.Description = "All review threads are resolved"
+	default:
+		predicateResult.Satisfied = true
+	}
+	return &predicateResult, nil
+}
+
+func (pred HasResolvedReviewThreads) Trigger() common.Trigger {
+	return common.TriggerReview | common.TriggerComment
+}
+
+// HasApprovingReviews compares the number of users who approved the pull
+// request with a GitHub review with an expression. Reviews by the pull request
+// author do not count and each user counts once. If InvalidateOnPush is true,
+// reviews submitted before the head commit was pushed do not count.
+type HasApprovingReviews struct {
+	Count            ComparisonExpr `yaml:"count"`
+	InvalidateOnPush bool           `yaml:"invalidate_on_push"`
+}
+
+var _ Predicate = &HasApprovingReviews{}
+
+func (pred *HasApprovingReviews) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "approving reviews",
+		ConditionPhrase: "meet the condition",
+		ConditionValues: []string{pred.Count.String()},
+	}
+
+	reviews, err := prctx.Reviews()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list reviews")
+	}
+
+	var pushedAt time.Time
+	if pred.InvalidateOnPush {
+		pushedAt, err = prctx.PushedAt(prctx.HeadSHA())
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get last push timestamp")
+		}
+	}
+
+	approvers := make(map[string]bool)
+	for _, r := range reviews {
+		if r.State != pull.ReviewApproved || r.Author == prctx.Author() {
+			continue
+		}
+		if pred.InvalidateOnPush && !r.CreatedAt.After(pushedAt) {
+			continue
+		}
+		approvers[r.Author] = true
+	}
+
+	count := int64(len(approvers))
+	predicateResult.Values = []string{strconv.FormatInt(count, 10)}
+	if pred.Count.Evaluate(count) {
+		predicateResult.Satisfied = true
+		return &predicateResult, nil
+	}
+
+	predicateResult.Description = fmt.Sprintf("The number of approving reviews (%d) does not match the condition %s", count, pred.Count)
+	return &predicateResult, nil
+}
+
+func (pred *HasApprovingReviews) Trigger() common.Trigger {
+	t := common.TriggerReview
+	if pred.InvalidateOnPush {
+		t |= common.TriggerCommit
+	}
+	return t
+}
+
+// ReviewSLA is satisfied if every user who was requested to review the pull
+// request approved it within the configured duration of their most recent
+// request. Pending requests only miss the SLA once the duration has passed.
+// Requests for teams and requests that were removed before the user approved
+// are ignored.
+//
+// Like Age, the result of this predicate changes with time alone, so results
+// set ReevaluateAt to the time the earliest pending request misses the SLA.
+type ReviewSLA struct {
+	Within Duration `yaml:"within"`
+}
+
+var _ Predicate = &ReviewSLA{}
+
+func (pred *ReviewSLA) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type rawReviewSLA ReviewSLA
+	var raw rawReviewSLA
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	if raw.Within <= 0 {
+		return errors.New("review_sla: within must be a positive duration")
+	}
+	*pred = ReviewSLA(raw)
+	return nil
+}
+
+func (pred *ReviewSLA) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	requests, err := prctx.ReviewRequests()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get review requests")
+	}
+
+	reviews, err := prctx.Reviews()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get reviews")
+	}
+
+	sla := time.Duration(pred.Within)
+	now := prctx.EvaluationTimestamp()
+
+	var values, missed []string
+	var reevaluateAt time.Time
+	for _, req := range requests {
+		if req.Type != pull.ReviewerUser {
+			continue
+		}
+
+		approvedAt := firstApprovalAfter(reviews, req.Name, req.RequestedAt)
+		if !req.RemovedAt.IsZero() && (approvedAt.IsZero() || approvedAt.After(req.RemovedAt)) {
+			continue
+		}
+
+		var value string
+		switch {
+		case !approvedAt.IsZero():
+			elapsed := approvedAt.Sub(req.RequestedAt)
+			if elapsed <= sla {
+				value = fmt.Sprintf("%s approved after %s (met)", req.Name, Duration(elapsed))
+			} else {
+				value = fmt.Sprintf("%s approved after %s (missed)", req.Name, Duration(elapsed))
+				missed = append(missed, req.Name)
+			}
+		default:
+			elapsed := now.Sub(req.RequestedAt)
+			if elapsed <= sla {
+				value = fmt.Sprintf("%s has not approved after %s (pending)", req.Name, Duration(elapsed))
+
+				// elapsed times equal to the SLA still meet it
+				if t := req.RequestedAt.Add(sla + time.Second); reevaluateAt.IsZero() || t.Before(reevaluateAt) {
+					reevaluateAt = t
+				}
+			} else {
+				value = fmt.Sprintf("%s has not approved after %s (missed)", req.Name, Duration(elapsed))
+				missed = append(missed, req.Name)
+			}
+		}
+		values = append(values, value)
+	}
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "requested reviewers",
+		Values:          values,
+		ConditionPhrase: "approved within",
+		ConditionValues: []string{pred.Within.String()},
+		ReevaluateAt:    reevaluateAt,
+	}
+
+	if len(missed) > 0 {
+		predicateResult.Description = fmt.Sprintf("The review SLA of %s was missed by %s", pred.Within, strings.Join(missed, ", "))
+		return &predicateResult, nil
+	}
+
+	predicateResult.Satisfied = true
+	return &predicateResult, nil
+}
+
+func (pred *ReviewSLA) Trigger() common.Trigger {
+	return common.TriggerReview | common.TriggerPullRequest
+}
+
+// firstApprovalAfter returns the time of the first approval by user that was
+// submitted at or after start, or the zero time if there is no such approval.
+func firstApprovalAfter(reviews []*pull.Review, user string, start time.Time) time.Time {
+	var first time.Time
+	for _, r := range reviews {
+		if r.Author != user || r.State != pull.ReviewApproved || r.CreatedAt.Before(start) {
+			continue
+		}
+		if first.IsZero() || r.CreatedAt.Before(first) {
+			first = r.CreatedAt
+		}
+	}
+	return first
+}
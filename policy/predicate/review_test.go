@@ -0,0 +1,384 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/palantir/policy-bot/pull/pulltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestHasResolvedReviewThreads(t *testing.T) {
+	threads := map[string][]*pull.ReviewThread{
+		"empty": nil,
+		"resolved": {
+			{Path: "app/client.go", IsResolved: true},
+			{Path: "app/server.go", IsResolved: true, IsOutdated: true},
+		},
+		"unresolved": {
+			{Path: "app/client.go", IsResolved: true},
+			{Path: "app/server.go", IsResolved: false},
+			{Path: "README.md", IsResolved: false},
+		},
+	}
+
+	tests := []struct {
+		Name     string
+		Pred     HasResolvedReviewThreads
+		Threads  string
+		Expected *common.PredicateResult
+	}{
+		{
+			Name:    "empty",
+			Pred:    true,
+			Threads: "empty",
+			Expected: &common.PredicateResult{
+				Satisfied:       true,
+				ConditionValues: []string{"there are none"},
+			},
+		},
+		{
+			Name:    "resolved",
+			Pred:    true,
+			Threads: "resolved",
+			Expected: &common.PredicateResult{
+				Satisfied:       true,
+				ConditionValues: []string{"there are none"},
+			},
+		},
+		{
+			Name:    "unresolved",
+			Pred:    true,
+			Threads: "unresolved",
+			Expected: &common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"app/server.go", "README.md"},
+				ConditionValues: []string{"there are none"},
+			},
+		},
+		{
+			Name:    "invertedEmpty",
+			Pred:    false,
+			Threads: "empty",
+			Expected: &common.PredicateResult{
+				Satisfied:       false,
+				ConditionValues: []string{"there is at least one"},
+			},
+		},
+		{
+			Name:    "invertedResolved",
+			Pred:    false,
+			Threads: "resolved",
+			Expected: &common.PredicateResult{
+				Satisfied:       false,
+				ConditionValues: []string{"there is at least one"},
+			},
+		},
+		{
+			Name:    "invertedUnresolved",
+			Pred:    false,
+			Threads: "unresolved",
+			Expected: &common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"app/server.go", "README.md"},
+				ConditionValues: []string{"there is at least one"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			prctx := &pulltest.Context{
+				ReviewThreadsValue: threads[test.Threads],
+			}
+
+			result, err := test.Pred.Evaluate(context.Background(), prctx)
+			require.NoError(t, err)
+			assertPredicateResult(t, test.Expected, result)
+		})
+	}
+}
+
+func TestHasResolvedReviewThreadsError(t *testing.T) {
+	prctx := &pulltest.Context{
+		ReviewThreadsError: assert.AnError,
+	}
+
+	_, err := HasResolvedReviewThreads(true).Evaluate(context.Background(), prctx)
+	assert.Error(t, err)
+}
+
+func TestHasApprovingReviews(t *testing.T) {
+	now := time.Now()
+
+	reviews := []*pull.Review{
+		{CreatedAt: now.Add(-3 * time.Hour), Author: "ttest", State: pull.ReviewApproved},
+		{CreatedAt: now.Add(-2 * time.Hour), Author: "jstrawnickel", State: pull.ReviewChangesRequested},
+		{CreatedAt: now.Add(-90 * time.Minute), Author: "mhaypenny", State: pull.ReviewApproved},
+		{CreatedAt: now.Add(-30 * time.Minute), Author: "jstrawnickel", State: pull.ReviewApproved},
+		{CreatedAt: now.Add(-20 * time.Minute), Author: "ttest", State: pull.ReviewApproved},
+		{CreatedAt: now.Add(-10 * time.Minute), Author: "bkeyes", State: pull.ReviewCommented},
+	}
+
+	prctx := &pulltest.Context{
+		AuthorValue:  "mhaypenny",
+		HeadSHAValue: "e05fcae367230ee709313dd2720da527d178ce43",
+		PushedAtValue: map[string]time.Time{
+			"e05fcae367230ee709313dd2720da527d178ce43": now.Add(-1 * time.Hour),
+		},
+		ReviewsValue: reviews,
+	}
+
+	tests := []struct {
+		Name     string
+		Pred     *HasApprovingReviews
+		Expected *common.PredicateResult
+	}{
+		{
+			Name: "countsEachApprover",
+			Pred: &HasApprovingReviews{
+				Count: ComparisonExpr{Op: OpGreaterThan, Value: 1},
+			},
+			Expected: &common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"2"},
+				ConditionValues: []string{"> 1"},
+			},
+		},
+		{
+			Name: "notEnoughApprovers",
+			Pred: &HasApprovingReviews{
+				Count: ComparisonExpr{Op: OpGreaterThan, Value: 2},
+			},
+			Expected: &common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"2"},
+				ConditionValues: []string{"> 2"},
+			},
+		},
+		{
+			Name: "invalidateOnPush",
+			Pred: &HasApprovingReviews{
+				Count:            ComparisonExpr{Op: OpGreaterThan, Value: 1},
+				InvalidateOnPush: true,
+			},
+			Expected: &common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"2"},
+				ConditionValues: []string{"> 1"},
+			},
+		},
+		{
+			Name: "invalidateOnPushIgnoresStale",
+			Pred: &HasApprovingReviews{
+				Count:            ComparisonExpr{Op: OpGreaterThan, Value: 2},
+				InvalidateOnPush: true,
+			},
+			Expected: &common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"2"},
+				ConditionValues: []string{"> 2"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			result, err := test.Pred.Evaluate(context.Background(), prctx)
+			require.NoError(t, err)
+			assertPredicateResult(t, test.Expected, result)
+		})
+	}
+
+	t.Run("staleApprovals", func(t *testing.T) {
+		prctx := &pulltest.Context{
+			AuthorValue:  "mhaypenny",
+			HeadSHAValue: "e05fcae367230ee709313dd2720da527d178ce43",
+			PushedAtValue: map[string]time.Time{
+				"e05fcae367230ee709313dd2720da527d178ce43": now.Add(-5 * time.Minute),
+			},
+			ReviewsValue: reviews,
+		}
+		pred := &HasApprovingReviews{
+			Count:            ComparisonExpr{Op: OpGreaterThan, Value: 0},
+			InvalidateOnPush: true,
+		}
+
+		result, err := pred.Evaluate(context.Background(), prctx)
+		require.NoError(t, err)
+		assert.False(t, result.Satisfied)
+		assert.Equal(t, []string{"0"}, result.Values)
+		assert.Equal(t, "The number of approving reviews (0) does not match the condition > 0", result.Description)
+	})
+
+	t.Run("trigger", func(t *testing.T) {
+		assert.Equal(t, common.TriggerReview, (&HasApprovingReviews{}).Trigger())
+		assert.Equal(t, common.TriggerReview|common.TriggerCommit, (&HasApprovingReviews{InvalidateOnPush: true}).Trigger())
+	})
+}
+
+func TestHasApprovingReviewsError(t *testing.T) {
+	prctx := &pulltest.Context{
+		ReviewsError: assert.AnError,
+	}
+
+	_, err := (&HasApprovingReviews{Count: ComparisonExpr{Op: OpGreaterThan, Value: 0}}).Evaluate(context.Background(), prctx)
+	assert.Error(t, err)
+}
+
+func TestReviewSLA(t *testing.T) {
+	now := time.Date(2023, time.March, 10, 12, 0, 0, 0, time.UTC)
+	hoursAgo := func(h int) time.Time {
+		return now.Add(-time.Duration(h) * time.Hour)
+	}
+
+	pred := &ReviewSLA{Within: Duration(24 * time.Hour)}
+
+	tests := []struct {
+		Name     string
+		Requests []*pull.ReviewRequest
+		Reviews  []*pull.Review
+		Expected *common.PredicateResult
+	}{
+		{
+			Name: "noRequests",
+			Expected: &common.PredicateResult{
+				Satisfied:       true,
+				ConditionValues: []string{"1d"},
+			},
+		},
+		{
+			Name: "approvedWithinSLA",
+			Requests: []*pull.ReviewRequest{
+				{Type: pull.ReviewerUser, Name: "mhaypenny", RequestedAt: hoursAgo(30)},
+				{Type: pull.ReviewerUser, Name: "ttest", RequestedAt: hoursAgo(30)},
+			},
+			Reviews: []*pull.Review{
+				{Author: "mhaypenny", State: pull.ReviewApproved, CreatedAt: hoursAgo(28)},
+				{Author: "ttest", State: pull.ReviewCommented, CreatedAt: hoursAgo(29)},
+				{Author: "ttest", State: pull.ReviewApproved, CreatedAt: hoursAgo(7)},
+			},
+			Expected: &common.PredicateResult{
+				Satisfied: true,
+				Values: []string{
+					"mhaypenny approved after 2h (met)",
+					"ttest approved after 23h (met)",
+				},
+				ConditionValues: []string{"1d"},
+			},
+		},
+		{
+			Name: "approvedAfterSLA",
+			Requests: []*pull.ReviewRequest{
+				{Type: pull.ReviewerUser, Name: "mhaypenny", RequestedAt: hoursAgo(30)},
+				{Type: pull.ReviewerUser, Name: "ttest", RequestedAt: hoursAgo(30)},
+			},
+			Reviews: []*pull.Review{
+				{Author: "mhaypenny", State: pull.ReviewApproved, CreatedAt: hoursAgo(28)},
+				{Author: "ttest", State: pull.ReviewApproved, CreatedAt: hoursAgo(1)},
+			},
+			Expected: &common.PredicateResult{
+				Satisfied: false,
+				Values: []string{
+					"mhaypenny approved after 2h (met)",
+					"ttest approved after 1d 5h (missed)",
+				},
+				ConditionValues: []string{"1d"},
+			},
+		},
+		{
+			Name: "pendingRequests",
+			Requests: []*pull.ReviewRequest{
+				{Type: pull.ReviewerUser, Name: "mhaypenny", RequestedAt: hoursAgo(3)},
+				{Type: pull.ReviewerUser, Name: "ttest", RequestedAt: hoursAgo(48)},
+			},
+			Expected: &common.PredicateResult{
+				Satisfied: false,
+				Values: []string{
+					"mhaypenny has not approved after 3h (pending)",
+					"ttest has not approved after 2d (missed)",
+				},
+				ConditionValues: []string{"1d"},
+				ReevaluateAt:    now.Add(21*time.Hour + time.Second),
+			},
+		},
+		{
+			Name: "approvalBeforeLatestRequest",
+			Requests: []*pull.ReviewRequest{
+				{Type: pull.ReviewerUser, Name: "mhaypenny", RequestedAt: hoursAgo(5)},
+			},
+			Reviews: []*pull.Review{
+				{Author: "mhaypenny", State: pull.ReviewApproved, CreatedAt: hoursAgo(40)},
+			},
+			Expected: &common.PredicateResult{
+				Satisfied: true,
+				Values: []string{
+					"mhaypenny has not approved after 5h (pending)",
+				},
+				ConditionValues: []string{"1d"},
+				ReevaluateAt:    now.Add(19*time.Hour + time.Second),
+			},
+		},
+		{
+			Name: "ignoresTeamsAndRemovedRequests",
+			Requests: []*pull.ReviewRequest{
+				{Type: pull.ReviewerTeam, Name: "team-maintainers", RequestedAt: hoursAgo(48)},
+				{Type: pull.ReviewerUser, Name: "mhaypenny", RequestedAt: hoursAgo(48), RemovedAt: hoursAgo(40)},
+				{Type: pull.ReviewerUser, Name: "ttest", RequestedAt: hoursAgo(48), RemovedAt: hoursAgo(40)},
+			},
+			Reviews: []*pull.Review{
+				{Author: "ttest", State: pull.ReviewApproved, CreatedAt: hoursAgo(42)},
+			},
+			Expected: &common.PredicateResult{
+				Satisfied: true,
+				Values: []string{
+					"ttest approved after 6h (met)",
+				},
+				ConditionValues: []string{"1d"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			prctx := &pulltest.Context{
+				EvaluationTimestampValue: now,
+				ReviewRequestsValue:      test.Requests,
+				ReviewsValue:             test.Reviews,
+			}
+
+			result, err := pred.Evaluate(context.Background(), prctx)
+			require.NoError(t, err)
+			assertPredicateResult(t, test.Expected, result)
+			assert.Equal(t, test.Expected.ReevaluateAt, result.ReevaluateAt, "reevaluation time was not correct")
+		})
+	}
+}
+
+func TestReviewSLAUnmarshal(t *testing.T) {
+	var pred ReviewSLA
+	require.NoError(t, yaml.UnmarshalStrict([]byte(`within: 2d`), &pred))
+	assert.Equal(t, Duration(48*time.Hour), pred.Within)
+
+	assert.Error(t, yaml.UnmarshalStrict([]byte(`within: 0s`), &ReviewSLA{}))
+	assert.Error(t, yaml.UnmarshalStrict([]byte(`{}`), &ReviewSLA{}))
+}
@@ -17,6 +17,8 @@ package predicate
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/palantir/policy-bot/policy/common"
 	"github.com/palantir/policy-bot/pull"
@@ -135,24 +137,79 @@ func (pred *HasValidSignaturesBy) Trigger() common.Trigger {
 	return common.TriggerCommit
 }
 
+// HasValidSignaturesByKeys is satisfied if all commits have valid GPG or SSH
+// signatures by one of the allowed keys. GPG keys match by key ID and SSH keys
+// match by fingerprint, so both types can be mixed. GitHub does not report the
+// fingerprints of GPG keys, so GPG fingerprints never match. Keys are allowed
+// if they are listed in KeyIDs, in KeyFingerprints, or in KeyFile, a file on
+// the base branch of the repository. Using the base branch prevents a pull
+// request from allowing its own keys. Each line of the file contains a single
+// key ID or fingerprint. Blank lines and text after a "#" are ignored.
 type HasValidSignaturesByKeys struct {
-	KeyIDs []string `yaml:"key_ids"`
+	KeyIDs          []string `yaml:"key_ids"`
+	KeyFingerprints []string `yaml:"key_fingerprints"`
+	KeyFile         string   `yaml:"key_file"`
 }
 
 var _ Predicate = &HasValidSignaturesByKeys{}
 
+// sshFingerprintPrefix is the prefix of the fingerprints GitHub reports for
+// SSH signing keys.
+const sshFingerprintPrefix = "SHA256:"
+
+func (pred *HasValidSignaturesByKeys) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type rawHasValidSignaturesByKeys HasValidSignaturesByKeys
+	var raw rawHasValidSignaturesByKeys
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+
+	for _, id := range raw.KeyIDs {
+		if strings.HasPrefix(id, sshFingerprintPrefix) {
+			return errors.Errorf("key_ids: %q is an SSH key fingerprint, list it in key_fingerprints", id)
+		}
+	}
+	for _, fp := range raw.KeyFingerprints {
+		if !strings.HasPrefix(fp, sshFingerprintPrefix) {
+			return errors.Errorf("key_fingerprints: %q is not an SSH key fingerprint, list GPG keys by ID in key_ids", fp)
+		}
+	}
+
+	*pred = HasValidSignaturesByKeys(raw)
+	return nil
+}
+
 func (pred *HasValidSignaturesByKeys) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
 	commits, err := prctx.Commits()
 
+	allowedKeys := append(slices.Clone(pred.KeyIDs), pred.KeyFingerprints...)
+
 	predicateResult := common.PredicateResult{
 		ConditionPhrase: "have valid signatures by keys",
-		ConditionValues: pred.KeyIDs,
+		ConditionValues: allowedKeys,
 	}
 
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get commits")
 	}
 
+	if pred.KeyFile != "" {
+		content, exists, err := prctx.BaseFileContent(pred.KeyFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get content of %s", pred.KeyFile)
+		}
+		if !exists {
+			predicateResult.ConditionPhrase = "have valid signatures by keys listed in the file"
+			predicateResult.ConditionValues = []string{pred.KeyFile}
+			predicateResult.Description = fmt.Sprintf("The file %s does not exist", pred.KeyFile)
+			predicateResult.Satisfied = false
+			return &predicateResult, nil
+		}
+
+		allowedKeys = append(allowedKeys, parseListFile(content)...)
+		predicateResult.ConditionValues = allowedKeys
+	}
+
 	keys := make(map[string][]string)
 
 	var commitHashes []string
@@ -167,34 +224,25 @@ func (pred *HasValidSignaturesByKeys) Evaluate(ctx context.Context, prctx pull.C
 			return &predicateResult, nil
 		}
 		commitHashes = append(commitHashes, c.SHA)
-		// Only GPG signatures are valid for this predicate
+		// Only GPG and SSH signatures are valid for this predicate. SSH
+		// signatures have no key ID, so they are identified by fingerprint.
 		switch c.Signature.Type {
 		case pull.SignatureGpg:
-			commitSHAs, ok := keys[c.Signature.KeyID]
-			if ok {
-				keys[c.Signature.KeyID] = append(commitSHAs, c.SHA)
-			} else {
-				keys[c.Signature.KeyID] = []string{c.SHA}
-			}
+			keys[c.Signature.KeyID] = append(keys[c.Signature.KeyID], c.SHA)
+		case pull.SignatureSSH:
+			keys[c.Signature.KeyFingerprint] = append(keys[c.Signature.KeyFingerprint], c.SHA)
 		default:
 			predicateResult.Values = []string{c.SHA}
 			predicateResult.ValuePhrase = "commits"
-			predicateResult.ConditionPhrase = "have GPG signatures"
-			predicateResult.Description = fmt.Sprintf("Commit %.10s signature is not a GPG signature", c.SHA)
+			predicateResult.ConditionPhrase = "have GPG or SSH signatures"
+			predicateResult.Description = fmt.Sprintf("Commit %.10s signature is not a GPG or SSH signature", c.SHA)
 			predicateResult.Satisfied = false
 			return &predicateResult, nil
 		}
 	}
 
 	for key := range keys {
-		isValidKey := false
-		for _, acceptedKey := range pred.KeyIDs {
-			if key == acceptedKey {
-				isValidKey = true
-				break
-			}
-		}
-		if !isValidKey {
+		if !slices.Contains(allowedKeys, key) {
 			predicateResult.ConditionPhrase = "exist in the set of allowed keys"
 			predicateResult.Values = []string{key}
 			predicateResult.ValuePhrase = "keys"
@@ -214,6 +262,125 @@ func (pred *HasValidSignaturesByKeys) Trigger() common.Trigger {
 	return common.TriggerCommit
 }
 
+// HasValidSignaturesFrom requires valid signatures on commits authored by the
+// matching actors. Commits by other authors do not need to be signed. Commits
+// with no author who is a GitHub user cannot be matched against the actors, so
+// they always require valid signatures. To require signatures from everyone
+// except trusted users, like external contributors but not internal bots,
+// match all users with "*" and list the trusted users in Except.
+type HasValidSignaturesFrom struct {
+	common.Actors `yaml:",inline"`
+}
+
+var _ Predicate = &HasValidSignaturesFrom{}
+
+func (pred *HasValidSignaturesFrom) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	commits, err := prctx.Commits()
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "commits",
+		ConditionPhrase: "have valid signatures if authored by members of",
+		ConditionsMap: map[string][]string{
+			"Organizations": pred.Organizations,
+			"Teams":         pred.Teams,
+			"Users":         pred.Users,
+		},
+	}
+	if e := pred.Except; e != nil {
+		predicateResult.ConditionsMap["Except Organizations"] = e.Organizations
+		predicateResult.ConditionsMap["Except Teams"] = e.Teams
+		predicateResult.ConditionsMap["Except Users"] = e.Users
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get commits")
+	}
+
+	var commitHashes []string
+	var unverified []string
+	var desc string
+
+	for _, c := range commits {
+		if c.Author != "" {
+			member, err := pred.IsActor(ctx, prctx, c.Author)
+			if err != nil {
+				return nil, err
+			}
+			if !member {
+				continue
+			}
+		}
+
+		commitHashes = append(commitHashes, c.SHA)
+		if valid, reason := hasValidSignature(ctx, c); !valid {
+			if desc == "" {
+				desc = reason
+			}
+			unverified = append(unverified, c.SHA)
+		}
+	}
+
+	if len(unverified) > 0 {
+		predicateResult.Values = unverified
+		predicateResult.Description = desc
+		if len(unverified) > 1 {
+			predicateResult.Description = fmt.Sprintf("%d commits by the specified authors do not have valid signatures", len(unverified))
+		}
+		predicateResult.Satisfied = false
+		return &predicateResult, nil
+	}
+
+	predicateResult.Values = commitHashes
+	predicateResult.Satisfied = true
+	return &predicateResult, nil
+}
+
+func (pred *HasValidSignaturesFrom) Trigger() common.Trigger {
+	return common.TriggerCommit
+}
+
+// HeadCommitVerified checks GitHub's verification of the signature on the
+// head commit of the pull request.
+type HeadCommitVerified bool
+
+var _ Predicate = HeadCommitVerified(false)
+
+func (pred HeadCommitVerified) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	verification, err := prctx.HeadCommitVerification()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get head commit verification")
+	}
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "head commits",
+		Values:          []string{prctx.HeadSHA()},
+		ConditionPhrase: "are",
+		ConditionValues: []string{"verified by GitHub"},
+	}
+
+	// Treat a missing verification as an unverified commit
+	if verification == nil {
+		verification = &pull.Verification{Reason: "unknown"}
+	}
+
+	if verification.Verified == bool(pred) {
+		predicateResult.Satisfied = true
+		return &predicateResult, nil
+	}
+
+	if pred {
+		predicateResult.Description = fmt.Sprintf("Head commit %.10s is not verified: %s", prctx.HeadSHA(), verification.Reason)
+	} else {
+		predicateResult.Description = fmt.Sprintf("Head commit %.10s is verified", prctx.HeadSHA())
+	}
+	predicateResult.Satisfied = false
+	return &predicateResult, nil
+}
+
+func (pred HeadCommitVerified) Trigger() common.Trigger {
+	return common.TriggerCommit
+}
+
 func hasValidSignature(ctx context.Context, commit *pull.Commit) (bool, string) {
 	if commit.Signature == nil {
 		return false, fmt.Sprintf("Commit %.10s has no signature", commit.SHA)
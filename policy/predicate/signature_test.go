@@ -22,6 +22,8 @@ import (
 	"github.com/palantir/policy-bot/pull"
 	"github.com/palantir/policy-bot/pull/pulltest"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
 )
 
 func TestHasValidSignatures(t *testing.T) {
@@ -346,6 +348,406 @@ func TestHasValidSignaturesByKeys(t *testing.T) {
 	})
 }
 
+func TestHasValidSignaturesByKeysSSH(t *testing.T) {
+	p := &HasValidSignaturesByKeys{
+		KeyIDs:          []string{"3AA5C34371567BD2"},
+		KeyFingerprints: []string{"SHA256:4ZaT6bS1Zz0aX8Dk7cWv3qJ0lQ8yW2cXo8pV9rN5bXk"},
+	}
+
+	allowedKeys := []string{"3AA5C34371567BD2", "SHA256:4ZaT6bS1Zz0aX8Dk7cWv3qJ0lQ8yW2cXo8pV9rN5bXk"}
+
+	gpgCommit := &pull.Commit{
+		SHA:       "abcdef123456789",
+		Author:    "ttest",
+		Committer: "ttest",
+		Signature: &pull.Signature{
+			Type:    pull.SignatureGpg,
+			IsValid: true,
+			Signer:  "ttest",
+			State:   "VALID",
+			KeyID:   "3AA5C34371567BD2",
+		},
+	}
+
+	sshCommit := func(fingerprint string) *pull.Commit {
+		return &pull.Commit{
+			SHA:       "123456789abcdef",
+			Author:    "mhaypenny",
+			Committer: "mhaypenny",
+			Signature: &pull.Signature{
+				Type:           pull.SignatureSSH,
+				IsValid:        true,
+				Signer:         "mhaypenny",
+				State:          "VALID",
+				KeyFingerprint: fingerprint,
+			},
+		}
+	}
+
+	runSignatureTests(t, p, []SignatureTestCase{
+		{
+			"ValidSignatureByAllowedSSHKey",
+			&pulltest.Context{
+				CommitsValue: []*pull.Commit{
+					sshCommit("SHA256:4ZaT6bS1Zz0aX8Dk7cWv3qJ0lQ8yW2cXo8pV9rN5bXk"),
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"123456789abcdef"},
+				ConditionValues: allowedKeys,
+			},
+		},
+		{
+			"ValidSignatureByOtherSSHKey",
+			&pulltest.Context{
+				CommitsValue: []*pull.Commit{
+					sshCommit("SHA256:Hq2v0n6b1yXzR8pW3kE7cT5aL9mD4fG2sJ0uB6iQ1oN"),
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"SHA256:Hq2v0n6b1yXzR8pW3kE7cT5aL9mD4fG2sJ0uB6iQ1oN"},
+				ConditionValues: allowedKeys,
+			},
+		},
+		{
+			"MixedGPGAndSSHKeys",
+			&pulltest.Context{
+				CommitsValue: []*pull.Commit{
+					gpgCommit,
+					sshCommit("SHA256:4ZaT6bS1Zz0aX8Dk7cWv3qJ0lQ8yW2cXo8pV9rN5bXk"),
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"abcdef123456789", "123456789abcdef"},
+				ConditionValues: allowedKeys,
+			},
+		},
+		{
+			"SmimeSignature",
+			&pulltest.Context{
+				CommitsValue: []*pull.Commit{
+					{
+						SHA:       "fedcba987654321",
+						Author:    "ttest",
+						Committer: "ttest",
+						Signature: &pull.Signature{
+							Type:    pull.SignatureSmime,
+							IsValid: true,
+							Signer:  "ttest",
+							State:   "VALID",
+						},
+					},
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"fedcba987654321"},
+				ConditionValues: allowedKeys,
+			},
+		},
+	})
+}
+
+func TestHasValidSignaturesFrom(t *testing.T) {
+	p := &HasValidSignaturesFrom{
+		common.Actors{
+			Users: []string{"mhaypenny"},
+		},
+	}
+
+	conditions := map[string][]string{
+		"Organizations": nil,
+		"Teams":         nil,
+		"Users":         p.Users,
+	}
+
+	validSignature := &pull.Signature{
+		Type:    pull.SignatureGpg,
+		IsValid: true,
+		Signer:  "mhaypenny",
+		State:   "VALID",
+		KeyID:   "3AA5C34371567BD2",
+	}
+
+	runSignatureTests(t, p, []SignatureTestCase{
+		{
+			"ValidSignatureByMatchingAuthor",
+			&pulltest.Context{
+				CommitsValue: []*pull.Commit{
+					{
+						SHA:       "abcdef123456789",
+						Author:    "mhaypenny",
+						Committer: "mhaypenny",
+						Signature: validSignature,
+					},
+					{
+						SHA:       "123456789abcdef",
+						Author:    "ttest",
+						Committer: "ttest",
+					},
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:     true,
+				Values:        []string{"abcdef123456789"},
+				ConditionsMap: conditions,
+			},
+		},
+		{
+			"UnsignedCommitsByMatchingAuthor",
+			&pulltest.Context{
+				CommitsValue: []*pull.Commit{
+					{
+						SHA:       "abcdef123456789",
+						Author:    "mhaypenny",
+						Committer: "mhaypenny",
+					},
+					{
+						SHA:       "fedcba987654321",
+						Author:    "mhaypenny",
+						Committer: "mhaypenny",
+						Signature: validSignature,
+					},
+					{
+						SHA:       "123456789abcdef",
+						Author:    "mhaypenny",
+						Committer: "mhaypenny",
+						Signature: &pull.Signature{
+							Type:    pull.SignatureGpg,
+							IsValid: false,
+							State:   "UNKNOWN_KEY",
+						},
+					},
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:     false,
+				Values:        []string{"abcdef123456789", "123456789abcdef"},
+				ConditionsMap: conditions,
+			},
+		},
+		{
+			"NoCommitsByMatchingAuthor",
+			&pulltest.Context{
+				CommitsValue: []*pull.Commit{
+					{
+						SHA:       "123456789abcdef",
+						Author:    "ttest",
+						Committer: "ttest",
+					},
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:     true,
+				ConditionsMap: conditions,
+			},
+		},
+	})
+}
+
+func TestHasValidSignaturesFromExcept(t *testing.T) {
+	p := &HasValidSignaturesFrom{
+		common.Actors{
+			Users: []string{"*"},
+			Except: &common.ExcludedActors{
+				Users:         []string{"release-bot[bot]"},
+				Organizations: []string{"testorg"},
+			},
+		},
+	}
+
+	conditions := map[string][]string{
+		"Organizations":        nil,
+		"Teams":                nil,
+		"Users":                {"*"},
+		"Except Organizations": {"testorg"},
+		"Except Teams":         nil,
+		"Except Users":         {"release-bot[bot]"},
+	}
+
+	validSignature := &pull.Signature{
+		Type:    pull.SignatureGpg,
+		IsValid: true,
+		Signer:  "external-user",
+		State:   "VALID",
+		KeyID:   "3AA5C34371567BD2",
+	}
+
+	orgMemberships := map[string][]string{
+		"mhaypenny": {"testorg"},
+	}
+
+	runSignatureTests(t, p, []SignatureTestCase{
+		{
+			"UnsignedCommitsByTrustedAuthors",
+			&pulltest.Context{
+				OrgMemberships: orgMemberships,
+				CommitsValue: []*pull.Commit{
+					{
+						SHA:       "abcdef123456789",
+						Author:    "release-bot[bot]",
+						Committer: "release-bot[bot]",
+					},
+					{
+						SHA:       "fedcba987654321",
+						Author:    "mhaypenny",
+						Committer: "mhaypenny",
+					},
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:     true,
+				ConditionsMap: conditions,
+			},
+		},
+		{
+			"SignedCommitByExternalAuthor",
+			&pulltest.Context{
+				OrgMemberships: orgMemberships,
+				CommitsValue: []*pull.Commit{
+					{
+						SHA:       "abcdef123456789",
+						Author:    "release-bot[bot]",
+						Committer: "release-bot[bot]",
+					},
+					{
+						SHA:       "123456789abcdef",
+						Author:    "external-user",
+						Committer: "external-user",
+						Signature: validSignature,
+					},
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:     true,
+				Values:        []string{"123456789abcdef"},
+				ConditionsMap: conditions,
+			},
+		},
+		{
+			"UnsignedCommitByExternalAuthor",
+			&pulltest.Context{
+				OrgMemberships: orgMemberships,
+				CommitsValue: []*pull.Commit{
+					{
+						SHA:       "abcdef123456789",
+						Author:    "release-bot[bot]",
+						Committer: "release-bot[bot]",
+					},
+					{
+						SHA:       "123456789abcdef",
+						Author:    "external-user",
+						Committer: "external-user",
+					},
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:     false,
+				Values:        []string{"123456789abcdef"},
+				ConditionsMap: conditions,
+			},
+		},
+		{
+			"UnsignedCommitWithoutAuthor",
+			&pulltest.Context{
+				OrgMemberships: orgMemberships,
+				CommitsValue: []*pull.Commit{
+					{
+						SHA:       "abcdef123456789",
+						Author:    "mhaypenny",
+						Committer: "mhaypenny",
+					},
+					{
+						SHA: "123456789abcdef",
+					},
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:     false,
+				Values:        []string{"123456789abcdef"},
+				ConditionsMap: conditions,
+			},
+		},
+		{
+			"SignedCommitWithoutAuthor",
+			&pulltest.Context{
+				OrgMemberships: orgMemberships,
+				CommitsValue: []*pull.Commit{
+					{
+						SHA:       "123456789abcdef",
+						Signature: validSignature,
+					},
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:     true,
+				Values:        []string{"123456789abcdef"},
+				ConditionsMap: conditions,
+			},
+		},
+	})
+}
+
+func TestHeadCommitVerified(t *testing.T) {
+	pTrue := HeadCommitVerified(true)
+	pFalse := HeadCommitVerified(false)
+
+	testCases := []SignatureTestCase{
+		{
+			"Verified",
+			&pulltest.Context{
+				HeadSHAValue: "abcdef123456789",
+				HeadCommitVerificationValue: &pull.Verification{
+					Verified: true,
+					Reason:   "valid",
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"abcdef123456789"},
+				ConditionValues: []string{"verified by GitHub"},
+			},
+		},
+		{
+			"Unsigned",
+			&pulltest.Context{
+				HeadSHAValue: "abcdef123456789",
+				HeadCommitVerificationValue: &pull.Verification{
+					Verified: false,
+					Reason:   "unsigned",
+				},
+			},
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"abcdef123456789"},
+				ConditionValues: []string{"verified by GitHub"},
+			},
+		},
+		{
+			"NoVerification",
+			&pulltest.Context{
+				HeadSHAValue: "abcdef123456789",
+			},
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"abcdef123456789"},
+				ConditionValues: []string{"verified by GitHub"},
+			},
+		},
+	}
+
+	runSignatureTests(t, pTrue, testCases)
+
+	// Invert the expected outcomes and test against the false predicate
+	for idx := range testCases {
+		testCases[idx].ExpectedPredicateResult.Satisfied = !testCases[idx].ExpectedPredicateResult.Satisfied
+	}
+	runSignatureTests(t, pFalse, testCases)
+}
+
 type SignatureTestCase struct {
 	Name                    string
 	Context                 pull.Context
@@ -364,3 +766,108 @@ func runSignatureTests(t *testing.T, p Predicate, cases []SignatureTestCase) {
 		})
 	}
 }
+
+func TestHasValidSignaturesByKeysFile(t *testing.T) {
+	p := &HasValidSignaturesByKeys{
+		KeyIDs:  []string{"3AA5C34371567BD2"},
+		KeyFile: ".github/signing-keys",
+	}
+
+	keyFile := `# trusted release keys
+4AEE18F83AFDEB23 # ttest
+5890A69D0D8CF1BB8D2E4E9A8C6F19E34A4FC0B2
+`
+
+	newContext := func(keyID string) *pulltest.Context {
+		return &pulltest.Context{
+			AuthorValue: "mhaypenny",
+			BaseFilesValue: map[string]string{
+				".github/signing-keys": keyFile,
+			},
+			CommitsValue: []*pull.Commit{
+				{
+					SHA:       "abcdef123456789",
+					Author:    "ttest",
+					Committer: "ttest",
+					Signature: &pull.Signature{
+						Type:    pull.SignatureGpg,
+						IsValid: true,
+						Signer:  "ttest",
+						State:   "VALID",
+						KeyID:   keyID,
+					},
+				},
+			},
+		}
+	}
+
+	allowedKeys := []string{"3AA5C34371567BD2", "4AEE18F83AFDEB23", "5890A69D0D8CF1BB8D2E4E9A8C6F19E34A4FC0B2"}
+
+	missingFile := newContext("4AEE18F83AFDEB23")
+	missingFile.BaseFilesValue = nil
+
+	runSignatureTests(t, p, []SignatureTestCase{
+		{
+			"KeyInConfig",
+			newContext("3AA5C34371567BD2"),
+			&common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"abcdef123456789"},
+				ConditionValues: allowedKeys,
+			},
+		},
+		{
+			"KeyIDInFile",
+			newContext("4AEE18F83AFDEB23"),
+			&common.PredicateResult{
+				Satisfied:       true,
+				Values:          []string{"abcdef123456789"},
+				ConditionValues: allowedKeys,
+			},
+		},
+		{
+			"GPGFingerprintInFile",
+			newContext("8C6F19E34A4FC0B2"),
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"8C6F19E34A4FC0B2"},
+				ConditionValues: allowedKeys,
+			},
+		},
+		{
+			"KeyNotAllowed",
+			newContext("7F3A9C2E1B4D6058"),
+			&common.PredicateResult{
+				Satisfied:       false,
+				Values:          []string{"7F3A9C2E1B4D6058"},
+				ConditionValues: allowedKeys,
+			},
+		},
+		{
+			"MissingFile",
+			missingFile,
+			&common.PredicateResult{
+				Satisfied:       false,
+				ConditionValues: []string{".github/signing-keys"},
+			},
+		},
+	})
+}
+
+func TestHasValidSignaturesByKeysUnmarshal(t *testing.T) {
+	var pred HasValidSignaturesByKeys
+	require.NoError(t, yaml.UnmarshalStrict([]byte(`
+key_ids: ["3AA5C34371567BD2"]
+key_fingerprints: ["SHA256:4ZaT6bS1Zz0aX8Dk7cWv3qJ0lQ8yW2cXo8pV9rN5bXk"]
+key_file: .github/signing-keys
+`), &pred))
+	assert.Equal(t, []string{"3AA5C34371567BD2"}, pred.KeyIDs)
+	assert.Equal(t, []string{"SHA256:4ZaT6bS1Zz0aX8Dk7cWv3qJ0lQ8yW2cXo8pV9rN5bXk"}, pred.KeyFingerprints)
+	assert.Equal(t, ".github/signing-keys", pred.KeyFile)
+
+	gpgFingerprint := `key_fingerprints: ["5890A69D0D8CF1BB8D2E4E9A8C6F19E34A4FC0B2"]`
+	assert.Error(t, yaml.UnmarshalStrict([]byte(gpgFingerprint), &HasValidSignaturesByKeys{}))
+
+	sshKeyID := `key_ids: ["SHA256:4ZaT6bS1Zz0aX8Dk7cWv3qJ0lQ8yW2cXo8pV9rN5bXk"]`
+	assert.Error(t, yaml.UnmarshalStrict([]byte(sshKeyID), &HasValidSignaturesByKeys{}))
+}
@@ -17,6 +17,7 @@ package predicate
 import (
 	"context"
 	"fmt"
+	"maps"
 	"slices"
 	"strings"
 
@@ -92,6 +93,89 @@ func (pred HasStatus) Trigger() common.Trigger {
 	return common.TriggerStatus
 }
 
+// HasCheckResult checks that the specified check runs or statuses exist and
+// have one of the allowed conclusions. Checks are identified by exact name or
+// by patterns. Each pattern must match at least one check and all matching
+// checks must have an allowed conclusion.
+type HasCheckResult struct {
+	Conclusions AllowedConclusions `yaml:"conclusions"`
+	Checks      []string           `yaml:"checks"`
+	Patterns    []common.Regexp    `yaml:"patterns"`
+}
+
+var _ Predicate = &HasCheckResult{}
+
+func (pred *HasCheckResult) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	statuses, err := prctx.LatestStatuses()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list commit statuses")
+	}
+
+	conclusions := pred.Conclusions
+	if len(conclusions) == 0 {
+		conclusions = AllowedConclusions{"success"}
+	}
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "checks",
+		ConditionPhrase: fmt.Sprintf("exist and have conclusion %s", conclusions.joinWithOr()),
+	}
+
+	var matched, missing, failing []string
+	check := func(name string) {
+		matched = append(matched, name)
+		if !slices.Contains(conclusions, statuses[name]) {
+			failing = append(failing, name)
+		}
+	}
+
+	for _, name := range pred.Checks {
+		if _, ok := statuses[name]; !ok {
+			missing = append(missing, name)
+			continue
+		}
+		check(name)
+	}
+
+	names := slices.Sorted(maps.Keys(statuses))
+	for _, pattern := range pred.Patterns {
+		found := false
+		for _, name := range names {
+			if pattern.Matches(name) {
+				found = true
+				if !slices.Contains(matched, name) {
+					check(name)
+				}
+			}
+		}
+		if !found {
+			missing = append(missing, pattern.String())
+		}
+	}
+
+	if len(missing) > 0 {
+		predicateResult.Values = missing
+		predicateResult.Description = "One or more checks is missing: " + strings.Join(missing, ", ")
+		predicateResult.Satisfied = false
+		return &predicateResult, nil
+	}
+
+	if len(failing) > 0 {
+		predicateResult.Values = failing
+		predicateResult.Description = fmt.Sprintf("One or more checks has not concluded with %s: %s", conclusions.joinWithOr(), strings.Join(failing, ", "))
+		predicateResult.Satisfied = false
+		return &predicateResult, nil
+	}
+
+	predicateResult.Values = matched
+	predicateResult.Satisfied = true
+	return &predicateResult, nil
+}
+
+func (pred *HasCheckResult) Trigger() common.Trigger {
+	return common.TriggerStatus
+}
+
 // HasSuccessfulStatus checks that the specified statuses have a successful
 // conclusion.
 //
@@ -111,6 +195,60 @@ func (pred HasSuccessfulStatus) Trigger() common.Trigger {
 	return common.TriggerStatus
 }
 
+// HasCompletedStatuses checks that every status and check run reported for
+// the head commit has reached a terminal state, regardless of the conclusion.
+type HasCompletedStatuses bool
+
+var _ Predicate = HasCompletedStatuses(false)
+
+// pendingStates are the status and check run states that are not terminal.
+// Check runs without a conclusion report their status instead.
+var pendingStates = []string{"", "pending", "expected", "queued", "in_progress", "requested", "waiting"}
+
+func (pred HasCompletedStatuses) Evaluate(ctx context.Context, prctx pull.Context) (*common.PredicateResult, error) {
+	statuses, err := prctx.LatestStatuses()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list commit statuses")
+	}
+
+	predicateResult := common.PredicateResult{
+		ValuePhrase:     "status checks",
+		ConditionPhrase: "have all completed",
+	}
+
+	var pending []string
+	for name, state := range statuses {
+		if slices.Contains(pendingStates, state) {
+			pending = append(pending, name)
+		}
+	}
+	slices.Sort(pending)
+
+	if len(pending) > 0 {
+		predicateResult.Values = pending
+		if pred {
+			predicateResult.Description = "One or more statuses has not completed: " + strings.Join(pending, ", ")
+			predicateResult.Satisfied = false
+			return &predicateResult, nil
+		}
+		predicateResult.Satisfied = true
+		return &predicateResult, nil
+	}
+
+	predicateResult.Values = slices.Sorted(maps.Keys(statuses))
+	if pred {
+		predicateResult.Satisfied = true
+		return &predicateResult, nil
+	}
+	predicateResult.Description = "All statuses have completed"
+	predicateResult.Satisfied = false
+	return &predicateResult, nil
+}
+
+func (pred HasCompletedStatuses) Trigger() common.Trigger {
+	return common.TriggerStatus
+}
+
 // joinWithOr returns a string that represents the allowed conclusions in a
 // format that can be used in a sentence. For example, if the allowed
 // conclusions are "success" and "failure", this will return "success or
@@ -17,6 +17,7 @@ package predicate
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"slices"
 	"strings"
 	"testing"
@@ -172,6 +173,206 @@ func TestHasSuccessfulStatus(t *testing.T) {
 	}
 }
 
+func TestHasCompletedStatuses(t *testing.T) {
+	ctx := context.Background()
+
+	testCases := []struct {
+		name      string
+		predicate HasCompletedStatuses
+		statuses  map[string]string
+		expected  *common.PredicateResult
+	}{
+		{
+			"all statuses completed",
+			true,
+			map[string]string{
+				"build": "success",
+				"lint":  "failure",
+				"scan":  "skipped",
+			},
+			&common.PredicateResult{
+				Satisfied: true,
+				Values:    []string{"build", "lint", "scan"},
+			},
+		},
+		{
+			"no statuses",
+			true,
+			map[string]string{},
+			&common.PredicateResult{
+				Satisfied: true,
+			},
+		},
+		{
+			"some statuses pending",
+			true,
+			map[string]string{
+				"build":  "success",
+				"deploy": "pending",
+				"lint":   "in_progress",
+				"scan":   "queued",
+			},
+			&common.PredicateResult{
+				Satisfied: false,
+				Values:    []string{"deploy", "lint", "scan"},
+			},
+		},
+		{
+			"negated with pending statuses",
+			false,
+			map[string]string{
+				"build":  "success",
+				"deploy": "pending",
+			},
+			&common.PredicateResult{
+				Satisfied: true,
+				Values:    []string{"deploy"},
+			},
+		},
+		{
+			"negated with all statuses completed",
+			false,
+			map[string]string{
+				"build": "success",
+			},
+			&common.PredicateResult{
+				Satisfied: false,
+				Values:    []string{"build"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prctx := &pulltest.Context{
+				LatestStatusesValue: tc.statuses,
+			}
+
+			result, err := tc.predicate.Evaluate(ctx, prctx)
+			if assert.NoError(t, err, "evaluation failed") {
+				assertPredicateResult(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestHasCheckResult(t *testing.T) {
+	ctx := context.Background()
+
+	statuses := map[string]string{
+		"build (ubuntu)":  "success",
+		"build (windows)": "success",
+		"build (macos)":   "skipped",
+		"lint":            "success",
+		"scan":            "neutral",
+		"deploy":          "failure",
+	}
+
+	testCases := []struct {
+		name      string
+		predicate *HasCheckResult
+		expected  *common.PredicateResult
+	}{
+		{
+			"named checks succeed",
+			&HasCheckResult{
+				Checks: []string{"lint", "build (ubuntu)"},
+			},
+			&common.PredicateResult{
+				Satisfied: true,
+				Values:    []string{"lint", "build (ubuntu)"},
+			},
+		},
+		{
+			"named check fails",
+			&HasCheckResult{
+				Checks: []string{"lint", "deploy"},
+			},
+			&common.PredicateResult{
+				Satisfied: false,
+				Values:    []string{"deploy"},
+			},
+		},
+		{
+			"named check missing",
+			&HasCheckResult{
+				Checks: []string{"lint", "test", "deploy"},
+			},
+			&common.PredicateResult{
+				Satisfied: false,
+				Values:    []string{"test"},
+			},
+		},
+		{
+			"multiple conclusions",
+			&HasCheckResult{
+				Conclusions: AllowedConclusions{"success", "neutral"},
+				Checks:      []string{"lint", "scan"},
+			},
+			&common.PredicateResult{
+				Satisfied: true,
+				Values:    []string{"lint", "scan"},
+			},
+		},
+		{
+			"pattern matches checks with allowed conclusions",
+			&HasCheckResult{
+				Conclusions: AllowedConclusions{"success", "skipped"},
+				Patterns:    []common.Regexp{common.NewCompiledRegexp(regexp.MustCompile(`^build \(.*\)$`))},
+			},
+			&common.PredicateResult{
+				Satisfied: true,
+				Values:    []string{"build (macos)", "build (ubuntu)", "build (windows)"},
+			},
+		},
+		{
+			"pattern matches a check with a disallowed conclusion",
+			&HasCheckResult{
+				Patterns: []common.Regexp{common.NewCompiledRegexp(regexp.MustCompile(`^build \(.*\)$`))},
+			},
+			&common.PredicateResult{
+				Satisfied: false,
+				Values:    []string{"build (macos)"},
+			},
+		},
+		{
+			"pattern matches no checks",
+			&HasCheckResult{
+				Checks:   []string{"lint"},
+				Patterns: []common.Regexp{common.NewCompiledRegexp(regexp.MustCompile(`^test `))},
+			},
+			&common.PredicateResult{
+				Satisfied: false,
+				Values:    []string{"^test "},
+			},
+		},
+		{
+			"names and patterns overlap",
+			&HasCheckResult{
+				Checks:   []string{"build (ubuntu)"},
+				Patterns: []common.Regexp{common.NewCompiledRegexp(regexp.MustCompile(`^build \((ubuntu|windows)\)$`))},
+			},
+			&common.PredicateResult{
+				Satisfied: true,
+				Values:    []string{"build (ubuntu)", "build (windows)"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prctx := &pulltest.Context{
+				LatestStatusesValue: statuses,
+			}
+
+			result, err := tc.predicate.Evaluate(ctx, prctx)
+			if assert.NoError(t, err, "evaluation failed") {
+				assertPredicateResult(t, tc.expected, result)
+			}
+		})
+	}
+}
+
 type StatusTestSuite struct {
 	nameSuffix        string
 	predicate         Predicate
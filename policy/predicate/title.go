@@ -24,6 +24,10 @@ import (
 type Title struct {
 	Matches    []common.Regexp `yaml:"matches"`
 	NotMatches []common.Regexp `yaml:"not_matches"`
+
+	// Ignore is a list of patterns for titles that never satisfy the
+	// predicate, even if they also match Matches or do not match NotMatches.
+	Ignore []common.Regexp `yaml:"ignore"`
 }
 
 var _ Predicate = Title{}
@@ -47,6 +51,18 @@ func (pred Title) Evaluate(ctx context.Context, prctx pull.Context) (*common.Pre
 		notMatchPatterns = append(notMatchPatterns, reg.String())
 	}
 
+	if anyMatches(pred.Ignore, title) {
+		var ignorePatterns []string
+		for _, reg := range pred.Ignore {
+			ignorePatterns = append(ignorePatterns, reg.String())
+		}
+
+		predicateResult.ConditionsMap = map[string][]string{"ignore": ignorePatterns}
+		predicateResult.Description = "PR Title matches an Ignore pattern"
+		predicateResult.Satisfied = false
+		return &predicateResult, nil
+	}
+
 	if len(pred.Matches) > 0 {
 		if anyMatches(pred.Matches, title) {
 			predicateResult.ConditionsMap = map[string][]string{"match": matchPatterns}
@@ -225,6 +225,46 @@ func TestWithMixedRules(t *testing.T) {
 	})
 }
 
+func TestWithIgnoreRule(t *testing.T) {
+	p := &Title{
+		Matches: []common.Regexp{
+			common.NewCompiledRegexp(regexp.MustCompile("^(feat|fix|chore): ")),
+		},
+		Ignore: []common.Regexp{
+			common.NewCompiledRegexp(regexp.MustCompile("(?i)wip")),
+		},
+	}
+
+	runTitleTestCase(t, p, []TitleTestCase{
+		{
+			"matches pattern",
+			&pulltest.Context{
+				TitleValue: "feat: add title ignore patterns",
+			},
+			&common.PredicateResult{
+				Satisfied: true,
+				Values:    []string{"feat: add title ignore patterns"},
+				ConditionsMap: map[string][]string{
+					"match": {"^(feat|fix|chore): "},
+				},
+			},
+		},
+		{
+			"matches pattern and ignore pattern",
+			&pulltest.Context{
+				TitleValue: "feat: WIP add title ignore patterns",
+			},
+			&common.PredicateResult{
+				Satisfied: false,
+				Values:    []string{"feat: WIP add title ignore patterns"},
+				ConditionsMap: map[string][]string{
+					"ignore": {"(?i)wip"},
+				},
+			},
+		},
+	})
+}
+
 type TitleTestCase struct {
 	name                    string
 	context                 pull.Context
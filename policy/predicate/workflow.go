@@ -25,6 +25,10 @@ import (
 	"github.com/pkg/errors"
 )
 
+// HasWorkflowResult is satisfied if the latest runs of all of the workflows
+// concluded with one of the allowed conclusions. Workflows are identified by
+// the path of the workflow file or by the name of the workflow. See
+// pull.Context.LatestWorkflowRuns for how names and paths are matched.
 type HasWorkflowResult struct {
 	Conclusions AllowedConclusions `yaml:"conclusions,omitempty"`
 	Workflows   []string           `yaml:"workflows,omitempty"`
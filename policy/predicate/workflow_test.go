@@ -73,6 +73,63 @@ func TestHasSuccessfulWorkflowRun(t *testing.T) {
 				Values:    []string{".github/workflows/test.yml"},
 			},
 		},
+		{
+			name: "a workflow succeeds by name",
+			latestWorkflowRunsValue: map[string][]string{
+				".github/workflows/test.yml": {"success"},
+				"Test":                       {"success"},
+			},
+			predicate: HasWorkflowResult{
+				Workflows: []string{"Test"},
+			},
+			ExpectedPredicateResult: &common.PredicateResult{
+				Satisfied: true,
+				Values:    []string{"Test"},
+			},
+		},
+		{
+			name: "a workflow fails by name",
+			latestWorkflowRunsValue: map[string][]string{
+				".github/workflows/test.yml": {"failure"},
+				"Test":                       {"failure"},
+			},
+			predicate: HasWorkflowResult{
+				Workflows: []string{"Test"},
+			},
+			ExpectedPredicateResult: &common.PredicateResult{
+				Satisfied: false,
+				Values:    []string{"Test"},
+			},
+		},
+		{
+			name: "workflows by name and path",
+			latestWorkflowRunsValue: map[string][]string{
+				".github/workflows/test.yml":  {"success"},
+				".github/workflows/test2.yml": {"failure"},
+				"Test":                        {"success"},
+				"Test 2":                      {"failure"},
+			},
+			predicate: HasWorkflowResult{
+				Workflows: []string{"Test", ".github/workflows/test2.yml"},
+			},
+			ExpectedPredicateResult: &common.PredicateResult{
+				Satisfied: false,
+				Values:    []string{".github/workflows/test2.yml"},
+			},
+		},
+		{
+			name: "a workflow is missing by name",
+			latestWorkflowRunsValue: map[string][]string{
+				".github/workflows/test.yml": {"success"},
+			},
+			predicate: HasWorkflowResult{
+				Workflows: []string{"Test"},
+			},
+			ExpectedPredicateResult: &common.PredicateResult{
+				Satisfied: false,
+				Values:    []string{"Test"},
+			},
+		},
 		{
 			name: "a workflow fails and succeeds",
 			latestWorkflowRunsValue: map[string][]string{
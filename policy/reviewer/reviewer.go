@@ -18,8 +18,11 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"slices"
 	"sort"
+	"strings"
 
+	"github.com/palantir/policy-bot/policy/codeowners"
 	"github.com/palantir/policy-bot/policy/common"
 	"github.com/palantir/policy-bot/pull"
 	"github.com/pkg/errors"
@@ -75,6 +78,22 @@ func (s Selection) IsEmpty() bool {
 	return len(s.Users) == 0 && len(s.Teams) == 0
 }
 
+func (s *Selection) addUsers(users ...string) {
+	for _, u := range users {
+		if !slices.Contains(s.Users, u) {
+			s.Users = append(s.Users, u)
+		}
+	}
+}
+
+func (s *Selection) addTeams(teams ...string) {
+	for _, t := range teams {
+		if !slices.Contains(s.Teams, t) {
+			s.Teams = append(s.Teams, t)
+		}
+	}
+}
+
 // FindRequests returns all pending leaf results with review requests enabled.
 func FindRequests(result *common.Result) []*common.Result {
 	if result.Status != common.StatusPending {
@@ -166,26 +185,245 @@ func getPossibleReviewers(prctx pull.Context, users map[string]struct{}, collabo
 func SelectReviewers(ctx context.Context, prctx pull.Context, results []*common.Result, r *rand.Rand) (Selection, error) {
 	selection := Selection{}
 
-	for _, child := range results {
-		logger := zerolog.Ctx(ctx).With().Str(LogKeyLeafNode, child.Name).Logger()
+	for _, result := range results {
+		logger := zerolog.Ctx(ctx).With().Str(LogKeyLeafNode, result.Name).Logger()
 		childCtx := logger.WithContext(ctx)
 
-		switch child.ReviewRequestRule.Mode {
-		case common.RequestModeTeams:
-			if err := selectTeamReviewers(childCtx, prctx, &selection, child); err != nil {
-				return selection, err
+		requests, err := expandPathRequests(childCtx, prctx, result)
+		if err != nil {
+			return selection, err
+		}
+
+		for _, child := range requests {
+			switch child.ReviewRequestRule.Mode {
+			case common.RequestModeTeams:
+				if err := selectTeamReviewers(childCtx, prctx, &selection, child); err != nil {
+					return selection, err
+				}
+			case common.RequestModeCodeOwners:
+				if err := selectCodeOwnerReviewers(childCtx, prctx, &selection); err != nil {
+					return selection, err
+				}
+			case common.RequestModeAllUsers, common.RequestModeRandomUsers, common.RequestModeLeastLoadedUsers:
+				if err := selectUserReviewers(childCtx, prctx, &selection, child, r); err != nil {
+					return selection, err
+				}
+			default:
+				return selection, fmt.Errorf("unknown reviewer selection mode: %s", child.ReviewRequestRule.Mode)
 			}
-		case common.RequestModeAllUsers, common.RequestModeRandomUsers:
-			if err := selectUserReviewers(childCtx, prctx, &selection, child, r); err != nil {
-				return selection, err
+		}
+	}
+	return selection, nil
+}
+
+// SelectMergedReviewers is like SelectReviewers, but selects users for all of
+// the results together instead of for each result independently. When several
+// results request a number of users, it prefers users who can review for more
+// than one of the results, so that the selection contains as few users as
+// possible. Users and teams appear at most once in the selection.
+func SelectMergedReviewers(ctx context.Context, prctx pull.Context, results []*common.Result, r *rand.Rand) (Selection, error) {
+	selection := Selection{}
+
+	var requests []userRequest
+	for _, result := range results {
+		logger := zerolog.Ctx(ctx).With().Str(LogKeyLeafNode, result.Name).Logger()
+		childCtx := logger.WithContext(ctx)
+
+		expanded, err := expandPathRequests(childCtx, prctx, result)
+		if err != nil {
+			return selection, err
+		}
+
+		for _, child := range expanded {
+			switch child.ReviewRequestRule.Mode {
+			case common.RequestModeTeams:
+				if err := selectTeamReviewers(childCtx, prctx, &selection, child); err != nil {
+					return selection, err
+				}
+			case common.RequestModeCodeOwners:
+				if err := selectCodeOwnerReviewers(childCtx, prctx, &selection); err != nil {
+					return selection, err
+				}
+			case common.RequestModeAllUsers:
+				if err := selectUserReviewers(childCtx, prctx, &selection, child, r); err != nil {
+					return selection, err
+				}
+			case common.RequestModeRandomUsers, common.RequestModeLeastLoadedUsers:
+				req, err := newUserRequest(childCtx, prctx, child, r)
+				if err != nil {
+					return selection, err
+				}
+				requests = append(requests, req)
+			default:
+				return selection, fmt.Errorf("unknown reviewer selection mode: %s", child.ReviewRequestRule.Mode)
 			}
-		default:
-			return selection, fmt.Errorf("unknown reviewer selection mode: %s", child.ReviewRequestRule.Mode)
 		}
 	}
+
+	mergeUserRequests(&selection, requests)
 	return selection, nil
 }
 
+// userRequest is a request for a number of users from a list of candidates
+// ordered from most to least preferred. Users who are already requested to
+// review are not candidates and count toward the requested number.
+type userRequest struct {
+	requested  []string
+	candidates []string
+	count      int
+}
+
+func newUserRequest(ctx context.Context, prctx pull.Context, result *common.Result, r *rand.Rand) (userRequest, error) {
+	possibleReviewers, err := findPossibleReviewers(ctx, prctx, result)
+	if err != nil {
+		return userRequest{}, err
+	}
+
+	requested, candidates, err := splitRequestedUsers(prctx, possibleReviewers)
+	if err != nil {
+		return userRequest{}, err
+	}
+
+	switch result.ReviewRequestRule.Mode {
+	case common.RequestModeRandomUsers:
+		r.Shuffle(len(candidates), func(i, j int) {
+			candidates[i], candidates[j] = candidates[j], candidates[i]
+		})
+	case common.RequestModeLeastLoadedUsers:
+		if candidates, err = sortLeastLoadedUsers(candidates, prctx); err != nil {
+			return userRequest{}, err
+		}
+	}
+
+	count := max(result.ReviewRequestRule.RequestedCount-len(requested), 0)
+	return userRequest{
+		requested:  requested,
+		candidates: candidates,
+		count:      min(count, len(candidates)),
+	}, nil
+}
+
+// mergeUserRequests adds users to the selection until every request has the
+// requested number of selected candidates. Users already requested to review
+// are kept in the selection first. At each step, it adds the user who is a
+// candidate for the most requests that still need users. Ties are broken by
+// the best position of the user in the candidate lists, then by name.
+func mergeUserRequests(selection *Selection, requests []userRequest) {
+	for _, req := range requests {
+		selection.addUsers(req.requested...)
+	}
+
+	chosen := make(map[string]bool)
+	for _, u := range selection.Users {
+		chosen[u] = true
+	}
+
+	for {
+		score := make(map[string]int)
+		rank := make(map[string]int)
+		for _, req := range requests {
+			have := 0
+			for _, c := range req.candidates {
+				if chosen[c] {
+					have++
+				}
+			}
+			if have >= req.count {
+				continue
+			}
+
+			for i, c := range req.candidates {
+				if chosen[c] {
+					continue
+				}
+				score[c]++
+				if r, ok := rank[c]; !ok || i < r {
+					rank[c] = i
+				}
+			}
+		}
+		if len(score) == 0 {
+			return
+		}
+
+		var best string
+		for u := range score {
+			switch {
+			case best == "":
+				best = u
+			case score[u] != score[best]:
+				if score[u] > score[best] {
+					best = u
+				}
+			case rank[u] != rank[best]:
+				if rank[u] < rank[best] {
+					best = u
+				}
+			case u < best:
+				best = u
+			}
+		}
+
+		chosen[best] = true
+		selection.addUsers(best)
+	}
+}
+
+// expandPathRequests returns the results to use for reviewer selection. If the
+// review request rule of the result has path reviewers, it returns a copy of
+// the result for each group of path reviewers that matches a changed file.
+// Otherwise, or if no groups match, it returns the original result.
+func expandPathRequests(ctx context.Context, prctx pull.Context, result *common.Result) ([]*common.Result, error) {
+	logger := zerolog.Ctx(ctx)
+
+	rule := result.ReviewRequestRule
+	if len(rule.PathReviewers) == 0 {
+		return []*common.Result{result}, nil
+	}
+
+	files, err := prctx.ChangedFiles()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list changed files")
+	}
+
+	var requests []*common.Result
+	for _, pr := range rule.PathReviewers {
+		if !anyFileMatches(files, pr.Paths) {
+			continue
+		}
+
+		expandedRule := *rule
+		expandedRule.Users = pr.Users
+		expandedRule.Teams = pr.Teams
+		expandedRule.Organizations = pr.Organizations
+		expandedRule.Permissions = pr.Permissions
+		expandedRule.PathReviewers = nil
+
+		expanded := *result
+		expanded.ReviewRequestRule = &expandedRule
+		requests = append(requests, &expanded)
+	}
+
+	if len(requests) == 0 {
+		logger.Debug().Msg("No path reviewers match the changed files; using default reviewers")
+		return []*common.Result{result}, nil
+	}
+
+	logger.Debug().Msgf("Found %d groups of path reviewers that match the changed files", len(requests))
+	return requests, nil
+}
+
+func anyFileMatches(files []*pull.File, paths []common.Regexp) bool {
+	for _, f := range files {
+		for _, p := range paths {
+			if p.Matches(f.Filename) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func selectTeamReviewers(ctx context.Context, prctx pull.Context, selection *Selection, result *common.Result) error {
 	logger := zerolog.Ctx(ctx)
 
@@ -205,13 +443,169 @@ func selectTeamReviewers(ctx context.Context, prctx pull.Context, selection *Sel
 	}
 
 	logger.Debug().Msgf("Requesting %d teams for review", len(teams))
-	selection.Teams = append(selection.Teams, teams...)
+	selection.addTeams(teams...)
+	return nil
+}
+
+// selectCodeOwnerReviewers selects the users and teams that own the files
+// changed by the pull request according to the CODEOWNERS file. Teams must be
+// repository collaborators and users must not be the author of the pull
+// request. Owners listed by email address are ignored.
+func selectCodeOwnerReviewers(ctx context.Context, prctx pull.Context, selection *Selection) error {
+	logger := zerolog.Ctx(ctx)
+
+	owners, err := codeowners.Load(prctx)
+	if err != nil {
+		return err
+	}
+	if owners == nil {
+		logger.Debug().Msg("No CODEOWNERS file found; skipping review request")
+		return nil
+	}
+
+	files, err := prctx.ChangedFiles()
+	if err != nil {
+		return errors.Wrap(err, "failed to list changed files")
+	}
+
+	eligibleTeams, err := prctx.Teams()
+	if err != nil {
+		return err
+	}
+
+	collaborators, err := prctx.RepositoryCollaborators()
+	if err != nil {
+		return errors.Wrap(err, "failed to list repository collaborators")
+	}
+
+	users := make(map[string]struct{})
+	var teams []string
+	for _, f := range files {
+		for _, owner := range owners.Owners(f.Filename) {
+			switch {
+			case codeowners.IsTeam(owner):
+				org, team, _ := strings.Cut(owner, "/")
+				if _, ok := eligibleTeams[team]; ok && strings.EqualFold(org, prctx.RepositoryOwner()) {
+					teams = append(teams, team)
+				}
+			case !strings.Contains(owner, "@"):
+				users[owner] = struct{}{}
+			}
+		}
+	}
+	sort.Strings(teams)
+
+	possibleReviewers := getPossibleReviewers(prctx, users, collaborators)
+
+	logger.Debug().Msgf("Found %d code owner users and %d code owner teams; selecting all", len(possibleReviewers), len(teams))
+	selection.addUsers(possibleReviewers...)
+	selection.addTeams(teams...)
 	return nil
 }
 
 func selectUserReviewers(ctx context.Context, prctx pull.Context, selection *Selection, result *common.Result, r *rand.Rand) error {
 	logger := zerolog.Ctx(ctx)
 
+	possibleReviewers, err := findPossibleReviewers(ctx, prctx, result)
+	if err != nil {
+		return err
+	}
+	if len(possibleReviewers) == 0 {
+		logger.Debug().Msg("Found 0 eligible reviewers; skipping review request")
+		return nil
+	}
+
+	switch result.ReviewRequestRule.Mode {
+	case common.RequestModeAllUsers:
+		logger.Debug().Msgf("Found %d eligible reviewers; selecting all", len(possibleReviewers))
+		selection.addUsers(possibleReviewers...)
+
+	case common.RequestModeRandomUsers:
+		requested, available, err := splitRequestedUsers(prctx, possibleReviewers)
+		if err != nil {
+			return err
+		}
+
+		count := max(result.ReviewRequestRule.RequestedCount-len(requested), 0)
+		candidates := excludeSelectedUsers(available, *selection, count)
+		selectedUsers := selectRandomUsers(count, candidates, r)
+
+		logger.Debug().Msgf("Found %d eligible reviewers (%d already requested, %d not already selected); randomly selecting %d", len(possibleReviewers), len(requested), len(candidates), count)
+		selection.addUsers(requested...)
+		selection.addUsers(selectedUsers...)
+
+	case common.RequestModeLeastLoadedUsers:
+		requested, available, err := splitRequestedUsers(prctx, possibleReviewers)
+		if err != nil {
+			return err
+		}
+
+		count := max(result.ReviewRequestRule.RequestedCount-len(requested), 0)
+		candidates := excludeSelectedUsers(available, *selection, count)
+		selectedUsers, err := selectLeastLoadedUsers(count, candidates, prctx)
+		if err != nil {
+			return err
+		}
+
+		logger.Debug().Msgf("Found %d eligible reviewers (%d already requested, %d not already selected); selecting %d with the fewest pending review requests", len(possibleReviewers), len(requested), len(candidates), count)
+		selection.addUsers(requested...)
+		selection.addUsers(selectedUsers...)
+	}
+	return nil
+}
+
+// splitRequestedUsers splits users into those who are currently requested to
+// review the pull request and those who may be newly requested. Users whose
+// review requests were removed are in neither list, so they are never
+// requested again.
+func splitRequestedUsers(prctx pull.Context, users []string) (requested []string, available []string, err error) {
+	reviewers, err := prctx.RequestedReviewers()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to list requested reviewers")
+	}
+
+	existing := make(map[string]*pull.Reviewer)
+	for _, r := range reviewers {
+		if r.Type == pull.ReviewerUser {
+			existing[r.Name] = r
+		}
+	}
+
+	for _, u := range users {
+		r, ok := existing[u]
+		switch {
+		case !ok:
+			available = append(available, u)
+		case !r.Removed:
+			requested = append(requested, u)
+		}
+	}
+	return requested, available, nil
+}
+
+// excludeSelectedUsers removes users that are already in the selection from
+// the list of candidates, so that each rule requests different users when
+// possible. If fewer than count candidates remain, it returns all of the
+// candidates, allowing a rule to reuse users selected for other rules.
+func excludeSelectedUsers(candidates []string, selection Selection, count int) []string {
+	var unselected []string
+	for _, u := range candidates {
+		if !slices.Contains(selection.Users, u) {
+			unselected = append(unselected, u)
+		}
+	}
+	if len(unselected) < count {
+		return candidates
+	}
+	return unselected
+}
+
+// findPossibleReviewers returns the users who may be requested to review for
+// a result, sorted by name. The author of the pull request and users who are
+// not collaborators on the repository are never included.
+func findPossibleReviewers(ctx context.Context, prctx pull.Context, result *common.Result) ([]string, error) {
+	logger := zerolog.Ctx(ctx)
+
 	allUsers := make(map[string]struct{})
 	for _, user := range result.ReviewRequestRule.Users {
 		allUsers[user] = struct{}{}
@@ -243,7 +637,18 @@ func selectUserReviewers(ctx context.Context, prctx pull.Context, selection *Sel
 
 	collaborators, err := prctx.RepositoryCollaborators()
 	if err != nil {
-		return errors.Wrap(err, "failed to list repository collaborators")
+		return nil, errors.Wrap(err, "failed to list repository collaborators")
+	}
+
+	for _, user := range result.ReviewRequestRule.Users {
+		if !common.IsUserPattern(user) {
+			continue
+		}
+		for _, c := range collaborators {
+			if common.MatchesUser(user, c.Name) {
+				allUsers[c.Name] = struct{}{}
+			}
+		}
 	}
 
 	if len(result.ReviewRequestRule.Permissions) > 0 {
@@ -258,24 +663,56 @@ func selectUserReviewers(ctx context.Context, prctx pull.Context, selection *Sel
 	}
 
 	possibleReviewers := getPossibleReviewers(prctx, allUsers, collaborators)
-	if len(possibleReviewers) == 0 {
-		logger.Debug().Msg("Found 0 eligible reviewers; skipping review request")
-		return nil
+	if result.ReviewRequestRule.Except == nil {
+		return possibleReviewers, nil
 	}
 
-	switch result.ReviewRequestRule.Mode {
-	case common.RequestModeAllUsers:
-		logger.Debug().Msgf("Found %d eligible reviewers; selecting all", len(possibleReviewers))
-		selection.Users = append(selection.Users, possibleReviewers...)
+	var included []string
+	for _, user := range possibleReviewers {
+		excluded, err := result.ReviewRequestRule.Except.IsExcluded(prctx, user)
+		if err != nil {
+			return nil, err
+		}
+		if !excluded {
+			included = append(included, user)
+		}
+	}
+	return included, nil
+}
 
-	case common.RequestModeRandomUsers:
-		count := result.ReviewRequestRule.RequestedCount
-		selectedUsers := selectRandomUsers(count, possibleReviewers, r)
+// selectLeastLoadedUsers selects the n users with the fewest pending review
+// requests in the repository. Users with the same number of requests are
+// selected in the order they are listed, which must be sorted for the
+// selection to be consistent.
+func selectLeastLoadedUsers(n int, users []string, prctx pull.Context) ([]string, error) {
+	if n == 0 {
+		return nil, nil
+	}
+	if n >= len(users) {
+		return users, nil
+	}
+
+	sorted, err := sortLeastLoadedUsers(users, prctx)
+	if err != nil {
+		return nil, err
+	}
+	return sorted[:n], nil
+}
 
-		logger.Debug().Msgf("Found %d eligible reviewers; randomly selecting %d", len(possibleReviewers), count)
-		selection.Users = append(selection.Users, selectedUsers...)
+// sortLeastLoadedUsers returns a copy of users ordered by the number of
+// pending review requests, from fewest to most. The sort is stable.
+func sortLeastLoadedUsers(users []string, prctx pull.Context) ([]string, error) {
+	pending, err := prctx.PendingReviewRequests(users)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get pending review requests")
 	}
-	return nil
+
+	sorted := make([]string, len(users))
+	copy(sorted, users)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return pending[sorted[i]] < pending[sorted[j]]
+	})
+	return sorted, nil
 }
 
 func requestsTeam(r *common.Result, team string) bool {
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"math/rand"
+	"regexp"
 	"testing"
 
 	"github.com/palantir/policy-bot/policy/common"
@@ -185,6 +186,369 @@ func TestSelectRandomUsers(t *testing.T) {
 	assert.Equal(t, []string{"c", "e", "b", "f"}, multiplePseudoRandom)
 }
 
+func TestSelectLeastLoadedUsers(t *testing.T) {
+	prctx := &pulltest.Context{
+		PendingReviewRequestsValue: map[string]int{
+			"a": 3,
+			"b": 1,
+			"c": 0,
+			"d": 1,
+		},
+	}
+	users := []string{"a", "b", "c", "d", "e"}
+
+	selected, err := selectLeastLoadedUsers(0, users, prctx)
+	require.NoError(t, err)
+	assert.Empty(t, selected, "0 selection should return nothing")
+
+	selected, err = selectLeastLoadedUsers(6, users, prctx)
+	require.NoError(t, err)
+	assert.Equal(t, users, selected, "selecting more users than exist should return all users")
+
+	selected, err = selectLeastLoadedUsers(2, users, prctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c", "e"}, selected)
+
+	selected, err = selectLeastLoadedUsers(3, users, prctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c", "e", "b"}, selected, "ties should be broken by order")
+
+	prctx.PendingReviewRequestsError = errors.New("search failed")
+	_, err = selectLeastLoadedUsers(1, users, prctx)
+	assert.Error(t, err)
+}
+
+func TestSelectReviewers_LeastLoadedUsers(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	results := []*common.Result{
+		{
+			Name:   "users",
+			Status: common.StatusPending,
+			ReviewRequestRule: &common.ReviewRequestRule{
+				Users:          []string{"mhaypenny", "review-approver", "contributor-committer", "contributor-author"},
+				RequiredCount:  2,
+				RequestedCount: 2,
+				Mode:           common.RequestModeLeastLoadedUsers,
+			},
+		},
+	}
+
+	prctx := makeContext().(*pulltest.Context)
+	prctx.PendingReviewRequestsValue = map[string]int{
+		"mhaypenny":             0,
+		"review-approver":       2,
+		"contributor-committer": 5,
+		"contributor-author":    2,
+	}
+
+	selection, err := SelectReviewers(context.Background(), prctx, results, r)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"contributor-author", "review-approver"}, selection.Users, "the author cannot be requested")
+}
+
+func TestSelectReviewers_SharedCandidates(t *testing.T) {
+	newResult := func(name string, users []string, count int) *common.Result {
+		return &common.Result{
+			Name:   name,
+			Status: common.StatusPending,
+			ReviewRequestRule: &common.ReviewRequestRule{
+				Users:          users,
+				RequiredCount:  count,
+				RequestedCount: count,
+				Mode:           common.RequestModeLeastLoadedUsers,
+			},
+		}
+	}
+
+	prctx := makeContext().(*pulltest.Context)
+	prctx.PendingReviewRequestsValue = map[string]int{
+		"review-approver":       0,
+		"contributor-author":    1,
+		"contributor-committer": 2,
+	}
+
+	t.Run("preferUnselected", func(t *testing.T) {
+		results := []*common.Result{
+			newResult("first", []string{"review-approver", "contributor-author", "contributor-committer"}, 1),
+			newResult("second", []string{"review-approver", "contributor-author", "contributor-committer"}, 1),
+		}
+
+		selection, err := SelectReviewers(context.Background(), prctx, results, rand.New(rand.NewSource(42)))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"review-approver", "contributor-author"}, selection.Users)
+	})
+
+	t.Run("reuseWhenRequired", func(t *testing.T) {
+		results := []*common.Result{
+			newResult("first", []string{"review-approver", "contributor-author", "contributor-committer"}, 1),
+			newResult("second", []string{"review-approver", "contributor-author"}, 2),
+		}
+
+		selection, err := SelectReviewers(context.Background(), prctx, results, rand.New(rand.NewSource(42)))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"review-approver", "contributor-author"}, selection.Users)
+	})
+
+	t.Run("notEnoughCandidates", func(t *testing.T) {
+		results := []*common.Result{
+			newResult("first", []string{"review-approver", "contributor-author"}, 2),
+			newResult("second", []string{"review-approver", "contributor-author", "contributor-committer"}, 2),
+		}
+
+		selection, err := SelectReviewers(context.Background(), prctx, results, rand.New(rand.NewSource(42)))
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"review-approver", "contributor-author"}, selection.Users, "a single unselected candidate is not enough")
+	})
+}
+
+func TestSelectReviewers_ExistingRequests(t *testing.T) {
+	users := []string{"review-approver", "contributor-author", "contributor-committer"}
+	results := []*common.Result{
+		{
+			Name:   "users",
+			Status: common.StatusPending,
+			ReviewRequestRule: &common.ReviewRequestRule{
+				Users:          users,
+				RequiredCount:  2,
+				RequestedCount: 2,
+				Mode:           common.RequestModeLeastLoadedUsers,
+			},
+		},
+	}
+
+	prctx := makeContext().(*pulltest.Context)
+	prctx.PendingReviewRequestsValue = map[string]int{
+		"review-approver":       0,
+		"contributor-author":    1,
+		"contributor-committer": 2,
+	}
+
+	t.Run("partialCoverage", func(t *testing.T) {
+		prctx.RequestedReviewersValue = []*pull.Reviewer{
+			{Type: pull.ReviewerUser, Name: "contributor-committer"},
+		}
+
+		selection, err := SelectReviewers(context.Background(), prctx, results, rand.New(rand.NewSource(42)))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"contributor-committer", "review-approver"}, selection.Users)
+		assert.Equal(t, []string{"review-approver"}, selection.Difference(prctx.RequestedReviewersValue).Users, "only one new user should be requested")
+	})
+
+	t.Run("fullCoverage", func(t *testing.T) {
+		prctx.RequestedReviewersValue = []*pull.Reviewer{
+			{Type: pull.ReviewerUser, Name: "contributor-committer"},
+			{Type: pull.ReviewerUser, Name: "contributor-author"},
+		}
+
+		selection, err := SelectReviewers(context.Background(), prctx, results, rand.New(rand.NewSource(42)))
+		require.NoError(t, err)
+		assert.Empty(t, selection.Difference(prctx.RequestedReviewersValue).Users, "no new users should be requested")
+	})
+
+	t.Run("removedRequest", func(t *testing.T) {
+		prctx.RequestedReviewersValue = []*pull.Reviewer{
+			{Type: pull.ReviewerUser, Name: "review-approver", Removed: true},
+			{Type: pull.ReviewerUser, Name: "contributor-committer"},
+		}
+
+		selection, err := SelectReviewers(context.Background(), prctx, results, rand.New(rand.NewSource(42)))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"contributor-committer", "contributor-author"}, selection.Users, "removed users should not be requested again")
+	})
+
+	t.Run("randomUsers", func(t *testing.T) {
+		prctx.RequestedReviewersValue = []*pull.Reviewer{
+			{Type: pull.ReviewerUser, Name: "contributor-author"},
+		}
+
+		randomResults := []*common.Result{
+			{
+				Name:   "users",
+				Status: common.StatusPending,
+				ReviewRequestRule: &common.ReviewRequestRule{
+					Users:          users,
+					RequiredCount:  2,
+					RequestedCount: 2,
+					Mode:           common.RequestModeRandomUsers,
+				},
+			},
+		}
+
+		selection, err := SelectReviewers(context.Background(), prctx, randomResults, rand.New(rand.NewSource(42)))
+		require.NoError(t, err)
+		require.Len(t, selection.Users, 2)
+		assert.Equal(t, "contributor-author", selection.Users[0])
+		assert.Len(t, selection.Difference(prctx.RequestedReviewersValue).Users, 1, "only one new user should be requested")
+	})
+}
+
+func TestExcludeSelectedUsers(t *testing.T) {
+	selection := Selection{Users: []string{"a", "b"}}
+	candidates := []string{"a", "b", "c", "d"}
+
+	assert.Equal(t, []string{"c", "d"}, excludeSelectedUsers(candidates, selection, 2))
+	assert.Equal(t, candidates, excludeSelectedUsers(candidates, selection, 3), "too few unselected candidates for the requested count")
+	assert.Equal(t, []string{"c", "d"}, excludeSelectedUsers(candidates, selection, 0))
+}
+
+func TestMergeUserRequests(t *testing.T) {
+	tests := map[string]struct {
+		Selected []string
+		Requests []userRequest
+		Expected []string
+	}{
+		"noRequests": {
+			Selected: []string{"a", "b"},
+			Expected: []string{"a", "b"},
+		},
+		"sharedCandidate": {
+			Requests: []userRequest{
+				{candidates: []string{"a", "b", "c"}, count: 1},
+				{candidates: []string{"c", "d"}, count: 1},
+			},
+			Expected: []string{"c"},
+		},
+		"alreadySelected": {
+			Selected: []string{"b"},
+			Requests: []userRequest{
+				{candidates: []string{"a", "b", "c"}, count: 1},
+				{candidates: []string{"c", "d"}, count: 1},
+			},
+			Expected: []string{"b", "c"},
+		},
+		"preferredOrder": {
+			Requests: []userRequest{
+				{candidates: []string{"c", "b", "a"}, count: 2},
+			},
+			Expected: []string{"c", "b"},
+		},
+		"multipleUsers": {
+			Requests: []userRequest{
+				{candidates: []string{"a", "b", "c"}, count: 2},
+				{candidates: []string{"d", "b"}, count: 1},
+				{candidates: []string{"e", "c"}, count: 1},
+			},
+			Expected: []string{"b", "c"},
+		},
+		"disjoint": {
+			Requests: []userRequest{
+				{candidates: []string{"a", "b"}, count: 1},
+				{candidates: []string{"d", "c"}, count: 1},
+			},
+			Expected: []string{"a", "d"},
+		},
+		"zeroCount": {
+			Requests: []userRequest{
+				{candidates: []string{"a", "b"}, count: 0},
+			},
+		},
+		"alreadyRequested": {
+			Requests: []userRequest{
+				{requested: []string{"b"}, candidates: []string{"a", "c"}, count: 1},
+				{candidates: []string{"b", "d"}, count: 1},
+			},
+			Expected: []string{"b", "a"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			selection := Selection{Users: test.Selected}
+			mergeUserRequests(&selection, test.Requests)
+			assert.Equal(t, test.Expected, selection.Users)
+		})
+	}
+}
+
+func TestSelectMergedReviewers(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	results := []*common.Result{
+		{
+			Name:   "first",
+			Status: common.StatusPending,
+			ReviewRequestRule: &common.ReviewRequestRule{
+				Users:          []string{"contributor-author", "review-approver", "contributor-committer"},
+				RequiredCount:  1,
+				RequestedCount: 1,
+				Mode:           common.RequestModeRandomUsers,
+			},
+		},
+		{
+			Name:   "second",
+			Status: common.StatusPending,
+			ReviewRequestRule: &common.ReviewRequestRule{
+				Users:          []string{"review-approver", "comment-approver"},
+				RequiredCount:  1,
+				RequestedCount: 1,
+				Mode:           common.RequestModeLeastLoadedUsers,
+			},
+		},
+		{
+			Name:   "third",
+			Status: common.StatusPending,
+			ReviewRequestRule: &common.ReviewRequestRule{
+				Users:          []string{"contributor-author"},
+				RequiredCount:  1,
+				RequestedCount: 1,
+				Mode:           common.RequestModeAllUsers,
+			},
+		},
+	}
+
+	prctx := makeContext().(*pulltest.Context)
+	prctx.PendingReviewRequestsValue = map[string]int{}
+
+	selection, err := SelectMergedReviewers(context.Background(), prctx, results, r)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"contributor-author", "review-approver"}, selection.Users)
+	assert.Empty(t, selection.Teams)
+}
+
+func TestSelectMergedReviewers_ExistingRequests(t *testing.T) {
+	results := []*common.Result{
+		{
+			Name:   "users",
+			Status: common.StatusPending,
+			ReviewRequestRule: &common.ReviewRequestRule{
+				Users:          []string{"review-approver", "contributor-author", "contributor-committer"},
+				RequiredCount:  2,
+				RequestedCount: 2,
+				Mode:           common.RequestModeLeastLoadedUsers,
+			},
+		},
+	}
+
+	prctx := makeContext().(*pulltest.Context)
+	prctx.PendingReviewRequestsValue = map[string]int{
+		"review-approver":       0,
+		"contributor-author":    1,
+		"contributor-committer": 2,
+	}
+
+	t.Run("partialCoverage", func(t *testing.T) {
+		prctx.RequestedReviewersValue = []*pull.Reviewer{
+			{Type: pull.ReviewerUser, Name: "contributor-committer"},
+		}
+
+		selection, err := SelectMergedReviewers(context.Background(), prctx, results, rand.New(rand.NewSource(42)))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"contributor-committer", "review-approver"}, selection.Users)
+		assert.Equal(t, []string{"review-approver"}, selection.Difference(prctx.RequestedReviewersValue).Users, "only one new user should be requested")
+	})
+
+	t.Run("removedRequest", func(t *testing.T) {
+		prctx.RequestedReviewersValue = []*pull.Reviewer{
+			{Type: pull.ReviewerUser, Name: "review-approver", Removed: true},
+			{Type: pull.ReviewerUser, Name: "contributor-committer"},
+		}
+
+		selection, err := SelectMergedReviewers(context.Background(), prctx, results, rand.New(rand.NewSource(42)))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"contributor-committer", "contributor-author"}, selection.Users, "removed users should not be requested again")
+		assert.Equal(t, []string{"contributor-author"}, selection.Difference(prctx.RequestedReviewersValue).Users)
+	})
+}
+
 func TestSelectReviewers(t *testing.T) {
 	r := rand.New(rand.NewSource(42))
 	results := []*common.Result{
@@ -346,6 +710,94 @@ func TestSelectReviewers_Team(t *testing.T) {
 	require.Len(t, selection.Users, 0, "policy should request 0 users")
 }
 
+func TestSelectReviewers_UserPattern(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	results := []*common.Result{
+		{
+			Name:   "UserPattern",
+			Status: common.StatusPending,
+			ReviewRequestRule: &common.ReviewRequestRule{
+				Users:         []string{"contributor-*", "review-approver"},
+				RequiredCount: 1,
+				Mode:          common.RequestModeAllUsers,
+			},
+		},
+	}
+
+	prctx := makeContext()
+	selection, err := SelectReviewers(context.Background(), prctx, results, r)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"contributor-author", "contributor-committer", "review-approver"}, selection.Users)
+}
+
+func TestSelectReviewers_CodeOwners(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	results := []*common.Result{
+		{
+			Name:   "CodeOwners",
+			Status: common.StatusPending,
+			ReviewRequestRule: &common.ReviewRequestRule{
+				Users:         []string{"review-approver"},
+				RequiredCount: 1,
+				Mode:          common.RequestModeCodeOwners,
+			},
+		},
+	}
+
+	prctx := makeContext().(*pulltest.Context)
+	prctx.BaseFilesValue = map[string]string{
+		".github/CODEOWNERS": `
+*             @everyone/team-admin
+*.go          @everyone/team-write contributor-author mhaypenny
+/docs/        review-approver docs@example.com
+/docs/*.md    @everyone/team-not-collaborators @other-org/team-write
+/config/      not-a-collaborator
+`,
+	}
+
+	t.Run("lastMatchWins", func(t *testing.T) {
+		prctx.ChangedFilesValue = []*pull.File{
+			{Filename: "server/main.go", Status: pull.FileModified},
+			{Filename: "docs/install.txt", Status: pull.FileAdded},
+		}
+
+		selection, err := SelectReviewers(context.Background(), prctx, results, r)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"contributor-author", "review-approver"}, selection.Users, "the author and email owners should not be requested")
+		assert.Equal(t, []string{"team-write"}, selection.Teams)
+	})
+
+	t.Run("ineligibleOwners", func(t *testing.T) {
+		prctx.ChangedFilesValue = []*pull.File{
+			{Filename: "docs/README.md", Status: pull.FileModified},
+			{Filename: "config/app.yml", Status: pull.FileModified},
+		}
+
+		selection, err := SelectReviewers(context.Background(), prctx, results, r)
+		require.NoError(t, err)
+		assert.True(t, selection.IsEmpty(), "non-collaborators and teams in other organizations should not be requested")
+	})
+
+	t.Run("defaultOwners", func(t *testing.T) {
+		prctx.ChangedFilesValue = []*pull.File{
+			{Filename: "README.md", Status: pull.FileModified},
+		}
+
+		selection, err := SelectReviewers(context.Background(), prctx, results, r)
+		require.NoError(t, err)
+		assert.Empty(t, selection.Users)
+		assert.Equal(t, []string{"team-admin"}, selection.Teams)
+	})
+
+	t.Run("noCodeOwners", func(t *testing.T) {
+		prctx.BaseFilesValue = nil
+
+		selection, err := SelectReviewers(context.Background(), prctx, results, r)
+		require.NoError(t, err)
+		assert.True(t, selection.IsEmpty())
+	})
+}
+
 func TestSelectReviewers_TeamNotCollaborator(t *testing.T) {
 	r := rand.New(rand.NewSource(42))
 	results := []*common.Result{
@@ -390,6 +842,92 @@ func TestSelectReviewers_Org(t *testing.T) {
 	require.Contains(t, selection.Users, "review-approver", "review-approver must be selected")
 }
 
+func TestSelectReviewers_PathReviewers(t *testing.T) {
+	pathReviewers := []*common.PathReviewers{
+		{
+			Paths: []common.Regexp{common.NewCompiledRegexp(regexp.MustCompile("^frontend/.*"))},
+			Teams: []string{"everyone/team-write"},
+		},
+		{
+			Paths: []common.Regexp{common.NewCompiledRegexp(regexp.MustCompile("^backend/.*"))},
+			Users: []string{"review-approver"},
+		},
+		{
+			Paths: []common.Regexp{common.NewCompiledRegexp(regexp.MustCompile("^docs/.*"))},
+			Users: []string{"contributor-author"},
+		},
+	}
+
+	newResults := func(mode common.RequestMode) []*common.Result {
+		return []*common.Result{
+			{
+				Name:   "paths",
+				Status: common.StatusPending,
+				ReviewRequestRule: &common.ReviewRequestRule{
+					Users:          []string{"contributor-committer"},
+					RequiredCount:  1,
+					RequestedCount: 1,
+					Mode:           mode,
+					PathReviewers:  pathReviewers,
+				},
+			},
+		}
+	}
+
+	t.Run("multipleAreas", func(t *testing.T) {
+		prctx := makeContext().(*pulltest.Context)
+		prctx.ChangedFilesValue = []*pull.File{
+			{Filename: "frontend/app.js", Status: pull.FileModified},
+			{Filename: "backend/server.go", Status: pull.FileModified},
+		}
+
+		r := rand.New(rand.NewSource(42))
+		selection, err := SelectReviewers(context.Background(), prctx, newResults(common.RequestModeRandomUsers), r)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"user-team-write", "review-approver"}, selection.Users)
+		assert.Empty(t, selection.Teams, "no teams should be returned")
+	})
+
+	t.Run("singleArea", func(t *testing.T) {
+		prctx := makeContext().(*pulltest.Context)
+		prctx.ChangedFilesValue = []*pull.File{
+			{Filename: "backend/server.go", Status: pull.FileModified},
+			{Filename: "backend/handler.go", Status: pull.FileAdded},
+		}
+
+		r := rand.New(rand.NewSource(42))
+		selection, err := SelectReviewers(context.Background(), prctx, newResults(common.RequestModeRandomUsers), r)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"review-approver"}, selection.Users)
+	})
+
+	t.Run("teamsMode", func(t *testing.T) {
+		prctx := makeContext().(*pulltest.Context)
+		prctx.ChangedFilesValue = []*pull.File{
+			{Filename: "frontend/app.js", Status: pull.FileModified},
+			{Filename: "backend/server.go", Status: pull.FileModified},
+		}
+
+		r := rand.New(rand.NewSource(42))
+		selection, err := SelectReviewers(context.Background(), prctx, newResults(common.RequestModeTeams), r)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"team-write"}, selection.Teams)
+		assert.Empty(t, selection.Users, "no users should be returned")
+	})
+
+	t.Run("noMatchingArea", func(t *testing.T) {
+		prctx := makeContext().(*pulltest.Context)
+		prctx.ChangedFilesValue = []*pull.File{
+			{Filename: "README.md", Status: pull.FileModified},
+		}
+
+		r := rand.New(rand.NewSource(42))
+		selection, err := SelectReviewers(context.Background(), prctx, newResults(common.RequestModeRandomUsers), r)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"contributor-committer"}, selection.Users)
+	})
+}
+
 func makeContext() pull.Context {
 	return &pulltest.Context{
 		OwnerValue: "everyone",
@@ -48,6 +48,35 @@ func NewOptionsFromRequest(r *http.Request) (Options, error) {
 	return o, nil
 }
 
+// BatchOptions contains the pull requests to simulate in a batch and the options applied to each of them.
+type BatchOptions struct {
+	PullRequests []int   `json:"pull_requests"`
+	Options      Options `json:"options"`
+}
+
+func NewBatchOptionsFromRequest(r *http.Request) (BatchOptions, error) {
+	var o BatchOptions
+	if r.Body == nil {
+		return o, errors.New("request body is required")
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&o); err != nil {
+		return o, errors.Wrap(err, "failed to unmarshal body into batch options")
+	}
+
+	if len(o.PullRequests) == 0 {
+		return o, errors.New("at least one pull request is required")
+	}
+	for _, number := range o.PullRequests {
+		if number <= 0 {
+			return o, errors.Errorf("invalid pull request number: %d", number)
+		}
+	}
+
+	o.Options.setDefaults()
+	return o, nil
+}
+
 // setDefaults sets any values for the options that were not intentionally set in the request body but which should have
 // consistent values for the length of the simulation, such as the created time for a comment or review.
 func (o *Options) setDefaults() {
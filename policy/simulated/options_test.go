@@ -67,6 +67,44 @@ func TestOptionsFromRequest(t *testing.T) {
 	assert.Equal(t, "test-base", opt.BaseBranch)
 }
 
+func TestBatchOptionsFromRequest(t *testing.T) {
+	newRequest := func(body string) *http.Request {
+		return httptest.NewRequest(http.MethodPost, "http:", bytes.NewBuffer([]byte(body)))
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		body := `
+		{
+			"pull_requests":[1, 2, 3],
+			"options":{
+				"add_reviews":[
+					{"author":"iignore", "body":":+1:", "state":"approved"}
+				],
+				"base_branch":"test-base"
+			}
+		}`
+
+		opt, err := NewBatchOptionsFromRequest(newRequest(body))
+		require.NoError(t, err)
+
+		assert.Equal(t, []int{1, 2, 3}, opt.PullRequests)
+		assert.Equal(t, "test-base", opt.Options.BaseBranch)
+		assert.Equal(t, "iignore", opt.Options.AddReviews[0].Author)
+		assert.NotNil(t, opt.Options.AddReviews[0].CreatedAt)
+		assert.Equal(t, "simulated-reviewID-0", opt.Options.AddReviews[0].ID)
+	})
+
+	t.Run("noPullRequests", func(t *testing.T) {
+		_, err := NewBatchOptionsFromRequest(newRequest(`{"options":{"base_branch":"test-base"}}`))
+		assert.EqualError(t, err, "at least one pull request is required")
+	})
+
+	t.Run("invalidNumber", func(t *testing.T) {
+		_, err := NewBatchOptionsFromRequest(newRequest(`{"pull_requests":[1, 0]}`))
+		assert.EqualError(t, err, "invalid pull request number: 0")
+	})
+}
+
 func TestOptionDefaults(t *testing.T) {
 	options := Options{
 		AddComments: []Comment{
@@ -15,7 +15,10 @@
 package pull
 
 import (
+	"fmt"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 // MembershipContext defines methods to get information
@@ -33,14 +36,18 @@ type MembershipContext interface {
 
 	// OrganizationMembers returns the list of org member usernames in the given organization.
 	OrganizationMembers(org string) ([]string, error)
+
+	// UserTeams returns the teams in the given organization that the user is
+	// a member of. Teams are returned as "org-name/team-name".
+	UserTeams(org, user string) ([]string, error)
 }
 
 // Context is the context for a pull request. It defines methods to get
 // information about the pull request and the VCS system containing the pull
 // request (e.g. GitHub).
 //
-// A new Context should be created for each request, so implementations are not
-// required to be thread-safe.
+// A new Context should be created for each request. Implementations must be
+// safe for concurrent use because rules may be evaluated in parallel.
 type Context interface {
 	MembershipContext
 
@@ -67,6 +74,11 @@ type Context interface {
 	// Author returns the username of the user who opened the pull request.
 	Author() string
 
+	// AuthorCreatedAt returns the time when the GitHub account of the author
+	// was created. It returns the zero time if the author is a bot or if the
+	// account no longer exists.
+	AuthorCreatedAt() (time.Time, error)
+
 	// CreatedAt returns the time when the pull request was created.
 	CreatedAt() time.Time
 
@@ -85,9 +97,19 @@ type Context interface {
 	// The base branch will always be unprefixed.
 	Branches() (base string, head string)
 
+	// HeadRepository returns the owner and name of the repository that
+	// contains the head branch. For pull requests from forks, this is the
+	// fork. Otherwise, it is the repository that the pull request targets.
+	// Both values are empty if the head repository was deleted.
+	HeadRepository() (owner string, name string)
+
 	// ChangedFiles returns the files that were changed in this pull request.
 	ChangedFiles() ([]*File, error)
 
+	// CommitFiles returns the files that were changed by the commit with the
+	// given SHA, compared to its first parent.
+	CommitFiles(sha string) ([]*File, error)
+
 	// Commits returns the commits that are part of this pull request. The
 	// commit order is implementation dependent.
 	Commits() ([]*Commit, error)
@@ -100,19 +122,47 @@ type Context interface {
 	// implementation dependent.
 	Comments() ([]*Comment, error)
 
-	// Reviews lists all reviews on a Pull Request. The review order is
-	// implementation dependent.
+	// Reviews lists all reviews on a Pull Request, including dismissed
+	// reviews. The review order is implementation dependent.
 	Reviews() ([]*Review, error)
 
+	// ReviewThreads lists all review threads on a Pull Request. The thread
+	// order is implementation dependent.
+	ReviewThreads() ([]*ReviewThread, error)
+
+	// ReopenedAt returns the time at which the Pull Request was most recently
+	// reopened. It returns the zero time if the Pull Request was never
+	// reopened.
+	ReopenedAt() (time.Time, error)
+
+	// ForcePushedAt returns the time at which the head branch of the Pull
+	// Request was most recently force-pushed, rewriting its history. It
+	// returns the zero time if the head branch was never force-pushed.
+	ForcePushedAt() (time.Time, error)
+
+	// ReviewRequests returns the most recent review request for each user or
+	// team that was ever requested to review the Pull Request, including
+	// requests that were later removed. The request order is implementation
+	// dependent.
+	ReviewRequests() ([]*ReviewRequest, error)
+
 	// IsDraft returns the draft status of the Pull Request.
 	IsDraft() bool
 
+	// AutoMerge returns the auto-merge request of the Pull Request, or nil if
+	// auto-merge is not enabled.
+	AutoMerge() *AutoMerge
+
 	// RepositoryCollaborators returns the repository collaborators.
 	RepositoryCollaborators() ([]*Collaborator, error)
 
 	// CollaboratorPermission returns the permission level of user on the repository.
 	CollaboratorPermission(user string) (Permission, error)
 
+	// CollaboratorPermissionOn returns the permission level of user on a
+	// different repository, identified by owner and name.
+	CollaboratorPermissionOn(owner, repo, user string) (Permission, error)
+
 	// Teams lists the set of team collaborators, along with their respective
 	// permission on a repo.
 	Teams() (map[string]Permission, error)
@@ -121,18 +171,108 @@ type Context interface {
 	// the pull request.
 	RequestedReviewers() ([]*Reviewer, error)
 
+	// PendingReviewRequests returns the number of open pull requests in the
+	// repository that are waiting for a review from each user, including the
+	// current pull request. The result contains an entry for every user.
+	PendingReviewRequests(users []string) (map[string]int, error)
+
 	// LatestStatuses returns a map of status check names to the latest result
 	LatestStatuses() (map[string]string, error)
 
 	// LatestWorkflowRuns returns the latest GitHub Actions workflow runs for
-	// the pull request. The keys of the map are paths to the workflow files and
-	// the values are the conclusions of the latest runs, one per event type.
+	// the pull request. The keys of the map are paths to the workflow files
+	// and the names of the workflows and the values are the conclusions of the
+	// latest runs, one per event type. A name includes the runs of all
+	// workflows with that name. If a name is the same as the path of another
+	// workflow, the key refers to the workflow with that path.
 	LatestWorkflowRuns() (map[string][]string, error)
 
+	// LatestDeployments returns a map from environment names to the state of
+	// the latest deployment of the head commit to each environment. The state
+	// is the state of the most recent deployment status, or "pending" if the
+	// deployment has no statuses.
+	LatestDeployments() (map[string]string, error)
+
 	// Labels returns a list of labels applied on the Pull Request
 	Labels() ([]string, error)
+
+	// LabelAppliers returns a map from label names to the user who most
+	// recently applied each label to the Pull Request. Label names are
+	// lowercase. The map includes labels that were applied and later removed.
+	LabelAppliers() (map[string]string, error)
+
+	// HeadCommitVerification returns GitHub's verification of the signature
+	// on the head commit of the Pull Request.
+	HeadCommitVerification() (*Verification, error)
+
+	// BaseFileContent returns the content of the file at path on the base
+	// branch of the Pull Request. If the file does not exist, it returns an
+	// empty string and false.
+	BaseFileContent(path string) (string, bool, error)
+
+	// FileContent returns the content of the file at path at the given ref,
+	// which may be a branch name or a commit SHA. If the file does not exist,
+	// it returns an empty string and false. If the file is too large to
+	// return, the error wraps ErrFileTooLarge.
+	FileContent(path, ref string) (string, bool, error)
+
+	// IsProtectedBranch returns true if the branch in the repository of the
+	// pull request has branch protection enabled. Branches that do not exist
+	// are not protected.
+	IsProtectedBranch(branch string) (bool, error)
+
+	// UserRegions returns a map from lowercase usernames to the regions that
+	// the server configuration assigns to the users. Users without a region
+	// are not in the map.
+	UserRegions() map[string]string
+
+	// EventSource returns the type of event that caused the evaluation of the
+	// pull request.
+	EventSource() EventSource
 }
 
+// EventSource identifies the type of event that caused an evaluation.
+type EventSource string
+
+const (
+	// EventSourcePullRequest is used when evaluating the pull request itself
+	// after events like new commits, reviews, or comments.
+	EventSourcePullRequest EventSource = "pull_request"
+
+	// EventSourceMergeGroup is used when evaluating the pull request for a
+	// merge group commit created by a merge queue.
+	EventSourceMergeGroup EventSource = "merge_group"
+)
+
+// ErrFileTooLarge is returned when the content of a file is too large to
+// retrieve.
+var ErrFileTooLarge = errors.New("file is too large")
+
+// TooManyError is returned when a pull request has more files or commits than
+// GitHub returns, so the full list cannot be evaluated. Use errors.As to
+// detect this error and errors.Is to compare with ErrTooManyFiles or
+// ErrTooManyCommits.
+type TooManyError struct {
+	// Resource is the plural name of the things there are too many of, like
+	// "files" or "commits".
+	Resource string
+
+	// Max is the maximum number of items that GitHub returns.
+	Max int
+}
+
+func (e *TooManyError) Error() string {
+	return fmt.Sprintf("too many %s in pull request, maximum is %d", e.Resource, e.Max)
+}
+
+var (
+	// ErrTooManyFiles is returned when a pull request changes too many files.
+	ErrTooManyFiles = &TooManyError{Resource: "files", Max: MaxPullRequestFiles}
+
+	// ErrTooManyCommits is returned when a pull request has too many commits.
+	ErrTooManyCommits = &TooManyError{Resource: "commits", Max: MaxPullRequestCommits}
+)
+
 type FileStatus int
 
 const (
@@ -146,6 +286,22 @@ type File struct {
 	Status    FileStatus
 	Additions int
 	Deletions int
+
+	// Patch is the unified diff of the changes to the file. It is empty if
+	// GitHub does not provide a diff, like for binary or very large files.
+	Patch string
+
+	// PreviousFilename is the name of the file before it was renamed. Renames
+	// are split into a deleted file with the old name and an added file with
+	// the new name; only the added file sets this field.
+	PreviousFilename string
+
+	// IsBinary is true if the file appears to be binary. GitHub does not mark
+	// binary files directly, so this is inferred from a missing diff with no
+	// line changes, so empty files also appear binary. GitHub also omits the
+	// diff for renames without line changes, so renamed files are never
+	// binary, even if a binary file was renamed.
+	IsBinary bool
 }
 
 type Commit struct {
@@ -153,6 +309,9 @@ type Commit struct {
 	Parents         []string
 	CommittedViaWeb bool
 
+	// Message is the full commit message, including the subject and body.
+	Message string
+
 	// Author is the login name of the author. It is empty if the author is not
 	// a real user.
 	Author string
@@ -161,6 +320,18 @@ type Commit struct {
 	// committer is not a real user.
 	Committer string
 
+	// AuthorEmail and CommitterEmail are the email addresses recorded in the
+	// commit. They are set by the client that created the commit and are not
+	// verified by GitHub.
+	AuthorEmail    string
+	CommitterEmail string
+
+	// AuthoredAt and CommittedAt are the author and committer timestamps
+	// recorded in the commit. Both are set by the client that created the
+	// commit and are not verified by GitHub.
+	AuthoredAt  time.Time
+	CommittedAt time.Time
+
 	// Signature is the signature and details that was extracted from the commit.
 	// It is nil if the commit has no signature
 	Signature *Signature
@@ -178,6 +349,31 @@ func (c *Commit) Users() []string {
 	return users
 }
 
+// IsUpdateMerge returns true if the commit is a merge commit created in the UI
+// or with the API that merges the target branch into the pull request branch,
+// like the commits created by the "Update branch" button. The commits are the
+// commits of the pull request.
+func IsUpdateMerge(commits []*Commit, c *Commit) bool {
+	// must be a simple merge commit (exactly 2 parents)
+	if len(c.Parents) != 2 {
+		return false
+	}
+
+	// must be created via the UI or the API (no local merges)
+	if !c.CommittedViaWeb {
+		return false
+	}
+
+	shas := make(map[string]bool)
+	for _, c := range commits {
+		shas[c.SHA] = true
+	}
+
+	// first parent must exist: it is a commit on the head branch
+	// second parent must not exist: it is already in the base branch
+	return shas[c.Parents[0]] && !shas[c.Parents[1]]
+}
+
 type SignatureType string
 
 const (
@@ -187,14 +383,27 @@ const (
 )
 
 type Signature struct {
-	Type           SignatureType
-	IsValid        bool
+	Type    SignatureType
+	IsValid bool
+
+	// KeyID is set for GPG signatures and KeyFingerprint is set for SSH
+	// signatures. GitHub does not report the fingerprints of GPG keys.
 	KeyID          string
 	KeyFingerprint string
 	Signer         string
 	State          string
 }
 
+// Verification is GitHub's assessment of the signature on a commit.
+type Verification struct {
+	// Verified is true if GitHub verified the commit signature.
+	Verified bool
+
+	// Reason explains the verification result, using values like "valid",
+	// "unsigned", or "unknown_key".
+	Reason string
+}
+
 type Comment struct {
 	CreatedAt    time.Time
 	LastEditedAt time.Time
@@ -224,6 +433,16 @@ type Review struct {
 	Teams []string
 }
 
+type ReviewThread struct {
+	Path       string
+	IsResolved bool
+	IsOutdated bool
+
+	// Author and CreatedAt describe the first comment in the thread.
+	Author    string
+	CreatedAt time.Time
+}
+
 type ReviewerType string
 
 const (
@@ -235,6 +454,32 @@ type Reviewer struct {
 	Type    ReviewerType
 	Name    string
 	Removed bool
+
+	// RemovedBy is the user who removed the review request. It is empty if
+	// the request was not removed or if the user is unknown.
+	RemovedBy string
+}
+
+// AutoMerge describes a request to merge a Pull Request automatically once
+// all requirements are met.
+type AutoMerge struct {
+	// EnabledBy is the user who enabled auto-merge. It may be empty if the
+	// user is unknown.
+	EnabledBy string
+
+	// MergeMethod is the lowercase merge method: "merge", "squash", or
+	// "rebase".
+	MergeMethod string
+}
+
+type ReviewRequest struct {
+	Type        ReviewerType
+	Name        string
+	RequestedAt time.Time
+
+	// RemovedAt is the time at which the request was removed. It is the zero
+	// time if the request was not removed after it was most recently made.
+	RemovedAt time.Time
 }
 
 type Collaborator struct {
@@ -16,8 +16,12 @@ package pull
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"reflect"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/go-github/v65/github"
@@ -33,6 +37,10 @@ const (
 	// MaxPullRequestCommits is the max number of commits returned by GitHub
 	// https://developer.github.com/v3/pulls/#list-commits-on-a-pull-request
 	MaxPullRequestCommits = 250
+
+	// pendingReviewRequestsBatchSize is the maximum number of users whose
+	// pending review requests are counted by one GraphQL query
+	pendingReviewRequestsBatchSize = 20
 )
 
 // Locator identifies a pull request and optionally contains a full or partial
@@ -108,6 +116,12 @@ func (loc Locator) toV4(ctx context.Context, client *githubv4.Client) (*v4PullRe
 	v4.BaseRefName = loc.Value.GetBase().GetRef()
 	v4.BaseRepository.DatabaseID = loc.Value.GetBase().GetRepo().GetID()
 	v4.IsDraft = loc.Value.GetDraft()
+	if am := loc.Value.GetAutoMerge(); am != nil {
+		v4.AutoMergeRequest = &v4AutoMergeRequest{
+			EnabledBy:   v4Actor{Login: am.GetEnabledBy().GetLogin()},
+			MergeMethod: am.GetMergeMethod(),
+		}
+	}
 	return &v4, nil
 }
 
@@ -123,25 +137,91 @@ type GitHubContext struct {
 
 	evalTimestamp time.Time
 
+	disablePushBatching     bool
+	directCollaboratorsOnly bool
+	userRegions             map[string]string
+	eventSource             EventSource
+
 	owner  string
 	repo   string
 	number int
 	pr     *v4PullRequest
 
-	// cached fields
-	files         []*File
-	commits       []*Commit
-	comments      []*Comment
-	reviews       []*Review
-	reviewers     []*Reviewer
-	collaborators []*Collaborator
+	// Cached fields are guarded by the mutex declared before them. Accessors
+	// hold the mutex while loading data, so concurrent callers wait for and
+	// share a single request, while different data loads in parallel.
+	filesMu sync.Mutex
+	files   []*File
+
+	commitFilesMu sync.Mutex
+	commitFiles   map[string][]*File
+
+	commitsMu sync.Mutex
+	commits   []*Commit
+
+	pagedMu  sync.Mutex
+	comments []*Comment
+	reviews  []*Review
+
+	reviewThreadsMu sync.Mutex
+	reviewThreads   []*ReviewThread
+
+	reopenedAtMu sync.Mutex
+	reopenedAt   *time.Time
+
+	forcePushedAtMu sync.Mutex
+	forcePushedAt   *time.Time
+
+	authorCreatedAtMu sync.Mutex
+	authorCreatedAt   *time.Time
+
+	reviewersMu sync.Mutex
+	reviewers   []*Reviewer
+
+	reviewRequestsMu sync.Mutex
+	reviewRequests   []*ReviewRequest
+
+	pendingRequestsMu sync.Mutex
+	pendingRequests   map[string]int
+
+	collaboratorsMu sync.Mutex
+	collaborators   []*Collaborator
+
+	permissionsMu sync.Mutex
 	permissions   map[string]Permission
-	teams         map[string]Permission
-	membership    map[string]bool
-	statuses      map[string]string
-	labels        []string
-	pushedAt      map[string]time.Time
-	workflowRuns  map[string][]string
+
+	otherPermissionsMu sync.Mutex
+	otherPermissions   map[string]Permission
+
+	teamsMu sync.Mutex
+	teams   map[string]Permission
+
+	statusesMu sync.Mutex
+	statuses   map[string]string
+
+	labelsMu sync.Mutex
+	labels   []string
+
+	labelAppliersMu sync.Mutex
+	labelAppliers   map[string]string
+
+	pushedAtMu sync.Mutex
+	pushedAt   map[string]time.Time
+
+	workflowRunsMu sync.Mutex
+	workflowRuns   map[string][]string
+
+	deploymentsMu sync.Mutex
+	deployments   map[string]string
+
+	verificationMu sync.Mutex
+	verification   *Verification
+
+	fileContentsMu sync.Mutex
+	fileContents   map[string]*string
+
+	protectedBranchesMu sync.Mutex
+	protectedBranches   map[string]bool
 }
 
 // NewGitHubContext creates a new pull.Context that makes GitHub requests to
@@ -237,6 +317,36 @@ func (ghc *GitHubContext) Author() string {
 	return ghc.pr.Author.GetV3Login()
 }
 
+func (ghc *GitHubContext) AuthorCreatedAt() (time.Time, error) {
+	ghc.authorCreatedAtMu.Lock()
+	defer ghc.authorCreatedAtMu.Unlock()
+
+	if ghc.authorCreatedAt == nil {
+		var createdAt time.Time
+
+		// Bots are not users and deleted accounts have no login
+		if author := ghc.Author(); author != "" && !strings.HasSuffix(author, "[bot]") {
+			var q struct {
+				User *struct {
+					CreatedAt time.Time
+				} `graphql:"user(login: $login)"`
+			}
+			qvars := map[string]interface{}{
+				"login": githubv4.String(author),
+			}
+
+			if err := ghc.v4client.Query(ghc.ctx, &q, qvars); err != nil {
+				return time.Time{}, errors.Wrap(err, "failed to get author account")
+			}
+			if q.User != nil {
+				createdAt = q.User.CreatedAt
+			}
+		}
+		ghc.authorCreatedAt = &createdAt
+	}
+	return *ghc.authorCreatedAt, nil
+}
+
 func (ghc *GitHubContext) CreatedAt() time.Time {
 	return ghc.pr.CreatedAt
 }
@@ -257,6 +367,17 @@ func (ghc *GitHubContext) IsDraft() bool {
 	return ghc.pr.IsDraft
 }
 
+func (ghc *GitHubContext) AutoMerge() *AutoMerge {
+	am := ghc.pr.AutoMergeRequest
+	if am == nil {
+		return nil
+	}
+	return &AutoMerge{
+		EnabledBy:   am.EnabledBy.GetV3Login(),
+		MergeMethod: strings.ToLower(am.MergeMethod),
+	}
+}
+
 // Branches returns the names of the base and head branch. If the head branch
 // is from another repository (it is a fork) then the branch name is
 // `owner:branchName`.
@@ -269,7 +390,17 @@ func (ghc *GitHubContext) Branches() (base string, head string) {
 	return
 }
 
+func (ghc *GitHubContext) HeadRepository() (owner string, name string) {
+	if !ghc.pr.IsCrossRepository {
+		return ghc.owner, ghc.repo
+	}
+	return ghc.pr.HeadRepository.Owner.Login, ghc.pr.HeadRepository.Name
+}
+
 func (ghc *GitHubContext) ChangedFiles() ([]*File, error) {
+	ghc.filesMu.Lock()
+	defer ghc.filesMu.Unlock()
+
 	if ghc.files == nil {
 		opt := github.ListOptions{
 			PerPage: 100,
@@ -288,43 +419,98 @@ func (ghc *GitHubContext) ChangedFiles() ([]*File, error) {
 			opt.Page = res.NextPage
 		}
 
-		ghc.files = make([]*File, 0, len(allFiles))
-		for _, f := range allFiles {
-			status := FileModified
-			switch f.GetStatus() {
-			case "added":
-				status = FileAdded
-			case "deleted":
-				status = FileDeleted
-			case "renamed":
-				// Break renames into components: the new file is added and we
-				// generate an extra entry for the old file that is deleted.
-				// Attribute all modifications to the new file to avoid double
-				// counting.
-				status = FileAdded
-				ghc.files = append(ghc.files, &File{
-					Filename:  f.GetPreviousFilename(),
-					Status:    FileDeleted,
-					Additions: 0,
-					Deletions: 0,
-				})
-			}
-
-			ghc.files = append(ghc.files, &File{
-				Filename:  f.GetFilename(),
-				Status:    status,
-				Additions: f.GetAdditions(),
-				Deletions: f.GetDeletions(),
-			})
-		}
+		ghc.files = convertCommitFiles(allFiles)
 	}
 	if len(ghc.files) >= MaxPullRequestFiles {
-		return nil, errors.Errorf("too many files in pull request, maximum is %d", MaxPullRequestFiles)
+		return nil, ErrTooManyFiles
 	}
 	return ghc.files, nil
 }
 
+func (ghc *GitHubContext) CommitFiles(sha string) ([]*File, error) {
+	ghc.commitFilesMu.Lock()
+	defer ghc.commitFilesMu.Unlock()
+
+	if files, ok := ghc.commitFiles[sha]; ok {
+		return files, nil
+	}
+	if ghc.commitFiles == nil {
+		ghc.commitFiles = make(map[string][]*File)
+	}
+
+	repoID := ghc.pr.BaseRepository.DatabaseID
+	if gc := ghc.globalCache; gc != nil {
+		if files, ok := gc.GetCommitFiles(repoID, sha); ok {
+			ghc.commitFiles[sha] = files
+			return files, nil
+		}
+	}
+
+	opt := github.ListOptions{
+		PerPage: 100,
+	}
+
+	var allFiles []*github.CommitFile
+	for {
+		commit, res, err := ghc.client.Repositories.GetCommit(ghc.ctx, ghc.owner, ghc.repo, sha, &opt)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get files of commit %s", sha)
+		}
+		allFiles = append(allFiles, commit.Files...)
+		if res.NextPage == 0 {
+			break
+		}
+		opt.Page = res.NextPage
+	}
+
+	files := convertCommitFiles(allFiles)
+	ghc.commitFiles[sha] = files
+	if gc := ghc.globalCache; gc != nil {
+		gc.SetCommitFiles(repoID, sha, files)
+	}
+	return files, nil
+}
+
+func convertCommitFiles(commitFiles []*github.CommitFile) []*File {
+	files := make([]*File, 0, len(commitFiles))
+	for _, f := range commitFiles {
+		status := FileModified
+		switch f.GetStatus() {
+		case "added":
+			status = FileAdded
+		case "deleted":
+			status = FileDeleted
+		case "renamed":
+			// Break renames into components: the new file is added and we
+			// generate an extra entry for the old file that is deleted.
+			// Attribute all modifications to the new file to avoid double
+			// counting.
+			status = FileAdded
+			files = append(files, &File{
+				Filename:  f.GetPreviousFilename(),
+				Status:    FileDeleted,
+				Additions: 0,
+				Deletions: 0,
+			})
+		}
+
+		files = append(files, &File{
+			Filename:         f.GetFilename(),
+			Status:           status,
+			Additions:        f.GetAdditions(),
+			Deletions:        f.GetDeletions(),
+			Patch:            f.GetPatch(),
+			PreviousFilename: f.GetPreviousFilename(),
+			IsBinary:         f.Patch == nil && f.GetChanges() == 0 && f.GetStatus() != "renamed",
+		})
+	}
+	return files
+}
+
 func (ghc *GitHubContext) Commits() ([]*Commit, error) {
+	ghc.commitsMu.Lock()
+	defer ghc.commitsMu.Unlock()
+
 	if ghc.commits == nil {
 		commits, err := ghc.loadCommits()
 		if err != nil {
@@ -333,12 +519,15 @@ func (ghc *GitHubContext) Commits() ([]*Commit, error) {
 		ghc.commits = commits
 	}
 	if len(ghc.commits) >= MaxPullRequestCommits {
-		return nil, errors.Errorf("too many commits in pull request, maximum is %d", MaxPullRequestCommits)
+		return nil, ErrTooManyCommits
 	}
 	return ghc.commits, nil
 }
 
 func (ghc *GitHubContext) PushedAt(sha string) (time.Time, error) {
+	ghc.pushedAtMu.Lock()
+	defer ghc.pushedAtMu.Unlock()
+
 	repoID := ghc.pr.BaseRepository.DatabaseID
 	if ghc.pushedAt == nil {
 		ghc.pushedAt = make(map[string]time.Time)
@@ -368,6 +557,11 @@ func (ghc *GitHubContext) PushedAt(sha string) (time.Time, error) {
 			break
 		}
 
+		// Without batching, only consider the requested commit
+		if ghc.disablePushBatching {
+			break
+		}
+
 		c, err := ghc.nextChildCommit(sha)
 		if err != nil {
 			return time.Time{}, err
@@ -394,6 +588,47 @@ func (ghc *GitHubContext) PushedAt(sha string) (time.Time, error) {
 	return pushedAt, nil
 }
 
+// DisablePushBatching configures PushedAt to only consider the push time of
+// the requested commit. By default, PushedAt assumes that a commit without a
+// known push time was pushed in the same batch as its nearest descendant with
+// a known push time. With batching disabled, commits without a known push time
+// use the evaluation timestamp instead.
+func (ghc *GitHubContext) DisablePushBatching() {
+	ghc.disablePushBatching = true
+}
+
+// UseDirectCollaboratorsOnly configures RepositoryCollaborators to only return
+// direct collaborators and members of repository teams. This avoids listing
+// all collaborators, which includes every member of the organization and can
+// be slow in large organizations. Users who only inherit permissions from the
+// organization are not returned.
+func (ghc *GitHubContext) UseDirectCollaboratorsOnly() {
+	ghc.directCollaboratorsOnly = true
+}
+
+// SetUserRegions sets the map returned by UserRegions. Usernames in the map
+// must be lowercase.
+func (ghc *GitHubContext) SetUserRegions(regions map[string]string) {
+	ghc.userRegions = regions
+}
+
+func (ghc *GitHubContext) UserRegions() map[string]string {
+	return ghc.userRegions
+}
+
+// SetEventSource sets the value returned by EventSource. By default, the
+// source is EventSourcePullRequest.
+func (ghc *GitHubContext) SetEventSource(source EventSource) {
+	ghc.eventSource = source
+}
+
+func (ghc *GitHubContext) EventSource() EventSource {
+	if ghc.eventSource == "" {
+		return EventSourcePullRequest
+	}
+	return ghc.eventSource
+}
+
 // tryPushedAt attempts to get the push time for a commit from the local cache,
 // the global cache, or the GitHub API. It returns the zero time if it could
 // not find a push time in any source.
@@ -402,7 +637,8 @@ func (ghc *GitHubContext) PushedAt(sha string) (time.Time, error) {
 // no global cache and avoids the possibility that the global cache evicts
 // entries during the lifetime of the context.
 //
-// The local cache must be initialized before calling tryPushedAt.
+// The caller must hold pushedAtMu and the local cache must be initialized
+// before calling tryPushedAt.
 func (ghc *GitHubContext) tryPushedAt(repoID int64, sha string) (time.Time, error) {
 	if t, ok := ghc.pushedAt[sha]; ok {
 		return t, nil
@@ -441,6 +677,9 @@ func (ghc *GitHubContext) nextChildCommit(sha string) (*Commit, error) {
 }
 
 func (ghc *GitHubContext) Comments() ([]*Comment, error) {
+	ghc.pagedMu.Lock()
+	defer ghc.pagedMu.Unlock()
+
 	if ghc.comments == nil {
 		if err := ghc.loadPagedData(); err != nil {
 			return nil, err
@@ -450,6 +689,9 @@ func (ghc *GitHubContext) Comments() ([]*Comment, error) {
 }
 
 func (ghc *GitHubContext) Reviews() ([]*Review, error) {
+	ghc.pagedMu.Lock()
+	defer ghc.pagedMu.Unlock()
+
 	if ghc.reviews == nil {
 		if err := ghc.loadPagedData(); err != nil {
 			return nil, err
@@ -458,7 +700,142 @@ func (ghc *GitHubContext) Reviews() ([]*Review, error) {
 	return ghc.reviews, nil
 }
 
+func (ghc *GitHubContext) ReopenedAt() (time.Time, error) {
+	ghc.reopenedAtMu.Lock()
+	defer ghc.reopenedAtMu.Unlock()
+
+	if ghc.reopenedAt == nil {
+		var q struct {
+			Repository struct {
+				PullRequest struct {
+					TimelineItems struct {
+						Nodes []struct {
+							ReopenedEvent struct {
+								CreatedAt time.Time
+							} `graphql:"... on ReopenedEvent"`
+						}
+					} `graphql:"timelineItems(last: 1, itemTypes: [REOPENED_EVENT])"`
+				} `graphql:"pullRequest(number: $number)"`
+			} `graphql:"repository(owner: $owner, name: $name)"`
+		}
+		qvars := map[string]interface{}{
+			"owner":  githubv4.String(ghc.owner),
+			"name":   githubv4.String(ghc.repo),
+			"number": githubv4.Int(ghc.number),
+		}
+
+		if err := ghc.v4client.Query(ghc.ctx, &q, qvars); err != nil {
+			return time.Time{}, errors.Wrap(err, "failed to list reopened events")
+		}
+
+		var reopenedAt time.Time
+		if nodes := q.Repository.PullRequest.TimelineItems.Nodes; len(nodes) > 0 {
+			reopenedAt = nodes[0].ReopenedEvent.CreatedAt
+		}
+		ghc.reopenedAt = &reopenedAt
+	}
+	return *ghc.reopenedAt, nil
+}
+
+func (ghc *GitHubContext) ForcePushedAt() (time.Time, error) {
+	ghc.forcePushedAtMu.Lock()
+	defer ghc.forcePushedAtMu.Unlock()
+
+	if ghc.forcePushedAt == nil {
+		var q struct {
+			Repository struct {
+				PullRequest struct {
+					TimelineItems struct {
+						Nodes []struct {
+							HeadRefForcePushedEvent struct {
+								CreatedAt time.Time
+							} `graphql:"... on HeadRefForcePushedEvent"`
+						}
+					} `graphql:"timelineItems(last: 1, itemTypes: [HEAD_REF_FORCE_PUSHED_EVENT])"`
+				} `graphql:"pullRequest(number: $number)"`
+			} `graphql:"repository(owner: $owner, name: $name)"`
+		}
+		qvars := map[string]interface{}{
+			"owner":  githubv4.String(ghc.owner),
+			"name":   githubv4.String(ghc.repo),
+			"number": githubv4.Int(ghc.number),
+		}
+
+		if err := ghc.v4client.Query(ghc.ctx, &q, qvars); err != nil {
+			return time.Time{}, errors.Wrap(err, "failed to list force-push events")
+		}
+
+		var forcePushedAt time.Time
+		if nodes := q.Repository.PullRequest.TimelineItems.Nodes; len(nodes) > 0 {
+			forcePushedAt = nodes[0].HeadRefForcePushedEvent.CreatedAt
+		}
+		ghc.forcePushedAt = &forcePushedAt
+	}
+	return *ghc.forcePushedAt, nil
+}
+
+func (ghc *GitHubContext) ReviewThreads() ([]*ReviewThread, error) {
+	ghc.reviewThreadsMu.Lock()
+	defer ghc.reviewThreadsMu.Unlock()
+
+	if ghc.reviewThreads == nil {
+		var q struct {
+			Repository struct {
+				PullRequest struct {
+					ReviewThreads struct {
+						PageInfo v4PageInfo
+						Nodes    []struct {
+							Path       string
+							IsResolved bool
+							IsOutdated bool
+							Comments   struct {
+								Nodes []struct {
+									Author    v4Actor
+									CreatedAt time.Time
+								}
+							} `graphql:"comments(first: 1)"`
+						}
+					} `graphql:"reviewThreads(first: 100, after: $cursor)"`
+				} `graphql:"pullRequest(number: $number)"`
+			} `graphql:"repository(owner: $owner, name: $name)"`
+		}
+		qvars := map[string]interface{}{
+			"owner":  githubv4.String(ghc.owner),
+			"name":   githubv4.String(ghc.repo),
+			"number": githubv4.Int(ghc.number),
+			"cursor": (*githubv4.String)(nil),
+		}
+
+		threads := []*ReviewThread{}
+		for {
+			if err := ghc.v4client.Query(ghc.ctx, &q, qvars); err != nil {
+				return nil, errors.Wrap(err, "failed to list review threads")
+			}
+			for _, n := range q.Repository.PullRequest.ReviewThreads.Nodes {
+				thread := &ReviewThread{
+					Path:       n.Path,
+					IsResolved: n.IsResolved,
+					IsOutdated: n.IsOutdated,
+				}
+				if len(n.Comments.Nodes) > 0 {
+					thread.Author = n.Comments.Nodes[0].Author.GetV3Login()
+					thread.CreatedAt = n.Comments.Nodes[0].CreatedAt
+				}
+				threads = append(threads, thread)
+			}
+			if !q.Repository.PullRequest.ReviewThreads.PageInfo.UpdateCursor(qvars, "cursor") {
+				break
+			}
+		}
+		ghc.reviewThreads = threads
+	}
+	return ghc.reviewThreads, nil
+}
+
 func (ghc *GitHubContext) RepositoryCollaborators() ([]*Collaborator, error) {
+	ghc.collaboratorsMu.Lock()
+	defer ghc.collaboratorsMu.Unlock()
+
 	if ghc.collaborators == nil {
 		// For reviewer assignment, we need to figure out how each collaborator
 		// gets permissions on the repository. We _should_ be able to do this
@@ -478,97 +855,66 @@ func (ghc *GitHubContext) RepositoryCollaborators() ([]*Collaborator, error) {
 		// should only be used when assigning user reviewers, in which case
 		// almost all of the calls would have been made anyway.
 
-		var q struct {
-			Repository struct {
-				DirectCollaborators struct {
-					PageInfo v4PageInfo
-					Edges    []struct {
-						Permission string
-					}
-					Nodes []v4Actor
-				} `graphql:"direct: collaborators(affiliation: DIRECT, first: 100, after: $directCursor)"`
-				AllCollaborators struct {
-					PageInfo v4PageInfo
-					Edges    []struct {
-						Permission string
-					}
-					Nodes []v4Actor
-				} `graphql:"all: collaborators(affiliation: ALL, first: 100, after: $allCursor)"`
-			} `graphql:"repository(owner: $owner, name: $name)"`
-		}
-		qvars := map[string]interface{}{
-			"owner":        githubv4.String(ghc.owner),
-			"name":         githubv4.String(ghc.repo),
-			"directCursor": (*githubv4.String)(nil),
-			"allCursor":    (*githubv4.String)(nil),
-		}
-
-		directPerms := make(map[string]Permission)
-
-		var collaborators []*Collaborator
-		for {
-			complete := 0
-			if err := ghc.v4client.Query(ghc.ctx, &q, qvars); err != nil {
-				return nil, errors.Wrap(err, "failed to load repository collaborators")
+		var wg sync.WaitGroup
+		var directPerms, allPerms map[string]Permission
+		var teamPerms map[string]Permission
+		var teamMembership map[string][]string
+		var directErr, allErr, teamErr error
+
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			directPerms, _, directErr = ghc.loadCollaborators(githubv4.CollaboratorAffiliationDirect)
+		}()
+		go func() {
+			defer wg.Done()
+			teamPerms, teamMembership, teamErr = ghc.loadTeamMembership()
+		}()
+
+		var allNames []string
+		if !ghc.directCollaboratorsOnly {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				allPerms, allNames, allErr = ghc.loadCollaborators(githubv4.CollaboratorAffiliationAll)
+			}()
+		}
+		wg.Wait()
+
+		for _, err := range []error{directErr, allErr, teamErr} {
+			if err != nil {
+				return nil, err
 			}
+		}
 
-			for i, u := range q.Repository.DirectCollaborators.Nodes {
-				edge := q.Repository.DirectCollaborators.Edges[i]
-				name := u.GetV3Login()
-
-				p, err := ParsePermission(edge.Permission)
-				if err != nil {
-					return nil, errors.Wrapf(err, "%s", name)
-				}
-				directPerms[name] = p
+		if ghc.directCollaboratorsOnly {
+			// Without the list of all collaborators, direct collaborators
+			// and members of repository teams are the only collaborators
+			allPerms = make(map[string]Permission)
+			for name, p := range directPerms {
+				allPerms[name] = p
 			}
-			if !q.Repository.DirectCollaborators.PageInfo.UpdateCursor(qvars, "directCursor") {
-				complete++
-			}
-
-			for i, u := range q.Repository.AllCollaborators.Nodes {
-				edge := q.Repository.AllCollaborators.Edges[i]
-				name := u.GetV3Login()
-
-				p, err := ParsePermission(edge.Permission)
-				if err != nil {
-					return nil, errors.Wrapf(err, "%s", name)
+			for name, teams := range teamMembership {
+				for _, team := range teams {
+					if tp := teamPerms[team]; tp > allPerms[name] {
+						allPerms[name] = tp
+					}
 				}
-
-				collaborators = append(collaborators, &Collaborator{
-					Name: name,
-					Permissions: []CollaboratorPermission{
-						{Permission: p},
-					},
-				})
 			}
-			if !q.Repository.AllCollaborators.PageInfo.UpdateCursor(qvars, "allCursor") {
-				complete++
-			}
-
-			if complete == 2 {
-				break
+			for name := range allPerms {
+				allNames = append(allNames, name)
 			}
+			sort.Strings(allNames)
 		}
 
-		teamPerms, err := ghc.Teams()
-		if err != nil {
-			return nil, err
-		}
-
-		teamMembership := make(map[string][]string)
-		for team := range teamPerms {
-			// List full membership instead of testing each collaborator under
-			// the assumption that (teams * members) is much less than the
-			// total number of collaborators, which include those from the org
-			members, err := ghc.TeamMembers(ghc.owner + "/" + team)
-			if err != nil {
-				return nil, err
-			}
-
-			for _, member := range members {
-				teamMembership[member] = append(teamMembership[member], team)
-			}
+		var collaborators []*Collaborator
+		for _, name := range allNames {
+			collaborators = append(collaborators, &Collaborator{
+				Name: name,
+				Permissions: []CollaboratorPermission{
+					{Permission: allPerms[name]},
+				},
+			})
 		}
 
 		fillPermissions := func(c *Collaborator) {
@@ -606,7 +952,147 @@ func (ghc *GitHubContext) RepositoryCollaborators() ([]*Collaborator, error) {
 	return ghc.collaborators, nil
 }
 
+// loadCollaborators returns the permission of each collaborator with the
+// given affiliation and the names of the collaborators in the order returned
+// by GitHub.
+func (ghc *GitHubContext) loadCollaborators(affiliation githubv4.CollaboratorAffiliation) (map[string]Permission, []string, error) {
+	var q struct {
+		Repository struct {
+			Collaborators struct {
+				PageInfo v4PageInfo
+				Edges    []struct {
+					Permission string
+				}
+				Nodes []v4Actor
+			} `graphql:"collaborators(affiliation: $affiliation, first: 100, after: $cursor)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+	qvars := map[string]interface{}{
+		"owner":       githubv4.String(ghc.owner),
+		"name":        githubv4.String(ghc.repo),
+		"affiliation": affiliation,
+		"cursor":      (*githubv4.String)(nil),
+	}
+
+	perms := make(map[string]Permission)
+
+	var names []string
+	for {
+		if err := ghc.v4client.Query(ghc.ctx, &q, qvars); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to load %s repository collaborators", strings.ToLower(string(affiliation)))
+		}
+
+		for i, u := range q.Repository.Collaborators.Nodes {
+			edge := q.Repository.Collaborators.Edges[i]
+			name := u.GetV3Login()
+
+			p, err := ParsePermission(edge.Permission)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "%s", name)
+			}
+			if _, ok := perms[name]; !ok {
+				names = append(names, name)
+			}
+			perms[name] = p
+		}
+		if !q.Repository.Collaborators.PageInfo.UpdateCursor(qvars, "cursor") {
+			break
+		}
+	}
+	return perms, names, nil
+}
+
+// loadTeamMembership returns the permission of each repository team and the
+// repository teams of each user.
+func (ghc *GitHubContext) loadTeamMembership() (map[string]Permission, map[string][]string, error) {
+	teamPerms, err := ghc.Teams()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	teamMembership := make(map[string][]string)
+	for team := range teamPerms {
+		// List full membership instead of testing each collaborator under
+		// the assumption that (teams * members) is much less than the
+		// total number of collaborators, which include those from the org
+		members, err := ghc.TeamMembers(ghc.owner + "/" + team)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, member := range members {
+			teamMembership[member] = append(teamMembership[member], team)
+		}
+	}
+	return teamPerms, teamMembership, nil
+}
+
+func (ghc *GitHubContext) PendingReviewRequests(users []string) (map[string]int, error) {
+	ghc.pendingRequestsMu.Lock()
+	defer ghc.pendingRequestsMu.Unlock()
+
+	if ghc.pendingRequests == nil {
+		ghc.pendingRequests = make(map[string]int)
+	}
+
+	var missing []string
+	seen := make(map[string]bool)
+	for _, user := range users {
+		if _, ok := ghc.pendingRequests[user]; !ok && !seen[user] {
+			missing = append(missing, user)
+			seen[user] = true
+		}
+	}
+
+	for len(missing) > 0 {
+		n := min(len(missing), pendingReviewRequestsBatchSize)
+		if err := ghc.loadPendingReviewRequests(missing[:n]); err != nil {
+			return nil, err
+		}
+		missing = missing[n:]
+	}
+
+	counts := make(map[string]int, len(users))
+	for _, user := range users {
+		counts[user] = ghc.pendingRequests[user]
+	}
+	return counts, nil
+}
+
+// loadPendingReviewRequests counts the pending review requests of each user
+// with a single GraphQL query that contains one aliased search per user. The
+// caller must hold pendingRequestsMu.
+func (ghc *GitHubContext) loadPendingReviewRequests(users []string) error {
+	type search struct {
+		IssueCount int
+	}
+
+	fields := make([]reflect.StructField, len(users))
+	qvars := make(map[string]interface{}, len(users))
+	for i, user := range users {
+		fields[i] = reflect.StructField{
+			Name: fmt.Sprintf("Search%d", i),
+			Type: reflect.TypeOf(search{}),
+			Tag:  reflect.StructTag(fmt.Sprintf(`graphql:"search%d: search(query: $query%d, type: ISSUE)"`, i, i)),
+		}
+		qvars[fmt.Sprintf("query%d", i)] = githubv4.String(fmt.Sprintf("repo:%s/%s is:pr is:open review-requested:%s", ghc.owner, ghc.repo, user))
+	}
+
+	q := reflect.New(reflect.StructOf(fields))
+	if err := ghc.v4client.Query(ghc.ctx, q.Interface(), qvars); err != nil {
+		return errors.Wrapf(err, "failed to count pending review requests for %s", strings.Join(users, ", "))
+	}
+
+	for i, user := range users {
+		ghc.pendingRequests[user] = q.Elem().Field(i).Interface().(search).IssueCount
+	}
+	return nil
+}
+
 func (ghc *GitHubContext) CollaboratorPermission(user string) (Permission, error) {
+	ghc.permissionsMu.Lock()
+	defer ghc.permissionsMu.Unlock()
+
 	if ghc.permissions == nil {
 		ghc.permissions = make(map[string]Permission)
 	}
@@ -614,6 +1100,38 @@ func (ghc *GitHubContext) CollaboratorPermission(user string) (Permission, error
 		return p, nil
 	}
 
+	perm, err := ghc.loadCollaboratorPermission(ghc.owner, ghc.repo, user)
+	if err != nil {
+		return PermissionNone, err
+	}
+
+	ghc.permissions[user] = perm
+	return perm, nil
+}
+
+func (ghc *GitHubContext) CollaboratorPermissionOn(owner, repo, user string) (Permission, error) {
+	ghc.otherPermissionsMu.Lock()
+	defer ghc.otherPermissionsMu.Unlock()
+
+	if ghc.otherPermissions == nil {
+		ghc.otherPermissions = make(map[string]Permission)
+	}
+
+	key := owner + "/" + repo + ":" + user
+	if p, ok := ghc.otherPermissions[key]; ok {
+		return p, nil
+	}
+
+	perm, err := ghc.loadCollaboratorPermission(owner, repo, user)
+	if err != nil {
+		return PermissionNone, errors.Wrapf(err, "failed to get permission on %s/%s", owner, repo)
+	}
+
+	ghc.otherPermissions[key] = perm
+	return perm, nil
+}
+
+func (ghc *GitHubContext) loadCollaboratorPermission(owner, repo, user string) (Permission, error) {
 	// Use GraphQL because the v3 API to get collaborator permissions does not
 	// support maintain and triage permissions as of 2021-05-07.
 	var q struct {
@@ -628,8 +1146,8 @@ func (ghc *GitHubContext) CollaboratorPermission(user string) (Permission, error
 		} `graphql:"repository(owner: $owner, name: $name)"`
 	}
 	qvars := map[string]interface{}{
-		"owner":  githubv4.String(ghc.owner),
-		"name":   githubv4.String(ghc.repo),
+		"owner":  githubv4.String(owner),
+		"name":   githubv4.String(repo),
 		"user":   githubv4.String(user),
 		"cursor": (*githubv4.String)(nil),
 	}
@@ -653,8 +1171,6 @@ func (ghc *GitHubContext) CollaboratorPermission(user string) (Permission, error
 			break
 		}
 	}
-
-	ghc.permissions[user] = perm
 	return perm, nil
 }
 
@@ -668,6 +1184,9 @@ func findUserIndex(user string, users []v4Actor) int {
 }
 
 func (ghc *GitHubContext) RequestedReviewers() ([]*Reviewer, error) {
+	ghc.reviewersMu.Lock()
+	defer ghc.reviewersMu.Unlock()
+
 	if ghc.reviewers == nil {
 		if err := ghc.loadRequestedReviewers(); err != nil {
 			return nil, err
@@ -723,7 +1242,9 @@ func (ghc *GitHubContext) loadRequestedReviewers() error {
 		}
 
 		for _, n := range q.Repository.PullRequest.TimelineItems.Nodes {
-			reviewers = append(reviewers, n.ReviewRequestRemovedEvent.RequestedReviewer.ToReviewer(true))
+			r := n.ReviewRequestRemovedEvent.RequestedReviewer.ToReviewer(true)
+			r.RemovedBy = n.ReviewRequestRemovedEvent.Actor.GetV3Login()
+			reviewers = append(reviewers, r)
 		}
 		if !q.Repository.PullRequest.TimelineItems.PageInfo.UpdateCursor(qvars, "timelineCursor") {
 			complete++
@@ -737,7 +1258,79 @@ func (ghc *GitHubContext) loadRequestedReviewers() error {
 	return nil
 }
 
+func (ghc *GitHubContext) ReviewRequests() ([]*ReviewRequest, error) {
+	ghc.reviewRequestsMu.Lock()
+	defer ghc.reviewRequestsMu.Unlock()
+
+	if ghc.reviewRequests == nil {
+		var q struct {
+			Repository struct {
+				PullRequest struct {
+					TimelineItems struct {
+						PageInfo v4PageInfo
+						Nodes    []struct {
+							Type                 string `graphql:"__typename"`
+							ReviewRequestedEvent struct {
+								CreatedAt         time.Time
+								RequestedReviewer v4RequestedReviewer
+							} `graphql:"... on ReviewRequestedEvent"`
+							ReviewRequestRemovedEvent struct {
+								CreatedAt         time.Time
+								RequestedReviewer v4RequestedReviewer
+							} `graphql:"... on ReviewRequestRemovedEvent"`
+						}
+					} `graphql:"timelineItems(first: 100, after: $cursor, itemTypes: [REVIEW_REQUESTED_EVENT, REVIEW_REQUEST_REMOVED_EVENT])"`
+				} `graphql:"pullRequest(number: $number)"`
+			} `graphql:"repository(owner: $owner, name: $name)"`
+		}
+		qvars := map[string]interface{}{
+			"owner":  githubv4.String(ghc.owner),
+			"name":   githubv4.String(ghc.repo),
+			"number": githubv4.Int(ghc.number),
+			"cursor": (*githubv4.String)(nil),
+		}
+
+		// timeline items are in chronological order, so later events for the
+		// same reviewer replace the state set by earlier events
+		requests := []*ReviewRequest{}
+		byReviewer := make(map[Reviewer]*ReviewRequest)
+		for {
+			if err := ghc.v4client.Query(ghc.ctx, &q, qvars); err != nil {
+				return nil, errors.Wrap(err, "failed to list review request events")
+			}
+			for _, n := range q.Repository.PullRequest.TimelineItems.Nodes {
+				switch n.Type {
+				case "ReviewRequestedEvent":
+					r := n.ReviewRequestedEvent.RequestedReviewer.ToReviewer(false)
+					req, ok := byReviewer[*r]
+					if !ok {
+						req = &ReviewRequest{Type: r.Type, Name: r.Name}
+						byReviewer[*r] = req
+						requests = append(requests, req)
+					}
+					req.RequestedAt = n.ReviewRequestedEvent.CreatedAt
+					req.RemovedAt = time.Time{}
+
+				case "ReviewRequestRemovedEvent":
+					r := n.ReviewRequestRemovedEvent.RequestedReviewer.ToReviewer(false)
+					if req, ok := byReviewer[*r]; ok {
+						req.RemovedAt = n.ReviewRequestRemovedEvent.CreatedAt
+					}
+				}
+			}
+			if !q.Repository.PullRequest.TimelineItems.PageInfo.UpdateCursor(qvars, "cursor") {
+				break
+			}
+		}
+		ghc.reviewRequests = requests
+	}
+	return ghc.reviewRequests, nil
+}
+
 func (ghc *GitHubContext) Teams() (map[string]Permission, error) {
+	ghc.teamsMu.Lock()
+	defer ghc.teamsMu.Unlock()
+
 	if ghc.teams == nil {
 		opt := &github.ListOptions{
 			PerPage: 100,
@@ -763,7 +1356,19 @@ func (ghc *GitHubContext) Teams() (map[string]Permission, error) {
 }
 
 func (ghc *GitHubContext) LatestStatuses() (map[string]string, error) {
+	ghc.statusesMu.Lock()
+	defer ghc.statusesMu.Unlock()
+
 	if ghc.statuses == nil {
+		repoID := ghc.pr.BaseRepository.DatabaseID
+		if gc := ghc.globalCache; gc != nil {
+			if statuses, ok := gc.GetStatuses(repoID, ghc.HeadSHA()); ok {
+				ghc.statuses = statuses
+				return ghc.statuses, nil
+			}
+		}
+
+		loadedAt := time.Now()
 		statuses, err := ghc.getStatuses()
 		if err != nil {
 			return nil, err
@@ -779,6 +1384,9 @@ func (ghc *GitHubContext) LatestStatuses() (map[string]string, error) {
 		}
 
 		ghc.statuses = statuses
+		if gc := ghc.globalCache; gc != nil {
+			gc.SetStatuses(repoID, ghc.HeadSHA(), statuses, loadedAt)
+		}
 	}
 
 	return ghc.statuses, nil
@@ -813,21 +1421,22 @@ func (ghc *GitHubContext) getCheckStatuses() (map[string]string, error) {
 		},
 	}
 	// get all pages of results
-	statuses := make(map[string]string)
+	latest := make(map[string]*github.CheckRun)
 	for {
 		checkRuns, resp, err := ghc.client.Checks.ListCheckRunsForRef(ghc.ctx, ghc.owner, ghc.repo, ghc.HeadSHA(), opt)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to get check runs for page %d", opt.Page)
 		}
 
-		// Check runs are ordered from most to least recent. In some cases,
-		// like when a commit is included in multiple PRs or when users re-run
-		// checks, there may be multiple runs with the same name. We only want
-		// to keep the first (most recent) result for each name.
+		// In some cases, like when a commit is included in multiple PRs or
+		// when users re-run checks, there may be multiple runs with the same
+		// name. We only want to keep the most recent result for each name.
+		// GitHub usually lists the most recent run first, but this is not
+		// guaranteed across pages, so compare the run times directly.
 		for _, checkRun := range checkRuns.CheckRuns {
 			name := checkRun.GetName()
-			if _, exists := statuses[name]; !exists {
-				statuses[name] = checkRun.GetConclusion()
+			if prev, exists := latest[name]; !exists || isNewerCheckRun(checkRun, prev) {
+				latest[name] = checkRun
 			}
 		}
 
@@ -836,10 +1445,45 @@ func (ghc *GitHubContext) getCheckStatuses() (map[string]string, error) {
 		}
 		opt.Page = resp.NextPage
 	}
+
+	statuses := make(map[string]string, len(latest))
+	for name, checkRun := range latest {
+		// Check runs that have not completed have no conclusion, so use the
+		// status (e.g. "queued", "in_progress") instead
+		if conclusion := checkRun.GetConclusion(); conclusion != "" {
+			statuses[name] = conclusion
+		} else {
+			statuses[name] = checkRun.GetStatus()
+		}
+	}
 	return statuses, nil
 }
 
+// isNewerCheckRun returns true if run a started after run b. If both runs
+// started at the same time, the run that completed later is newer and a run
+// that has not completed is newer than one that has.
+func isNewerCheckRun(a, b *github.CheckRun) bool {
+	aStart, bStart := a.GetStartedAt().Time, b.GetStartedAt().Time
+	if !aStart.Equal(bStart) {
+		return aStart.After(bStart)
+	}
+
+	aEnd, bEnd := a.GetCompletedAt().Time, b.GetCompletedAt().Time
+	switch {
+	case aEnd.Equal(bEnd):
+		return false
+	case aEnd.IsZero():
+		return true
+	case bEnd.IsZero():
+		return false
+	}
+	return aEnd.After(bEnd)
+}
+
 func (ghc *GitHubContext) LatestWorkflowRuns() (map[string][]string, error) {
+	ghc.workflowRunsMu.Lock()
+	defer ghc.workflowRunsMu.Unlock()
+
 	if ghc.workflowRuns != nil {
 		return ghc.workflowRuns, nil
 	}
@@ -879,7 +1523,13 @@ func (ghc *GitHubContext) LatestWorkflowRuns() (map[string][]string, error) {
 	// conclusions of `success`, here this would mean that both the
 	// `pull_request` and `push` events would have to pass, if triggered, for
 	// the workflow to be considered successful.
+	//
+	// Runs are keyed by the path of the workflow file and by the name of the
+	// workflow. If several workflows have the same name, the name includes
+	// the runs of all of them. If the name of one workflow is the same as the
+	// path of another, the path takes precedence.
 	runsWithDate := make(map[string]map[string]*github.WorkflowRun)
+	namesByPath := make(map[string]string)
 	for {
 		runs, resp, err := ghc.client.Actions.ListRepositoryWorkflowRuns(ghc.ctx, ghc.owner, ghc.repo, opt)
 		if err != nil {
@@ -892,6 +1542,9 @@ func (ghc *GitHubContext) LatestWorkflowRuns() (map[string][]string, error) {
 			}
 
 			eventName := run.GetEvent()
+			if name := run.GetName(); name != "" {
+				namesByPath[run.GetPath()] = name
+			}
 
 			previousRuns := runsWithDate[*run.Path]
 			if previousRuns == nil {
@@ -922,10 +1575,87 @@ func (ghc *GitHubContext) LatestWorkflowRuns() (map[string][]string, error) {
 		}
 	}
 
+	workflowsByName := make(map[string][]string)
+	for path, eventRuns := range runsWithDate {
+		name := namesByPath[path]
+		if name == "" {
+			continue
+		}
+		if _, isPath := runsWithDate[name]; isPath {
+			continue
+		}
+		for _, run := range eventRuns {
+			workflowsByName[name] = append(workflowsByName[name], run.GetConclusion())
+		}
+	}
+	for name, conclusions := range workflowsByName {
+		workflowRuns[name] = conclusions
+	}
+
+	ghc.workflowRuns = workflowRuns
 	return workflowRuns, nil
 }
 
+func (ghc *GitHubContext) LatestDeployments() (map[string]string, error) {
+	ghc.deploymentsMu.Lock()
+	defer ghc.deploymentsMu.Unlock()
+
+	if ghc.deployments != nil {
+		return ghc.deployments, nil
+	}
+
+	opt := &github.DeploymentsListOptions{
+		SHA: ghc.HeadSHA(),
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+			Page:    0,
+		},
+	}
+
+	latest := make(map[string]*github.Deployment)
+	for {
+		deployments, resp, err := ghc.client.Repositories.ListDeployments(ghc.ctx, ghc.owner, ghc.repo, opt)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get deployments for page %d", opt.Page)
+		}
+
+		for _, d := range deployments {
+			env := d.GetEnvironment()
+			if prev, ok := latest[env]; ok && d.GetCreatedAt().Before(prev.GetCreatedAt().Time) {
+				continue
+			}
+			latest[env] = d
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	deployments := make(map[string]string, len(latest))
+	for env, d := range latest {
+		// Statuses are returned newest first, so only the first is needed
+		statuses, _, err := ghc.client.Repositories.ListDeploymentStatuses(ghc.ctx, ghc.owner, ghc.repo, d.GetID(), &github.ListOptions{PerPage: 1})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get statuses for deployment %d", d.GetID())
+		}
+
+		state := "pending"
+		if len(statuses) > 0 {
+			state = statuses[0].GetState()
+		}
+		deployments[env] = state
+	}
+
+	ghc.deployments = deployments
+	return deployments, nil
+}
+
 func (ghc *GitHubContext) Labels() ([]string, error) {
+	ghc.labelsMu.Lock()
+	defer ghc.labelsMu.Unlock()
+
 	if ghc.labels == nil {
 		issueLabels, _, err := ghc.client.Issues.ListLabelsByIssue(ghc.ctx, ghc.owner, ghc.repo, ghc.number, &github.ListOptions{
 			Page:    0,
@@ -944,6 +1674,151 @@ func (ghc *GitHubContext) Labels() ([]string, error) {
 	return ghc.labels, nil
 }
 
+func (ghc *GitHubContext) LabelAppliers() (map[string]string, error) {
+	ghc.labelAppliersMu.Lock()
+	defer ghc.labelAppliersMu.Unlock()
+
+	if ghc.labelAppliers == nil {
+		var q struct {
+			Repository struct {
+				PullRequest struct {
+					TimelineItems struct {
+						PageInfo v4PageInfo
+						Nodes    []struct {
+							LabeledEvent struct {
+								Actor v4Actor
+								Label struct {
+									Name string
+								}
+							} `graphql:"... on LabeledEvent"`
+						}
+					} `graphql:"timelineItems(first: 100, after: $cursor, itemTypes: [LABELED_EVENT])"`
+				} `graphql:"pullRequest(number: $number)"`
+			} `graphql:"repository(owner: $owner, name: $name)"`
+		}
+		qvars := map[string]interface{}{
+			"owner":  githubv4.String(ghc.owner),
+			"name":   githubv4.String(ghc.repo),
+			"number": githubv4.Int(ghc.number),
+			"cursor": (*githubv4.String)(nil),
+		}
+
+		// timeline items are returned in chronological order, so later events
+		// replace earlier ones for the same label
+		appliers := make(map[string]string)
+		for {
+			if err := ghc.v4client.Query(ghc.ctx, &q, qvars); err != nil {
+				return nil, errors.Wrap(err, "failed to list labeled events")
+			}
+			for _, n := range q.Repository.PullRequest.TimelineItems.Nodes {
+				event := n.LabeledEvent
+				appliers[strings.ToLower(event.Label.Name)] = event.Actor.GetV3Login()
+			}
+			if !q.Repository.PullRequest.TimelineItems.PageInfo.UpdateCursor(qvars, "cursor") {
+				break
+			}
+		}
+		ghc.labelAppliers = appliers
+	}
+	return ghc.labelAppliers, nil
+}
+
+func (ghc *GitHubContext) HeadCommitVerification() (*Verification, error) {
+	ghc.verificationMu.Lock()
+	defer ghc.verificationMu.Unlock()
+
+	if ghc.verification == nil {
+		commit, _, err := ghc.client.Git.GetCommit(ghc.ctx, ghc.owner, ghc.repo, ghc.HeadSHA())
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get commit %s", ghc.HeadSHA())
+		}
+
+		ghc.verification = &Verification{
+			Verified: commit.GetVerification().GetVerified(),
+			Reason:   commit.GetVerification().GetReason(),
+		}
+	}
+	return ghc.verification, nil
+}
+
+func (ghc *GitHubContext) BaseFileContent(path string) (string, bool, error) {
+	base, _ := ghc.Branches()
+	return ghc.FileContent(path, base)
+}
+
+func (ghc *GitHubContext) FileContent(path, ref string) (string, bool, error) {
+	ghc.fileContentsMu.Lock()
+	defer ghc.fileContentsMu.Unlock()
+
+	key := ref + ":" + path
+	if content, ok := ghc.fileContents[key]; ok {
+		if content == nil {
+			return "", false, nil
+		}
+		return *content, true, nil
+	}
+
+	if ghc.fileContents == nil {
+		ghc.fileContents = make(map[string]*string)
+	}
+
+	opts := &github.RepositoryContentGetOptions{Ref: ref}
+
+	file, _, _, err := ghc.client.Repositories.GetContents(ghc.ctx, ghc.owner, ghc.repo, path, opts)
+	if err != nil {
+		if isNotFound(err) {
+			ghc.fileContents[key] = nil
+			return "", false, nil
+		}
+		return "", false, errors.Wrapf(err, "failed to get content of %s on %s", path, ref)
+	}
+
+	// the path exists, but is a directory
+	if file == nil {
+		ghc.fileContents[key] = nil
+		return "", false, nil
+	}
+
+	// GitHub omits the content of files larger than 1 MB and sets the
+	// encoding to "none"
+	if file.GetEncoding() == "none" {
+		return "", true, errors.Wrapf(ErrFileTooLarge, "failed to get content of %s on %s", path, ref)
+	}
+
+	content, err := file.GetContent()
+	if err != nil {
+		return "", false, errors.Wrapf(err, "failed to decode content of %s on %s", path, ref)
+	}
+
+	ghc.fileContents[key] = &content
+	return content, true, nil
+}
+
+func (ghc *GitHubContext) IsProtectedBranch(branch string) (bool, error) {
+	ghc.protectedBranchesMu.Lock()
+	defer ghc.protectedBranchesMu.Unlock()
+
+	if protected, ok := ghc.protectedBranches[branch]; ok {
+		return protected, nil
+	}
+
+	if ghc.protectedBranches == nil {
+		ghc.protectedBranches = make(map[string]bool)
+	}
+
+	protected := true
+	_, _, err := ghc.client.Repositories.GetBranchProtection(ghc.ctx, ghc.owner, ghc.repo, branch)
+	if err != nil {
+		if !errors.Is(err, github.ErrBranchNotProtected) && !isNotFound(err) {
+			return false, errors.Wrapf(err, "failed to get protection for branch %s", branch)
+		}
+		protected = false
+	}
+
+	ghc.protectedBranches[branch] = protected
+	return protected, nil
+}
+
 func (ghc *GitHubContext) loadPagedData() error {
 	// this is a minor optimization: make max(c,r) requests instead of c+r
 	var q struct {
@@ -957,7 +1832,7 @@ func (ghc *GitHubContext) loadPagedData() error {
 				Reviews struct {
 					PageInfo v4PageInfo
 					Nodes    []v4PullRequestReview
-				} `graphql:"reviews(first: 100, after: $reviewCursor, states: [APPROVED, CHANGES_REQUESTED, COMMENTED])"`
+				} `graphql:"reviews(first: 100, after: $reviewCursor, states: [APPROVED, CHANGES_REQUESTED, COMMENTED, DISMISSED])"`
 			} `graphql:"pullRequest(number: $number)"`
 		} `graphql:"repository(owner: $owner, name: $name)"`
 	}
@@ -990,7 +1865,7 @@ func (ghc *GitHubContext) loadPagedData() error {
 			case "COMMENTED":
 				comments = append(comments, r.ToComment())
 				fallthrough
-			case "APPROVED", "CHANGES_REQUESTED":
+			case "APPROVED", "CHANGES_REQUESTED", "DISMISSED":
 				reviews = append(reviews, r.ToReview())
 			}
 		}
@@ -1098,6 +1973,8 @@ type v4PullRequest struct {
 	IsCrossRepository bool
 	IsDraft           bool
 
+	AutoMergeRequest *v4AutoMergeRequest
+
 	HeadRefOID     string
 	HeadRefName    string
 	HeadRepository struct {
@@ -1111,6 +1988,11 @@ type v4PullRequest struct {
 	}
 }
 
+type v4AutoMergeRequest struct {
+	EnabledBy   v4Actor
+	MergeMethod string
+}
+
 type v4PageInfo struct {
 	EndCursor   *githubv4.String
 	HasNextPage bool
@@ -1202,8 +2084,11 @@ type v4PullRequestCommit struct {
 
 type v4Commit struct {
 	OID             string
+	Message         string
 	Author          v4GitActor
 	Committer       v4GitActor
+	AuthoredDate    time.Time
+	CommittedDate   time.Time
 	CommittedViaWeb bool
 	Parents         struct {
 		Nodes []struct {
@@ -1226,10 +2111,15 @@ func (c *v4Commit) ToCommit() *Commit {
 
 	return &Commit{
 		SHA:             c.OID,
+		Message:         c.Message,
 		Parents:         parents,
 		CommittedViaWeb: c.CommittedViaWeb,
 		Author:          c.Author.User.GetV3Login(),
 		Committer:       c.Committer.User.GetV3Login(),
+		AuthorEmail:     c.Author.Email,
+		CommitterEmail:  c.Committer.Email,
+		AuthoredAt:      c.AuthoredDate,
+		CommittedAt:     c.CommittedDate,
 		Signature:       signature,
 	}
 }
@@ -1276,7 +2166,8 @@ func (a *v4Actor) GetV3Login() string {
 }
 
 type v4GitActor struct {
-	User *v4Actor
+	Email string
+	User  *v4Actor
 }
 
 func isNotFound(err error) bool {
@@ -17,27 +17,37 @@ package pull
 import (
 	"context"
 	"strings"
+	"sync"
 
 	"github.com/google/go-github/v65/github"
 	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
 )
 
 type GitHubMembershipContext struct {
-	ctx    context.Context
-	client *github.Client
-
+	ctx      context.Context
+	client   *github.Client
+	v4client *githubv4.Client
+
+	// mu guards the maps, which may be used by rules evaluated concurrently.
+	// It is not held during requests, so concurrent callers may load the same
+	// value more than once.
+	mu          sync.Mutex
 	membership  map[string]bool
 	orgMembers  map[string][]string
 	teamMembers map[string][]string
+	userTeams   map[string][]string
 }
 
-func NewGitHubMembershipContext(ctx context.Context, client *github.Client) *GitHubMembershipContext {
+func NewGitHubMembershipContext(ctx context.Context, client *github.Client, v4client *githubv4.Client) *GitHubMembershipContext {
 	return &GitHubMembershipContext{
 		ctx:         ctx,
 		client:      client,
+		v4client:    v4client,
 		membership:  make(map[string]bool),
 		orgMembers:  make(map[string][]string),
 		teamMembers: make(map[string][]string),
+		userTeams:   make(map[string][]string),
 	}
 }
 
@@ -61,7 +71,9 @@ func (mc *GitHubMembershipContext) IsTeamMember(team, user string) (bool, error)
 		return false, err
 	}
 
+	mc.mu.Lock()
 	isMember, ok := mc.membership[key]
+	mc.mu.Unlock()
 	if ok {
 		return isMember, nil
 	}
@@ -72,7 +84,10 @@ func (mc *GitHubMembershipContext) IsTeamMember(team, user string) (bool, error)
 	}
 
 	isMember = membership != nil && membership.GetState() == "active"
+
+	mc.mu.Lock()
 	mc.membership[key] = isMember
+	mc.mu.Unlock()
 
 	return isMember, nil
 }
@@ -80,7 +95,9 @@ func (mc *GitHubMembershipContext) IsTeamMember(team, user string) (bool, error)
 func (mc *GitHubMembershipContext) IsOrgMember(org, user string) (bool, error) {
 	key := membershipKey(org, user)
 
+	mc.mu.Lock()
 	isMember, ok := mc.membership[key]
+	mc.mu.Unlock()
 	if ok {
 		return isMember, nil
 	}
@@ -90,12 +107,18 @@ func (mc *GitHubMembershipContext) IsOrgMember(org, user string) (bool, error) {
 		return false, errors.Wrap(err, "failed to get organization membership")
 	}
 
+	mc.mu.Lock()
 	mc.membership[key] = isMember
+	mc.mu.Unlock()
+
 	return isMember, nil
 }
 
 func (mc *GitHubMembershipContext) OrganizationMembers(org string) ([]string, error) {
+	mc.mu.Lock()
 	members, ok := mc.orgMembers[org]
+	mc.mu.Unlock()
+
 	if !ok {
 		opt := &github.ListMembersOptions{
 			ListOptions: github.ListOptions{
@@ -110,21 +133,29 @@ func (mc *GitHubMembershipContext) OrganizationMembers(org string) ([]string, er
 			}
 			for _, u := range users {
 				members = append(members, u.GetLogin())
-				// And cache these values for later lookups
-				mc.membership[membershipKey(org, u.GetLogin())] = true
 			}
 			if resp.NextPage == 0 {
 				break
 			}
 			opt.Page = resp.NextPage
 		}
+
+		mc.mu.Lock()
 		mc.orgMembers[org] = members
+		// And cache these values for later lookups
+		for _, member := range members {
+			mc.membership[membershipKey(org, member)] = true
+		}
+		mc.mu.Unlock()
 	}
 	return members, nil
 }
 
 func (mc *GitHubMembershipContext) TeamMembers(team string) ([]string, error) {
+	mc.mu.Lock()
 	members, ok := mc.teamMembers[team]
+	mc.mu.Unlock()
+
 	if !ok {
 		opt := &github.TeamListTeamMembersOptions{
 			ListOptions: github.ListOptions{
@@ -144,15 +175,72 @@ func (mc *GitHubMembershipContext) TeamMembers(team string) ([]string, error) {
 			}
 			for _, u := range users {
 				members = append(members, u.GetLogin())
-				// And cache these values for later lookups
-				mc.membership[membershipKey(team, u.GetLogin())] = true
 			}
 			if resp.NextPage == 0 {
 				break
 			}
 			opt.Page = resp.NextPage
 		}
+
+		mc.mu.Lock()
 		mc.teamMembers[team] = members
+		// And cache these values for later lookups
+		for _, member := range members {
+			mc.membership[membershipKey(team, member)] = true
+		}
+		mc.mu.Unlock()
 	}
 	return members, nil
 }
+
+func (mc *GitHubMembershipContext) UserTeams(org, user string) ([]string, error) {
+	key := membershipKey(org, user)
+
+	mc.mu.Lock()
+	teams, ok := mc.userTeams[key]
+	mc.mu.Unlock()
+	if ok {
+		return teams, nil
+	}
+
+	// Use GraphQL because the v3 API can only list teams for the
+	// authenticated user, not for an arbitrary user.
+	var q struct {
+		Organization struct {
+			Teams struct {
+				PageInfo v4PageInfo
+				Nodes    []struct {
+					Slug string
+				}
+			} `graphql:"teams(userLogins: [$user], first: 100, after: $cursor)"`
+		} `graphql:"organization(login: $org)"`
+	}
+	qvars := map[string]interface{}{
+		"org":    githubv4.String(org),
+		"user":   githubv4.String(user),
+		"cursor": (*githubv4.String)(nil),
+	}
+
+	teams = []string{}
+	for {
+		if err := mc.v4client.Query(mc.ctx, &q, qvars); err != nil {
+			return nil, errors.Wrapf(err, "failed to list teams of user %s in org %s", user, org)
+		}
+		for _, t := range q.Organization.Teams.Nodes {
+			teams = append(teams, org+"/"+t.Slug)
+		}
+		if !q.Organization.Teams.PageInfo.UpdateCursor(qvars, "cursor") {
+			break
+		}
+	}
+
+	mc.mu.Lock()
+	mc.userTeams[key] = teams
+	// And cache these values for later lookups
+	for _, team := range teams {
+		mc.membership[membershipKey(team, user)] = true
+	}
+	mc.mu.Unlock()
+
+	return teams, nil
+}
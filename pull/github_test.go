@@ -16,10 +16,14 @@ package pull
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
 	"sort"
+	"sync"
 	"testing"
 	"time"
 
@@ -27,6 +31,7 @@ import (
 	"github.com/shurcooL/githubv4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
 )
 
 func TestChangedFiles(t *testing.T) {
@@ -62,6 +67,7 @@ func TestChangedFiles(t *testing.T) {
 	assert.Equal(t, FileAdded, files[4].Status)
 	assert.Equal(t, 2, files[4].Additions)
 	assert.Equal(t, 4, files[4].Deletions)
+	assert.Equal(t, "path/old.txt", files[4].PreviousFilename)
 
 	// verify that the file list is cached
 	files, err = ctx.ChangedFiles()
@@ -71,6 +77,61 @@ func TestChangedFiles(t *testing.T) {
 	assert.Equal(t, 2, filesRule.Count, "cached files were not used")
 }
 
+func TestCommitFiles(t *testing.T) {
+	rp := &ResponsePlayer{}
+	filesRule := rp.AddRule(
+		ExactPathMatcher("/repos/testorg/testrepo/commits/e05fcae367230ee709313dd2720da527d178ce43"),
+		"testdata/responses/repo_commit_files.yml",
+	)
+
+	ctx := makeContext(t, rp, nil, nil)
+
+	files, err := ctx.CommitFiles("e05fcae367230ee709313dd2720da527d178ce43")
+	require.NoError(t, err)
+
+	require.Len(t, files, 3, "incorrect number of files")
+	assert.Equal(t, 1, filesRule.Count, "no http request was made")
+
+	assert.Equal(t, "docs/usage.md", files[0].Filename)
+	assert.Equal(t, FileModified, files[0].Status)
+
+	assert.Equal(t, "src/old.go", files[1].Filename)
+	assert.Equal(t, FileDeleted, files[1].Status)
+
+	assert.Equal(t, "src/new.go", files[2].Filename)
+	assert.Equal(t, FileAdded, files[2].Status)
+
+	// verify that the file list is cached
+	files, err = ctx.CommitFiles("e05fcae367230ee709313dd2720da527d178ce43")
+	require.NoError(t, err)
+
+	require.Len(t, files, 3, "incorrect number of files")
+	assert.Equal(t, 1, filesRule.Count, "cached files were not used")
+}
+
+func TestCommitFilesGlobalCache(t *testing.T) {
+	rp := &ResponsePlayer{}
+	filesRule := rp.AddRule(
+		ExactPathMatcher("/repos/testorg/testrepo/commits/e05fcae367230ee709313dd2720da527d178ce43"),
+		"testdata/responses/repo_commit_files.yml",
+	)
+
+	gc := NewMockGlobalCache()
+
+	ctx := makeContext(t, rp, nil, gc)
+	files, err := ctx.CommitFiles("e05fcae367230ee709313dd2720da527d178ce43")
+	require.NoError(t, err)
+	require.Len(t, files, 3, "incorrect number of files")
+	assert.Equal(t, 1, filesRule.Count, "no http request was made")
+
+	// a new context for the same repository uses the global cache
+	ctx = makeContext(t, rp, nil, gc)
+	files, err = ctx.CommitFiles("e05fcae367230ee709313dd2720da527d178ce43")
+	require.NoError(t, err)
+	require.Len(t, files, 3, "incorrect number of files")
+	assert.Equal(t, 1, filesRule.Count, "global cached files were not used")
+}
+
 func TestChangedFilesNoFiles(t *testing.T) {
 	rp := &ResponsePlayer{}
 	filesRule := rp.AddRule(
@@ -94,6 +155,125 @@ func TestChangedFilesNoFiles(t *testing.T) {
 	assert.Equal(t, 1, filesRule.Count, "cached files were not used")
 }
 
+func TestChangedFilesTooMany(t *testing.T) {
+	files := make([]map[string]interface{}, MaxPullRequestFiles)
+	for i := range files {
+		files[i] = map[string]interface{}{
+			"filename":  fmt.Sprintf("path/file%d.txt", i),
+			"status":    "modified",
+			"additions": 1,
+			"changes":   1,
+		}
+	}
+
+	rp := &ResponsePlayer{}
+	rp.AddRule(
+		ExactPathMatcher("/repos/testorg/testrepo/pulls/123/files"),
+		writeResponseFile(t, files),
+	)
+
+	ctx := makeContext(t, rp, nil, nil)
+
+	_, err := ctx.ChangedFiles()
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrTooManyFiles)
+
+	var tooMany *TooManyError
+	if assert.ErrorAs(t, err, &tooMany) {
+		assert.Equal(t, "files", tooMany.Resource)
+		assert.Equal(t, MaxPullRequestFiles, tooMany.Max)
+	}
+}
+
+func TestCommitsTooMany(t *testing.T) {
+	nodes := make([]map[string]interface{}, MaxPullRequestCommits)
+	for i := range nodes {
+		oid := fmt.Sprintf("%040x", i+1)
+		if i == len(nodes)-1 {
+			oid = "e05fcae367230ee709313dd2720da527d178ce43"
+		}
+		nodes[i] = map[string]interface{}{
+			"commit": map[string]interface{}{"oid": oid},
+		}
+	}
+
+	rp := &ResponsePlayer{}
+	rp.AddRule(
+		GraphQLNodePrefixMatcher("repository.pullRequest.commits"),
+		writeResponseFile(t, map[string]interface{}{
+			"data": map[string]interface{}{
+				"repository": map[string]interface{}{
+					"pullRequest": map[string]interface{}{
+						"commits": map[string]interface{}{
+							"pageInfo": map[string]interface{}{"hasNextPage": false},
+							"nodes":    nodes,
+						},
+					},
+				},
+			},
+		}),
+	)
+
+	ctx := makeContext(t, rp, nil, nil)
+
+	_, err := ctx.Commits()
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrTooManyCommits)
+
+	var tooMany *TooManyError
+	if assert.ErrorAs(t, err, &tooMany) {
+		assert.Equal(t, "commits", tooMany.Resource)
+		assert.Equal(t, MaxPullRequestCommits, tooMany.Max)
+	}
+}
+
+// writeResponseFile writes a response file for a ResponsePlayer that returns
+// the JSON encoding of body and returns the path to the file.
+func writeResponseFile(t *testing.T, body interface{}) string {
+	b, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	d, err := yaml.Marshal([]SavedResponse{{Status: http.StatusOK, Body: string(b)}})
+	require.NoError(t, err)
+
+	file := filepath.Join(t.TempDir(), "response.yml")
+	require.NoError(t, os.WriteFile(file, d, 0644))
+	return file
+}
+
+func TestChangedFilesBinary(t *testing.T) {
+	rp := &ResponsePlayer{}
+	rp.AddRule(
+		ExactPathMatcher("/repos/testorg/testrepo/pulls/123/files"),
+		"testdata/responses/pull_files_binary.yml",
+	)
+
+	ctx := makeContext(t, rp, nil, nil)
+
+	files, err := ctx.ChangedFiles()
+	require.NoError(t, err)
+
+	require.Len(t, files, 6, "incorrect number of files")
+
+	assert.Equal(t, "assets/logo.png", files[0].Filename)
+	assert.True(t, files[0].IsBinary, "file without a diff should be binary")
+
+	assert.Equal(t, "README.md", files[1].Filename)
+	assert.False(t, files[1].IsBinary, "file with a diff should not be binary")
+
+	assert.Equal(t, "assets/old.png", files[2].Filename)
+	assert.False(t, files[2].IsBinary, "old name of a renamed file should not be binary")
+
+	assert.Equal(t, "assets/new.png", files[3].Filename)
+	assert.False(t, files[3].IsBinary, "renamed file without line changes should not be binary")
+
+	assert.Equal(t, "docs/old.md", files[4].Filename)
+	assert.False(t, files[4].IsBinary, "old name of a renamed file should not be binary")
+
+	assert.Equal(t, "docs/new.md", files[5].Filename)
+	assert.False(t, files[5].IsBinary, "renamed text file should not be binary")
+}
+
 func TestCommits(t *testing.T) {
 	rp := &ResponsePlayer{}
 	dataRule := rp.AddRule(
@@ -112,16 +292,26 @@ func TestCommits(t *testing.T) {
 	assert.Equal(t, "a6f3f69b64eaafece5a0d854eb4af11c0d64394c", commits[0].SHA)
 	assert.Equal(t, "mhaypenny", commits[0].Author)
 	assert.Equal(t, "mhaypenny", commits[0].Committer)
+	assert.Equal(t, "mhaypenny@example.com", commits[0].AuthorEmail)
+	assert.Equal(t, "mhaypenny@example.com", commits[0].CommitterEmail)
 	assert.Nil(t, commits[0].Signature)
+	assert.Equal(t, time.Date(2020, 9, 30, 17, 30, 0, 0, time.UTC), commits[0].AuthoredAt)
+	assert.Equal(t, time.Date(2020, 9, 30, 17, 35, 0, 0, time.UTC), commits[0].CommittedAt)
+	assert.Equal(t, "PROJ-123: add feature\n\nLonger description.", commits[0].Message)
 
 	assert.Equal(t, "1fc89f1cedf8e3f3ce516ab75b5952295c8ea5e9", commits[1].SHA)
 	assert.Equal(t, "mhaypenny", commits[1].Author)
 	assert.Equal(t, "mhaypenny", commits[1].Committer)
+	assert.Equal(t, "mhaypenny@users.noreply.github.com", commits[1].AuthorEmail)
+	assert.Equal(t, "noreply@github.com", commits[1].CommitterEmail)
 	assert.Nil(t, commits[1].Signature)
 
 	assert.Equal(t, "e05fcae367230ee709313dd2720da527d178ce43", commits[2].SHA)
 	assert.Equal(t, "ttest", commits[2].Author)
 	assert.Equal(t, "mhaypenny", commits[2].Committer)
+	assert.Equal(t, "ttest@example.com", commits[2].AuthorEmail)
+	assert.Equal(t, "mhaypenny@example.com", commits[2].CommitterEmail)
+	assert.Equal(t, "Fix tests", commits[2].Message)
 
 	// verify that the signature was handled correctly
 	assert.NotNil(t, commits[2].Signature)
@@ -149,7 +339,7 @@ func TestReviews(t *testing.T) {
 	reviews, err := ctx.Reviews()
 	require.NoError(t, err)
 
-	require.Len(t, reviews, 3, "incorrect number of reviews")
+	require.Len(t, reviews, 4, "incorrect number of reviews")
 	assert.Equal(t, 2, dataRule.Count, "no http request was made")
 
 	expectedTime, err := time.Parse(time.RFC3339, "2018-06-27T20:33:26Z")
@@ -173,11 +363,14 @@ func TestReviews(t *testing.T) {
 	assert.Equal(t, ReviewCommented, reviews[2].State)
 	assert.Equal(t, "A review comment", reviews[2].Body)
 
+	assert.Equal(t, "ttest", reviews[3].Author)
+	assert.Equal(t, ReviewDismissed, reviews[3].State)
+
 	// verify that the review list is cached
 	reviews, err = ctx.Reviews()
 	require.NoError(t, err)
 
-	require.Len(t, reviews, 3, "incorrect number of reviews")
+	require.Len(t, reviews, 4, "incorrect number of reviews")
 	assert.Equal(t, 2, dataRule.Count, "cached reviews were not used")
 }
 
@@ -285,6 +478,43 @@ func TestNoComments(t *testing.T) {
 	assert.Equal(t, 1, dataRule.Count, "cached comments were not used")
 }
 
+func TestConcurrentCommentsAndReviews(t *testing.T) {
+	rp := &ResponsePlayer{}
+	dataRule := rp.AddRule(
+		GraphQLNodePrefixMatcher("repository.pullRequest.comments"),
+		"testdata/responses/pull_no_comments.yml",
+	)
+
+	ctx := makeContext(t, rp, nil, nil)
+
+	// Run with -race to detect unsynchronized access to the cached fields.
+	// All goroutines wait for start so that they access the context together.
+	start := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			<-start
+			comments, err := ctx.Comments()
+			assert.NoError(t, err)
+			assert.Empty(t, comments, "incorrect number of comments")
+		}()
+		go func() {
+			defer wg.Done()
+			<-start
+			reviews, err := ctx.Reviews()
+			assert.NoError(t, err)
+			assert.Empty(t, reviews, "incorrect number of reviews")
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	assert.Equal(t, 1, dataRule.Count, "comments and reviews were loaded more than once")
+}
+
 func TestIsTeamMember(t *testing.T) {
 	rp := &ResponsePlayer{}
 	yesRule1 := rp.AddRule(
@@ -397,6 +627,33 @@ func TestIsOrgMember(t *testing.T) {
 	assert.Equal(t, 1, yesRule.Count, "cached membership was not used")
 }
 
+func TestUserTeams(t *testing.T) {
+	rp := &ResponsePlayer{}
+	dataRule := rp.AddRule(
+		GraphQLVariableMatcher{Prefix: "organization.teams", Name: "user", Value: "mhaypenny"},
+		"testdata/responses/org_user_teams_mhaypenny.yml",
+	)
+
+	ctx := makeContext(t, rp, nil, nil)
+
+	teams, err := ctx.UserTeams("testorg", "mhaypenny")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"testorg/platform", "testorg/security"}, teams)
+	assert.Equal(t, 1, dataRule.Count, "no http request was made")
+
+	// verify that teams are cached and used for membership
+	teams, err = ctx.UserTeams("testorg", "mhaypenny")
+	require.NoError(t, err)
+	assert.Len(t, teams, 2)
+
+	isMember, err := ctx.IsTeamMember("testorg/security", "mhaypenny")
+	require.NoError(t, err)
+
+	assert.True(t, isMember, "user is not a member")
+	assert.Equal(t, 1, dataRule.Count, "cached teams were not used")
+}
+
 func TestBranches(t *testing.T) {
 	rp := &ResponsePlayer{}
 	ctx := makeContext(t, rp, nil, nil)
@@ -404,6 +661,10 @@ func TestBranches(t *testing.T) {
 	base, head := ctx.Branches()
 	assert.Equal(t, "develop", base, "base branch was not correctly set")
 	assert.Equal(t, "test-branch", head, "head branch was not correctly set")
+
+	owner, repo := ctx.HeadRepository()
+	assert.Equal(t, "testorg", owner, "head repository owner was not correctly set")
+	assert.Equal(t, "testrepo", repo, "head repository name was not correctly set")
 }
 
 func TestCrossRepoBranches(t *testing.T) {
@@ -424,6 +685,10 @@ func TestCrossRepoBranches(t *testing.T) {
 	base, head := ctx.Branches()
 	assert.Equal(t, "develop", base, "cross-repo base branch was not correctly set")
 	assert.Equal(t, "testorg2:test-branch", head, "cross-repo head branch was not correctly set")
+
+	owner, repo := ctx.HeadRepository()
+	assert.Equal(t, "testorg2", owner, "cross-repo head repository owner was not correctly set")
+	assert.Equal(t, "testrepofork", repo, "cross-repo head repository name was not correctly set")
 }
 
 func TestCollaboratorPermission(t *testing.T) {
@@ -458,81 +723,128 @@ func TestCollaboratorPermission(t *testing.T) {
 }
 
 func TestRepositoryCollaborators(t *testing.T) {
-	rp := &ResponsePlayer{}
-	rp.AddRule(
-		ExactPathMatcher("/repos/testorg/testrepo/teams"),
-		"testdata/responses/repo_teams.yml",
-	)
-	rp.AddRule(
-		ExactPathMatcher("/orgs/testorg/teams/maintainers/members"),
-		"testdata/responses/repo_team_members_maintainers.yml",
-	)
-	rp.AddRule(
-		ExactPathMatcher("/orgs/testorg/teams/admins/members"),
-		"testdata/responses/repo_team_members_admins.yml",
-	)
-	rp.AddRule(
-		GraphQLNodePrefixMatcher("repository.collaborators"),
-		"testdata/responses/repo_collaborators.yml",
-	)
-
-	ctx := makeContext(t, rp, nil, nil)
-
-	collaborators, err := ctx.RepositoryCollaborators()
-	require.NoError(t, err)
-
-	require.Len(t, collaborators, 8, "incorrect number of collaborators")
-	sort.Slice(collaborators, func(i, j int) bool { return collaborators[i].Name < collaborators[j].Name })
-
-	c0 := collaborators[0]
-	assert.Equal(t, "direct-admin", c0.Name)
-	assert.Equal(t, []CollaboratorPermission{
-		{Permission: PermissionAdmin, ViaRepo: true},
-	}, c0.Permissions)
-
-	c1 := collaborators[1]
-	assert.Equal(t, "direct-triage", c1.Name)
-	assert.Equal(t, []CollaboratorPermission{
-		{Permission: PermissionTriage, ViaRepo: true},
-	}, c1.Permissions)
+	makeResponsePlayer := func() (rp *ResponsePlayer, directRule *Rule, allRule *Rule) {
+		rp = &ResponsePlayer{}
+		rp.AddRule(
+			ExactPathMatcher("/repos/testorg/testrepo/teams"),
+			"testdata/responses/repo_teams.yml",
+		)
+		rp.AddRule(
+			ExactPathMatcher("/orgs/testorg/teams/maintainers/members"),
+			"testdata/responses/repo_team_members_maintainers.yml",
+		)
+		rp.AddRule(
+			ExactPathMatcher("/orgs/testorg/teams/admins/members"),
+			"testdata/responses/repo_team_members_admins.yml",
+		)
+		directRule = rp.AddRule(
+			GraphQLVariableMatcher{Prefix: "repository.collaborators", Name: "affiliation", Value: "DIRECT"},
+			"testdata/responses/repo_collaborators_direct.yml",
+		)
+		allRule = rp.AddRule(
+			GraphQLVariableMatcher{Prefix: "repository.collaborators", Name: "affiliation", Value: "ALL"},
+			"testdata/responses/repo_collaborators_all.yml",
+		)
+		return
+	}
 
-	c2 := collaborators[2]
-	assert.Equal(t, "direct-write-team-maintain", c2.Name)
-	assert.Equal(t, []CollaboratorPermission{
-		{Permission: PermissionMaintain, ViaRepo: true},
-		{Permission: PermissionWrite, ViaRepo: true},
-	}, c2.Permissions)
+	t.Run("all", func(t *testing.T) {
+		rp, directRule, allRule := makeResponsePlayer()
+		ctx := makeContext(t, rp, nil, nil)
 
-	c3 := collaborators[3]
-	assert.Equal(t, "org-owner", c3.Name)
-	assert.Equal(t, []CollaboratorPermission{
-		{Permission: PermissionAdmin, ViaRepo: false},
-	}, c3.Permissions)
+		collaborators, err := ctx.RepositoryCollaborators()
+		require.NoError(t, err)
 
-	c4 := collaborators[4]
-	assert.Equal(t, "org-owner-team-maintain", c4.Name)
-	assert.Equal(t, []CollaboratorPermission{
-		{Permission: PermissionAdmin, ViaRepo: false},
-		{Permission: PermissionMaintain, ViaRepo: true},
-	}, c4.Permissions)
+		assert.Equal(t, 1, directRule.Count, "incorrect http request count")
+		assert.Equal(t, 2, allRule.Count, "incorrect http request count")
+
+		require.Len(t, collaborators, 8, "incorrect number of collaborators")
+		sort.Slice(collaborators, func(i, j int) bool { return collaborators[i].Name < collaborators[j].Name })
+
+		c0 := collaborators[0]
+		assert.Equal(t, "direct-admin", c0.Name)
+		assert.Equal(t, []CollaboratorPermission{
+			{Permission: PermissionAdmin, ViaRepo: true},
+		}, c0.Permissions)
+
+		c1 := collaborators[1]
+		assert.Equal(t, "direct-triage", c1.Name)
+		assert.Equal(t, []CollaboratorPermission{
+			{Permission: PermissionTriage, ViaRepo: true},
+		}, c1.Permissions)
+
+		c2 := collaborators[2]
+		assert.Equal(t, "direct-write-team-maintain", c2.Name)
+		assert.Equal(t, []CollaboratorPermission{
+			{Permission: PermissionMaintain, ViaRepo: true},
+			{Permission: PermissionWrite, ViaRepo: true},
+		}, c2.Permissions)
+
+		c3 := collaborators[3]
+		assert.Equal(t, "org-owner", c3.Name)
+		assert.Equal(t, []CollaboratorPermission{
+			{Permission: PermissionAdmin, ViaRepo: false},
+		}, c3.Permissions)
+
+		c4 := collaborators[4]
+		assert.Equal(t, "org-owner-team-maintain", c4.Name)
+		assert.Equal(t, []CollaboratorPermission{
+			{Permission: PermissionAdmin, ViaRepo: false},
+			{Permission: PermissionMaintain, ViaRepo: true},
+		}, c4.Permissions)
+
+		c5 := collaborators[5]
+		assert.Equal(t, "org-read", c5.Name)
+		assert.Equal(t, []CollaboratorPermission{
+			{Permission: PermissionRead, ViaRepo: false},
+		}, c5.Permissions)
+
+		c6 := collaborators[6]
+		assert.Equal(t, "team-admin", c6.Name)
+		assert.Equal(t, []CollaboratorPermission{
+			{Permission: PermissionAdmin, ViaRepo: true},
+		}, c6.Permissions)
+
+		c7 := collaborators[7]
+		assert.Equal(t, "team-maintain", c7.Name)
+		assert.Equal(t, []CollaboratorPermission{
+			{Permission: PermissionMaintain, ViaRepo: true},
+		}, c7.Permissions)
+	})
 
-	c5 := collaborators[5]
-	assert.Equal(t, "org-read", c5.Name)
-	assert.Equal(t, []CollaboratorPermission{
-		{Permission: PermissionRead, ViaRepo: false},
-	}, c5.Permissions)
+	t.Run("directOnly", func(t *testing.T) {
+		rp, directRule, allRule := makeResponsePlayer()
+		ctx := makeContext(t, rp, nil, nil)
+		ctx.(*GitHubContext).UseDirectCollaboratorsOnly()
 
-	c6 := collaborators[6]
-	assert.Equal(t, "team-admin", c6.Name)
-	assert.Equal(t, []CollaboratorPermission{
-		{Permission: PermissionAdmin, ViaRepo: true},
-	}, c6.Permissions)
+		collaborators, err := ctx.RepositoryCollaborators()
+		require.NoError(t, err)
 
-	c7 := collaborators[7]
-	assert.Equal(t, "team-maintain", c7.Name)
-	assert.Equal(t, []CollaboratorPermission{
-		{Permission: PermissionMaintain, ViaRepo: true},
-	}, c7.Permissions)
+		assert.Equal(t, 1, directRule.Count, "incorrect http request count")
+		assert.Equal(t, 0, allRule.Count, "incorrect http request count")
+
+		assert.Equal(t, []*Collaborator{
+			{Name: "direct-admin", Permissions: []CollaboratorPermission{
+				{Permission: PermissionAdmin, ViaRepo: true},
+			}},
+			{Name: "direct-triage", Permissions: []CollaboratorPermission{
+				{Permission: PermissionTriage, ViaRepo: true},
+			}},
+			{Name: "direct-write-team-maintain", Permissions: []CollaboratorPermission{
+				{Permission: PermissionMaintain, ViaRepo: true},
+				{Permission: PermissionWrite, ViaRepo: true},
+			}},
+			{Name: "org-owner-team-maintain", Permissions: []CollaboratorPermission{
+				{Permission: PermissionMaintain, ViaRepo: true},
+			}},
+			{Name: "team-admin", Permissions: []CollaboratorPermission{
+				{Permission: PermissionAdmin, ViaRepo: true},
+			}},
+			{Name: "team-maintain", Permissions: []CollaboratorPermission{
+				{Permission: PermissionMaintain, ViaRepo: true},
+			}},
+		}, collaborators)
+	})
 }
 
 func TestPushedAt(t *testing.T) {
@@ -611,6 +923,118 @@ func TestPushedAt(t *testing.T) {
 	})
 }
 
+func TestConcurrentAccess(t *testing.T) {
+	rp := &ResponsePlayer{}
+	filesRule := rp.AddRule(
+		ExactPathMatcher("/repos/testorg/testrepo/pulls/123/files"),
+		"testdata/responses/pull_files.yml",
+	)
+	commitsRule := rp.AddRule(
+		GraphQLNodePrefixMatcher("repository.pullRequest.commits"),
+		"testdata/responses/pull_commits.yml",
+	)
+	rp.AddRule(
+		ExactPathMatcher("/repos/testorg/testrepo/commits/e05fcae367230ee709313dd2720da527d178ce43/statuses"),
+		"testdata/responses/repo_statuses_e05fcae367230ee709313dd2720da527d178ce43.yml",
+	)
+	rp.AddRule(
+		ExactPathMatcher("/orgs/testorg/teams/yes-team/memberships/mhaypenny"),
+		"testdata/responses/membership_team123_mhaypenny.yml",
+	)
+
+	ctx := makeContext(t, rp, nil, nil)
+
+	// Run with -race to detect unsynchronized access to the cached fields.
+	// All goroutines wait for start so that they access the context together.
+	start := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(4)
+		go func() {
+			defer wg.Done()
+			<-start
+			files, err := ctx.ChangedFiles()
+			assert.NoError(t, err)
+			assert.Len(t, files, 5, "incorrect number of files")
+		}()
+		go func() {
+			defer wg.Done()
+			<-start
+			commits, err := ctx.Commits()
+			assert.NoError(t, err)
+			assert.Len(t, commits, 3, "incorrect number of commits")
+		}()
+		go func() {
+			defer wg.Done()
+			<-start
+			pushedAt, err := ctx.PushedAt("e05fcae367230ee709313dd2720da527d178ce43")
+			assert.NoError(t, err)
+			assert.Equal(t, time.Date(2020, 9, 30, 17, 30, 0, 0, time.UTC), pushedAt, "incorrect pushed at for commit")
+		}()
+		go func() {
+			defer wg.Done()
+			<-start
+			isMember, err := ctx.IsTeamMember("testorg/yes-team", "mhaypenny")
+			assert.NoError(t, err)
+			assert.True(t, isMember, "user is not a member")
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	assert.Equal(t, 2, filesRule.Count, "files were loaded more than once")
+	assert.Equal(t, 2, commitsRule.Count, "commits were loaded more than once")
+}
+
+func TestPushedAtWithoutBatching(t *testing.T) {
+	rp := &ResponsePlayer{}
+	commitsRule := rp.AddRule(
+		GraphQLNodePrefixMatcher("repository.pullRequest.commits"),
+		"testdata/responses/pull_commits.yml",
+	)
+	statusRuleA6F := rp.AddRule(
+		ExactPathMatcher("/repos/testorg/testrepo/commits/a6f3f69b64eaafece5a0d854eb4af11c0d64394c/statuses"),
+		"testdata/responses/repo_statuses_none.yml",
+	)
+	statusRule1FC := rp.AddRule(
+		ExactPathMatcher("/repos/testorg/testrepo/commits/1fc89f1cedf8e3f3ce516ab75b5952295c8ea5e9/statuses"),
+		"testdata/responses/repo_statuses_none.yml",
+	)
+	statusRuleE05 := rp.AddRule(
+		ExactPathMatcher("/repos/testorg/testrepo/commits/e05fcae367230ee709313dd2720da527d178ce43/statuses"),
+		"testdata/responses/repo_statuses_e05fcae367230ee709313dd2720da527d178ce43.yml",
+	)
+
+	expectedTime := time.Date(2020, 9, 30, 17, 30, 0, 0, time.UTC)
+
+	gc := NewMockGlobalCache()
+	ctx := makeContext(t, rp, nil, gc)
+	ctx.(*GitHubContext).DisablePushBatching()
+
+	t.Run("fromStatus", func(t *testing.T) {
+		pushedAt, err := ctx.PushedAt("e05fcae367230ee709313dd2720da527d178ce43")
+		require.NoError(t, err)
+
+		assert.Equal(t, expectedTime, pushedAt, "incorrect pushed at for commit")
+		assert.Equal(t, 3, statusRuleE05.Count, "incorrect http request count")
+	})
+
+	t.Run("fromEvaluationTimestamp", func(t *testing.T) {
+		pushedAt, err := ctx.PushedAt("a6f3f69b64eaafece5a0d854eb4af11c0d64394c")
+		require.NoError(t, err)
+
+		assert.Equal(t, ctx.EvaluationTimestamp(), pushedAt, "incorrect pushed at for commit")
+		assert.Equal(t, 0, commitsRule.Count, "incorrect http request count")
+		assert.Equal(t, 1, statusRuleA6F.Count, "incorrect http request count")
+		assert.Equal(t, 0, statusRule1FC.Count, "incorrect http request count")
+		assert.Equal(t, 3, statusRuleE05.Count, "incorrect http request count")
+
+		_, cached := gc.PushedAt["1234:1fc89f1cedf8e3f3ce516ab75b5952295c8ea5e9"]
+		assert.False(t, cached, "unrelated commit was stored in global cache")
+	})
+}
+
 func TestLatestWorkflowRunsNoRuns(t *testing.T) {
 	rp := &ResponsePlayer{}
 	noRunsRule := rp.AddRule(
@@ -637,11 +1061,53 @@ func TestLatestWorkflowRuns(t *testing.T) {
 	runs, err := ctx.LatestWorkflowRuns()
 	require.NoError(t, err)
 
-	assert.Len(t, runs, 3, "incorrect number of workflow runs")
+	assert.Len(t, runs, 4, "incorrect number of workflow runs")
 	assert.ElementsMatch(t, runs[".github/workflows/a.yml"], []string{"success", "skipped"}, "incorrect conclusion for workflow run a")
 	assert.ElementsMatch(t, runs[".github/workflows/b.yml"], []string{"failure"}, "incorrect conclusion for workflow run b")
 	assert.ElementsMatch(t, runs[".github/workflows/c.yml"], []string{"cancelled"}, "incorrect conclusion for workflow run c")
+	assert.ElementsMatch(t, runs["Build"], []string{"success", "skipped", "cancelled"}, "incorrect conclusion for workflows named Build")
+	assert.NotContains(t, runs, "Deploy", "incomplete workflow runs should be ignored")
 	assert.Equal(t, 2, runsRule.Count, "incorrect http request count")
+
+	// verify that the runs are cached
+	_, err = ctx.LatestWorkflowRuns()
+	require.NoError(t, err)
+	assert.Equal(t, 2, runsRule.Count, "cached workflow runs were not used")
+}
+
+func TestLatestDeployments(t *testing.T) {
+	rp := &ResponsePlayer{}
+	deploymentsRule := rp.AddRule(
+		ExactPathMatcher("/repos/testorg/testrepo/deployments"),
+		"testdata/responses/pull_deployments.yml",
+	)
+	for _, id := range []string{"2", "3", "4", "5"} {
+		rp.AddRule(
+			ExactPathMatcher("/repos/testorg/testrepo/deployments/"+id+"/statuses"),
+			"testdata/responses/pull_deployment_"+id+"_statuses.yml",
+		)
+	}
+	oldStatusesRule := rp.AddRule(
+		ExactPathMatcher("/repos/testorg/testrepo/deployments/1/statuses"),
+		"testdata/responses/pull_deployment_1_statuses.yml",
+	)
+
+	ctx := makeContext(t, rp, nil, nil)
+	deployments, err := ctx.LatestDeployments()
+	require.NoError(t, err)
+
+	assert.Len(t, deployments, 4, "incorrect number of deployments")
+	assert.Equal(t, "success", deployments["staging"], "incorrect state for 'staging' deployment")
+	assert.Equal(t, "failure", deployments["production"], "incorrect state for 'production' deployment")
+	assert.Equal(t, "in_progress", deployments["qa"], "incorrect state for 'qa' deployment")
+	assert.Equal(t, "pending", deployments["preview"], "incorrect state for 'preview' deployment")
+	assert.Equal(t, 0, oldStatusesRule.Count, "statuses of older deployments should not be requested")
+	assert.Equal(t, 1, deploymentsRule.Count, "incorrect http request count")
+
+	// verify that the deployments are cached
+	_, err = ctx.LatestDeployments()
+	require.NoError(t, err)
+	assert.Equal(t, 1, deploymentsRule.Count, "cached deployments were not used")
 }
 
 func TestLatestStatuses(t *testing.T) {
@@ -661,11 +1127,476 @@ func TestLatestStatuses(t *testing.T) {
 	statuses, err := ctx.LatestStatuses()
 	require.NoError(t, err)
 
-	assert.Len(t, statuses, 4, "incorrect number of statuses")
+	assert.Len(t, statuses, 5, "incorrect number of statuses")
 	assert.Equal(t, statuses["commit-status-a"], "success", "incorrect conclusion for 'commit-status-a' status")
 	assert.Equal(t, statuses["commit-status-b"], "pending", "incorrect conclusion for 'commit-status-a' status")
 	assert.Equal(t, statuses["check-run-a"], "success", "incorrect conclusion for 'check-run-a' status")
 	assert.Equal(t, statuses["check-run-b"], "failure", "incorrect conclusion for 'check-run-b' status")
+	assert.Equal(t, statuses["check-run-c"], "in_progress", "incorrect conclusion for 'check-run-c' status")
+}
+
+func TestLatestStatusesGlobalCache(t *testing.T) {
+	pr := defaultTestPR()
+
+	rp := &ResponsePlayer{}
+	statusRule := rp.AddRule(
+		ExactPathMatcher("/repos/testorg/testrepo/commits/"+pr.Head.GetSHA()+"/status"),
+		"testdata/responses/combined_status_for_ref.yml",
+	)
+	checkRunRule := rp.AddRule(
+		ExactPathMatcher("/repos/testorg/testrepo/commits/"+pr.Head.GetSHA()+"/check-runs"),
+		"testdata/responses/check_runs_for_ref.yml",
+	)
+
+	gc := NewMockGlobalCache()
+
+	t.Run("fromAPI", func(t *testing.T) {
+		ctx := makeContext(t, rp, pr, gc)
+
+		statuses, err := ctx.LatestStatuses()
+		require.NoError(t, err)
+
+		assert.Len(t, statuses, 5, "incorrect number of statuses")
+		assert.Equal(t, 1, statusRule.Count, "incorrect http request count")
+		assert.Equal(t, 1, checkRunRule.Count, "incorrect http request count")
+		assert.Equal(t, statuses, gc.Statuses["1234:"+pr.Head.GetSHA()], "incorrect value in global cache")
+	})
+
+	t.Run("fromGlobalCache", func(t *testing.T) {
+		ctx := makeContext(t, rp, pr, gc)
+
+		statuses, err := ctx.LatestStatuses()
+		require.NoError(t, err)
+
+		assert.Len(t, statuses, 5, "incorrect number of statuses")
+		assert.Equal(t, 1, statusRule.Count, "incorrect http request count")
+		assert.Equal(t, 1, checkRunRule.Count, "incorrect http request count")
+	})
+
+	t.Run("afterDelete", func(t *testing.T) {
+		gc.DeleteStatuses(1234, pr.Head.GetSHA())
+		ctx := makeContext(t, rp, pr, gc)
+
+		statuses, err := ctx.LatestStatuses()
+		require.NoError(t, err)
+
+		assert.Len(t, statuses, 5, "incorrect number of statuses")
+		assert.Equal(t, 2, statusRule.Count, "incorrect http request count")
+		assert.Equal(t, 2, checkRunRule.Count, "incorrect http request count")
+	})
+}
+
+func TestLatestStatusesDuplicateCheckRuns(t *testing.T) {
+	pr := defaultTestPR()
+
+	rp := &ResponsePlayer{}
+	rp.AddRule(
+		ExactPathMatcher("/repos/testorg/testrepo/commits/"+pr.Head.GetSHA()+"/status"),
+		"testdata/responses/combined_status_for_ref.yml",
+	)
+	checksRule := rp.AddRule(
+		ExactPathMatcher("/repos/testorg/testrepo/commits/"+pr.Head.GetSHA()+"/check-runs"),
+		"testdata/responses/check_runs_for_ref_duplicates.yml",
+	)
+
+	ctx := makeContext(t, rp, pr, nil)
+	statuses, err := ctx.LatestStatuses()
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, checksRule.Count, "incorrect number of check run requests")
+	assert.Equal(t, "success", statuses["check-run-a"], "newer run on a later page did not replace older run")
+	assert.Equal(t, "success", statuses["check-run-b"], "older run on a later page replaced newer run")
+	assert.Equal(t, "in_progress", statuses["check-run-c"], "incomplete run did not replace completed run with the same start time")
+}
+
+func TestHeadCommitVerification(t *testing.T) {
+	pr := defaultTestPR()
+
+	rp := &ResponsePlayer{}
+	commitRule := rp.AddRule(
+		ExactPathMatcher("/repos/testorg/testrepo/git/commits/"+pr.Head.GetSHA()),
+		"testdata/responses/git_commit_verified.yml",
+	)
+
+	ctx := makeContext(t, rp, pr, nil)
+	verification, err := ctx.HeadCommitVerification()
+	require.NoError(t, err)
+
+	assert.True(t, verification.Verified, "commit should be verified")
+	assert.Equal(t, "valid", verification.Reason, "incorrect verification reason")
+
+	// verify that the commit is cached
+	_, err = ctx.HeadCommitVerification()
+	require.NoError(t, err)
+	assert.Equal(t, 1, commitRule.Count, "cached commit was not used")
+}
+
+func TestBaseFileContent(t *testing.T) {
+	rp := &ResponsePlayer{}
+	fileRule := rp.AddRule(
+		ExactPathMatcher("/repos/testorg/testrepo/contents/MAINTAINERS"),
+		"testdata/responses/repo_contents_maintainers.yml",
+	)
+
+	ctx := makeContext(t, rp, defaultTestPR(), nil)
+
+	content, exists, err := ctx.BaseFileContent("MAINTAINERS")
+	require.NoError(t, err)
+
+	assert.True(t, exists, "file should exist")
+	assert.Equal(t, "# maintainers\nmhaypenny\ntestorg/devtools\n", content)
+
+	// verify that the file is cached
+	_, _, err = ctx.BaseFileContent("MAINTAINERS")
+	require.NoError(t, err)
+	assert.Equal(t, 1, fileRule.Count, "cached file was not used")
+
+	// missing files do not exist
+	content, exists, err = ctx.BaseFileContent("OWNERS")
+	require.NoError(t, err)
+
+	assert.False(t, exists, "file should not exist")
+	assert.Empty(t, content)
+}
+
+func TestFileContent(t *testing.T) {
+	rp := &ResponsePlayer{}
+	fileRule := rp.AddRule(
+		ExactPathMatcher("/repos/testorg/testrepo/contents/MAINTAINERS"),
+		"testdata/responses/repo_contents_maintainers.yml",
+	)
+	rp.AddRule(
+		ExactPathMatcher("/repos/testorg/testrepo/contents/assets/large.bin"),
+		"testdata/responses/repo_contents_large.yml",
+	)
+
+	ctx := makeContext(t, rp, defaultTestPR(), nil)
+
+	content, exists, err := ctx.FileContent("MAINTAINERS", "e05fcae367230ee709313dd2720da527d178ce43")
+	require.NoError(t, err)
+
+	assert.True(t, exists, "file should exist")
+	assert.Equal(t, "# maintainers\nmhaypenny\ntestorg/devtools\n", content)
+
+	// verify that the file is cached per ref
+	_, _, err = ctx.FileContent("MAINTAINERS", "e05fcae367230ee709313dd2720da527d178ce43")
+	require.NoError(t, err)
+	assert.Equal(t, 1, fileRule.Count, "cached file was not used")
+
+	_, _, err = ctx.FileContent("MAINTAINERS", "develop")
+	require.NoError(t, err)
+	assert.Equal(t, 2, fileRule.Count, "file was not requested for a different ref")
+
+	// large files exist, but have no content
+	_, exists, err = ctx.FileContent("assets/large.bin", "e05fcae367230ee709313dd2720da527d178ce43")
+	assert.True(t, exists, "file should exist")
+	assert.ErrorIs(t, err, ErrFileTooLarge)
+}
+
+func TestLabelAppliers(t *testing.T) {
+	rp := &ResponsePlayer{}
+	dataRule := rp.AddRule(
+		GraphQLNodePrefixMatcher("repository.pullRequest.timelineItems"),
+		"testdata/responses/pull_labeled_events.yml",
+	)
+
+	ctx := makeContext(t, rp, nil, nil)
+
+	appliers, err := ctx.LabelAppliers()
+	require.NoError(t, err)
+
+	expected := map[string]string{
+		"security-approved": "security-bot[bot]",
+		"needs-review":      "ttest",
+	}
+	assert.Equal(t, expected, appliers)
+	assert.Equal(t, 2, dataRule.Count, "no http request was made")
+
+	// verify that the appliers are cached
+	_, err = ctx.LabelAppliers()
+	require.NoError(t, err)
+	assert.Equal(t, 2, dataRule.Count, "cached appliers were not used")
+}
+
+func TestReopenedAt(t *testing.T) {
+	rp := &ResponsePlayer{}
+	dataRule := rp.AddRule(
+		GraphQLNodePrefixMatcher("repository.pullRequest.timelineItems"),
+		"testdata/responses/pull_reopened_events.yml",
+	)
+
+	ctx := makeContext(t, rp, nil, nil)
+
+	reopenedAt, err := ctx.ReopenedAt()
+	require.NoError(t, err)
+
+	expectedTime, err := time.Parse(time.RFC3339, "2018-06-29T18:04:12Z")
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedTime, reopenedAt)
+	assert.Equal(t, 1, dataRule.Count, "no http request was made")
+
+	// verify that the time is cached
+	_, err = ctx.ReopenedAt()
+	require.NoError(t, err)
+	assert.Equal(t, 1, dataRule.Count, "cached reopened time was not used")
+}
+
+func TestForcePushedAt(t *testing.T) {
+	rp := &ResponsePlayer{}
+	dataRule := rp.AddRule(
+		GraphQLNodePrefixMatcher("repository.pullRequest.timelineItems"),
+		"testdata/responses/pull_force_pushed_events.yml",
+	)
+
+	ctx := makeContext(t, rp, nil, nil)
+
+	forcePushedAt, err := ctx.ForcePushedAt()
+	require.NoError(t, err)
+
+	expectedTime, err := time.Parse(time.RFC3339, "2018-06-29T19:15:43Z")
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedTime, forcePushedAt)
+	assert.Equal(t, 1, dataRule.Count, "no http request was made")
+
+	// verify that the time is cached
+	_, err = ctx.ForcePushedAt()
+	require.NoError(t, err)
+	assert.Equal(t, 1, dataRule.Count, "cached force-push time was not used")
+}
+
+func TestForcePushedAtNeverForcePushed(t *testing.T) {
+	rp := &ResponsePlayer{}
+	dataRule := rp.AddRule(
+		GraphQLNodePrefixMatcher("repository.pullRequest.timelineItems"),
+		"testdata/responses/pull_no_reopened_events.yml",
+	)
+
+	ctx := makeContext(t, rp, nil, nil)
+
+	forcePushedAt, err := ctx.ForcePushedAt()
+	require.NoError(t, err)
+	assert.True(t, forcePushedAt.IsZero(), "pull request was never force-pushed")
+	assert.Equal(t, 1, dataRule.Count, "no http request was made")
+}
+
+func TestAuthorCreatedAt(t *testing.T) {
+	rp := &ResponsePlayer{}
+	dataRule := rp.AddRule(
+		GraphQLNodePrefixMatcher("user"),
+		"testdata/responses/user_created_at.yml",
+	)
+
+	ctx := makeContext(t, rp, nil, nil)
+
+	createdAt, err := ctx.AuthorCreatedAt()
+	require.NoError(t, err)
+
+	expectedTime, err := time.Parse(time.RFC3339, "2011-03-18T22:54:02Z")
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedTime, createdAt)
+	assert.Equal(t, 1, dataRule.Count, "no http request was made")
+
+	// verify that the time is cached
+	_, err = ctx.AuthorCreatedAt()
+	require.NoError(t, err)
+	assert.Equal(t, 1, dataRule.Count, "cached creation time was not used")
+}
+
+func TestIsProtectedBranch(t *testing.T) {
+	rp := &ResponsePlayer{}
+	protectedRule := rp.AddRule(
+		ExactPathMatcher("/repos/testorg/testrepo/branches/develop/protection"),
+		"testdata/responses/repo_branch_protection.yml",
+	)
+	unprotectedRule := rp.AddRule(
+		ExactPathMatcher("/repos/testorg/testrepo/branches/feature/protection"),
+		"testdata/responses/repo_branch_not_protected.yml",
+	)
+
+	ctx := makeContext(t, rp, nil, nil)
+
+	protected, err := ctx.IsProtectedBranch("develop")
+	require.NoError(t, err)
+	assert.True(t, protected, "develop should be protected")
+	assert.Equal(t, 1, protectedRule.Count, "no http request was made")
+
+	protected, err = ctx.IsProtectedBranch("feature")
+	require.NoError(t, err)
+	assert.False(t, protected, "feature should not be protected")
+	assert.Equal(t, 1, unprotectedRule.Count, "no http request was made")
+
+	// verify that the results are cached
+	_, err = ctx.IsProtectedBranch("develop")
+	require.NoError(t, err)
+	_, err = ctx.IsProtectedBranch("feature")
+	require.NoError(t, err)
+	assert.Equal(t, 1, protectedRule.Count, "cached protection was not used")
+	assert.Equal(t, 1, unprotectedRule.Count, "cached protection was not used")
+}
+
+func TestReopenedAtNeverReopened(t *testing.T) {
+	rp := &ResponsePlayer{}
+	dataRule := rp.AddRule(
+		GraphQLNodePrefixMatcher("repository.pullRequest.timelineItems"),
+		"testdata/responses/pull_no_reopened_events.yml",
+	)
+
+	ctx := makeContext(t, rp, nil, nil)
+
+	reopenedAt, err := ctx.ReopenedAt()
+	require.NoError(t, err)
+	assert.True(t, reopenedAt.IsZero(), "pull request was never reopened")
+
+	// verify that the zero time is cached
+	_, err = ctx.ReopenedAt()
+	require.NoError(t, err)
+	assert.Equal(t, 1, dataRule.Count, "cached reopened time was not used")
+}
+
+func TestPendingReviewRequests(t *testing.T) {
+	rp := &ResponsePlayer{}
+	dataRule := rp.AddRule(
+		GraphQLNodePrefixMatcher("search"),
+		"testdata/responses/search_pending_review_requests.yml",
+	)
+
+	ctx := makeContext(t, rp, nil, nil)
+
+	counts, err := ctx.PendingReviewRequests([]string{"mhaypenny", "alice", "mhaypenny"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"mhaypenny": 4, "alice": 0}, counts)
+	assert.Equal(t, 1, dataRule.Count, "counts were not loaded with one http request")
+
+	// verify that the counts are cached
+	counts, err = ctx.PendingReviewRequests([]string{"alice"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"alice": 0}, counts)
+	assert.Equal(t, 1, dataRule.Count, "cached counts were not used")
+}
+
+func TestAutoMerge(t *testing.T) {
+	rp := &ResponsePlayer{}
+
+	ctx := makeContext(t, rp, nil, nil)
+	assert.Nil(t, ctx.AutoMerge(), "auto-merge is not enabled")
+
+	pr := defaultTestPR()
+	pr.AutoMerge = &github.PullRequestAutoMerge{
+		EnabledBy:   &github.User{Login: github.String("mhaypenny")},
+		MergeMethod: github.String("squash"),
+	}
+
+	ctx = makeContext(t, rp, pr, nil)
+	assert.Equal(t, &AutoMerge{EnabledBy: "mhaypenny", MergeMethod: "squash"}, ctx.AutoMerge())
+}
+
+func TestRequestedReviewers(t *testing.T) {
+	rp := &ResponsePlayer{}
+	dataRule := rp.AddRule(
+		GraphQLNodePrefixMatcher("repository.pullRequest.reviewRequests"),
+		"testdata/responses/pull_requested_reviewers.yml",
+	)
+
+	ctx := makeContext(t, rp, nil, nil)
+
+	reviewers, err := ctx.RequestedReviewers()
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, dataRule.Count, "incorrect http request count")
+	assert.Equal(t, []*Reviewer{
+		{Type: ReviewerUser, Name: "ttest"},
+		{Type: ReviewerTeam, Name: "team-maintainers", Removed: true, RemovedBy: "mhaypenny"},
+		{Type: ReviewerUser, Name: "bkeyes", Removed: true, RemovedBy: "policy-bot[bot]"},
+	}, reviewers)
+
+	// verify that the reviewers are cached
+	_, err = ctx.RequestedReviewers()
+	require.NoError(t, err)
+	assert.Equal(t, 1, dataRule.Count, "cached reviewers were not used")
+}
+
+func TestReviewRequests(t *testing.T) {
+	rp := &ResponsePlayer{}
+	dataRule := rp.AddRule(
+		GraphQLNodePrefixMatcher("repository.pullRequest.timelineItems"),
+		"testdata/responses/pull_review_request_events.yml",
+	)
+
+	ctx := makeContext(t, rp, nil, nil)
+
+	requests, err := ctx.ReviewRequests()
+	require.NoError(t, err)
+
+	require.Len(t, requests, 3, "incorrect number of review requests")
+	assert.Equal(t, 2, dataRule.Count, "no http request was made")
+
+	parseTime := func(s string) time.Time {
+		ts, err := time.Parse(time.RFC3339, s)
+		require.NoError(t, err)
+		return ts
+	}
+
+	assert.Equal(t, &ReviewRequest{
+		Type:        ReviewerUser,
+		Name:        "mhaypenny",
+		RequestedAt: parseTime("2018-06-27T20:28:22Z"),
+		RemovedAt:   parseTime("2018-06-27T21:00:00Z"),
+	}, requests[0])
+
+	assert.Equal(t, &ReviewRequest{
+		Type:        ReviewerTeam,
+		Name:        "team-maintainers",
+		RequestedAt: parseTime("2018-06-27T20:28:22Z"),
+	}, requests[1])
+
+	assert.Equal(t, &ReviewRequest{
+		Type:        ReviewerUser,
+		Name:        "ttest",
+		RequestedAt: parseTime("2018-06-28T10:00:00Z"),
+	}, requests[2], "re-requesting a review did not replace the removed request")
+
+	// verify that the requests are cached
+	_, err = ctx.ReviewRequests()
+	require.NoError(t, err)
+	assert.Equal(t, 2, dataRule.Count, "cached review requests were not used")
+}
+
+func TestReviewThreads(t *testing.T) {
+	rp := &ResponsePlayer{}
+	dataRule := rp.AddRule(
+		GraphQLNodePrefixMatcher("repository.pullRequest.reviewThreads"),
+		"testdata/responses/pull_review_threads.yml",
+	)
+
+	ctx := makeContext(t, rp, nil, nil)
+
+	threads, err := ctx.ReviewThreads()
+	require.NoError(t, err)
+
+	require.Len(t, threads, 2, "incorrect number of review threads")
+	assert.Equal(t, 2, dataRule.Count, "no http request was made")
+
+	expectedTime, err := time.Parse(time.RFC3339, "2018-06-27T20:33:26Z")
+	require.NoError(t, err)
+
+	assert.Equal(t, "app/client.go", threads[0].Path)
+	assert.True(t, threads[0].IsResolved)
+	assert.False(t, threads[0].IsOutdated)
+	assert.Equal(t, "mhaypenny", threads[0].Author)
+	assert.Equal(t, expectedTime, threads[0].CreatedAt)
+
+	assert.Equal(t, "app/server.go", threads[1].Path)
+	assert.False(t, threads[1].IsResolved)
+	assert.True(t, threads[1].IsOutdated)
+	assert.Equal(t, "ttest", threads[1].Author)
+
+	// verify that the threads are cached
+	_, err = ctx.ReviewThreads()
+	require.NoError(t, err)
+	assert.Equal(t, 2, dataRule.Count, "cached review threads were not used")
 }
 
 func makeContext(t *testing.T, rp *ResponsePlayer, pr *github.PullRequest, gc GlobalCache) Context {
@@ -676,7 +1607,7 @@ func makeContext(t *testing.T, rp *ResponsePlayer, pr *github.PullRequest, gc Gl
 	base, _ := url.Parse("http://github.localhost/")
 	client.BaseURL = base
 
-	mbrCtx := NewGitHubMembershipContext(ctx, client)
+	mbrCtx := NewGitHubMembershipContext(ctx, client, v4client)
 	if pr == nil {
 		pr = defaultTestPR()
 	}
@@ -731,12 +1662,18 @@ func newTime(t time.Time) *time.Time {
 }
 
 type MockGlobalCache struct {
-	PushedAt map[string]time.Time
+	PushedAt    map[string]time.Time
+	RuleResults map[string]interface{}
+	CommitFiles map[string][]*File
+	Statuses    map[string]map[string]string
 }
 
 func NewMockGlobalCache() *MockGlobalCache {
 	return &MockGlobalCache{
-		PushedAt: make(map[string]time.Time),
+		PushedAt:    make(map[string]time.Time),
+		RuleResults: make(map[string]interface{}),
+		CommitFiles: make(map[string][]*File),
+		Statuses:    make(map[string]map[string]string),
 	}
 }
 
@@ -748,3 +1685,34 @@ func (c *MockGlobalCache) GetPushedAt(repoID int64, sha string) (time.Time, bool
 func (c *MockGlobalCache) SetPushedAt(repoID int64, sha string, t time.Time) {
 	c.PushedAt[fmt.Sprintf("%d:%s", repoID, sha)] = t
 }
+
+func (c *MockGlobalCache) GetRuleResult(key string) (interface{}, bool) {
+	r, ok := c.RuleResults[key]
+	return r, ok
+}
+
+func (c *MockGlobalCache) SetRuleResult(key string, result interface{}) {
+	c.RuleResults[key] = result
+}
+
+func (c *MockGlobalCache) GetCommitFiles(repoID int64, sha string) ([]*File, bool) {
+	f, ok := c.CommitFiles[fmt.Sprintf("%d:%s", repoID, sha)]
+	return f, ok
+}
+
+func (c *MockGlobalCache) SetCommitFiles(repoID int64, sha string, files []*File) {
+	c.CommitFiles[fmt.Sprintf("%d:%s", repoID, sha)] = files
+}
+
+func (c *MockGlobalCache) GetStatuses(repoID int64, sha string) (map[string]string, bool) {
+	s, ok := c.Statuses[fmt.Sprintf("%d:%s", repoID, sha)]
+	return s, ok
+}
+
+func (c *MockGlobalCache) SetStatuses(repoID int64, sha string, statuses map[string]string, loadedAt time.Time) {
+	c.Statuses[fmt.Sprintf("%d:%s", repoID, sha)] = statuses
+}
+
+func (c *MockGlobalCache) DeleteStatuses(repoID int64, sha string) {
+	delete(c.Statuses, fmt.Sprintf("%d:%s", repoID, sha))
+}
@@ -16,6 +16,7 @@ package pull
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	lru "github.com/hashicorp/golang-lru"
@@ -28,21 +29,78 @@ import (
 type GlobalCache interface {
 	GetPushedAt(repoID int64, sha string) (time.Time, bool)
 	SetPushedAt(repoID int64, sha string, t time.Time)
+
+	// GetRuleResult and SetRuleResult store the results of approval rules.
+	// The key identifies all of the inputs to the rule, so a result never
+	// becomes stale for its key. The result value is opaque to the cache.
+	GetRuleResult(key string) (interface{}, bool)
+	SetRuleResult(key string, result interface{})
+
+	// GetCommitFiles and SetCommitFiles store the files changed by a commit.
+	// The returned slice must not be modified.
+	GetCommitFiles(repoID int64, sha string) ([]*File, bool)
+	SetCommitFiles(repoID int64, sha string, files []*File)
+
+	// GetStatuses and SetStatuses store the latest statuses and check run
+	// conclusions for a commit. Unlike other values, statuses change when new
+	// statuses are posted for the same commit, so DeleteStatuses must be
+	// called whenever a status or check run for the commit changes.
+	// SetStatuses does not store statuses that started loading at loadedAt if
+	// DeleteStatuses was called for the commit after that time. The returned
+	// map must not be modified.
+	GetStatuses(repoID int64, sha string) (map[string]string, bool)
+	SetStatuses(repoID int64, sha string, statuses map[string]string, loadedAt time.Time)
+	DeleteStatuses(repoID int64, sha string)
 }
 
 // LRUGlobalCache is a GlobalCache where each data type is stored in a separate
 // LRU cache. This prevents frequently used data of one type from evicting less
 // frequently used data of a different type.
 type LRUGlobalCache struct {
-	pushedAt *lru.Cache
+	pushedAt    *lru.Cache
+	ruleResults *lru.Cache
+	commitFiles *lru.Cache
+
+	statusMu sync.Mutex
+	statuses *lru.Cache
+}
+
+// statusEntry is a value in the statuses cache. An entry with nil statuses
+// records when the statuses of a commit were deleted.
+type statusEntry struct {
+	statuses  map[string]string
+	deletedAt time.Time
 }
 
-func NewLRUGlobalCache(pushedAtSize int) (*LRUGlobalCache, error) {
+// NewLRUGlobalCache creates a cache with the given size for each data type.
+// Statuses are not cached if statusSize is zero.
+func NewLRUGlobalCache(pushedAtSize, ruleResultSize, commitFilesSize, statusSize int) (*LRUGlobalCache, error) {
 	pushedAt, err := lru.New(pushedAtSize)
 	if err != nil {
 		return nil, err
 	}
-	return &LRUGlobalCache{pushedAt: pushedAt}, nil
+	ruleResults, err := lru.New(ruleResultSize)
+	if err != nil {
+		return nil, err
+	}
+	commitFiles, err := lru.New(commitFilesSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses *lru.Cache
+	if statusSize > 0 {
+		statuses, err = lru.New(statusSize)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &LRUGlobalCache{
+		pushedAt:    pushedAt,
+		ruleResults: ruleResults,
+		commitFiles: commitFiles,
+		statuses:    statuses,
+	}, nil
 }
 
 func (c *LRUGlobalCache) GetPushedAt(repoID int64, sha string) (time.Time, bool) {
@@ -61,3 +119,74 @@ func (c *LRUGlobalCache) SetPushedAt(repoID int64, sha string, t time.Time) {
 func pushedAtKey(repoID int64, sha string) string {
 	return fmt.Sprintf("%d:%s", repoID, sha)
 }
+
+func (c *LRUGlobalCache) GetRuleResult(key string) (interface{}, bool) {
+	return c.ruleResults.Get(key)
+}
+
+func (c *LRUGlobalCache) SetRuleResult(key string, result interface{}) {
+	c.ruleResults.Add(key, result)
+}
+
+func (c *LRUGlobalCache) GetCommitFiles(repoID int64, sha string) ([]*File, bool) {
+	if val, ok := c.commitFiles.Get(commitFilesKey(repoID, sha)); ok {
+		if files, ok := val.([]*File); ok {
+			return files, true
+		}
+	}
+	return nil, false
+}
+
+func (c *LRUGlobalCache) SetCommitFiles(repoID int64, sha string, files []*File) {
+	c.commitFiles.Add(commitFilesKey(repoID, sha), files)
+}
+
+func commitFilesKey(repoID int64, sha string) string {
+	return fmt.Sprintf("%d:%s", repoID, sha)
+}
+
+func (c *LRUGlobalCache) GetStatuses(repoID int64, sha string) (map[string]string, bool) {
+	if c.statuses == nil {
+		return nil, false
+	}
+	if val, ok := c.statuses.Get(statusesKey(repoID, sha)); ok {
+		if entry, ok := val.(statusEntry); ok && entry.statuses != nil {
+			return entry.statuses, true
+		}
+	}
+	return nil, false
+}
+
+func (c *LRUGlobalCache) SetStatuses(repoID int64, sha string, statuses map[string]string, loadedAt time.Time) {
+	if c.statuses == nil {
+		return
+	}
+
+	c.statusMu.Lock()
+	defer c.statusMu.Unlock()
+
+	key := statusesKey(repoID, sha)
+	if val, ok := c.statuses.Peek(key); ok {
+		if entry, ok := val.(statusEntry); ok && entry.deletedAt.After(loadedAt) {
+			return
+		}
+	}
+	c.statuses.Add(key, statusEntry{statuses: statuses})
+}
+
+func (c *LRUGlobalCache) DeleteStatuses(repoID int64, sha string) {
+	if c.statuses == nil {
+		return
+	}
+
+	c.statusMu.Lock()
+	defer c.statusMu.Unlock()
+
+	// Keep the deletion time so that statuses loaded before the deletion
+	// are not stored by evaluations that are still in progress
+	c.statuses.Add(statusesKey(repoID, sha), statusEntry{deletedAt: time.Now()})
+}
+
+func statusesKey(repoID int64, sha string) string {
+	return fmt.Sprintf("%d:%s", repoID, sha)
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pull
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUGlobalCacheStatuses(t *testing.T) {
+	statuses := map[string]string{"ci": "success"}
+
+	t.Run("disabled", func(t *testing.T) {
+		gc, err := NewLRUGlobalCache(1, 1, 1, 0)
+		require.NoError(t, err)
+
+		gc.SetStatuses(1234, "abc", statuses, time.Now())
+		_, ok := gc.GetStatuses(1234, "abc")
+		assert.False(t, ok, "statuses were cached")
+	})
+
+	t.Run("setAndDelete", func(t *testing.T) {
+		gc, err := NewLRUGlobalCache(1, 1, 1, 10)
+		require.NoError(t, err)
+
+		gc.SetStatuses(1234, "abc", statuses, time.Now())
+		cached, ok := gc.GetStatuses(1234, "abc")
+		require.True(t, ok, "statuses were not cached")
+		assert.Equal(t, statuses, cached)
+
+		_, ok = gc.GetStatuses(1234, "def")
+		assert.False(t, ok, "statuses were cached for a different commit")
+
+		gc.DeleteStatuses(1234, "abc")
+		_, ok = gc.GetStatuses(1234, "abc")
+		assert.False(t, ok, "statuses were not deleted")
+	})
+
+	t.Run("loadedBeforeDelete", func(t *testing.T) {
+		gc, err := NewLRUGlobalCache(1, 1, 1, 10)
+		require.NoError(t, err)
+
+		loadedAt := time.Now()
+		gc.DeleteStatuses(1234, "abc")
+
+		gc.SetStatuses(1234, "abc", statuses, loadedAt)
+		_, ok := gc.GetStatuses(1234, "abc")
+		assert.False(t, ok, "statuses loaded before the delete were cached")
+
+		gc.SetStatuses(1234, "abc", statuses, time.Now())
+		_, ok = gc.GetStatuses(1234, "abc")
+		assert.True(t, ok, "statuses loaded after the delete were not cached")
+	})
+}
@@ -95,3 +95,25 @@ func extractNodeNames(set ast.SelectionSet) nodeNameTree {
 	}
 	return t
 }
+
+// GraphQLVariableMatcher matches if the GraphQL query in the request matches
+// Prefix like GraphQLNodePrefixMatcher and sets the variable Name to Value.
+type GraphQLVariableMatcher struct {
+	Prefix string
+	Name   string
+	Value  string
+}
+
+func (m GraphQLVariableMatcher) Matches(r *http.Request, body []byte) bool {
+	if !GraphQLNodePrefixMatcher(m.Prefix).Matches(r, body) {
+		return false
+	}
+
+	var d struct {
+		Variables map[string]interface{} `json:"variables"`
+	}
+	if err := json.Unmarshal(body, &d); err != nil {
+		return false
+	}
+	return d.Variables[m.Name] == m.Value
+}
@@ -15,6 +15,7 @@
 package pulltest
 
 import (
+	"strings"
 	"time"
 
 	"github.com/palantir/policy-bot/pull"
@@ -33,15 +34,26 @@ type Context struct {
 	StateValue     string
 	HeadSHAValue   string
 
+	AuthorCreatedAtValue time.Time
+	AuthorCreatedAtError error
+
 	BranchBaseName string
 	BranchHeadName string
 
+	// HeadOwnerValue and HeadRepoValue default to the base repository
+	HeadOwnerValue string
+	HeadRepoValue  string
+
 	BodyValue *pull.Body
 	BodyError error
 
 	ChangedFilesValue []*pull.File
 	ChangedFilesError error
 
+	// CommitFilesValue maps commit SHAs to the files changed by the commit
+	CommitFilesValue map[string][]*pull.File
+	CommitFilesError error
+
 	CommitsValue []*pull.Commit
 	CommitsError error
 
@@ -65,19 +77,65 @@ type Context struct {
 	CollaboratorsValue []*pull.Collaborator
 	CollaboratorsError error
 
+	// OtherPermissions maps "owner/repo" to a map of users to permissions
+	OtherPermissions      map[string]map[string]pull.Permission
+	OtherPermissionsError error
+
 	RequestedReviewersValue []*pull.Reviewer
 	RequestedReviewersError error
 
+	PendingReviewRequestsValue map[string]int
+	PendingReviewRequestsError error
+
 	LatestStatusesValue map[string]string
 	LatestStatusesError error
 
 	LatestWorkflowRunsValue map[string][]string
 	LatestWorkflowRunsError error
 
+	LatestDeploymentsValue map[string]string
+	LatestDeploymentsError error
+
 	LabelsValue []string
 	LabelsError error
 
+	ReopenedAtValue time.Time
+	ReopenedAtError error
+
+	ForcePushedAtValue time.Time
+	ForcePushedAtError error
+
+	ReviewThreadsValue []*pull.ReviewThread
+	ReviewThreadsError error
+
+	ReviewRequestsValue []*pull.ReviewRequest
+	ReviewRequestsError error
+
+	UserRegionsValue map[string]string
+
+	EventSourceValue pull.EventSource
+
+	LabelAppliersValue map[string]string
+	LabelAppliersError error
+
+	HeadCommitVerificationValue *pull.Verification
+	HeadCommitVerificationError error
+
+	// BaseFilesValue maps paths to file content; missing paths do not exist
+	BaseFilesValue map[string]string
+	BaseFilesError error
+
+	// FilesValue maps refs to maps of paths to file content; missing refs or
+	// paths do not exist
+	FilesValue map[string]map[string]string
+	FilesError error
+
+	ProtectedBranchesValue []string
+	ProtectedBranchesError error
+
 	Draft bool
+
+	AutoMergeValue *pull.AutoMerge
 }
 
 func (c *Context) EvaluationTimestamp() time.Time {
@@ -117,6 +175,10 @@ func (c *Context) Author() string {
 	return c.AuthorValue
 }
 
+func (c *Context) AuthorCreatedAt() (time.Time, error) {
+	return c.AuthorCreatedAtValue, c.AuthorCreatedAtError
+}
+
 func (c *Context) CreatedAt() time.Time {
 	return c.CreatedAtValue
 }
@@ -137,14 +199,29 @@ func (c *Context) IsDraft() bool {
 	return c.Draft
 }
 
+func (c *Context) AutoMerge() *pull.AutoMerge {
+	return c.AutoMergeValue
+}
+
 func (c *Context) Branches() (base string, head string) {
 	return c.BranchBaseName, c.BranchHeadName
 }
 
+func (c *Context) HeadRepository() (owner string, name string) {
+	if c.HeadOwnerValue != "" || c.HeadRepoValue != "" {
+		return c.HeadOwnerValue, c.HeadRepoValue
+	}
+	return c.RepositoryOwner(), c.RepositoryName()
+}
+
 func (c *Context) ChangedFiles() ([]*pull.File, error) {
 	return c.ChangedFilesValue, c.ChangedFilesError
 }
 
+func (c *Context) CommitFiles(sha string) ([]*pull.File, error) {
+	return c.CommitFilesValue[sha], c.CommitFilesError
+}
+
 func (c *Context) Commits() ([]*pull.Commit, error) {
 	return c.CommitsValue, c.CommitsError
 }
@@ -179,6 +256,20 @@ func (c *Context) IsOrgMember(org, user string) (bool, error) {
 	return false, nil
 }
 
+func (c *Context) UserTeams(org, user string) ([]string, error) {
+	if c.TeamMembershipError != nil {
+		return nil, c.TeamMembershipError
+	}
+
+	var teams []string
+	for _, t := range c.TeamMemberships[user] {
+		if strings.HasPrefix(t, org+"/") {
+			teams = append(teams, t)
+		}
+	}
+	return teams, nil
+}
+
 func (c *Context) CollaboratorPermission(user string) (pull.Permission, error) {
 	if c.CollaboratorsError != nil {
 		return pull.PermissionNone, c.CollaboratorsError
@@ -191,6 +282,13 @@ func (c *Context) CollaboratorPermission(user string) (pull.Permission, error) {
 	return pull.PermissionNone, nil
 }
 
+func (c *Context) CollaboratorPermissionOn(owner, repo, user string) (pull.Permission, error) {
+	if c.OtherPermissionsError != nil {
+		return pull.PermissionNone, c.OtherPermissionsError
+	}
+	return c.OtherPermissions[owner+"/"+repo][user], nil
+}
+
 func (c *Context) RepositoryCollaborators() ([]*pull.Collaborator, error) {
 	if c.CollaboratorsError != nil {
 		return nil, c.CollaboratorsError
@@ -241,6 +339,18 @@ func (c *Context) RequestedReviewers() ([]*pull.Reviewer, error) {
 	return c.RequestedReviewersValue, c.RequestedReviewersError
 }
 
+func (c *Context) PendingReviewRequests(users []string) (map[string]int, error) {
+	if c.PendingReviewRequestsError != nil {
+		return nil, c.PendingReviewRequestsError
+	}
+
+	counts := make(map[string]int, len(users))
+	for _, user := range users {
+		counts[user] = c.PendingReviewRequestsValue[user]
+	}
+	return counts, nil
+}
+
 func (c *Context) Comments() ([]*pull.Comment, error) {
 	return c.CommentsValue, c.CommentsError
 }
@@ -261,9 +371,76 @@ func (c *Context) LatestWorkflowRuns() (map[string][]string, error) {
 	return c.LatestWorkflowRunsValue, c.LatestWorkflowRunsError
 }
 
+func (c *Context) LatestDeployments() (map[string]string, error) {
+	return c.LatestDeploymentsValue, c.LatestDeploymentsError
+}
+
 func (c *Context) Labels() ([]string, error) {
 	return c.LabelsValue, c.LabelsError
 }
 
+func (c *Context) ReopenedAt() (time.Time, error) {
+	return c.ReopenedAtValue, c.ReopenedAtError
+}
+
+func (c *Context) ForcePushedAt() (time.Time, error) {
+	return c.ForcePushedAtValue, c.ForcePushedAtError
+}
+
+func (c *Context) ReviewThreads() ([]*pull.ReviewThread, error) {
+	return c.ReviewThreadsValue, c.ReviewThreadsError
+}
+
+func (c *Context) ReviewRequests() ([]*pull.ReviewRequest, error) {
+	return c.ReviewRequestsValue, c.ReviewRequestsError
+}
+
+func (c *Context) UserRegions() map[string]string {
+	return c.UserRegionsValue
+}
+
+func (c *Context) EventSource() pull.EventSource {
+	if c.EventSourceValue == "" {
+		return pull.EventSourcePullRequest
+	}
+	return c.EventSourceValue
+}
+
+func (c *Context) LabelAppliers() (map[string]string, error) {
+	return c.LabelAppliersValue, c.LabelAppliersError
+}
+
+func (c *Context) HeadCommitVerification() (*pull.Verification, error) {
+	return c.HeadCommitVerificationValue, c.HeadCommitVerificationError
+}
+
+func (c *Context) BaseFileContent(path string) (string, bool, error) {
+	if c.BaseFilesError != nil {
+		return "", false, c.BaseFilesError
+	}
+	content, ok := c.BaseFilesValue[path]
+	return content, ok, nil
+}
+
+func (c *Context) FileContent(path, ref string) (string, bool, error) {
+	if c.FilesError != nil {
+		return "", false, c.FilesError
+	}
+	content, ok := c.FilesValue[ref][path]
+	return content, ok, nil
+}
+
+func (c *Context) IsProtectedBranch(branch string) (bool, error) {
+	if c.ProtectedBranchesError != nil {
+		return false, c.ProtectedBranchesError
+	}
+	for _, b := range c.ProtectedBranchesValue {
+		if b == branch {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // assert that the test object implements the full interface
 var _ pull.Context = &Context{}
@@ -19,6 +19,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strings"
+	"sync"
 
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
@@ -44,6 +45,8 @@ type Rule struct {
 
 type ResponsePlayer struct {
 	Rules []*Rule
+
+	mu sync.Mutex
 }
 
 func (rp *ResponsePlayer) AddRule(matcher RequestMatcher, file string) *Rule {
@@ -109,6 +112,9 @@ func (rp *ResponsePlayer) findMatch(req *http.Request) *Rule {
 }
 
 func (rp *ResponsePlayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
 	rule := rp.findMatch(req)
 	if rule == nil {
 		return errorResponse(req, http.StatusNotFound, fmt.Sprintf("no matching rule for \"%s %s\"", req.Method, req.URL.Path))
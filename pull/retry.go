@@ -0,0 +1,188 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pull
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// MaxRetryAfter is the longest delay requested by a Retry-After header that
+// retries will respect. Responses that request longer delays are returned
+// without retrying.
+const MaxRetryAfter = time.Minute
+
+// RetryOptions configures how GitHub API requests that fail with transient
+// errors are retried.
+type RetryOptions struct {
+	// MaxAttempts is the maximum number of times to send a request, including
+	// the first attempt. Values less than 2 disable retries.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry. The delay doubles after
+	// each retry, unless a response includes a Retry-After header.
+	BaseDelay time.Duration
+
+	// GraphQLPath is the path of the GraphQL API endpoint. POST requests to
+	// this path are retried if they contain queries. Mutations and other POST
+	// requests are never retried.
+	GraphQLPath string
+}
+
+// ClientRetry returns HTTP client middleware that retries idempotent requests
+// that fail with server errors, network errors, or secondary rate limits.
+// Because the middleware wraps the whole request, any client timeout also
+// includes the time spent waiting between retries.
+func ClientRetry(opts RetryOptions) func(http.RoundTripper) http.RoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		if opts.MaxAttempts < 2 {
+			return next
+		}
+		return &retryTransport{next: next, opts: opts}
+	}
+}
+
+type retryTransport struct {
+	next http.RoundTripper
+	opts RetryOptions
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.isRetryable(req) {
+		return t.next.RoundTrip(req)
+	}
+
+	ctx := req.Context()
+	delay := t.opts.BaseDelay
+	attemptReq := req
+
+	for attempt := 1; ; attempt++ {
+		res, err := t.next.RoundTrip(attemptReq)
+		if attempt >= t.opts.MaxAttempts || !shouldRetry(res, err) || ctx.Err() != nil {
+			return res, err
+		}
+
+		wait := delay
+		if res != nil {
+			if d, ok := retryAfter(res); ok {
+				if d > MaxRetryAfter {
+					return res, err
+				}
+				wait = d
+			}
+			// drain the body so the connection can be reused
+			_, _ = io.Copy(io.Discard, res.Body)
+			_ = res.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		delay *= 2
+
+		attemptReq = req.Clone(ctx)
+		if req.GetBody != nil {
+			if attemptReq.Body, err = req.GetBody(); err != nil {
+				return nil, errors.Wrap(err, "failed to reset request body for retry")
+			}
+		}
+	}
+}
+
+// isRetryable returns true if the request is safe to send more than once.
+func (t *retryTransport) isRetryable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		return true
+	case http.MethodPost:
+		return t.opts.GraphQLPath != "" && req.URL.Path == t.opts.GraphQLPath && isGraphQLQuery(req)
+	}
+	return false
+}
+
+// isGraphQLQuery returns true if the body of the request is a GraphQL query.
+// It returns false if the body is a mutation or cannot be read again.
+func isGraphQLQuery(req *http.Request) bool {
+	if req.GetBody == nil {
+		return false
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return false
+	}
+	defer func() { _ = body.Close() }()
+
+	var payload struct {
+		Query string `json:"query"`
+	}
+	if err := json.NewDecoder(body).Decode(&payload); err != nil {
+		return false
+	}
+
+	query := strings.TrimSpace(payload.Query)
+	return query != "" && !strings.HasPrefix(query, "mutation")
+}
+
+func shouldRetry(res *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	switch res.StatusCode {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+		// GitHub sets Retry-After on secondary rate limit responses, but not
+		// on other forbidden responses or when the primary limit is exceeded
+		return res.Header.Get("Retry-After") != ""
+	}
+	return false
+}
+
+// retryAfter returns the delay requested by the Retry-After header of the
+// response, which is either a number of seconds or an HTTP date.
+func retryAfter(res *http.Response) (time.Duration, bool) {
+	v := strings.TrimSpace(res.Header.Get("Retry-After"))
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pull
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientRetry(t *testing.T) {
+	newClient := func(rp *ResponsePlayer, attempts int) *http.Client {
+		retry := ClientRetry(RetryOptions{
+			MaxAttempts: attempts,
+			BaseDelay:   time.Millisecond,
+			GraphQLPath: "/graphql",
+		})
+		return &http.Client{Transport: retry(rp)}
+	}
+
+	post := func(t *testing.T, client *http.Client, body string) *http.Response {
+		req, err := http.NewRequest(http.MethodPost, "http://github.localhost/graphql", strings.NewReader(body))
+		require.NoError(t, err)
+
+		res, err := client.Do(req)
+		require.NoError(t, err)
+		_ = res.Body.Close()
+		return res
+	}
+
+	t.Run("retriesGet", func(t *testing.T) {
+		rp := &ResponsePlayer{}
+		rule := rp.AddRule(ExactPathMatcher("/repos/testorg/testrepo/pulls/123"), "testdata/responses/retry_unavailable.yml")
+
+		res, err := newClient(rp, 3).Get("http://github.localhost/repos/testorg/testrepo/pulls/123")
+		require.NoError(t, err)
+		_ = res.Body.Close()
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, 2, rule.Count, "incorrect number of http requests")
+	})
+
+	t.Run("retriesGraphQLQuery", func(t *testing.T) {
+		rp := &ResponsePlayer{}
+		rule := rp.AddRule(GraphQLNodePrefixMatcher("repository"), "testdata/responses/retry_unavailable.yml")
+
+		res := post(t, newClient(rp, 3), `{"query":"query($owner:String!){repository(owner:$owner){id}}"}`)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, 2, rule.Count, "incorrect number of http requests")
+	})
+
+	t.Run("doesNotRetryGraphQLMutation", func(t *testing.T) {
+		rp := &ResponsePlayer{}
+		rule := rp.AddRule(ExactPathMatcher("/graphql"), "testdata/responses/retry_unavailable.yml")
+
+		res := post(t, newClient(rp, 3), `{"query":"mutation($input:DismissPullRequestReviewInput!){dismissPullRequestReview(input:$input){clientMutationId}}"}`)
+
+		assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+		assert.Equal(t, 1, rule.Count, "incorrect number of http requests")
+	})
+
+	t.Run("maxAttempts", func(t *testing.T) {
+		rp := &ResponsePlayer{}
+		rule := rp.AddRule(ExactPathMatcher("/repos/testorg/testrepo/pulls/123"), "testdata/responses/retry_unavailable.yml")
+
+		res, err := newClient(rp, 1).Get("http://github.localhost/repos/testorg/testrepo/pulls/123")
+		require.NoError(t, err)
+		_ = res.Body.Close()
+
+		assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+		assert.Equal(t, 1, rule.Count, "incorrect number of http requests")
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	newResponse := func(value string) *http.Response {
+		res := &http.Response{Header: make(http.Header)}
+		if value != "" {
+			res.Header.Set("Retry-After", value)
+		}
+		return res
+	}
+
+	d, ok := retryAfter(newResponse("30"))
+	assert.True(t, ok)
+	assert.Equal(t, 30*time.Second, d)
+
+	d, ok = retryAfter(newResponse(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)))
+	assert.True(t, ok)
+	assert.InDelta(t, time.Hour, d, float64(5*time.Second))
+
+	_, ok = retryAfter(newResponse(""))
+	assert.False(t, ok)
+
+	_, ok = retryAfter(newResponse("soon"))
+	assert.False(t, ok)
+}
+
+func TestShouldRetry(t *testing.T) {
+	newResponse := func(status int, retryAfter string) *http.Response {
+		res := &http.Response{StatusCode: status, Header: make(http.Header)}
+		if retryAfter != "" {
+			res.Header.Set("Retry-After", retryAfter)
+		}
+		return res
+	}
+
+	assert.True(t, shouldRetry(newResponse(http.StatusBadGateway, ""), nil))
+	assert.True(t, shouldRetry(newResponse(http.StatusTooManyRequests, ""), nil))
+	assert.True(t, shouldRetry(newResponse(http.StatusForbidden, "60"), nil), "secondary rate limits should be retried")
+	assert.False(t, shouldRetry(newResponse(http.StatusForbidden, ""), nil), "permission errors should not be retried")
+	assert.False(t, shouldRetry(newResponse(http.StatusNotFound, ""), nil))
+	assert.False(t, shouldRetry(newResponse(http.StatusOK, ""), nil))
+}
@@ -71,12 +71,53 @@ type CachingConfig struct {
 	// The size of the global cache for commit push times. Each entry uses
 	// roughly 100 bytes of memory.
 	PushedAtSize int `yaml:"pushed_at_size"`
+
+	// The size of the global cache for the results of approval rules that
+	// set the cache_result option. Each entry stores the complete result of
+	// a rule, which is usually a few kilobytes of memory.
+	RuleResultSize int `yaml:"rule_result_size"`
+
+	// The size of the global cache for the files changed by individual
+	// commits. Each entry stores the file list of a commit, including diffs,
+	// so the memory used depends on the size of the commits.
+	CommitFilesSize int `yaml:"commit_files_size"`
+
+	// The size of the global cache for the statuses and check runs of head
+	// commits. Cached statuses are removed when the server receives a status
+	// or check_run event for the commit, so this cache must only be enabled
+	// if a single instance of the server receives all webhooks. Disabled if
+	// zero.
+	StatusSize int `yaml:"status_size"`
+
+	// The maximum age of a last known good policy. If set, policy-bot uses
+	// the most recent valid policy for a branch when it cannot load the
+	// current policy, as long as the policy was loaded within this duration.
+	// Once the cached policy is too old, evaluation fails with an error.
+	PolicyMaxAge time.Duration `yaml:"policy_max_age"`
+
+	// The number of last known good policies to keep in memory. Only used
+	// when PolicyMaxAge is set.
+	PolicySize int `yaml:"policy_size"`
 }
 
 type WorkerConfig struct {
 	Workers       int           `yaml:"workers"`
 	QueueSize     int           `yaml:"queue_size"`
 	GithubTimeout time.Duration `yaml:"github_timeout"`
+
+	// GithubRetry configures retries of GitHub API requests that fail with
+	// server errors or secondary rate limits.
+	GithubRetry RetryConfig `yaml:"github_retry"`
+}
+
+type RetryConfig struct {
+	// The maximum number of attempts for each request, including the first
+	// attempt. Set to 1 to disable retries.
+	MaxAttempts int `yaml:"max_attempts"`
+
+	// The delay before the first retry, which doubles for each later retry.
+	// Retry-After headers in responses take precedence.
+	BaseDelay time.Duration `yaml:"base_delay"`
 }
 
 type SessionsConfig struct {
@@ -100,6 +141,10 @@ func ParseConfig(bytes []byte) (*Config, error) {
 	c.Logging.SetValuesFromEnv(envPrefix)
 	c.Github.SetValuesFromEnv("")
 
+	if err := c.Options.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid options")
+	}
+
 	if v, ok := os.LookupEnv(envPrefix + "SESSIONS_KEY"); ok {
 		c.Sessions.Key = v
 	}
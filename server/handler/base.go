@@ -16,6 +16,9 @@ package handler
 
 import (
 	"context"
+	"fmt"
+	"sync"
+	"time"
 
 	"github.com/google/go-github/v65/github"
 	"github.com/palantir/go-baseapp/baseapp"
@@ -39,9 +42,49 @@ type Base struct {
 	BaseConfig    *baseapp.HTTPConfig
 	PullOpts      *PullEvaluationOptions
 
+	// Scheduler, if non-nil, tracks scheduled evaluations so that each pull
+	// request has at most one pending evaluation.
+	Scheduler *Scheduler
+
 	AppName string
 }
 
+// Scheduler runs delayed evaluations, keeping at most one timer for each pull
+// request. It is shared by all handlers.
+type Scheduler struct {
+	mu     sync.Mutex
+	timers map[string]*time.Timer
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{timers: make(map[string]*time.Timer)}
+}
+
+// Schedule runs fn after the delay. If an evaluation is already scheduled for
+// the pull request, it is replaced, as the most recent evaluation determines
+// when the next one is needed.
+func (s *Scheduler) Schedule(loc pull.Locator, delay time.Duration, fn func()) {
+	key := fmt.Sprintf("%s/%s#%d", loc.Owner, loc.Repo, loc.Number)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t, ok := s.timers[key]; ok {
+		t.Stop()
+	}
+
+	var t *time.Timer
+	t = time.AfterFunc(delay, func() {
+		s.mu.Lock()
+		if s.timers[key] == t {
+			delete(s.timers, key)
+		}
+		s.mu.Unlock()
+		fn()
+	})
+	s.timers[key] = t
+}
+
 // PostStatus posts a GitHub commit status with consistent logging.
 func PostStatus(ctx context.Context, client *github.Client, owner, repo, ref string, status *github.RepoStatus) error {
 	zerolog.Ctx(ctx).Info().Msgf("Setting %q status on %s to %s: %s", status.GetContext(), ref, status.GetState(), status.GetDescription())
@@ -49,6 +92,15 @@ func PostStatus(ctx context.Context, client *github.Client, owner, repo, ref str
 	return errors.WithStack(err)
 }
 
+// InvalidateStatuses removes the cached statuses for a commit. It must be
+// called for every status or check run event, including events that do not
+// trigger evaluation, so that evaluation never uses outdated statuses.
+func (b *Base) InvalidateStatuses(repoID int64, sha string) {
+	if b.GlobalCache != nil {
+		b.GlobalCache.DeleteStatuses(repoID, sha)
+	}
+}
+
 func (b *Base) PreparePRContext(ctx context.Context, installationID int64, pr *github.PullRequest) (context.Context, zerolog.Logger) {
 	ctx, logger := githubapp.PreparePRContext(ctx, installationID, pr.GetBase().GetRepo(), pr.GetNumber())
 
@@ -59,6 +111,18 @@ func (b *Base) PreparePRContext(ctx context.Context, installationID int64, pr *g
 }
 
 func (b *Base) NewEvalContext(ctx context.Context, installationID int64, loc pull.Locator) (*EvalContext, error) {
+	return b.newEvalContext(ctx, installationID, loc, func(client *github.Client, prctx pull.Context) FetchedConfig {
+		baseBranch, _ := prctx.Branches()
+		owner := prctx.RepositoryOwner()
+		repository := prctx.RepositoryName()
+
+		return b.ConfigFetcher.ConfigForRepositoryBranch(ctx, client, owner, repository, baseBranch)
+	})
+}
+
+// newEvalContext creates an EvalContext for the pull request using the
+// policy returned by fetchConfig.
+func (b *Base) newEvalContext(ctx context.Context, installationID int64, loc pull.Locator, fetchConfig func(*github.Client, pull.Context) FetchedConfig) (*EvalContext, error) {
 	client, err := b.NewInstallationClient(installationID)
 	if err != nil {
 		return nil, err
@@ -69,17 +133,22 @@ func (b *Base) NewEvalContext(ctx context.Context, installationID int64, loc pul
 		return nil, err
 	}
 
-	mbrCtx := NewCrossOrgMembershipContext(ctx, client, loc.Owner, b.Installations, b.ClientCreator)
+	mbrCtx := NewCrossOrgMembershipContext(ctx, client, v4client, loc.Owner, b.Installations, b.ClientCreator)
 	prctx, err := pull.NewGitHubContext(ctx, mbrCtx, b.GlobalCache, client, v4client, loc)
 	if err != nil {
 		return nil, err
 	}
 
-	baseBranch, _ := prctx.Branches()
-	owner := prctx.RepositoryOwner()
-	repository := prctx.RepositoryName()
-
-	fetchedConfig := b.ConfigFetcher.ConfigForRepositoryBranch(ctx, client, owner, repository, baseBranch)
+	fetchedConfig := fetchConfig(client, prctx)
+	if ghc, ok := prctx.(*pull.GitHubContext); ok {
+		ghc.SetUserRegions(b.PullOpts.UserRegions())
+		if fetchedConfig.Config != nil && fetchedConfig.Config.Options.DisablePushBatching {
+			ghc.DisablePushBatching()
+		}
+		if fetchedConfig.Config != nil && fetchedConfig.Config.Options.DirectCollaboratorsOnly {
+			ghc.UseDirectCollaboratorsOnly()
+		}
+	}
 
 	return &EvalContext{
 		Client:   client,
@@ -90,6 +159,10 @@ func (b *Base) NewEvalContext(ctx context.Context, installationID int64, loc pul
 
 		PullContext: prctx,
 		Config:      fetchedConfig,
+
+		ScheduleEvaluation: b.scheduleEvaluation(installationID, loc),
+		AppName:            b.AppName,
+		ResultCache:        b.GlobalCache,
 	}, nil
 }
 
@@ -100,3 +173,34 @@ func (b *Base) Evaluate(ctx context.Context, installationID int64, trigger commo
 	}
 	return evalCtx.Evaluate(ctx, trigger)
 }
+
+// scheduleEvaluation returns a function that evaluates the pull request again
+// after a delay. Scheduled evaluations are kept in memory and are lost if the
+// server restarts; in this case, the next event for the pull request will
+// trigger evaluation as usual.
+func (b *Base) scheduleEvaluation(installationID int64, loc pull.Locator) func(context.Context, time.Duration) {
+	// Drop the pull request value so the scheduled evaluation loads the
+	// current state of the pull request
+	loc = pull.Locator{
+		Owner:  loc.Owner,
+		Repo:   loc.Repo,
+		Number: loc.Number,
+	}
+
+	return func(ctx context.Context, delay time.Duration) {
+		logger := *zerolog.Ctx(ctx)
+		registry := baseapp.MetricsCtx(ctx)
+		evaluate := func() {
+			ctx := baseapp.WithMetricsCtx(logger.WithContext(context.Background()), registry)
+			if err := b.Evaluate(ctx, installationID, common.TriggerAll, loc); err != nil {
+				logger.Error().Err(err).Msg("Failed to run scheduled evaluation")
+			}
+		}
+
+		if b.Scheduler != nil {
+			b.Scheduler.Schedule(loc, delay, evaluate)
+		} else {
+			time.AfterFunc(delay, evaluate)
+		}
+	}
+}
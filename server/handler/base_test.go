@@ -0,0 +1,51 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/palantir/policy-bot/pull"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduler(t *testing.T) {
+	s := NewScheduler()
+	loc := pull.Locator{Owner: "testorg", Repo: "testrepo", Number: 123}
+
+	var first, second atomic.Int32
+	done := make(chan struct{})
+
+	s.Schedule(loc, time.Hour, func() { first.Add(1) })
+	s.Schedule(loc, 10*time.Millisecond, func() {
+		second.Add(1)
+		close(done)
+	})
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("scheduled evaluation did not run")
+	}
+
+	assert.Equal(t, int32(0), first.Load(), "replaced evaluation should not run")
+	assert.Equal(t, int32(1), second.Load())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	assert.Empty(t, s.timers, "timer was not removed after running")
+}
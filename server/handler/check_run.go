@@ -37,10 +37,17 @@ func (h *CheckRun) Handle(ctx context.Context, eventType, deliveryID string, pay
 		return errors.Wrap(err, "failed to parse check_run event payload")
 	}
 
+	h.InvalidateStatuses(event.GetRepo().GetID(), event.GetCheckRun().GetHeadSHA())
+
 	if event.GetAction() != "completed" || event.GetCheckRun().GetConclusion() != "success" {
 		return nil
 	}
 
+	// ignore check runs posted by this app to avoid evaluating in a loop
+	if event.GetCheckRun().GetApp().GetSlug() == h.AppName {
+		return nil
+	}
+
 	repo := event.GetRepo()
 	repoID := repo.GetID()
 	ownerName := repo.GetOwner().GetLogin()
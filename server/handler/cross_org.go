@@ -17,11 +17,13 @@ package handler
 import (
 	"context"
 	"strings"
+	"sync"
 
 	"github.com/google/go-github/v65/github"
 	"github.com/palantir/go-githubapp/githubapp"
 	"github.com/palantir/policy-bot/pull"
 	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
 )
 
 type CrossOrgMembershipContext struct {
@@ -30,10 +32,12 @@ type CrossOrgMembershipContext struct {
 	installations githubapp.InstallationsService
 	clientCreator githubapp.ClientCreator
 
+	// mu guards mbrCtxs, which may be used by rules evaluated concurrently
+	mu      sync.Mutex
 	mbrCtxs map[string]pull.MembershipContext
 }
 
-func NewCrossOrgMembershipContext(ctx context.Context, client *github.Client, orgName string, installations githubapp.InstallationsService, clientCreator githubapp.ClientCreator) *CrossOrgMembershipContext {
+func NewCrossOrgMembershipContext(ctx context.Context, client *github.Client, v4client *githubv4.Client, orgName string, installations githubapp.InstallationsService, clientCreator githubapp.ClientCreator) *CrossOrgMembershipContext {
 	mbrCtx := &CrossOrgMembershipContext{
 		ctx:           ctx,
 		lookupClient:  client,
@@ -41,11 +45,14 @@ func NewCrossOrgMembershipContext(ctx context.Context, client *github.Client, or
 		clientCreator: clientCreator,
 		mbrCtxs:       make(map[string]pull.MembershipContext),
 	}
-	mbrCtx.mbrCtxs[orgName] = pull.NewGitHubMembershipContext(ctx, client)
+	mbrCtx.mbrCtxs[orgName] = pull.NewGitHubMembershipContext(ctx, client, v4client)
 	return mbrCtx
 }
 
 func (c *CrossOrgMembershipContext) getCtxForOrg(name string) (pull.MembershipContext, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	mbrCtx, ok := c.mbrCtxs[name]
 	if !ok {
 		org, _, err := c.lookupClient.Organizations.Get(c.ctx, name)
@@ -63,7 +70,12 @@ func (c *CrossOrgMembershipContext) getCtxForOrg(name string) (pull.MembershipCo
 			return nil, err
 		}
 
-		mbrCtx = pull.NewGitHubMembershipContext(c.ctx, client)
+		v4client, err := c.clientCreator.NewInstallationV4Client(installation.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		mbrCtx = pull.NewGitHubMembershipContext(c.ctx, client, v4client)
 		c.mbrCtxs[name] = mbrCtx
 	}
 
@@ -103,3 +115,11 @@ func (c *CrossOrgMembershipContext) TeamMembers(team string) ([]string, error) {
 	}
 	return mbrCtx.TeamMembers(team)
 }
+
+func (c *CrossOrgMembershipContext) UserTeams(org, user string) ([]string, error) {
+	mbrCtx, err := c.getCtxForOrg(org)
+	if err != nil {
+		return nil, err
+	}
+	return mbrCtx.UserTeams(org, user)
+}
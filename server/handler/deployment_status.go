@@ -0,0 +1,100 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/go-github/v65/github"
+	"github.com/palantir/go-githubapp/githubapp"
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/pkg/errors"
+)
+
+type DeploymentStatus struct {
+	Base
+}
+
+func (h *DeploymentStatus) Handles() []string { return []string{"deployment_status"} }
+
+// Handle deployment_status
+// https://docs.github.com/en/webhooks/webhook-events-and-payloads#deployment_status
+func (h *DeploymentStatus) Handle(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+	var event github.DeploymentStatusEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return errors.Wrap(err, "failed to parse deployment_status event payload")
+	}
+
+	if event.GetAction() != "created" {
+		return nil
+	}
+
+	repo := event.GetRepo()
+	ownerName := repo.GetOwner().GetLogin()
+	repoName := repo.GetName()
+	commitSHA := event.GetDeployment().GetSHA()
+	installationID := githubapp.GetInstallationIDFromEvent(&event)
+
+	client, err := h.NewInstallationClient(installationID)
+	if err != nil {
+		return err
+	}
+
+	ctx, logger := githubapp.PrepareRepoContext(ctx, installationID, repo)
+
+	// Evaluate for every state, not just success, because a new failed or
+	// in-progress deployment replaces an earlier successful one
+	prs, _, err := client.PullRequests.ListPullRequestsWithCommit(
+		ctx,
+		ownerName,
+		repoName,
+		commitSHA,
+		&github.ListOptions{
+			PerPage: 100,
+		})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list pull requests for SHA %s", commitSHA)
+	}
+	logger.Debug().Msgf(
+		"Deployment status event is for environment '%s' with state '%s', found %d PRs",
+		event.GetDeployment().GetEnvironment(),
+		event.GetDeploymentStatus().GetState(),
+		len(prs),
+	)
+
+	evaluationFailures := 0
+	for _, pr := range prs {
+		if pr.GetState() != "open" {
+			continue
+		}
+
+		if err := h.Evaluate(ctx, installationID, common.TriggerStatus, pull.Locator{
+			Owner:  ownerName,
+			Repo:   repoName,
+			Number: pr.GetNumber(),
+			Value:  pr,
+		}); err != nil {
+			evaluationFailures++
+			logger.Error().Err(err).Msgf("Failed to evaluate pull request '%d' for SHA '%s'", pr.GetNumber(), commitSHA)
+		}
+	}
+	if evaluationFailures == 0 {
+		return nil
+	}
+
+	return errors.Errorf("failed to evaluate %d pull requests", evaluationFailures)
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeploymentStatus(t *testing.T) {
+	const pullRequestSHA = "e05fcae367230ee709313dd2720da527d178ce43"
+
+	tests := map[string]struct {
+		State               string
+		ExpectedState       string
+		ExpectedDescription string
+	}{
+		"success": {
+			State:               "success",
+			ExpectedState:       "success",
+			ExpectedDescription: "All rules are approved",
+		},
+		"failure": {
+			State:               "failure",
+			ExpectedState:       "error",
+			ExpectedDescription: "All rules were skipped. At least one rule must match.",
+		},
+		"inProgress": {
+			State:               "in_progress",
+			ExpectedState:       "error",
+			ExpectedDescription: "All rules were skipped. At least one rule must match.",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			gh := newTestGitHub(t, `
+policy:
+  approval:
+    - staging deployed
+approval_rules:
+  - name: staging deployed
+    if:
+      has_successful_deployment:
+        environments: ["staging"]
+`)
+			gh.Mux.HandleFunc("GET /repos/testorg/testrepo/commits/"+pullRequestSHA+"/pulls", func(w http.ResponseWriter, r *http.Request) {
+				pr, err := os.ReadFile("testdata/pull_request.json")
+				require.NoError(t, err)
+				_, _ = fmt.Fprintf(w, "[%s]", pr)
+			})
+			gh.Mux.HandleFunc("GET /repos/testorg/testrepo/deployments", func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, pullRequestSHA, r.URL.Query().Get("sha"), "deployments were not filtered by SHA")
+				_, _ = io.WriteString(w, `[{"id": 3, "sha": "`+pullRequestSHA+`", "environment": "staging"}]`)
+			})
+			gh.Mux.HandleFunc("GET /repos/testorg/testrepo/deployments/3/statuses", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = fmt.Fprintf(w, `[{"id": 32, "state": %q, "environment": "staging"}]`, test.State)
+			})
+
+			payload, err := os.ReadFile("testdata/deployment_status_created.json")
+			require.NoError(t, err)
+
+			h := &DeploymentStatus{Base: gh.Base(t)}
+			err = h.Handle(context.Background(), "deployment_status", "test-delivery", payload)
+			require.NoError(t, err)
+
+			statuses := gh.Statuses(pullRequestSHA)
+			if assert.Len(t, statuses, 1, "incorrect number of statuses posted") {
+				status := statuses[0]
+				assert.Equal(t, test.ExpectedState, status.GetState())
+				assert.Equal(t, "policy-bot: develop", status.GetContext())
+				assert.Equal(t, test.ExpectedDescription, status.GetDescription())
+			}
+		})
+	}
+}
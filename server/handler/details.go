@@ -28,6 +28,7 @@ import (
 	"github.com/bluekeyes/hatpear"
 	"github.com/bluekeyes/templatetree"
 	"github.com/google/go-github/v65/github"
+	"github.com/palantir/go-baseapp/baseapp"
 	"github.com/palantir/go-githubapp/githubapp"
 	"github.com/palantir/policy-bot/policy/common"
 	"github.com/palantir/policy-bot/pull"
@@ -82,19 +83,40 @@ func (h *Details) ServeHTTP(w http.ResponseWriter, r *http.Request) error {
 	data.ExpandRequiredReviewers = h.PullOpts.ExpandRequiredReviewers
 	data.PullRequest = state.PullRequest
 
+	respond := func() error {
+		if acceptsJSON(r) {
+			res := DetailsResponse{
+				PullRequest:      newDetailsPullRequest(data.PullRequest),
+				PolicyURL:        data.PolicyURL,
+				IsTemporaryError: data.IsTemporaryError,
+				Result:           newDetailsResult(data.Result),
+			}
+			if data.Error != nil {
+				res.Error = data.Error.Error()
+			}
+			baseapp.WriteJSON(w, http.StatusOK, res)
+			return nil
+		}
+		return h.render(w, data)
+	}
+
 	evaluator, err := evalCtx.ParseConfig(ctx, common.TriggerAll)
 	if err != nil {
 		data.Error = err
-		return h.render(w, data)
+		return respond()
 	}
 	if evaluator == nil {
 		data.Error = errors.Errorf("Invalid policy at %s: %s", evalCtx.Config.Source, evalCtx.Config.Path)
-		return h.render(w, data)
+		return respond()
 	}
 
 	result, err := evalCtx.EvaluatePolicy(ctx, evaluator)
 	data.Result = &result
 
+	if !acceptsJSON(r) {
+		evalCtx.loadDismissalFiles(ctx, data.Result)
+	}
+
 	if err != nil {
 		if _, ok := errors.Cause(err).(*pull.TemporaryError); ok {
 			data.IsTemporaryError = true
@@ -107,7 +129,7 @@ func (h *Details) ServeHTTP(w http.ResponseWriter, r *http.Request) error {
 	// actions are best-effort, so if they were missed by normal event
 	// handling, we don't _need_ to retry them here.
 
-	return h.render(w, data)
+	return respond()
 }
 
 // getStateIfAllowed creates a new DetailsState if the request is for a valid
@@ -0,0 +1,229 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v65/github"
+	"github.com/palantir/policy-bot/policy/common"
+)
+
+// DetailsResponse is the JSON representation of the details page, returned
+// when a request to the details handler accepts "application/json". Clients
+// may depend on this format: fields may be added, but existing fields are
+// not renamed, removed, or changed to a different type.
+type DetailsResponse struct {
+	PullRequest DetailsPullRequest `json:"pull_request"`
+	PolicyURL   string             `json:"policy_url"`
+
+	// Error is the error that prevented evaluation or that occurred during
+	// evaluation, if any. Result may be nil if Error is set.
+	Error            string         `json:"error,omitempty"`
+	IsTemporaryError bool           `json:"is_temporary_error"`
+	Result           *DetailsResult `json:"result"`
+}
+
+type DetailsPullRequest struct {
+	Owner      string `json:"owner"`
+	Repository string `json:"repository"`
+	Number     int    `json:"number"`
+	Title      string `json:"title"`
+	Author     string `json:"author"`
+	HeadSHA    string `json:"head_sha"`
+	URL        string `json:"url"`
+}
+
+// DetailsResult is the result of a rule or of a group of rules. Results of
+// groups have children and no requirements.
+type DetailsResult struct {
+	Name              string     `json:"name"`
+	Description       string     `json:"description"`
+	Status            string     `json:"status"`
+	StatusDescription string     `json:"status_description"`
+	Error             string     `json:"error,omitempty"`
+	OverriddenBy      string     `json:"overridden_by,omitempty"`
+	ReevaluateAt      *time.Time `json:"reevaluate_at,omitempty"`
+
+	Predicates []*DetailsPredicate `json:"predicates"`
+	Requires   *DetailsRequires    `json:"requires,omitempty"`
+	Children   []*DetailsResult    `json:"children"`
+}
+
+type DetailsPredicate struct {
+	Satisfied       bool                `json:"satisfied"`
+	Description     string              `json:"description"`
+	ValuePhrase     string              `json:"value_phrase"`
+	Values          []string            `json:"values"`
+	ConditionPhrase string              `json:"condition_phrase"`
+	ConditionValues []string            `json:"condition_values"`
+	ConditionsMap   map[string][]string `json:"conditions_map,omitempty"`
+}
+
+type DetailsRequires struct {
+	Count         int                    `json:"count"`
+	Approvers     []*DetailsApproval     `json:"approvers"`
+	Conditions    []*DetailsPredicate    `json:"conditions"`
+	TeamCounts    []*DetailsTeamCount    `json:"team_counts"`
+	UserApprovals []*DetailsUserApproval `json:"user_approvals"`
+}
+
+// DetailsApproval is an approval from a user, either by a comment or by a
+// review, as indicated by Type.
+type DetailsApproval struct {
+	User      string    `json:"user"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type DetailsTeamCount struct {
+	Team      string             `json:"team"`
+	Count     int                `json:"count"`
+	Approvers []*DetailsApproval `json:"approvers"`
+}
+
+type DetailsUserApproval struct {
+	User     string           `json:"user"`
+	Approval *DetailsApproval `json:"approval"`
+}
+
+// acceptsJSON returns true if the request prefers a JSON response.
+func acceptsJSON(r *http.Request) bool {
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, _ := strings.Cut(accept, ";")
+		switch strings.TrimSpace(mediaType) {
+		case "application/json":
+			return true
+		case "text/html":
+			return false
+		}
+	}
+	return false
+}
+
+func newDetailsPullRequest(pr *github.PullRequest) DetailsPullRequest {
+	return DetailsPullRequest{
+		Owner:      pr.GetBase().GetRepo().GetOwner().GetLogin(),
+		Repository: pr.GetBase().GetRepo().GetName(),
+		Number:     pr.GetNumber(),
+		Title:      pr.GetTitle(),
+		Author:     pr.GetUser().GetLogin(),
+		HeadSHA:    pr.GetHead().GetSHA(),
+		URL:        pr.GetHTMLURL(),
+	}
+}
+
+func newDetailsResult(r *common.Result) *DetailsResult {
+	if r == nil {
+		return nil
+	}
+
+	res := &DetailsResult{
+		Name:              r.Name,
+		Description:       r.Description,
+		Status:            r.Status.String(),
+		StatusDescription: r.StatusDescription,
+		OverriddenBy:      r.OverriddenBy,
+		Predicates:        newDetailsPredicates(r.PredicateResults),
+		Children:          []*DetailsResult{},
+	}
+	if r.Error != nil {
+		res.Error = r.Error.Error()
+	}
+	if !r.ReevaluateAt.IsZero() {
+		t := r.ReevaluateAt
+		res.ReevaluateAt = &t
+	}
+
+	if len(r.Children) > 0 {
+		for _, c := range r.Children {
+			res.Children = append(res.Children, newDetailsResult(c))
+		}
+	} else {
+		res.Requires = newDetailsRequires(&r.Requires)
+	}
+	return res
+}
+
+func newDetailsPredicates(results []*common.PredicateResult) []*DetailsPredicate {
+	predicates := []*DetailsPredicate{}
+	for _, p := range results {
+		predicates = append(predicates, &DetailsPredicate{
+			Satisfied:       p.Satisfied,
+			Description:     p.Description,
+			ValuePhrase:     p.ValuePhrase,
+			Values:          nonNil(p.Values),
+			ConditionPhrase: p.ConditionPhrase,
+			ConditionValues: nonNil(p.ConditionValues),
+			ConditionsMap:   p.ConditionsMap,
+		})
+	}
+	return predicates
+}
+
+func newDetailsRequires(r *common.RequiresResult) *DetailsRequires {
+	req := &DetailsRequires{
+		Count:         r.Count,
+		Approvers:     newDetailsApprovals(r.Approvers),
+		Conditions:    newDetailsPredicates(r.Conditions),
+		TeamCounts:    []*DetailsTeamCount{},
+		UserApprovals: []*DetailsUserApproval{},
+	}
+	for _, tc := range r.TeamCounts {
+		req.TeamCounts = append(req.TeamCounts, &DetailsTeamCount{
+			Team:      tc.Team,
+			Count:     tc.Count,
+			Approvers: newDetailsApprovals(tc.Approvers),
+		})
+	}
+	for _, ua := range r.UserApprovals {
+		var approval *DetailsApproval
+		if ua.Approval != nil {
+			approval = newDetailsApproval(ua.Approval)
+		}
+		req.UserApprovals = append(req.UserApprovals, &DetailsUserApproval{
+			User:     ua.User,
+			Approval: approval,
+		})
+	}
+	return req
+}
+
+func newDetailsApprovals(candidates []*common.Candidate) []*DetailsApproval {
+	approvals := []*DetailsApproval{}
+	for _, c := range candidates {
+		approvals = append(approvals, newDetailsApproval(c))
+	}
+	return approvals
+}
+
+func newDetailsApproval(c *common.Candidate) *DetailsApproval {
+	return &DetailsApproval{
+		User:      c.User,
+		Type:      string(c.Type),
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+// nonNil returns an empty slice instead of nil so that lists are always
+// encoded as arrays instead of null.
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptsJSON(t *testing.T) {
+	tests := map[string]bool{
+		"":                                  false,
+		"application/json":                  true,
+		"application/json; charset=utf-8":   true,
+		"text/html, application/json":       false,
+		"application/json;q=0.9, text/html": true,
+		"text/html,application/xhtml+xml":   false,
+		"*/*":                               false,
+	}
+
+	for accept, expected := range tests {
+		r, err := http.NewRequest(http.MethodGet, "/details/testorg/testrepo/1", nil)
+		require.NoError(t, err)
+		r.Header.Set("Accept", accept)
+
+		assert.Equal(t, expected, acceptsJSON(r), "incorrect result for Accept: %q", accept)
+	}
+}
+
+func TestNewDetailsResult(t *testing.T) {
+	approvedAt := time.Date(2024, time.August, 14, 12, 0, 0, 0, time.UTC)
+
+	result := &common.Result{
+		Name:              "policy",
+		Status:            common.StatusPending,
+		StatusDescription: "0/1 rules approved",
+		Children: []*common.Result{
+			{
+				Name:              "review",
+				Description:       "Requires a review",
+				Status:            common.StatusPending,
+				StatusDescription: "1/2 required approvals",
+				PredicateResults: []*common.PredicateResult{
+					{
+						Satisfied:       true,
+						ValuePhrase:     "changed files",
+						Values:          []string{"server/handler.go"},
+						ConditionPhrase: "match",
+						ConditionsMap: map[string][]string{
+							"path patterns": {"^server/"},
+						},
+					},
+				},
+				Requires: common.RequiresResult{
+					Count: 2,
+					Approvers: []*common.Candidate{
+						{Type: common.ReviewCandidate, User: "mhaypenny", CreatedAt: approvedAt},
+					},
+				},
+			},
+			{
+				Name:   "broken",
+				Status: common.StatusSkipped,
+				Error:  errors.New("failed to list teams"),
+			},
+		},
+	}
+
+	b, err := json.Marshal(newDetailsResult(result))
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"name": "policy",
+		"description": "",
+		"status": "pending",
+		"status_description": "0/1 rules approved",
+		"predicates": [],
+		"children": [
+			{
+				"name": "review",
+				"description": "Requires a review",
+				"status": "pending",
+				"status_description": "1/2 required approvals",
+				"predicates": [
+					{
+						"satisfied": true,
+						"description": "",
+						"value_phrase": "changed files",
+						"values": ["server/handler.go"],
+						"condition_phrase": "match",
+						"condition_values": [],
+						"conditions_map": {"path patterns": ["^server/"]}
+					}
+				],
+				"requires": {
+					"count": 2,
+					"approvers": [
+						{"user": "mhaypenny", "type": "review", "created_at": "2024-08-14T12:00:00Z"}
+					],
+					"conditions": [],
+					"team_counts": [],
+					"user_approvals": []
+				},
+				"children": []
+			},
+			{
+				"name": "broken",
+				"description": "",
+				"status": "skipped",
+				"status_description": "",
+				"error": "failed to list teams",
+				"predicates": [],
+				"requires": {
+					"count": 0,
+					"approvers": [],
+					"conditions": [],
+					"team_counts": [],
+					"user_approvals": []
+				},
+				"children": []
+			}
+		]
+	}`, string(b))
+
+	assert.Nil(t, newDetailsResult(nil))
+}
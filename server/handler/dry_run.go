@@ -0,0 +1,171 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/google/go-github/v65/github"
+	"github.com/palantir/go-baseapp/baseapp"
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// maxDryRunPolicySize is the maximum size of a policy provided to DryRun.
+const maxDryRunPolicySize = 1 << 20
+
+// DryRun evaluates a policy provided in the request body against a pull
+// request and returns the result. Unlike Validate, it runs the full
+// evaluation, and unlike Simulate, it uses the provided policy with the
+// current state of the pull request. It never posts a status.
+//
+// The policy is evaluated with the permissions of the installation, so only
+// users with write access to the repository, who could change the policy in
+// the repository, may use it.
+type DryRun struct {
+	Base
+}
+
+// DryRunResponse is the response returned from DryRun.
+type DryRunResponse struct {
+	PullRequest DetailsPullRequest `json:"pull_request"`
+
+	// Error is the error that prevented evaluation or that occurred during
+	// evaluation, if any. Result may be nil if Error is set.
+	Error            string         `json:"error,omitempty"`
+	IsTemporaryError bool           `json:"is_temporary_error"`
+	Result           *DetailsResult `json:"result"`
+
+	// Status is the status that would be posted for the pull request, if any.
+	Status *DryRunStatus `json:"status,omitempty"`
+}
+
+type DryRunStatus struct {
+	State       string `json:"state"`
+	Description string `json:"description"`
+}
+
+func (h *DryRun) ServeHTTP(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	token := getToken(r)
+	if token == "" {
+		return writeAPIError(w, http.StatusUnauthorized, "missing token")
+	}
+
+	client, err := h.NewTokenClient(token)
+	if err != nil {
+		return errors.Wrap(err, "failed to create token client")
+	}
+
+	owner, repo, number, ok := parsePullParams(r)
+	if !ok {
+		return writeAPIError(w, http.StatusBadRequest, "failed to parse pull request parameters from request")
+	}
+
+	content, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxDryRunPolicySize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return writeAPIError(w, http.StatusRequestEntityTooLarge, "policy is too large")
+		}
+		return writeAPIError(w, http.StatusBadRequest, "failed to read policy from request")
+	}
+
+	// Load the pull request as the user to confirm they have access to it
+	pr, _, err := client.PullRequests.Get(ctx, owner, repo, number)
+	if err != nil {
+		if isNotFound(err) {
+			return writeAPIError(w, http.StatusNotFound, "failed to find pull request")
+		}
+
+		return errors.Wrap(err, "failed to get pull request")
+	}
+
+	// Load the repository as the user to confirm they have write access to it
+	repository, _, err := client.Repositories.Get(ctx, owner, repo)
+	if err != nil {
+		return errors.Wrap(err, "failed to get repository")
+	}
+	if !repository.GetPermissions()["push"] {
+		return writeAPIError(w, http.StatusForbidden, "write access to the repository is required")
+	}
+
+	installation, err := h.Installations.GetByOwner(ctx, owner)
+	if err != nil {
+		return writeAPIError(w, http.StatusNotFound, "not installed in org")
+	}
+
+	ctx, _ = h.PreparePRContext(ctx, installation.ID, pr)
+	evalCtx, err := h.newEvalContext(ctx, installation.ID, pull.Locator{
+		Owner:  owner,
+		Repo:   repo,
+		Number: number,
+		Value:  pr,
+	}, func(client *github.Client, _ pull.Context) FetchedConfig {
+		return h.ConfigFetcher.ConfigForContent(ctx, client, owner, "request", content)
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create evaluation context")
+	}
+
+	// The provided policy must not have side effects: store the status
+	// instead of posting it, do not share cached rule results with real
+	// evaluations, and do not schedule future evaluations.
+	evalCtx.SkipPostStatus = true
+	evalCtx.ResultCache = nil
+	evalCtx.ScheduleEvaluation = nil
+
+	res := DryRunResponse{
+		PullRequest: newDetailsPullRequest(pr),
+	}
+
+	evaluator, err := evalCtx.ParseConfig(ctx, common.TriggerAll)
+	if err != nil {
+		return writeAPIError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Policy is invalid. '%s'.", errors.Cause(err).Error()))
+	}
+	if evaluator == nil {
+		return writeAPIError(w, http.StatusUnprocessableEntity, "Policy is empty")
+	}
+
+	result, err := evalCtx.EvaluatePolicy(ctx, evaluator)
+	res.Result = newDetailsResult(&result)
+	if err != nil {
+		// Do not return the error, which may contain details of the
+		// evaluation that are not visible to the user
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Failed to evaluate provided policy")
+
+		res.Error = "Failed to evaluate policy"
+		if _, ok := errors.Cause(err).(*pull.TemporaryError); ok {
+			res.IsTemporaryError = true
+			res.Error = "Failed to evaluate policy due to a temporary error"
+		}
+	}
+	if s := evalCtx.Status; s != nil {
+		res.Status = &DryRunStatus{
+			State:       s.GetState(),
+			Description: s.GetDescription(),
+		}
+	}
+
+	// Intentionally skip evalCtx.RunPostEvaluateActions() so the provided
+	// policy cannot request reviewers, dismiss reviews, or take other actions
+
+	baseapp.WriteJSON(w, http.StatusOK, res)
+	return nil
+}
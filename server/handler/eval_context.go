@@ -18,9 +18,11 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/go-github/v65/github"
 	"github.com/palantir/policy-bot/policy"
+	"github.com/palantir/policy-bot/policy/approval"
 	"github.com/palantir/policy-bot/policy/common"
 	"github.com/palantir/policy-bot/pull"
 	"github.com/pkg/errors"
@@ -47,6 +49,28 @@ type EvalContext struct {
 	// callers should check for a non-nil status after each method call.
 	SkipPostStatus bool
 	Status         *github.RepoStatus
+
+	// StatusSHA, if set, is the commit that receives statuses instead of the
+	// head commit of the pull request.
+	StatusSHA string
+
+	// SkipReviewRequests disables reviewer assignment after evaluation. It is
+	// set when a user removed a review request, so that policy-bot does not
+	// immediately request the same reviewer again.
+	SkipReviewRequests bool
+
+	// ScheduleEvaluation, if non-nil, evaluates the pull request again after
+	// the delay. It is used when a pending result may change without any new
+	// activity on the pull request.
+	ScheduleEvaluation func(ctx context.Context, delay time.Duration)
+
+	// AppName is the slug of the GitHub app. It identifies the check runs
+	// created by this app when PostCheckRuns is enabled.
+	AppName string
+
+	// ResultCache, if non-nil, stores the results of approval rules that set
+	// the cache_result option and the break-glass overrides that were audited.
+	ResultCache pull.GlobalCache
 }
 
 // Evaluate runs the full process for evaluating a pull request.
@@ -59,7 +83,7 @@ func (ec *EvalContext) Evaluate(ctx context.Context, trigger common.Trigger) err
 		return nil
 	}
 
-	result, err := ec.EvaluatePolicy(ctx, evaluator)
+	result, err := ec.EvaluatePolicy(approval.WithRuleMetrics(ctx), evaluator)
 	if err != nil {
 		return err
 	}
@@ -95,13 +119,16 @@ func (ec *EvalContext) ParseConfig(ctx context.Context, trigger common.Trigger)
 		return nil, nil
 	}
 
-	evaluator, err := policy.ParsePolicy(fc.Config)
-	if err != nil {
-		msg := fmt.Sprintf("Invalid policy in %s: %s", fc.Source, fc.Path)
-		logger.Warn().Err(err).Msg(msg)
+	evaluator := fc.Evaluator
+	if evaluator == nil {
+		var err error
+		if evaluator, err = policy.ParsePolicy(fc.Config); err != nil {
+			msg := fmt.Sprintf("Invalid policy in %s: %s", fc.Source, fc.Path)
+			logger.Warn().Err(err).Msg(msg)
 
-		ec.PostStatus(ctx, "error", msg)
-		return nil, errors.Wrapf(err, "failed to create evaluator: %s: %s", fc.Source, fc.Path)
+			ec.PostStatus(ctx, "error", msg)
+			return nil, errors.Wrapf(err, "failed to create evaluator: %s: %s", fc.Source, fc.Path)
+		}
 	}
 
 	policyTrigger := evaluator.Trigger()
@@ -122,11 +149,21 @@ func (ec *EvalContext) ParseConfig(ctx context.Context, trigger common.Trigger)
 func (ec *EvalContext) EvaluatePolicy(ctx context.Context, evaluator common.Evaluator) (common.Result, error) {
 	logger := zerolog.Ctx(ctx)
 
+	if ec.ResultCache != nil {
+		ctx = approval.WithResultCache(ctx, ec.ResultCache)
+	}
+	ctx = approval.WithRuleConcurrency(ctx, ec.Options.RuleConcurrency)
+
 	result := evaluator.Evaluate(ctx, ec.PullContext)
 	if result.Error != nil {
 		msg := fmt.Sprintf("Error evaluating policy in %s: %s", ec.Config.Source, ec.Config.Path)
 		logger.Warn().Err(result.Error).Msg(msg)
 
+		var tooMany *pull.TooManyError
+		if errors.As(result.Error, &tooMany) {
+			msg = fmt.Sprintf("Pull request has too many %s to evaluate (GitHub returns at most %d)", tooMany.Resource, tooMany.Max)
+		}
+
 		ec.PostStatus(ctx, "error", msg)
 		return result, result.Error
 	}
@@ -149,7 +186,7 @@ func (ec *EvalContext) EvaluatePolicy(ctx context.Context, evaluator common.Eval
 		return result, err
 	}
 
-	ec.PostStatus(ctx, statusState, statusDescription)
+	ec.postStatusIfOpen(ctx, statusState, statusDescription, &result)
 	return result, nil
 }
 
@@ -162,6 +199,8 @@ func (ec *EvalContext) EvaluatePolicy(ctx context.Context, evaluator common.Eval
 func (ec *EvalContext) RunPostEvaluateActions(ctx context.Context, result common.Result, trigger common.Trigger) {
 	logger := zerolog.Ctx(ctx)
 
+	ec.auditOverride(ctx, result)
+
 	if err := ec.requestReviewsForResult(ctx, trigger, result); err != nil {
 		logger.Error().Err(err).Msg("Failed to request reviewers")
 	}
@@ -169,23 +208,100 @@ func (ec *EvalContext) RunPostEvaluateActions(ctx context.Context, result common
 	if err := ec.dismissStaleReviewsForResult(ctx, result); err != nil {
 		logger.Error().Err(err).Msg("Failed to dismiss stale reviews")
 	}
+
+	if next := result.NextReevaluation(); !next.IsZero() && ec.ScheduleEvaluation != nil {
+		delay := next.Sub(ec.PullContext.EvaluationTimestamp())
+		logger.Debug().Msgf("Scheduling re-evaluation in %s", delay)
+		ec.ScheduleEvaluation(ctx, delay)
+	}
+}
+
+// auditOverride records an audit log entry if the result was forced to pass
+// by a break-glass override. With a result cache, the entry is only recorded
+// by the first evaluation that applies the override to the head commit.
+func (ec *EvalContext) auditOverride(ctx context.Context, result common.Result) {
+	if result.OverriddenBy == "" {
+		return
+	}
+
+	if ec.ResultCache != nil {
+		key := fmt.Sprintf("audit:break_glass:%s/%s@%s:%s", ec.PullContext.RepositoryOwner(), ec.PullContext.RepositoryName(), ec.PullContext.HeadSHA(), result.OverriddenBy)
+		if _, ok := ec.ResultCache.GetRuleResult(key); ok {
+			zerolog.Ctx(ctx).Debug().Msg("Break-glass override was already audited")
+			return
+		}
+		ec.ResultCache.SetRuleResult(key, true)
+	}
+
+	zerolog.Ctx(ctx).Warn().
+		Str(LogKeyAudit, "break_glass").
+		Str("override_user", result.OverriddenBy).
+		Str("sha", ec.PullContext.HeadSHA()).
+		Msgf("Entity %s overrode the policy for %.7s using break-glass", result.OverriddenBy, ec.PullContext.HeadSHA())
+}
+
+// PostClosedStatus replaces a pending status on a closed PR with the status
+// configured by the ClosedStatus option. It does nothing if the option is not
+// set or if the current status is not pending.
+func (ec *EvalContext) PostClosedStatus(ctx context.Context, merged bool) error {
+	logger := zerolog.Ctx(ctx)
+
+	state := ec.Options.ClosedStatus
+	if state == "" {
+		return nil
+	}
+
+	statuses, err := ec.PullContext.LatestStatuses()
+	if err != nil {
+		return errors.Wrap(err, "failed to get statuses")
+	}
+
+	// in check run mode, pending results are check runs that are in progress
+	if current := statuses[ec.statusContext()]; current != "pending" && current != "in_progress" {
+		logger.Debug().Msgf("Skipping closed status because the current status is %q", current)
+		return nil
+	}
+
+	message := "Pull request was closed before the policy was satisfied"
+	if merged {
+		message = "Pull request was merged before the policy was satisfied"
+	}
+
+	ec.postStatus(ctx, state, message, nil)
+	return nil
 }
 
 // PostStatus posts a status for the evaluated PR.
 func (ec *EvalContext) PostStatus(ctx context.Context, state, message string) {
+	ec.postStatusIfOpen(ctx, state, message, nil)
+}
+
+func (ec *EvalContext) postStatusIfOpen(ctx context.Context, state, message string, result *common.Result) {
+	logger := zerolog.Ctx(ctx)
+
+	if !ec.SkipPostStatus && !ec.PullContext.IsOpen() {
+		logger.Info().Msg("Skipping status update because PR state is not open")
+		return
+	}
+
+	ec.postStatus(ctx, state, message, result)
+}
+
+// postStatus posts a status or a check run for the evaluated PR. If result is
+// non-nil, check runs include a summary of the result.
+func (ec *EvalContext) postStatus(ctx context.Context, state, message string, result *common.Result) {
 	logger := zerolog.Ctx(ctx)
 
 	owner := ec.PullContext.RepositoryOwner()
 	repo := ec.PullContext.RepositoryName()
-	sha := ec.PullContext.HeadSHA()
-	base, _ := ec.PullContext.Branches()
+	sha := ec.statusSHA()
 
 	publicURL := strings.TrimSuffix(ec.PublicURL, "/")
 	detailsURL := fmt.Sprintf("%s/details/%s/%s/%d", publicURL, owner, repo, ec.PullContext.Number())
 
 	status := github.RepoStatus{
 		State:       &state,
-		Context:     github.String(fmt.Sprintf("%s: %s", ec.Options.StatusCheckContext, base)),
+		Context:     github.String(ec.statusContext()),
 		Description: &message,
 		TargetURL:   &detailsURL,
 	}
@@ -195,8 +311,10 @@ func (ec *EvalContext) PostStatus(ctx context.Context, state, message string) {
 		return
 	}
 
-	if !ec.PullContext.IsOpen() {
-		logger.Info().Msg("Skipping status update because PR state is not open")
+	if ec.Options.PostCheckRuns {
+		if err := ec.postCheckRun(ctx, state, message, detailsURL, result); err != nil {
+			logger.Err(err).Msg("Failed to post check run")
+		}
 		return
 	}
 
@@ -210,3 +328,17 @@ func (ec *EvalContext) PostStatus(ctx context.Context, state, message string) {
 		}
 	}
 }
+
+// statusSHA returns the commit that receives statuses for the PR.
+func (ec *EvalContext) statusSHA() string {
+	if ec.StatusSHA != "" {
+		return ec.StatusSHA
+	}
+	return ec.PullContext.HeadSHA()
+}
+
+// statusContext returns the context of the status posted for the PR.
+func (ec *EvalContext) statusContext() string {
+	base, _ := ec.PullContext.Branches()
+	return fmt.Sprintf("%s: %s", ec.Options.StatusCheckContext, base)
+}
@@ -0,0 +1,208 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v65/github"
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// maxCheckRunAnnotations is the maximum number of annotations that GitHub
+// accepts in a single check run request.
+const maxCheckRunAnnotations = 50
+
+// postCheckRun creates or updates a check run with the same information as a
+// status. The name of the check run is the status context. If result is
+// non-nil, the output of the check run summarizes the rules in the result.
+func (ec *EvalContext) postCheckRun(ctx context.Context, state, message, detailsURL string, result *common.Result) error {
+	owner := ec.PullContext.RepositoryOwner()
+	repo := ec.PullContext.RepositoryName()
+	sha := ec.statusSHA()
+	name := ec.statusContext()
+
+	status, conclusion := checkRunState(state)
+	output := &github.CheckRunOutput{
+		Title:   github.String(message),
+		Summary: github.String(checkRunSummary(detailsURL, result)),
+	}
+	if result != nil {
+		output.Annotations = ec.checkRunAnnotations(result)
+	}
+
+	var completedAt *github.Timestamp
+	if conclusion != nil {
+		completedAt = &github.Timestamp{Time: time.Now()}
+	}
+
+	existing, err := ec.findCheckRun(ctx, owner, repo, sha, name)
+	if err != nil {
+		return err
+	}
+
+	zerolog.Ctx(ctx).Info().Msgf("Setting %q check run on %s to %s: %s", name, sha, state, message)
+
+	// Completed check runs cannot return to an in-progress state, so only
+	// update runs that are not complete or that keep the same conclusion.
+	// Otherwise, create a new run, which replaces the old run in the UI.
+	if existing != nil && (existing.GetStatus() != "completed" || conclusion != nil && existing.GetConclusion() == *conclusion) {
+		_, _, err := ec.Client.Checks.UpdateCheckRun(ctx, owner, repo, existing.GetID(), github.UpdateCheckRunOptions{
+			Name:        name,
+			DetailsURL:  &detailsURL,
+			Status:      &status,
+			Conclusion:  conclusion,
+			CompletedAt: completedAt,
+			Output:      output,
+		})
+		return errors.Wrap(err, "failed to update check run")
+	}
+
+	_, _, err = ec.Client.Checks.CreateCheckRun(ctx, owner, repo, github.CreateCheckRunOptions{
+		Name:        name,
+		HeadSHA:     sha,
+		DetailsURL:  &detailsURL,
+		Status:      &status,
+		Conclusion:  conclusion,
+		CompletedAt: completedAt,
+		Output:      output,
+	})
+	return errors.Wrap(err, "failed to create check run")
+}
+
+// findCheckRun returns the latest check run with name on the commit that was
+// created by this app, or nil if there is no such run.
+func (ec *EvalContext) findCheckRun(ctx context.Context, owner, repo, sha, name string) (*github.CheckRun, error) {
+	runs, _, err := ec.Client.Checks.ListCheckRunsForRef(ctx, owner, repo, sha, &github.ListCheckRunsOptions{
+		CheckName: &name,
+		Filter:    github.String("latest"),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list check runs")
+	}
+	for _, run := range runs.CheckRuns {
+		if run.GetApp().GetSlug() == ec.AppName {
+			return run, nil
+		}
+	}
+	return nil, nil
+}
+
+// checkRunState converts a status state to a check run status and conclusion.
+// The conclusion is nil if the check run is not complete.
+func checkRunState(state string) (string, *string) {
+	switch state {
+	case "pending":
+		return "in_progress", nil
+	case "success":
+		return "completed", github.String("success")
+	default:
+		// GitHub has no "error" conclusion for check runs, so errors fail
+		return "completed", github.String("failure")
+	}
+}
+
+// checkRunSummary returns a markdown summary of the rules in the result. The
+// summary lists disapproved, pending, and approved rules and links to the
+// details page, which has the full information.
+func checkRunSummary(detailsURL string, result *common.Result) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "See the [details page](%s) for more information.\n", detailsURL)
+	if result == nil {
+		return b.String()
+	}
+
+	sections := []struct {
+		Title  string
+		Status common.EvaluationStatus
+	}{
+		{"Disapproved", common.StatusDisapproved},
+		{"Pending", common.StatusPending},
+		{"Approved", common.StatusApproved},
+	}
+
+	rules := leafResults(result)
+	for _, s := range sections {
+		var lines []string
+		for _, r := range rules {
+			if r.Status == s.Status && r.Error == nil {
+				lines = append(lines, fmt.Sprintf("- **%s**: %s", r.Name, r.StatusDescription))
+			}
+		}
+		if len(lines) > 0 {
+			fmt.Fprintf(&b, "\n### %s\n\n%s\n", s.Title, strings.Join(lines, "\n"))
+		}
+	}
+	return b.String()
+}
+
+// checkRunAnnotations returns an annotation for each rule in the result that
+// is pending, disapproved, or has an error. GitHub requires annotations to
+// reference a file in the repository, so annotations reference the policy
+// file and are only created if it is in the same repository as the pull
+// request.
+func (ec *EvalContext) checkRunAnnotations(result *common.Result) []*github.CheckRunAnnotation {
+	repo := fmt.Sprintf("%s/%s", ec.PullContext.RepositoryOwner(), ec.PullContext.RepositoryName())
+	if ec.Config.Path == "" || !strings.HasPrefix(ec.Config.Source, repo+"@") {
+		return nil
+	}
+
+	var annotations []*github.CheckRunAnnotation
+	for _, r := range leafResults(result) {
+		var level, message string
+		switch {
+		case r.Error != nil:
+			level, message = "failure", r.Error.Error()
+		case r.Status == common.StatusDisapproved:
+			level, message = "failure", r.StatusDescription
+		case r.Status == common.StatusPending:
+			level, message = "warning", r.StatusDescription
+		default:
+			continue
+		}
+
+		annotations = append(annotations, &github.CheckRunAnnotation{
+			Path:            github.String(ec.Config.Path),
+			StartLine:       github.Int(1),
+			EndLine:         github.Int(1),
+			AnnotationLevel: github.String(level),
+			Title:           github.String(r.Name),
+			Message:         github.String(message),
+		})
+		if len(annotations) == maxCheckRunAnnotations {
+			break
+		}
+	}
+	return annotations
+}
+
+// leafResults returns the results in the tree rooted at result that have no
+// children, which are the results of individual rules.
+func leafResults(result *common.Result) []*common.Result {
+	if len(result.Children) == 0 {
+		return []*common.Result{result}
+	}
+
+	var leaves []*common.Result
+	for _, c := range result.Children {
+		leaves = append(leaves, leafResults(c)...)
+	}
+	return leaves
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull/pulltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckRunState(t *testing.T) {
+	status, conclusion := checkRunState("pending")
+	assert.Equal(t, "in_progress", status)
+	assert.Nil(t, conclusion)
+
+	status, conclusion = checkRunState("success")
+	assert.Equal(t, "completed", status)
+	assert.Equal(t, "success", *conclusion)
+
+	for _, state := range []string{"failure", "error"} {
+		status, conclusion = checkRunState(state)
+		assert.Equal(t, "completed", status)
+		assert.Equal(t, "failure", *conclusion)
+	}
+}
+
+func testCheckRunResult() *common.Result {
+	return &common.Result{
+		Name:   "policy",
+		Status: common.StatusPending,
+		Children: []*common.Result{
+			{
+				Name:   "approval",
+				Status: common.StatusPending,
+				Children: []*common.Result{
+					{Name: "review", Status: common.StatusPending, StatusDescription: "0/1 required approvals"},
+					{Name: "docs", Status: common.StatusApproved, StatusDescription: "Approved by mhaypenny"},
+					{Name: "skipped", Status: common.StatusSkipped, StatusDescription: "A precondition of this rule was not satisfied"},
+					{Name: "broken", Status: common.StatusSkipped, Error: errors.New("failed to list teams")},
+				},
+			},
+			{Name: "disapproval", Status: common.StatusDisapproved, StatusDescription: "Disapproved by ttest"},
+		},
+	}
+}
+
+func TestCheckRunSummary(t *testing.T) {
+	detailsURL := "https://policy-bot.localhost/details/testorg/testrepo/1"
+
+	summary := checkRunSummary(detailsURL, testCheckRunResult())
+	assert.Equal(t, `See the [details page](https://policy-bot.localhost/details/testorg/testrepo/1) for more information.
+
+### Disapproved
+
+- **disapproval**: Disapproved by ttest
+
+### Pending
+
+- **review**: 0/1 required approvals
+
+### Approved
+
+- **docs**: Approved by mhaypenny
+`, summary)
+
+	summary = checkRunSummary(detailsURL, nil)
+	assert.Equal(t, "See the [details page](https://policy-bot.localhost/details/testorg/testrepo/1) for more information.\n", summary)
+}
+
+func TestCheckRunAnnotations(t *testing.T) {
+	newEvalContext := func(source string) *EvalContext {
+		return &EvalContext{
+			PullContext: &pulltest.Context{
+				OwnerValue: "testorg",
+				RepoValue:  "testrepo",
+			},
+			Config: FetchedConfig{
+				Source: source,
+				Path:   ".policy.yml",
+			},
+		}
+	}
+
+	t.Run("sameRepository", func(t *testing.T) {
+		ec := newEvalContext("testorg/testrepo@develop")
+		annotations := ec.checkRunAnnotations(testCheckRunResult())
+
+		if assert.Len(t, annotations, 3) {
+			assert.Equal(t, "review", annotations[0].GetTitle())
+			assert.Equal(t, "warning", annotations[0].GetAnnotationLevel())
+			assert.Equal(t, ".policy.yml", annotations[0].GetPath())
+			assert.Equal(t, 1, annotations[0].GetStartLine())
+
+			assert.Equal(t, "broken", annotations[1].GetTitle())
+			assert.Equal(t, "failure", annotations[1].GetAnnotationLevel())
+			assert.Equal(t, "failed to list teams", annotations[1].GetMessage())
+
+			assert.Equal(t, "disapproval", annotations[2].GetTitle())
+			assert.Equal(t, "failure", annotations[2].GetAnnotationLevel())
+		}
+	})
+
+	t.Run("sharedRepository", func(t *testing.T) {
+		ec := newEvalContext("testorg/.github@main")
+		assert.Empty(t, ec.checkRunAnnotations(testCheckRunResult()))
+	})
+}
@@ -16,6 +16,7 @@ package handler
 
 import (
 	"context"
+	"strings"
 
 	"github.com/palantir/policy-bot/policy/common"
 	"github.com/pkg/errors"
@@ -23,6 +24,24 @@ import (
 	"github.com/shurcooL/githubv4"
 )
 
+// dismissalMessageData contains the values available to dismissal message
+// templates.
+type dismissalMessageData struct {
+	// Reason is the reason policy-bot dismissed the review
+	Reason string
+
+	// Reviewer is the author of the dismissed review
+	Reviewer string
+
+	// Rule is the name of the rule that dismissed the review
+	Rule string
+
+	// Owner, Repo, and Number identify the pull request
+	Owner  string
+	Repo   string
+	Number int
+}
+
 func (ec *EvalContext) dismissStaleReviewsForResult(ctx context.Context, result common.Result) error {
 	logger := zerolog.Ctx(ctx)
 
@@ -43,8 +62,14 @@ func (ec *EvalContext) dismissStaleReviewsForResult(ctx context.Context, result
 			continue
 		}
 
+		message, err := ec.dismissalMessage(d)
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to render dismissal message, using the dismissal reason instead")
+			message = d.Reason
+		}
+
 		logger.Info().Str("reason", d.Reason).Msgf("Dismissing stale review %s", d.Candidate.ReviewID)
-		if err := dismissPullRequestReview(ctx, ec.V4Client, d.Candidate.ReviewID, d.Reason); err != nil {
+		if err := dismissPullRequestReview(ctx, ec.V4Client, d.Candidate.ReviewID, message); err != nil {
 			return err
 		}
 		alreadyDismissed[d.Candidate.ReviewID] = true
@@ -53,11 +78,85 @@ func (ec *EvalContext) dismissStaleReviewsForResult(ctx context.Context, result
 	return nil
 }
 
-func findAllDismissals(result *common.Result) []*common.Dismissal {
-	var dismissals []*common.Dismissal
+// dismissalMessage returns the message to use when dismissing a review,
+// rendering the configured template if one exists.
+func (ec *EvalContext) dismissalMessage(d ruleDismissal) (string, error) {
+	if ec.Options == nil || ec.Options.DismissalMessage == "" {
+		return d.Reason, nil
+	}
+
+	tmpl := ec.Options.dismissalTemplate
+	if tmpl == nil {
+		return "", errors.New("dismissal message template was not validated")
+	}
+
+	data := dismissalMessageData{
+		Reason:   d.Reason,
+		Reviewer: d.Candidate.User,
+		Rule:     d.Rule,
+		Owner:    ec.PullContext.RepositoryOwner(),
+		Repo:     ec.PullContext.RepositoryName(),
+		Number:   ec.PullContext.Number(),
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", errors.Wrap(err, "failed to render dismissal message template")
+	}
+	return b.String(), nil
+}
+
+// loadDismissalFiles sets the files changed by the invalidating commit of each
+// dismissal caused by a push. This is only needed to show the files on the
+// details page, so normal evaluations skip it. Files are loaded on a best-effort
+// basis and dismissals keep no files if loading fails.
+//
+// Results may share dismissals with cached rule results, so the dismissals are
+// replaced with copies before setting their files.
+func (ec *EvalContext) loadDismissalFiles(ctx context.Context, result *common.Result) {
+	logger := zerolog.Ctx(ctx)
+
+	if len(result.Children) == 0 && result.Error == nil && len(result.Dismissals) > 0 {
+		dismissals := make([]*common.Dismissal, len(result.Dismissals))
+		for i, d := range result.Dismissals {
+			dismissal := *d
+			dismissals[i] = &dismissal
+
+			if d.Commit == "" || d.Files != nil {
+				continue
+			}
+
+			files, err := ec.PullContext.CommitFiles(d.Commit)
+			if err != nil {
+				logger.Warn().Err(err).Msgf("Failed to load files changed by commit %s", d.Commit)
+				continue
+			}
+
+			dismissal.Files = make([]string, len(files))
+			for j, f := range files {
+				dismissal.Files[j] = f.Filename
+			}
+		}
+		result.Dismissals = dismissals
+	}
+	for _, c := range result.Children {
+		ec.loadDismissalFiles(ctx, c)
+	}
+}
+
+// ruleDismissal is a dismissal and the name of the rule that created it.
+type ruleDismissal struct {
+	*common.Dismissal
+	Rule string
+}
+
+func findAllDismissals(result *common.Result) []ruleDismissal {
+	var dismissals []ruleDismissal
 
 	if len(result.Children) == 0 && result.Error == nil {
-		dismissals = append(dismissals, result.Dismissals...)
+		for _, d := range result.Dismissals {
+			dismissals = append(dismissals, ruleDismissal{Dismissal: d, Rule: result.Name})
+		}
 	}
 	for _, c := range result.Children {
 		dismissals = append(dismissals, findAllDismissals(c)...)
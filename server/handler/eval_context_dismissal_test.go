@@ -0,0 +1,151 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/palantir/policy-bot/pull/pulltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDismissalMessage(t *testing.T) {
+	d := ruleDismissal{
+		Dismissal: &common.Dismissal{
+			Candidate: &common.Candidate{
+				Type:     common.ReviewCandidate,
+				ReviewID: "review-1",
+				User:     "ttest",
+			},
+			Reason: "Invalidated by push of e05fcae",
+		},
+		Rule: "review from devtools",
+	}
+
+	newEvalContext := func(t *testing.T, message string) *EvalContext {
+		opts := &PullEvaluationOptions{
+			StatusCheckContext: DefaultStatusCheckContext,
+			DismissalMessage:   message,
+		}
+		require.NoError(t, opts.Validate())
+
+		return &EvalContext{
+			Options: opts,
+			PullContext: &pulltest.Context{
+				OwnerValue:  "testorg",
+				RepoValue:   "testrepo",
+				NumberValue: 123,
+			},
+		}
+	}
+
+	t.Run("defaultMessage", func(t *testing.T) {
+		msg, err := newEvalContext(t, "").dismissalMessage(d)
+		require.NoError(t, err)
+		assert.Equal(t, "Invalidated by push of e05fcae", msg)
+	})
+
+	t.Run("template", func(t *testing.T) {
+		ec := newEvalContext(t, "@{{.Reviewer}}, your review no longer counts for {{.Rule}} on {{.Owner}}/{{.Repo}}#{{.Number}}: {{.Reason}}")
+
+		msg, err := ec.dismissalMessage(d)
+		require.NoError(t, err)
+		assert.Equal(t, "@ttest, your review no longer counts for review from devtools on testorg/testrepo#123: Invalidated by push of e05fcae", msg)
+	})
+
+	t.Run("notValidated", func(t *testing.T) {
+		ec := &EvalContext{
+			Options: &PullEvaluationOptions{
+				DismissalMessage: "{{.Reason}}",
+			},
+		}
+		_, err := ec.dismissalMessage(d)
+		assert.Error(t, err)
+	})
+}
+
+func TestFindAllDismissals(t *testing.T) {
+	dismissal := &common.Dismissal{
+		Candidate: &common.Candidate{User: "ttest"},
+		Reason:    "Expired",
+	}
+
+	result := &common.Result{
+		Name: "policy",
+		Children: []*common.Result{
+			{
+				Name:       "rule-a",
+				Dismissals: []*common.Dismissal{dismissal},
+			},
+			{
+				Name:       "rule-b",
+				Error:      assert.AnError,
+				Dismissals: []*common.Dismissal{dismissal},
+			},
+		},
+	}
+
+	dismissals := findAllDismissals(result)
+	require.Len(t, dismissals, 1)
+	assert.Equal(t, "rule-a", dismissals[0].Rule)
+	assert.Equal(t, dismissal, dismissals[0].Dismissal)
+}
+
+func TestLoadDismissalFiles(t *testing.T) {
+	pushed := &common.Dismissal{
+		Candidate: &common.Candidate{User: "ttest"},
+		Reason:    "Invalidated by push of e05fcae",
+		Commit:    "e05fcae367230ee709313dd2720da527d178ce43",
+	}
+	expired := &common.Dismissal{
+		Candidate: &common.Candidate{User: "mhaypenny"},
+		Reason:    "Expired",
+	}
+
+	result := &common.Result{
+		Name: "policy",
+		Children: []*common.Result{
+			{
+				Name:       "rule-a",
+				Dismissals: []*common.Dismissal{pushed, expired},
+			},
+		},
+	}
+
+	ec := &EvalContext{
+		PullContext: &pulltest.Context{
+			CommitFilesValue: map[string][]*pull.File{
+				"e05fcae367230ee709313dd2720da527d178ce43": {
+					{Filename: "app/main.go", Status: pull.FileModified},
+					{Filename: "README.md", Status: pull.FileAdded},
+				},
+			},
+		},
+	}
+
+	ec.loadDismissalFiles(context.Background(), result)
+
+	dismissals := result.Children[0].Dismissals
+	require.Len(t, dismissals, 2)
+	assert.Equal(t, []string{"app/main.go", "README.md"}, dismissals[0].Files)
+	assert.Nil(t, dismissals[1].Files, "dismissals without a commit should not have files")
+
+	assert.Nil(t, pushed.Files, "original dismissal was modified")
+	assert.Equal(t, pushed.Commit, dismissals[0].Commit)
+}
@@ -31,7 +31,7 @@ import (
 func (ec *EvalContext) requestReviewsForResult(ctx context.Context, trigger common.Trigger, result common.Result) error {
 	logger := zerolog.Ctx(ctx)
 
-	if ec.PullContext.IsDraft() || result.Status != common.StatusPending {
+	if ec.SkipReviewRequests || ec.PullContext.IsDraft() || result.Status != common.StatusPending {
 		return nil
 	}
 
@@ -61,7 +61,12 @@ func (ec *EvalContext) requestReviews(ctx context.Context, reqs []*common.Result
 	// evaluations produce the same set of reviewers. This is required to avoid
 	// duplicate requests on later evaluations.
 	r := rand.New(rand.NewSource(ec.PullContext.CreatedAt().UnixNano()))
-	selection, err := reviewer.SelectReviewers(ctx, ec.PullContext, reqs, r)
+	selectReviewers := reviewer.SelectReviewers
+	if ec.Options.MergeReviewRequests {
+		selectReviewers = reviewer.SelectMergedReviewers
+	}
+
+	selection, err := selectReviewers(ctx, ec.PullContext, reqs, r)
 	if err != nil {
 		return errors.Wrap(err, "failed to select reviewers")
 	}
@@ -95,15 +100,15 @@ func (ec *EvalContext) requestReviews(ctx context.Context, reqs []*common.Result
 	// After a user leaves a review, GitHub removes the user from the request
 	// list. If the review didn't actually change the state of the requesting
 	// rule, policy-bot may request the same user again. To avoid this, include
-	// any reviews on the head commit in the set of existing reviewers to avoid
-	// re-requesting them until the content changes.
+	// any reviews on the head commit that were not dismissed in the set of
+	// existing reviewers to avoid re-requesting them until the content changes.
 	head := ec.PullContext.HeadSHA()
 	reviews, err := ec.PullContext.Reviews()
 	if err != nil {
 		return err
 	}
 	for _, r := range reviews {
-		if r.SHA == head {
+		if r.SHA == head && r.State != pull.ReviewDismissed {
 			reviewers = append(reviewers, &pull.Reviewer{
 				Type: pull.ReviewerUser,
 				Name: r.Author,
@@ -0,0 +1,229 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/palantir/policy-bot/pull/pulltest"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditOverride(t *testing.T) {
+	ec := &EvalContext{
+		PullContext: &pulltest.Context{
+			HeadSHAValue: "e05fcae367230ee709313dd2720da527d178ce43",
+		},
+	}
+
+	t.Run("overridden", func(t *testing.T) {
+		var buf bytes.Buffer
+		ctx := zerolog.New(&buf).WithContext(context.Background())
+
+		ec.auditOverride(ctx, common.Result{
+			Status:       common.StatusApproved,
+			OverriddenBy: "emergency",
+		})
+
+		var entry map[string]string
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+
+		assert.Equal(t, "warn", entry["level"])
+		assert.Equal(t, "break_glass", entry[LogKeyAudit])
+		assert.Equal(t, "emergency", entry["override_user"])
+		assert.Equal(t, "e05fcae367230ee709313dd2720da527d178ce43", entry["sha"])
+	})
+
+	t.Run("onlyFirstOverride", func(t *testing.T) {
+		cache, err := pull.NewLRUGlobalCache(1, 1, 1, 1)
+		require.NoError(t, err)
+
+		ec := &EvalContext{
+			PullContext: &pulltest.Context{
+				HeadSHAValue: "e05fcae367230ee709313dd2720da527d178ce43",
+			},
+			ResultCache: cache,
+		}
+		result := common.Result{
+			Status:       common.StatusApproved,
+			OverriddenBy: "emergency",
+		}
+
+		var buf bytes.Buffer
+		ctx := zerolog.New(&buf).Level(zerolog.WarnLevel).WithContext(context.Background())
+
+		ec.auditOverride(ctx, result)
+		assert.NotEmpty(t, buf.String())
+
+		buf.Reset()
+		ec.auditOverride(ctx, result)
+		assert.Empty(t, buf.String(), "override was audited more than once")
+	})
+
+	t.Run("notOverridden", func(t *testing.T) {
+		var buf bytes.Buffer
+		ctx := zerolog.New(&buf).WithContext(context.Background())
+
+		ec.auditOverride(ctx, common.Result{
+			Status: common.StatusApproved,
+		})
+
+		assert.Empty(t, buf.String())
+	})
+}
+
+func TestPostClosedStatus(t *testing.T) {
+	ctx := context.Background()
+
+	newEvalContext := func(closedStatus, currentStatus string) *EvalContext {
+		return &EvalContext{
+			Options: &PullEvaluationOptions{
+				StatusCheckContext: "policy-bot",
+				ClosedStatus:       closedStatus,
+			},
+			PullContext: &pulltest.Context{
+				StateValue:     "closed",
+				BranchBaseName: "develop",
+				LatestStatusesValue: map[string]string{
+					"policy-bot: develop": currentStatus,
+				},
+			},
+			SkipPostStatus: true,
+		}
+	}
+
+	t.Run("closedWhilePending", func(t *testing.T) {
+		ec := newEvalContext("error", "pending")
+		require.NoError(t, ec.PostClosedStatus(ctx, false))
+
+		if assert.NotNil(t, ec.Status, "status was not posted") {
+			assert.Equal(t, "error", ec.Status.GetState())
+			assert.Equal(t, "policy-bot: develop", ec.Status.GetContext())
+			assert.Equal(t, "Pull request was closed before the policy was satisfied", ec.Status.GetDescription())
+		}
+	})
+
+	t.Run("mergedWhilePending", func(t *testing.T) {
+		ec := newEvalContext("failure", "pending")
+		require.NoError(t, ec.PostClosedStatus(ctx, true))
+
+		if assert.NotNil(t, ec.Status, "status was not posted") {
+			assert.Equal(t, "failure", ec.Status.GetState())
+			assert.Equal(t, "Pull request was merged before the policy was satisfied", ec.Status.GetDescription())
+		}
+	})
+
+	t.Run("closedAfterApproval", func(t *testing.T) {
+		ec := newEvalContext("error", "success")
+		require.NoError(t, ec.PostClosedStatus(ctx, true))
+
+		assert.Nil(t, ec.Status, "status was incorrectly posted")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		ec := newEvalContext("", "pending")
+		require.NoError(t, ec.PostClosedStatus(ctx, false))
+
+		assert.Nil(t, ec.Status, "status was incorrectly posted")
+	})
+}
+
+type staticEvaluator common.Result
+
+func (eval *staticEvaluator) Trigger() common.Trigger {
+	return common.TriggerStatic
+}
+
+func (eval *staticEvaluator) Evaluate(ctx context.Context, prctx pull.Context) common.Result {
+	return common.Result(*eval)
+}
+
+func TestEvaluatePolicyError(t *testing.T) {
+	ctx := context.Background()
+
+	newEvalContext := func() *EvalContext {
+		return &EvalContext{
+			Options: &PullEvaluationOptions{
+				StatusCheckContext: "policy-bot",
+			},
+			PullContext: &pulltest.Context{
+				StateValue:     "open",
+				BranchBaseName: "develop",
+			},
+			Config: FetchedConfig{
+				Source: "testorg/testrepo@develop",
+				Path:   ".policy.yml",
+			},
+			SkipPostStatus: true,
+		}
+	}
+
+	t.Run("genericError", func(t *testing.T) {
+		ec := newEvalContext()
+
+		_, err := ec.EvaluatePolicy(ctx, &staticEvaluator{Error: errors.New("failed to list reviews")})
+		require.Error(t, err)
+
+		if assert.NotNil(t, ec.Status, "status was not posted") {
+			assert.Equal(t, "error", ec.Status.GetState())
+			assert.Equal(t, "Error evaluating policy in testorg/testrepo@develop: .policy.yml", ec.Status.GetDescription())
+		}
+	})
+
+	t.Run("tooManyCommits", func(t *testing.T) {
+		ec := newEvalContext()
+
+		_, err := ec.EvaluatePolicy(ctx, &staticEvaluator{Error: errors.Wrap(pull.ErrTooManyCommits, "failed to list commits")})
+		require.Error(t, err)
+
+		if assert.NotNil(t, ec.Status, "status was not posted") {
+			assert.Equal(t, "error", ec.Status.GetState())
+			assert.Equal(t, "Pull request has too many commits to evaluate (GitHub returns at most 250)", ec.Status.GetDescription())
+		}
+	})
+}
+
+func TestSkipReviewRequests(t *testing.T) {
+	ec := &EvalContext{
+		PullContext:        &pulltest.Context{},
+		SkipReviewRequests: true,
+	}
+
+	result := common.Result{
+		Status: common.StatusPending,
+		Children: []*common.Result{
+			{
+				Name:   "rule",
+				Status: common.StatusPending,
+				ReviewRequestRule: &common.ReviewRequestRule{
+					Users:         []string{"reviewer"},
+					RequiredCount: 1,
+					Mode:          common.RequestModeAllUsers,
+				},
+			},
+		},
+	}
+
+	// requesting reviews would fail because the context has no client
+	assert.NoError(t, ec.requestReviewsForResult(context.Background(), common.TriggerPullRequest, result))
+}
@@ -15,8 +15,14 @@
 package handler
 
 import (
+	"io"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
 )
 
 const (
@@ -24,6 +30,7 @@ const (
 	DefaultSharedRepository   = ".github"
 	DefaultSharedPolicyPath   = "policy.yml"
 	DefaultStatusCheckContext = "policy-bot"
+	DefaultRuleConcurrency    = 4
 )
 
 type PullEvaluationOptions struct {
@@ -34,6 +41,10 @@ type PullEvaluationOptions struct {
 
 	// StatusCheckContext will be used to create the status context. It will be used in the following
 	// pattern: <StatusCheckContext>: <Base Branch Name>
+	//
+	// Instances of the server that share repositories must use different
+	// contexts. Changing the context does not update existing statuses or
+	// branch protection rules that require the old context.
 	StatusCheckContext string `yaml:"status_check_context"`
 
 	// ExpandRequiredReviewers enables a UI feature where the details page
@@ -47,6 +58,45 @@ type PullEvaluationOptions struct {
 	// context behaviour, and will be removed in 2.0
 	PostInsecureStatusChecks bool `yaml:"post_insecure_status_checks"`
 
+	// PostCheckRuns posts the result of evaluation as a check run instead of
+	// a commit status. The check run has the same name as the status context
+	// and its output summarizes the rules of the policy. This requires the
+	// "Checks" write permission. Statuses for merge groups and new
+	// installations are always posted as commit statuses.
+	PostCheckRuns bool `yaml:"post_check_runs"`
+
+	// MergeReviewRequests selects reviewers for all pending rules together
+	// instead of for each rule independently. When several rules request
+	// users, users who can review for more than one rule are preferred so
+	// that fewer users are requested overall.
+	MergeReviewRequests bool `yaml:"merge_review_requests"`
+
+	// RuleConcurrency is the maximum number of rules in "and" and "or" blocks
+	// that are evaluated in parallel for each pull request. Set it to 1 to
+	// evaluate rules one at a time. If zero, DefaultRuleConcurrency is used.
+	RuleConcurrency int `yaml:"rule_concurrency"`
+
+	// Regions maps region names to the users in each region. Policies can use
+	// the has_approver_in_region predicate to require approval from users in
+	// specific regions or in a region other than the author's. Each user may
+	// be in at most one region.
+	Regions map[string][]string `yaml:"regions"`
+
+	// ClosedStatus is the state of the status posted when a pull request
+	// closes while its policy status is still pending. It must be "failure"
+	// or "error". A successful status is not allowed because the commit was
+	// never approved and could be reused by another pull request or branch.
+	// If empty, pending statuses are left as they are when pull requests close.
+	ClosedStatus string `yaml:"closed_status"`
+
+	// DismissalMessage is a Go template for the message used when dismissing
+	// stale reviews. If empty, the message is the reason for the dismissal.
+	// See dismissalMessageData for the values available to the template.
+	DismissalMessage string `yaml:"dismissal_message"`
+
+	// dismissalTemplate is the parsed DismissalMessage, set by Validate
+	dismissalTemplate *template.Template
+
 	// This field is unused but is left to avoid breaking configuration files.
 	// This value is now loaded from the GitHub API.
 	//
@@ -84,6 +134,76 @@ func (p *PullEvaluationOptions) fillDefaults() {
 	if p.StatusCheckContext == "" {
 		p.StatusCheckContext = DefaultStatusCheckContext
 	}
+
+	if p.RuleConcurrency == 0 {
+		p.RuleConcurrency = DefaultRuleConcurrency
+	}
+}
+
+// Validate returns an error if the options are not valid and parses the
+// dismissal message template. It should be called after defaults are filled.
+func (p *PullEvaluationOptions) Validate() error {
+	switch {
+	case strings.TrimSpace(p.StatusCheckContext) == "":
+		return errors.New("status_check_context must not be empty")
+	case strings.TrimSpace(p.StatusCheckContext) != p.StatusCheckContext:
+		return errors.Errorf("status_check_context %q must not start or end with whitespace", p.StatusCheckContext)
+	case strings.Contains(p.StatusCheckContext, ":"):
+		// the context is joined to branch names with a colon, so a colon in
+		// the context could match the statuses of a different instance
+		return errors.Errorf("status_check_context %q must not contain ':'", p.StatusCheckContext)
+	case p.RuleConcurrency < 0:
+		return errors.Errorf("rule_concurrency must not be negative, got %d", p.RuleConcurrency)
+	}
+
+	switch p.ClosedStatus {
+	case "", "failure", "error":
+	default:
+		return errors.Errorf("closed_status %q must be one of failure or error", p.ClosedStatus)
+	}
+
+	p.dismissalTemplate = nil
+	if p.DismissalMessage != "" {
+		tmpl, err := template.New("dismissal").Option("missingkey=error").Parse(p.DismissalMessage)
+		if err != nil {
+			return errors.Wrap(err, "invalid dismissal_message")
+		}
+		// Render the template once to find references to unknown values
+		if err := tmpl.Execute(io.Discard, dismissalMessageData{}); err != nil {
+			return errors.Wrap(err, "invalid dismissal_message")
+		}
+		p.dismissalTemplate = tmpl
+	}
+
+	regions := make([]string, 0, len(p.Regions))
+	for region := range p.Regions {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+
+	seen := make(map[string]string)
+	for _, region := range regions {
+		for _, user := range p.Regions[region] {
+			user = strings.ToLower(user)
+			if other, ok := seen[user]; ok && other != region {
+				return errors.Errorf("user %q is in multiple regions: %q and %q", user, other, region)
+			}
+			seen[user] = region
+		}
+	}
+	return nil
+}
+
+// UserRegions returns a map from lowercase usernames to the region of each
+// user in Regions.
+func (p *PullEvaluationOptions) UserRegions() map[string]string {
+	regions := make(map[string]string)
+	for region, users := range p.Regions {
+		for _, user := range users {
+			regions[strings.ToLower(user)] = region
+		}
+	}
+	return regions
 }
 
 func (p *PullEvaluationOptions) SetValuesFromEnv(prefix string) {
@@ -93,6 +213,11 @@ func (p *PullEvaluationOptions) SetValuesFromEnv(prefix string) {
 	setStringFromEnv("STATUS_CHECK_CONTEXT", prefix, &p.StatusCheckContext)
 	setBoolFromEnv("EXPAND_REQUIRED_REVIEWERS", prefix, &p.ExpandRequiredReviewers)
 	setBoolFromEnv("POST_INSECURE_STATUS_CHECKS", prefix, &p.PostInsecureStatusChecks)
+	setBoolFromEnv("POST_CHECK_RUNS", prefix, &p.PostCheckRuns)
+	setBoolFromEnv("MERGE_REVIEW_REQUESTS", prefix, &p.MergeReviewRequests)
+	setIntFromEnv("RULE_CONCURRENCY", prefix, &p.RuleConcurrency)
+	setStringFromEnv("CLOSED_STATUS", prefix, &p.ClosedStatus)
+	setStringFromEnv("DISMISSAL_MESSAGE", prefix, &p.DismissalMessage)
 	p.fillDefaults()
 }
 
@@ -121,3 +246,13 @@ func setBoolFromEnv(key, prefix string, value *bool) bool {
 	}
 	return false
 }
+
+func setIntFromEnv(key, prefix string, value *int) bool {
+	if v, ok := os.LookupEnv(prefix + key); ok {
+		if i, err := strconv.Atoi(v); err == nil {
+			*value = i
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,154 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPullEvaluationOptionsValidate(t *testing.T) {
+	tests := map[string]struct {
+		Context string
+		Valid   bool
+	}{
+		"default":    {Context: DefaultStatusCheckContext, Valid: true},
+		"custom":     {Context: "policy-bot-staging", Valid: true},
+		"spaces":     {Context: "policy bot (staging)", Valid: true},
+		"empty":      {Context: "", Valid: false},
+		"whitespace": {Context: "   ", Valid: false},
+		"padded":     {Context: " policy-bot ", Valid: false},
+		"colon":      {Context: "policy-bot: staging", Valid: false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := PullEvaluationOptions{StatusCheckContext: test.Context}
+
+			err := opts.Validate()
+			if test.Valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestPullEvaluationOptionsRegions(t *testing.T) {
+	opts := PullEvaluationOptions{
+		StatusCheckContext: DefaultStatusCheckContext,
+		Regions: map[string][]string{
+			"amer": {"mhaypenny", "Carol"},
+			"emea": {"alice"},
+		},
+	}
+	assert.NoError(t, opts.Validate())
+	assert.Equal(t, map[string]string{
+		"mhaypenny": "amer",
+		"carol":     "amer",
+		"alice":     "emea",
+	}, opts.UserRegions())
+
+	opts.Regions["apac"] = []string{"carol"}
+	assert.EqualError(t, opts.Validate(), `user "carol" is in multiple regions: "amer" and "apac"`)
+}
+
+func TestPullEvaluationOptionsDismissalMessage(t *testing.T) {
+	tests := map[string]struct {
+		Message string
+		Valid   bool
+	}{
+		"empty":        {Message: "", Valid: true},
+		"template":     {Message: "@{{.Reviewer}}: {{.Reason}}", Valid: true},
+		"invalid":      {Message: "{{.Reason", Valid: false},
+		"unknownField": {Message: "{{.Approver}}", Valid: false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := PullEvaluationOptions{
+				StatusCheckContext: DefaultStatusCheckContext,
+				DismissalMessage:   test.Message,
+			}
+
+			err := opts.Validate()
+			if test.Valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestPullEvaluationOptionsClosedStatus(t *testing.T) {
+	tests := map[string]bool{
+		"":        true,
+		"failure": true,
+		"error":   true,
+		"success": false,
+		"neutral": false,
+	}
+
+	for status, valid := range tests {
+		t.Run(status, func(t *testing.T) {
+			opts := PullEvaluationOptions{
+				StatusCheckContext: DefaultStatusCheckContext,
+				ClosedStatus:       status,
+			}
+
+			err := opts.Validate()
+			if valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestPullEvaluationOptionsValidateFromEnv(t *testing.T) {
+	tests := map[string]struct {
+		Env   map[string]string
+		Valid bool
+	}{
+		"defaults":             {Valid: true},
+		"closedStatus":         {Env: map[string]string{"CLOSED_STATUS": "error"}, Valid: true},
+		"invalidClosedStatus":  {Env: map[string]string{"CLOSED_STATUS": "success"}, Valid: false},
+		"dismissalMessage":     {Env: map[string]string{"DISMISSAL_MESSAGE": "{{.Reason}}"}, Valid: true},
+		"invalidDismissal":     {Env: map[string]string{"DISMISSAL_MESSAGE": "{{.Reason"}, Valid: false},
+		"invalidStatusContext": {Env: map[string]string{"STATUS_CHECK_CONTEXT": "policy-bot: staging"}, Valid: false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			for k, v := range test.Env {
+				t.Setenv("TEST_OPTIONS_"+k, v)
+			}
+
+			var opts PullEvaluationOptions
+			opts.SetValuesFromEnv("TEST_OPTIONS_")
+
+			err := opts.Validate()
+			if test.Valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
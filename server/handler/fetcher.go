@@ -20,7 +20,9 @@ import (
 	"github.com/google/go-github/v65/github"
 	"github.com/palantir/go-githubapp/appconfig"
 	"github.com/palantir/policy-bot/policy"
-	"gopkg.in/yaml.v2"
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
 )
 
 type FetchedConfig struct {
@@ -28,12 +30,27 @@ type FetchedConfig struct {
 	LoadError  error
 	ParseError error
 
+	// Evaluator is the parsed policy of Config, if the fetcher already parsed
+	// it. Cached configs are shared by concurrent evaluations, so they are
+	// only evaluated using this evaluator and are never parsed again.
+	Evaluator common.Evaluator
+
 	Source string
 	Path   string
 }
 
 type ConfigFetcher struct {
 	Loader *appconfig.Loader
+
+	// OrgDefaultLoader loads the default policy from OrgDefaultRepository
+	// for policies that extend it. If nil, policies cannot extend the
+	// default policy.
+	OrgDefaultLoader     *appconfig.Loader
+	OrgDefaultRepository string
+
+	// LastKnownGood is an optional cache of valid policies. If set, the
+	// fetcher uses the cached policy for a branch when loading fails.
+	LastKnownGood *PolicyCache
 }
 
 func (cf *ConfigFetcher) ConfigForRepositoryBranch(ctx context.Context, client *github.Client, owner, repository, branch string) FetchedConfig {
@@ -47,16 +64,140 @@ func (cf *ConfigFetcher) ConfigForRepositoryBranch(ctx context.Context, client *
 	switch {
 	case err != nil:
 		fc.LoadError = err
-		return fc
+		return cf.lastKnownGood(ctx, owner, repository, branch, fc)
 	case c.IsUndefined():
+		if cf.LastKnownGood != nil {
+			cf.LastKnownGood.Remove(owner, repository, branch)
+		}
 		return fc
 	}
 
-	var pc policy.Config
-	if err := yaml.UnmarshalStrict(c.Content, &pc); err != nil {
+	pc, err := policy.UnmarshalConfig(c.Path, c.Content)
+	if err != nil {
 		fc.ParseError = err
-	} else {
-		fc.Config = &pc
+		return fc
+	}
+
+	fc.Config = pc
+	if pc.Extends != "" {
+		fc = cf.extendOrgDefault(ctx, client, owner, pc, fc)
+		switch {
+		case fc.LoadError != nil:
+			return cf.lastKnownGood(ctx, owner, repository, branch, fc)
+		case fc.ParseError != nil:
+			return fc
+		}
+	}
+
+	if cf.LastKnownGood != nil {
+		fc = cf.addLastKnownGood(ctx, owner, repository, branch, fc)
+	}
+	return fc
+}
+
+// ConfigForContent parses a policy that was provided directly instead of
+// loaded from a repository. Policies that extend the org default policy are
+// combined with the default policy for the owner. The result is never cached.
+func (cf *ConfigFetcher) ConfigForContent(ctx context.Context, client *github.Client, owner, source string, content []byte) FetchedConfig {
+	fc := FetchedConfig{
+		Source: source,
+	}
+
+	pc, err := policy.UnmarshalConfig(fc.Path, content)
+	if err != nil {
+		fc.ParseError = err
+		return fc
+	}
+
+	fc.Config = pc
+	if pc.Extends != "" {
+		fc = cf.extendOrgDefault(ctx, client, owner, pc, fc)
+	}
+	return fc
+}
+
+// extendOrgDefault loads the default policy for the owner and combines it
+// with pc, which extends the default policy. Errors are set on the returned
+// config.
+func (cf *ConfigFetcher) extendOrgDefault(ctx context.Context, client *github.Client, owner string, pc *policy.Config, fc FetchedConfig) FetchedConfig {
+	fc.Config = nil
+
+	if pc.Extends != policy.ExtendsOrgDefault {
+		fc.ParseError = errors.Errorf("invalid extends value %q, must be %q", pc.Extends, policy.ExtendsOrgDefault)
+		return fc
+	}
+	if cf.OrgDefaultLoader == nil {
+		fc.ParseError = errors.New("policy extends the org default policy, but org default policies are disabled")
+		return fc
+	}
+
+	r, _, err := client.Repositories.Get(ctx, owner, cf.OrgDefaultRepository)
+	if err != nil {
+		if isNotFound(err) {
+			fc.ParseError = errors.Errorf("policy extends the org default policy, but %s/%s does not exist", owner, cf.OrgDefaultRepository)
+		} else {
+			fc.LoadError = errors.Wrap(err, "failed to get org default policy repository")
+		}
+		return fc
 	}
+
+	c, err := cf.OrgDefaultLoader.LoadConfig(ctx, client, owner, cf.OrgDefaultRepository, r.GetDefaultBranch())
+	switch {
+	case err != nil:
+		fc.LoadError = errors.Wrapf(err, "failed to load org default policy from %s", c.Source)
+		return fc
+	case c.IsUndefined():
+		fc.ParseError = errors.Errorf("policy extends the org default policy, but %s/%s does not define one", owner, cf.OrgDefaultRepository)
+		return fc
+	}
+
+	base, err := policy.UnmarshalConfig(c.Path, c.Content)
+	if err != nil {
+		fc.ParseError = errors.Wrapf(err, "failed to parse org default policy in %s: %s", c.Source, c.Path)
+		return fc
+	}
+
+	extended, err := pc.Extend(base)
+	if err != nil {
+		fc.ParseError = errors.WithMessage(err, "failed to extend org default policy")
+		return fc
+	}
+
+	fc.Config = extended
 	return fc
 }
+
+// addLastKnownGood parses the policy in fc and, if it is valid, stores it as
+// the last known good policy for a branch. It returns fc with the parsed
+// evaluator, if any. Invalid policies never replace the cached policy.
+func (cf *ConfigFetcher) addLastKnownGood(ctx context.Context, owner, repository, branch string, fc FetchedConfig) FetchedConfig {
+	evaluator, err := policy.ParsePolicy(fc.Config)
+	if err != nil {
+		zerolog.Ctx(ctx).Debug().Err(err).Msgf("Not caching invalid policy from %s", fc.Source)
+		return fc
+	}
+
+	fc.Evaluator = evaluator
+	cf.LastKnownGood.Add(owner, repository, branch, fc)
+	return fc
+}
+
+// lastKnownGood returns the cached policy for a branch if one exists and is
+// not too old. Otherwise, it returns fc, which must contain a load error.
+func (cf *ConfigFetcher) lastKnownGood(ctx context.Context, owner, repository, branch string, fc FetchedConfig) FetchedConfig {
+	if cf.LastKnownGood == nil {
+		return fc
+	}
+
+	cached, ok, err := cf.LastKnownGood.Get(owner, repository, branch)
+	switch {
+	case err != nil:
+		fc.LoadError = errors.Wrap(fc.LoadError, err.Error())
+		return fc
+	case !ok:
+		return fc
+	}
+
+	zerolog.Ctx(ctx).Warn().Err(fc.LoadError).Msgf("Error loading policy from %s, using last known good policy", fc.Source)
+	return cached
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigForContent(t *testing.T) {
+	ctx := context.Background()
+	cf := &ConfigFetcher{}
+
+	t.Run("valid", func(t *testing.T) {
+		fc := cf.ConfigForContent(ctx, nil, "palantir", "request", []byte(`
+policy:
+  approval:
+    - the team has approved
+approval_rules:
+  - name: the team has approved
+    requires:
+      count: 1
+      teams: ["palantir/team"]
+`))
+
+		require.NoError(t, fc.LoadError)
+		require.NoError(t, fc.ParseError)
+		require.NotNil(t, fc.Config)
+		assert.Equal(t, "request", fc.Source)
+		assert.Len(t, fc.Config.ApprovalRules, 1)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		fc := cf.ConfigForContent(ctx, nil, "palantir", "request", []byte("policy: [}"))
+
+		assert.Error(t, fc.ParseError)
+		assert.Nil(t, fc.Config)
+	})
+
+	t.Run("extendsWithoutOrgDefault", func(t *testing.T) {
+		fc := cf.ConfigForContent(ctx, nil, "palantir", "request", []byte("extends: org-default\n"))
+
+		if assert.Error(t, fc.ParseError) {
+			assert.Contains(t, fc.ParseError.Error(), "org default policies are disabled")
+		}
+		assert.Nil(t, fc.Config)
+	})
+}
+
+func TestAddLastKnownGood(t *testing.T) {
+	ctx := context.Background()
+
+	lkg, err := NewPolicyCache(10, time.Hour)
+	require.NoError(t, err)
+	cf := &ConfigFetcher{LastKnownGood: lkg}
+
+	valid := cf.ConfigForContent(ctx, nil, "palantir", "palantir/policy-bot@develop", []byte(`
+policy:
+  approval:
+    - the team has approved
+approval_rules:
+  - name: the team has approved
+    requires:
+      count: 1
+`))
+	require.NoError(t, valid.ParseError)
+
+	fc := cf.addLastKnownGood(ctx, "palantir", "policy-bot", "develop", valid)
+	assert.NotNil(t, fc.Evaluator, "valid policy was not parsed")
+
+	cached, ok, err := lkg.Get("palantir", "policy-bot", "develop")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, fc.Evaluator, cached.Evaluator, "cached policy does not have the parsed evaluator")
+
+	// the policy unmarshals, but references a rule that does not exist
+	invalid := cf.ConfigForContent(ctx, nil, "palantir", "palantir/policy-bot@develop", []byte(`
+policy:
+  approval:
+    - the other team has approved
+`))
+	require.NoError(t, invalid.ParseError)
+
+	fc = cf.addLastKnownGood(ctx, "palantir", "policy-bot", "develop", invalid)
+	assert.Nil(t, fc.Evaluator)
+
+	cached, ok, err = lkg.Get("palantir", "policy-bot", "develop")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Same(t, valid.Config, cached.Config, "invalid policy replaced the last known good policy")
+}
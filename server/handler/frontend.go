@@ -103,7 +103,8 @@ func LoadTemplates(c *FilesConfig, basePath string, githubURL string) (templatet
 				return r
 			},
 			"hasActors": func(requires common.RequiresResult) bool {
-				return len(requires.Actors.Users) > 0 || len(requires.Actors.Teams) > 0 || len(requires.Actors.Organizations) > 0
+				return len(requires.Actors.Users) > 0 || len(requires.Actors.Teams) > 0 || len(requires.Actors.Organizations) > 0 ||
+					len(requires.Actors.WriteCollaboratorsOf) > 0 || len(requires.Actors.Bots) > 0
 			},
 			"getMethods": func(results *common.Result) map[string][]string {
 				return getMethods(results)
@@ -167,14 +168,22 @@ func Static(prefix string, c *FilesConfig) http.Handler {
 func getMethods(result *common.Result) map[string][]string {
 	const (
 		commentKey        = "Comments containing"
+		commentLineKey    = "Comments with a line equal to"
 		commentPatternKey = "Comments matching patterns"
 		bodyPatternKey    = "The pull request body matching patterns"
 		reviewKey         = "GitHub reviews with status"
 	)
 
 	patternInfo := make(map[string][]string)
+	key := commentKey
+	if result.Methods.CommentsWholeLine {
+		key = commentLineKey
+	}
+	if result.Methods.CaseSensitive != nil && !*result.Methods.CaseSensitive {
+		key += " (ignoring case)"
+	}
 	for _, comment := range result.Methods.Comments {
-		patternInfo[commentKey] = append(patternInfo[commentKey], comment)
+		patternInfo[key] = append(patternInfo[key], comment)
 	}
 	for _, commentPattern := range result.Methods.CommentPatterns {
 		patternInfo[commentPatternKey] = append(patternInfo[commentPatternKey], commentPattern.String())
@@ -200,6 +209,12 @@ func getActors(result *common.Result, githubURL string) map[string][]Membership
 		orgKey  = "Members of the organizations"
 		teamKey = "Members of the teams"
 		userKey = "Users"
+		repoKey = "Users with write access to the repositories"
+		botKey  = "Bots"
+
+		exceptOrgKey  = "Except members of the organizations"
+		exceptTeamKey = "Except members of the teams"
+		exceptUserKey = "Except users"
 	)
 
 	membershipInfo := make(map[string][]Membership)
@@ -214,6 +229,25 @@ func getActors(result *common.Result, githubURL string) map[string][]Membership
 	for _, user := range result.Requires.Actors.Users {
 		membershipInfo[userKey] = append(membershipInfo[userKey], Membership{Name: user, Link: githubURL + "/" + user})
 	}
+	for _, repo := range result.Requires.Actors.WriteCollaboratorsOf {
+		membershipInfo[repoKey] = append(membershipInfo[repoKey], Membership{Name: repo, Link: githubURL + "/" + repo})
+	}
+	for _, bot := range result.Requires.Actors.Bots {
+		app := strings.TrimSuffix(bot, "[bot]")
+		membershipInfo[botKey] = append(membershipInfo[botKey], Membership{Name: app + "[bot]", Link: githubURL + "/apps/" + app})
+	}
+	if except := result.Requires.Actors.Except; except != nil {
+		for _, org := range except.Organizations {
+			membershipInfo[exceptOrgKey] = append(membershipInfo[exceptOrgKey], Membership{Name: org, Link: githubURL + "/orgs/" + org + "/people"})
+		}
+		for _, team := range except.Teams {
+			teamName := strings.Split(team, "/")
+			membershipInfo[exceptTeamKey] = append(membershipInfo[exceptTeamKey], Membership{Name: team, Link: githubURL + "/orgs/" + teamName[0] + "/teams/" + teamName[1] + "/members"})
+		}
+		for _, user := range except.Users {
+			membershipInfo[exceptUserKey] = append(membershipInfo[exceptUserKey], Membership{Name: user, Link: githubURL + "/" + user})
+		}
+	}
 	return membershipInfo
 }
 
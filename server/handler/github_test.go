@@ -0,0 +1,114 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/google/go-github/v65/github"
+	"github.com/palantir/go-baseapp/baseapp"
+	"github.com/palantir/go-githubapp/appconfig"
+	"github.com/palantir/go-githubapp/githubapp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testGitHub is a fake GitHub API for testorg/testrepo that serves a policy
+// file on any branch and records posted statuses. Tests may register
+// additional routes on Mux before sending requests.
+type testGitHub struct {
+	Mux *http.ServeMux
+
+	srv      *httptest.Server
+	mu       sync.Mutex
+	statuses map[string][]github.RepoStatus
+}
+
+func newTestGitHub(t *testing.T, policy string) *testGitHub {
+	gh := &testGitHub{
+		Mux:      http.NewServeMux(),
+		statuses: make(map[string][]github.RepoStatus),
+	}
+
+	gh.Mux.HandleFunc("POST /app/installations/42/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{"token": "test-token", "expires_at": "2100-01-01T00:00:00Z"}`)
+	})
+	gh.Mux.HandleFunc("GET /repos/testorg/testrepo/contents/.policy.yml", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(&github.RepositoryContent{
+			Type:     github.String("file"),
+			Encoding: github.String("base64"),
+			Path:     github.String(".policy.yml"),
+			Content:  github.String(base64.StdEncoding.EncodeToString([]byte(policy))),
+		})
+	})
+	gh.Mux.HandleFunc("POST /repos/testorg/testrepo/statuses/{sha}", func(w http.ResponseWriter, r *http.Request) {
+		var status github.RepoStatus
+		if assert.NoError(t, json.NewDecoder(r.Body).Decode(&status)) {
+			gh.mu.Lock()
+			gh.statuses[r.PathValue("sha")] = append(gh.statuses[r.PathValue("sha")], status)
+			gh.mu.Unlock()
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, "{}")
+	})
+	gh.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		http.NotFound(w, r)
+	})
+
+	gh.srv = httptest.NewServer(gh.Mux)
+	t.Cleanup(gh.srv.Close)
+
+	return gh
+}
+
+// Base returns a handler base that uses the fake API with installation 42.
+func (gh *testGitHub) Base(t *testing.T) Base {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	return Base{
+		ClientCreator: githubapp.NewClientCreator(gh.srv.URL+"/", gh.srv.URL+"/graphql", 1, keyPEM),
+		ConfigFetcher: &ConfigFetcher{
+			Loader: appconfig.NewLoader([]string{".policy.yml"}),
+		},
+		BaseConfig: &baseapp.HTTPConfig{
+			PublicURL: "https://policy-bot.localhost",
+		},
+		PullOpts: &PullEvaluationOptions{
+			StatusCheckContext: "policy-bot",
+		},
+		AppName: "policy-bot",
+	}
+}
+
+// Statuses returns the statuses posted for a commit.
+func (gh *testGitHub) Statuses(sha string) []github.RepoStatus {
+	gh.mu.Lock()
+	defer gh.mu.Unlock()
+	return gh.statuses[sha]
+}
@@ -149,6 +149,12 @@ func (h *IssueComment) affectsApproval(event github.IssueCommentEvent, config *p
 	if disapproval := config.Policy.Disapproval; disapproval != nil {
 		methods = append(methods, disapproval.Options.GetDisapproveMethods())
 		methods = append(methods, disapproval.Options.GetRevokeMethods())
+		if dm := disapproval.Options.GetDismissMethods(); dm != nil {
+			methods = append(methods, dm)
+		}
+	}
+	if breakGlass := config.Policy.BreakGlass; breakGlass != nil {
+		methods = append(methods, breakGlass.Options.GetMethods())
 	}
 
 	for _, m := range methods {
@@ -18,14 +18,22 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/google/go-github/v65/github"
 	"github.com/palantir/go-githubapp/githubapp"
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 )
 
+// mergeGroupRefPattern matches the head ref of a merge group, which contains
+// the number of the pull request that was added to the merge queue.
+var mergeGroupRefPattern = regexp.MustCompile(`^refs/heads/gh-readonly-queue/.+/pr-(\d+)-[0-9a-f]+$`)
+
 type MergeGroup struct {
 	Base
 }
@@ -34,6 +42,10 @@ func (h *MergeGroup) Handles() []string { return []string{"merge_group"} }
 
 // Handle merge_group
 // https://docs.github.com/webhooks-and-events/webhooks/webhook-events-and-payloads#merge_group
+//
+// The policy is evaluated for the pull request that was added to the merge
+// queue and the status is posted on the head commit of the merge group.
+// Approvals and other activity on the pull request apply to the merge group.
 func (h *MergeGroup) Handle(ctx context.Context, eventType, devlieryID string, payload []byte) error {
 	var event github.MergeGroupEvent
 
@@ -45,13 +57,58 @@ func (h *MergeGroup) Handle(ctx context.Context, eventType, devlieryID string, p
 		return nil
 	}
 
-	logger := zerolog.Ctx(ctx)
 	installationID := githubapp.GetInstallationIDFromEvent(&event)
 	client, err := h.NewInstallationClient(installationID)
 	if err != nil {
 		return err
 	}
 
+	repository := event.GetRepo().GetName()
+	owner := event.GetRepo().GetOwner().GetLogin()
+	mergeGroup := event.GetMergeGroup()
+
+	number, ok := mergeGroupPullRequest(mergeGroup.GetHeadRef())
+	if !ok {
+		zerolog.Ctx(ctx).Warn().Msgf("Failed to find pull request for merge group ref %q", mergeGroup.GetHeadRef())
+		return h.postPreviouslyApproved(ctx, client, event)
+	}
+
+	pr, _, err := client.PullRequests.Get(ctx, owner, repository, number)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get pull request %d for merge group", number)
+	}
+
+	ctx, _ = h.PreparePRContext(ctx, installationID, pr)
+	evalCtx, err := h.NewEvalContext(ctx, installationID, pull.Locator{
+		Owner:  owner,
+		Repo:   repository,
+		Number: number,
+		Value:  pr,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create evaluation context")
+	}
+	if ghc, ok := evalCtx.PullContext.(*pull.GitHubContext); ok {
+		ghc.SetEventSource(pull.EventSourceMergeGroup)
+	}
+	evalCtx.StatusSHA = mergeGroup.GetHeadSHA()
+
+	evaluator, err := evalCtx.ParseConfig(ctx, common.TriggerAll)
+	if err != nil || evaluator == nil {
+		return err
+	}
+
+	// Skip evalCtx.RunPostEvaluateActions() because actions like requesting
+	// reviewers apply to the pull request and run for pull request events
+	_, err = evalCtx.EvaluatePolicy(ctx, evaluator)
+	return err
+}
+
+// postPreviouslyApproved posts a successful status for a merge group that
+// could not be matched to a pull request.
+func (h *MergeGroup) postPreviouslyApproved(ctx context.Context, client *github.Client, event github.MergeGroupEvent) error {
+	logger := zerolog.Ctx(ctx)
+
 	repository := event.GetRepo().GetName()
 	owner := event.GetRepo().GetOwner().GetLogin()
 	mergeGroup := event.GetMergeGroup()
@@ -80,3 +137,17 @@ func (h *MergeGroup) Handle(ctx context.Context, eventType, devlieryID string, p
 
 	return nil
 }
+
+// mergeGroupPullRequest returns the number of the pull request in a merge
+// group given the head ref of the group.
+func mergeGroupPullRequest(headRef string) (int, bool) {
+	m := mergeGroupRefPattern.FindStringSubmatch(headRef)
+	if m == nil {
+		return 0, false
+	}
+	number, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, false
+	}
+	return number, true
+}
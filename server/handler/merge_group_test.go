@@ -0,0 +1,91 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeGroup(t *testing.T) {
+	const mergeGroupSHA = "7d2d6b5b1c2f4a0a8c5b0c94e0b9f5c0f1e2d3c4"
+	const pullRequestSHA = "e05fcae367230ee709313dd2720da527d178ce43"
+
+	gh := newTestGitHub(t, `
+policy:
+  approval:
+    - in merge queue
+approval_rules:
+  - name: in merge queue
+    if:
+      in_merge_queue: true
+`)
+	gh.Mux.HandleFunc("GET /repos/testorg/testrepo/pulls/123", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/pull_request.json")
+	})
+
+	t.Run("mergeGroup", func(t *testing.T) {
+		payload, err := os.ReadFile("testdata/merge_group_checks_requested.json")
+		require.NoError(t, err)
+
+		h := &MergeGroup{Base: gh.Base(t)}
+		err = h.Handle(context.Background(), "merge_group", "test-delivery", payload)
+		require.NoError(t, err)
+
+		assert.Empty(t, gh.Statuses(pullRequestSHA), "status was posted for pull request")
+
+		statuses := gh.Statuses(mergeGroupSHA)
+		if assert.Len(t, statuses, 1, "incorrect number of statuses posted") {
+			status := statuses[0]
+			assert.Equal(t, "success", status.GetState())
+			assert.Equal(t, "policy-bot: develop", status.GetContext())
+			assert.Equal(t, "All rules are approved", status.GetDescription())
+		}
+	})
+
+	t.Run("pullRequest", func(t *testing.T) {
+		payload, err := os.ReadFile("testdata/pull_request_ready_for_review.json")
+		require.NoError(t, err)
+
+		h := &PullRequest{Base: gh.Base(t)}
+		err = h.Handle(context.Background(), "pull_request", "test-delivery", payload)
+		require.NoError(t, err)
+
+		statuses := gh.Statuses(pullRequestSHA)
+		if assert.Len(t, statuses, 1, "incorrect number of statuses posted") {
+			status := statuses[0]
+			assert.Equal(t, "error", status.GetState())
+			assert.Equal(t, "All rules were skipped. At least one rule must match.", status.GetDescription())
+		}
+	})
+}
+
+func TestMergeGroupPullRequest(t *testing.T) {
+	number, ok := mergeGroupPullRequest("refs/heads/gh-readonly-queue/main/pr-1234-a6f3f69b64eaafece5a0d854eb4af11c0d64394c")
+	assert.True(t, ok)
+	assert.Equal(t, 1234, number)
+
+	number, ok = mergeGroupPullRequest("refs/heads/gh-readonly-queue/release/1.x/pr-56-a6f3f69b64eaafece5a0d854eb4af11c0d64394c")
+	assert.True(t, ok)
+	assert.Equal(t, 56, number)
+
+	_, ok = mergeGroupPullRequest("refs/heads/feature")
+	assert.False(t, ok)
+}
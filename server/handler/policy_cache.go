@@ -0,0 +1,86 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"fmt"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/pkg/errors"
+)
+
+// PolicyCache stores the last valid policy loaded for each repository branch.
+// If loading a policy fails, the ConfigFetcher uses the cached policy instead,
+// as long as it is not older than the maximum age.
+type PolicyCache struct {
+	maxAge time.Duration
+	cache  *lru.Cache
+	now    func() time.Time
+}
+
+type cachedPolicy struct {
+	config   FetchedConfig
+	loadedAt time.Time
+}
+
+func NewPolicyCache(size int, maxAge time.Duration) (*PolicyCache, error) {
+	if maxAge <= 0 {
+		return nil, errors.New("maximum policy age must be positive")
+	}
+
+	cache, err := lru.New(size)
+	if err != nil {
+		return nil, err
+	}
+	return &PolicyCache{maxAge: maxAge, cache: cache, now: time.Now}, nil
+}
+
+// Add stores a successfully loaded and parsed policy for a branch. The config
+// is shared with later evaluations and must not be modified after it is added.
+func (pc *PolicyCache) Add(owner, repository, branch string, fc FetchedConfig) {
+	pc.cache.Add(policyCacheKey(owner, repository, branch), cachedPolicy{
+		config:   fc,
+		loadedAt: pc.now(),
+	})
+}
+
+// Remove deletes any cached policy for a branch.
+func (pc *PolicyCache) Remove(owner, repository, branch string) {
+	pc.cache.Remove(policyCacheKey(owner, repository, branch))
+}
+
+// Get returns the cached policy for a branch and true if it exists. If the
+// cached policy is older than the maximum age, Get removes it and returns an
+// error instead.
+func (pc *PolicyCache) Get(owner, repository, branch string) (FetchedConfig, bool, error) {
+	key := policyCacheKey(owner, repository, branch)
+
+	val, ok := pc.cache.Get(key)
+	if !ok {
+		return FetchedConfig{}, false, nil
+	}
+
+	cp := val.(cachedPolicy)
+	if age := pc.now().Sub(cp.loadedAt); age > pc.maxAge {
+		pc.cache.Remove(key)
+		return FetchedConfig{}, false, errors.Errorf("last known good policy expired: loaded %s ago, maximum age is %s", age.Round(time.Second), pc.maxAge)
+	}
+	return cp.config, true, nil
+}
+
+func policyCacheKey(owner, repository, branch string) string {
+	return fmt.Sprintf("%s/%s:%s", owner, repository, branch)
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/palantir/policy-bot/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyCache(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	newCache := func(t *testing.T) *PolicyCache {
+		pc, err := NewPolicyCache(10, time.Hour)
+		require.NoError(t, err)
+		pc.now = func() time.Time { return now }
+		pc.Add("palantir", "policy-bot", "develop", FetchedConfig{
+			Config: &policy.Config{},
+			Source: "palantir/policy-bot@develop",
+			Path:   ".policy.yml",
+		})
+		return pc
+	}
+
+	t.Run("missing", func(t *testing.T) {
+		pc := newCache(t)
+
+		_, ok, err := pc.Get("palantir", "policy-bot", "main")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("beforeMaxAge", func(t *testing.T) {
+		pc := newCache(t)
+		pc.now = func() time.Time { return now.Add(time.Hour - time.Nanosecond) }
+
+		fc, ok, err := pc.Get("palantir", "policy-bot", "develop")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "palantir/policy-bot@develop", fc.Source)
+		assert.NotNil(t, fc.Config)
+	})
+
+	t.Run("atMaxAge", func(t *testing.T) {
+		pc := newCache(t)
+		pc.now = func() time.Time { return now.Add(time.Hour) }
+
+		_, ok, err := pc.Get("palantir", "policy-bot", "develop")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("afterMaxAge", func(t *testing.T) {
+		pc := newCache(t)
+		pc.now = func() time.Time { return now.Add(time.Hour + time.Second) }
+
+		_, ok, err := pc.Get("palantir", "policy-bot", "develop")
+		assert.EqualError(t, err, "last known good policy expired: loaded 1h0m1s ago, maximum age is 1h0m0s")
+		assert.False(t, ok)
+
+		_, ok, err = pc.Get("palantir", "policy-bot", "develop")
+		assert.NoError(t, err, "expired policy was not removed")
+		assert.False(t, ok)
+	})
+
+	t.Run("addRefreshesAge", func(t *testing.T) {
+		pc := newCache(t)
+		pc.now = func() time.Time { return now.Add(30 * time.Minute) }
+		pc.Add("palantir", "policy-bot", "develop", FetchedConfig{Config: &policy.Config{}})
+
+		pc.now = func() time.Time { return now.Add(90 * time.Minute) }
+
+		_, ok, err := pc.Get("palantir", "policy-bot", "develop")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		pc := newCache(t)
+		pc.Remove("palantir", "policy-bot", "develop")
+
+		_, ok, err := pc.Get("palantir", "policy-bot", "develop")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("invalidMaxAge", func(t *testing.T) {
+		_, err := NewPolicyCache(10, 0)
+		assert.Error(t, err)
+	})
+}
@@ -42,24 +42,66 @@ func (h *PullRequest) Handle(ctx context.Context, eventType, deliveryID string,
 	installationID := githubapp.GetInstallationIDFromEvent(&event)
 	ctx, _ = h.PreparePRContext(ctx, installationID, event.GetPullRequest())
 
-	var t common.Trigger
+	if event.GetAction() == "closed" {
+		return h.handleClosed(ctx, installationID, event)
+	}
+
+	t := h.trigger(event)
+	if t == common.TriggerStatic {
+		return nil
+	}
+
+	evalCtx, err := h.NewEvalContext(ctx, installationID, pull.Locator{
+		Owner:  event.GetRepo().GetOwner().GetLogin(),
+		Repo:   event.GetRepo().GetName(),
+		Number: event.GetPullRequest().GetNumber(),
+		Value:  event.GetPullRequest(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create evaluation context")
+	}
+
+	// Removing a review request can change the author_is_requested_reviewer
+	// predicate, but requesting reviewers would undo the removal
+	evalCtx.SkipReviewRequests = event.GetAction() == "review_request_removed"
+
+	return evalCtx.Evaluate(ctx, t)
+}
+
+// trigger returns the trigger for a pull request event, or TriggerStatic if
+// the event does not require evaluation.
+func (h *PullRequest) trigger(event github.PullRequestEvent) common.Trigger {
 	switch event.GetAction() {
 	case "opened", "reopened", "ready_for_review":
-		t = common.TriggerCommit | common.TriggerPullRequest
+		return common.TriggerCommit | common.TriggerPullRequest
 	case "synchronize":
-		t = common.TriggerCommit
-	case "edited":
-		t = common.TriggerPullRequest
+		return common.TriggerCommit
+	case "edited", "converted_to_draft", "review_requested", "review_request_removed", "auto_merge_enabled", "auto_merge_disabled":
+		return common.TriggerPullRequest
 	case "labeled", "unlabeled":
-		t = common.TriggerLabel
-	default:
+		// Ignore label changes made by policy-bot to avoid evaluation loops
+		if event.GetSender().GetLogin() == h.AppName+"[bot]" {
+			return common.TriggerStatic
+		}
+		return common.TriggerLabel
+	}
+	return common.TriggerStatic
+}
+
+func (h *PullRequest) handleClosed(ctx context.Context, installationID int64, event github.PullRequestEvent) error {
+	if h.PullOpts.ClosedStatus == "" {
 		return nil
 	}
 
-	return h.Evaluate(ctx, installationID, t, pull.Locator{
+	evalCtx, err := h.NewEvalContext(ctx, installationID, pull.Locator{
 		Owner:  event.GetRepo().GetOwner().GetLogin(),
 		Repo:   event.GetRepo().GetName(),
 		Number: event.GetPullRequest().GetNumber(),
 		Value:  event.GetPullRequest(),
 	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create evaluation context")
+	}
+
+	return evalCtx.PostClosedStatus(ctx, event.GetPullRequest().GetMerged())
 }
@@ -98,6 +98,13 @@ func (h *PullRequestReview) affectsApproval(reviewState pull.ReviewState, config
 	if disapproval := config.Policy.Disapproval; disapproval != nil {
 		states[disapproval.Options.GetDisapproveMethods().GithubReviewState] = struct{}{}
 		states[disapproval.Options.GetRevokeMethods().GithubReviewState] = struct{}{}
+		if dm := disapproval.Options.GetDismissMethods(); dm != nil {
+			states[dm.GithubReviewState] = struct{}{}
+		}
+		if disapproval.Options.GithubChangesRequested {
+			states[pull.ReviewApproved] = struct{}{}
+			states[pull.ReviewChangesRequested] = struct{}{}
+		}
 	}
 
 	for state := range states {
@@ -0,0 +1,54 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/go-github/v65/github"
+	"github.com/palantir/go-githubapp/githubapp"
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/pkg/errors"
+)
+
+type PullRequestReviewThread struct {
+	Base
+}
+
+func (h *PullRequestReviewThread) Handles() []string { return []string{"pull_request_review_thread"} }
+
+// Handle pull_request_review_thread
+// https://docs.github.com/webhooks/webhook-events-and-payloads#pull_request_review_thread
+func (h *PullRequestReviewThread) Handle(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+	var event github.PullRequestReviewThreadEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return errors.Wrap(err, "failed to parse pull request review thread event payload")
+	}
+
+	pr := event.GetPullRequest()
+	repo := event.GetRepo()
+	installationID := githubapp.GetInstallationIDFromEvent(&event)
+
+	ctx, _ = h.PreparePRContext(ctx, installationID, pr)
+
+	return h.Evaluate(ctx, installationID, common.TriggerReview, pull.Locator{
+		Owner:  repo.GetOwner().GetLogin(),
+		Repo:   repo.GetName(),
+		Number: pr.GetNumber(),
+		Value:  pr,
+	})
+}
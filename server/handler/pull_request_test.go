@@ -0,0 +1,170 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/google/go-github/v65/github"
+	"github.com/palantir/go-baseapp/baseapp"
+	"github.com/palantir/go-githubapp/appconfig"
+	"github.com/palantir/go-githubapp/githubapp"
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullRequestTrigger(t *testing.T) {
+	h := &PullRequest{
+		Base: Base{
+			AppName: "policy-bot",
+		},
+	}
+
+	loadEvent := func(t *testing.T) github.PullRequestEvent {
+		payload, err := os.ReadFile("testdata/pull_request_labeled.json")
+		require.NoError(t, err)
+
+		var event github.PullRequestEvent
+		require.NoError(t, json.Unmarshal(payload, &event))
+		return event
+	}
+
+	t.Run("labeled", func(t *testing.T) {
+		event := loadEvent(t)
+		assert.Equal(t, common.TriggerLabel, h.trigger(event))
+	})
+
+	t.Run("unlabeled", func(t *testing.T) {
+		event := loadEvent(t)
+		event.Action = github.String("unlabeled")
+		assert.Equal(t, common.TriggerLabel, h.trigger(event))
+	})
+
+	t.Run("labeledByPolicyBot", func(t *testing.T) {
+		event := loadEvent(t)
+		event.Sender.Login = github.String("policy-bot[bot]")
+		assert.Equal(t, common.TriggerStatic, h.trigger(event))
+	})
+
+	t.Run("otherActions", func(t *testing.T) {
+		event := loadEvent(t)
+
+		event.Action = github.String("synchronize")
+		assert.Equal(t, common.TriggerCommit, h.trigger(event))
+
+		event.Action = github.String("opened")
+		assert.Equal(t, common.TriggerCommit|common.TriggerPullRequest, h.trigger(event))
+
+		event.Action = github.String("ready_for_review")
+		assert.Equal(t, common.TriggerCommit|common.TriggerPullRequest, h.trigger(event))
+
+		event.Action = github.String("converted_to_draft")
+		assert.Equal(t, common.TriggerPullRequest, h.trigger(event))
+
+		event.Action = github.String("assigned")
+		assert.Equal(t, common.TriggerStatic, h.trigger(event))
+	})
+}
+
+func TestPullRequestReadyForReview(t *testing.T) {
+	const policy = `
+policy:
+  approval:
+    - no approval required
+approval_rules:
+  - name: no approval required
+`
+
+	var mu sync.Mutex
+	var statuses []github.RepoStatus
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /app/installations/42/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{"token": "test-token", "expires_at": "2100-01-01T00:00:00Z"}`)
+	})
+	mux.HandleFunc("GET /repos/testorg/testrepo/contents/.policy.yml", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "develop", r.URL.Query().Get("ref"))
+		_ = json.NewEncoder(w).Encode(&github.RepositoryContent{
+			Type:     github.String("file"),
+			Encoding: github.String("base64"),
+			Path:     github.String(".policy.yml"),
+			Content:  github.String(base64.StdEncoding.EncodeToString([]byte(policy))),
+		})
+	})
+	mux.HandleFunc("POST /repos/testorg/testrepo/statuses/e05fcae367230ee709313dd2720da527d178ce43", func(w http.ResponseWriter, r *http.Request) {
+		var status github.RepoStatus
+		if assert.NoError(t, json.NewDecoder(r.Body).Decode(&status)) {
+			mu.Lock()
+			statuses = append(statuses, status)
+			mu.Unlock()
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, "{}")
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		http.NotFound(w, r)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	h := &PullRequest{
+		Base: Base{
+			ClientCreator: githubapp.NewClientCreator(srv.URL+"/", srv.URL+"/graphql", 1, keyPEM),
+			ConfigFetcher: &ConfigFetcher{
+				Loader: appconfig.NewLoader([]string{".policy.yml"}),
+			},
+			BaseConfig: &baseapp.HTTPConfig{
+				PublicURL: "https://policy-bot.localhost",
+			},
+			PullOpts: &PullEvaluationOptions{
+				StatusCheckContext: "policy-bot",
+			},
+			AppName: "policy-bot",
+		},
+	}
+
+	payload, err := os.ReadFile("testdata/pull_request_ready_for_review.json")
+	require.NoError(t, err)
+
+	err = h.Handle(context.Background(), "pull_request", "test-delivery", payload)
+	require.NoError(t, err)
+
+	if assert.Len(t, statuses, 1, "incorrect number of statuses posted") {
+		status := statuses[0]
+		assert.Equal(t, "success", status.GetState())
+		assert.Equal(t, "policy-bot: develop", status.GetContext())
+		assert.Equal(t, "https://policy-bot.localhost/details/testorg/testrepo/123", status.GetTargetURL())
+	}
+}
@@ -124,9 +124,11 @@ func (h *Simulate) getSimulatedResult(ctx context.Context, installation githubap
 		return nil, nil
 	}
 
-	evaluator, err := policy.ParsePolicy(config.Config)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get policy evaluator")
+	evaluator := config.Evaluator
+	if evaluator == nil {
+		if evaluator, err = policy.ParsePolicy(config.Config); err != nil {
+			return nil, errors.Wrap(err, "failed to get policy evaluator")
+		}
 	}
 
 	result := evaluator.Evaluate(ctx, simulatedCtx)
@@ -144,11 +146,14 @@ func (h *Simulate) newSimulatedContext(ctx context.Context, installationID int64
 		return nil, nil, err
 	}
 
-	mbrCtx := NewCrossOrgMembershipContext(ctx, client, loc.Owner, h.Installations, h.ClientCreator)
+	mbrCtx := NewCrossOrgMembershipContext(ctx, client, v4client, loc.Owner, h.Installations, h.ClientCreator)
 	prctx, err := pull.NewGitHubContext(ctx, mbrCtx, h.GlobalCache, client, v4client, loc)
 	if err != nil {
 		return nil, nil, err
 	}
+	if ghc, ok := prctx.(*pull.GitHubContext); ok {
+		ghc.SetUserRegions(h.PullOpts.UserRegions())
+	}
 
 	simulatedPRCtx := simulated.NewContext(ctx, prctx, options)
 	baseBranch, _ := simulatedPRCtx.Branches()
@@ -0,0 +1,152 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/google/go-github/v65/github"
+	"github.com/palantir/go-baseapp/baseapp"
+	"github.com/palantir/go-githubapp/githubapp"
+	"github.com/palantir/policy-bot/policy/simulated"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"goji.io/pat"
+)
+
+const (
+	DefaultBatchSimulationConcurrency = 4
+	MaxBatchSimulationSize            = 100
+)
+
+// SimulateBatch performs the same simulated evaluation for multiple pull
+// requests in a repository and returns all of the results.
+type SimulateBatch struct {
+	Simulate
+
+	// Concurrency is the maximum number of pull requests evaluated at the
+	// same time. If not positive, DefaultBatchSimulationConcurrency is used.
+	Concurrency int
+}
+
+// BatchSimulationResponse is the result for one pull request in a batch. If
+// the pull request could not be evaluated, Error is set and Result is nil.
+type BatchSimulationResponse struct {
+	Number           int                 `json:"number"`
+	Result           *SimulationResponse `json:"result,omitempty"`
+	Error            string              `json:"error,omitempty"`
+	IsTemporaryError bool                `json:"is_temporary_error"`
+}
+
+func (h *SimulateBatch) ServeHTTP(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	token := getToken(r)
+	if token == "" {
+		return writeAPIError(w, http.StatusUnauthorized, "missing token")
+	}
+
+	client, err := h.NewTokenClient(token)
+	if err != nil {
+		return errors.Wrap(err, "failed to create token client")
+	}
+
+	owner := pat.Param(r, "owner")
+	repo := pat.Param(r, "repo")
+
+	batch, err := simulated.NewBatchOptionsFromRequest(r)
+	if err != nil {
+		return writeAPIError(w, http.StatusBadRequest, "failed to parse options from request")
+	}
+	if len(batch.PullRequests) > MaxBatchSimulationSize {
+		return writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("too many pull requests, the maximum is %d", MaxBatchSimulationSize))
+	}
+
+	installation, err := h.Installations.GetByOwner(ctx, owner)
+	if err != nil {
+		return writeAPIError(w, http.StatusNotFound, "not installed in org")
+	}
+
+	concurrency := h.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBatchSimulationConcurrency
+	}
+
+	responses := make([]BatchSimulationResponse, len(batch.PullRequests))
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for i, number := range batch.PullRequests {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i, number int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			responses[i] = h.simulatePullRequest(ctx, client, installation, owner, repo, number, batch.Options)
+		}(i, number)
+	}
+	wg.Wait()
+
+	baseapp.WriteJSON(w, http.StatusOK, responses)
+	return nil
+}
+
+// simulatePullRequest evaluates a single pull request in a batch. Errors are
+// reported in the response so that one pull request does not fail the batch.
+func (h *SimulateBatch) simulatePullRequest(ctx context.Context, client *github.Client, installation githubapp.Installation, owner, repo string, number int, options simulated.Options) BatchSimulationResponse {
+	response := BatchSimulationResponse{Number: number}
+
+	// fetch the pull request with the user's token to check that they can access it
+	pr, _, err := client.PullRequests.Get(ctx, owner, repo, number)
+	if err != nil {
+		if isNotFound(err) {
+			response.Error = "failed to find pull request"
+		} else {
+			zerolog.Ctx(ctx).Error().Err(err).Msgf("Failed to get pull request %d for batch simulation", number)
+			response.Error = "failed to get pull request"
+		}
+		return response
+	}
+
+	ctx, logger := h.PreparePRContext(ctx, installation.ID, pr)
+
+	result, err := h.getSimulatedResult(ctx, installation, pull.Locator{
+		Owner:  owner,
+		Repo:   repo,
+		Number: number,
+		Value:  pr,
+	}, options)
+	if err != nil {
+		// Do not return the error, which may contain details of the
+		// evaluation that are not visible to the user
+		logger.Error().Err(err).Msg("Failed to get simulated approval result for pull request")
+
+		response.Error = "Failed to evaluate policy"
+		if _, ok := errors.Cause(err).(*pull.TemporaryError); ok {
+			response.IsTemporaryError = true
+			response.Error = "Failed to evaluate policy due to a temporary error"
+		}
+		return response
+	}
+
+	response.Result = newSimulationResponse(result)
+	return response
+}
@@ -41,6 +41,8 @@ func (h *Status) Handle(ctx context.Context, eventType, deliveryID string, paylo
 		return errors.Wrap(err, "failed to parse status event payload")
 	}
 
+	h.InvalidateStatuses(event.GetRepo().GetID(), event.GetCommit().GetSHA())
+
 	ownContext := h.PullOpts.StatusCheckContext
 	if event.GetContext() == ownContext || strings.HasPrefix(event.GetContext(), ownContext+":") {
 		return h.processOwn(ctx, event)
@@ -24,7 +24,6 @@ import (
 	"github.com/palantir/policy-bot/policy"
 	"github.com/palantir/policy-bot/version"
 	"github.com/rs/zerolog"
-	"gopkg.in/yaml.v2"
 )
 
 type ValidateCheck struct {
@@ -68,12 +67,12 @@ func Validate() http.Handler {
 }
 
 func isValidLocalPolicy(requestPolicy []byte) (bool, error) {
-	var policyConfig policy.Config
-	if err := yaml.UnmarshalStrict(requestPolicy, &policyConfig); err != nil {
+	policyConfig, err := policy.UnmarshalConfig("", requestPolicy)
+	if err != nil {
 		return false, err
 	}
 
-	if _, err := policy.ParsePolicy(&policyConfig); err != nil {
+	if _, err := policy.ParsePolicy(policyConfig); err != nil {
 		return false, err
 	}
 
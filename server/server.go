@@ -45,11 +45,17 @@ const (
 	DefaultSessionLifetime = 24 * time.Hour
 	DefaultGitHubTimeout   = 10 * time.Second
 
+	DefaultGitHubMaxAttempts = 3
+	DefaultGitHubRetryDelay  = 500 * time.Millisecond
+
 	DefaultWebhookWorkers   = 10
 	DefaultWebhookQueueSize = 100
 
-	DefaultHTTPCacheSize     = 50 * datasize.MB
-	DefaultPushedAtCacheSize = 100_000
+	DefaultHTTPCacheSize        = 50 * datasize.MB
+	DefaultPushedAtCacheSize    = 100_000
+	DefaultRuleResultCacheSize  = 10_000
+	DefaultCommitFilesCacheSize = 1_000
+	DefaultPolicyCacheSize      = 1_000
 )
 
 type Server struct {
@@ -108,6 +114,18 @@ func New(c *Config) (*Server, error) {
 		return nil, errors.Wrap(err, "invalid v4 API URL")
 	}
 
+	retry := pull.RetryOptions{
+		MaxAttempts: c.Workers.GithubRetry.MaxAttempts,
+		BaseDelay:   c.Workers.GithubRetry.BaseDelay,
+		GraphQLPath: v4URL.Path,
+	}
+	if retry.MaxAttempts == 0 {
+		retry.MaxAttempts = DefaultGitHubMaxAttempts
+	}
+	if retry.BaseDelay == 0 {
+		retry.BaseDelay = DefaultGitHubRetryDelay
+	}
+
 	userAgent := fmt.Sprintf("policy-bot/%s", version.GetVersion())
 	cc, err := githubapp.NewDefaultCachingClientCreator(
 		c.Github,
@@ -122,6 +140,7 @@ func New(c *Config) (*Server, error) {
 				githubapp.LogRequestBody("^"+v4URL.Path+"$"),
 			),
 			githubapp.ClientMetrics(base.Registry()),
+			pull.ClientRetry(retry),
 		),
 	)
 	if err != nil {
@@ -143,21 +162,50 @@ func New(c *Config) (*Server, error) {
 		pushedAtSize = DefaultPushedAtCacheSize
 	}
 
-	globalCache, err := pull.NewLRUGlobalCache(pushedAtSize)
+	ruleResultSize := c.Cache.RuleResultSize
+	if ruleResultSize == 0 {
+		ruleResultSize = DefaultRuleResultCacheSize
+	}
+
+	commitFilesSize := c.Cache.CommitFilesSize
+	if commitFilesSize == 0 {
+		commitFilesSize = DefaultCommitFilesCacheSize
+	}
+
+	globalCache, err := pull.NewLRUGlobalCache(pushedAtSize, ruleResultSize, commitFilesSize, c.Cache.StatusSize)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to initialize global cache")
 	}
 
+	var lastKnownGood *handler.PolicyCache
+	if c.Cache.PolicyMaxAge > 0 {
+		policySize := c.Cache.PolicySize
+		if policySize == 0 {
+			policySize = DefaultPolicyCacheSize
+		}
+
+		lastKnownGood, err = handler.NewPolicyCache(policySize, c.Cache.PolicyMaxAge)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to initialize policy cache")
+		}
+	}
+
 	sharedPolicyPaths := []string{}
 	if c.Options.SharedPolicyPath != nil {
 		sharedPolicyPaths = []string{*c.Options.SharedPolicyPath}
 	}
 
+	var orgDefaultLoader *appconfig.Loader
+	if *c.Options.SharedRepository != "" {
+		orgDefaultLoader = appconfig.NewLoader(sharedPolicyPaths, appconfig.WithOwnerDefault("", nil))
+	}
+
 	basePolicyHandler := handler.Base{
 		ClientCreator: cc,
 		BaseConfig:    &c.Server,
 		Installations: githubapp.NewInstallationsService(appClient),
 		GlobalCache:   globalCache,
+		Scheduler:     handler.NewScheduler(),
 
 		PullOpts: &c.Options,
 		ConfigFetcher: &handler.ConfigFetcher{
@@ -165,6 +213,9 @@ func New(c *Config) (*Server, error) {
 				[]string{c.Options.PolicyPath},
 				appconfig.WithOwnerDefault(*c.Options.SharedRepository, sharedPolicyPaths),
 			),
+			OrgDefaultLoader:     orgDefaultLoader,
+			OrgDefaultRepository: *c.Options.SharedRepository,
+			LastKnownGood:        lastKnownGood,
 		},
 
 		AppName: app.GetSlug(),
@@ -186,9 +237,11 @@ func New(c *Config) (*Server, error) {
 			&handler.MergeGroup{Base: basePolicyHandler},
 			&handler.PullRequest{Base: basePolicyHandler},
 			&handler.PullRequestReview{Base: basePolicyHandler},
+			&handler.PullRequestReviewThread{Base: basePolicyHandler},
 			&handler.IssueComment{Base: basePolicyHandler},
 			&handler.Status{Base: basePolicyHandler},
 			&handler.CheckRun{Base: basePolicyHandler},
+			&handler.DeploymentStatus{Base: basePolicyHandler},
 			&handler.WorkflowRun{Base: basePolicyHandler},
 		},
 		c.Github.App.WebhookSecret,
@@ -198,6 +251,9 @@ func New(c *Config) (*Server, error) {
 				queueSize, workers,
 				githubapp.WithSchedulingMetrics(base.Registry()),
 				githubapp.WithAsyncErrorCallback(githubapp.MetricsAsyncErrorCallback(base.Registry())),
+				githubapp.WithContextDeriver(func(ctx context.Context) context.Context {
+					return baseapp.WithMetricsCtx(githubapp.DefaultContextDeriver(ctx), base.Registry())
+				}),
 			),
 		),
 	)
@@ -221,12 +277,20 @@ func New(c *Config) (*Server, error) {
 	simulateHandler := &handler.Simulate{
 		Base: basePolicyHandler,
 	}
+	simulateBatchHandler := &handler.SimulateBatch{
+		Simulate: *simulateHandler,
+	}
+	dryRunHandler := &handler.DryRun{
+		Base: basePolicyHandler,
+	}
 
 	// additional API routes
 	mux.Handle(pat.Get("/api/health"), handler.Health())
 	mux.Handle(pat.Get("/api/metrics"), handler.Metrics(base.Registry(), c.Prometheus))
 	mux.Handle(pat.Put("/api/validate"), handler.Validate())
+	mux.Handle(pat.Post("/api/validate/:owner/:repo/:number"), hatpear.Try(dryRunHandler))
 	mux.Handle(pat.Post("/api/simulate/:owner/:repo/:number"), hatpear.Try(simulateHandler))
+	mux.Handle(pat.Post("/api/simulate/:owner/:repo"), hatpear.Try(simulateBatchHandler))
 
 	oauth2RedirectURL := *publicURL
 	oauth2RedirectURL.Path = basePath + oauth2.DefaultRoute